@@ -0,0 +1,108 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"os"
+
+	coreosalicloud "github.com/gardener/gardener-extensions/controllers/os-coreos-alicloud/cmd/gardener-extension-os-coreos-alicloud/app"
+	coreos "github.com/gardener/gardener-extensions/controllers/os-coreos/cmd/gardener-extension-os-coreos/app"
+	provideralicloud "github.com/gardener/gardener-extensions/controllers/provider-alicloud/cmd/gardener-extension-provider-alicloud/app"
+	provideraws "github.com/gardener/gardener-extensions/controllers/provider-aws/cmd/gardener-extension-provider-aws/app"
+	providerazure "github.com/gardener/gardener-extensions/controllers/provider-azure/cmd/gardener-extension-provider-azure/app"
+	providergcp "github.com/gardener/gardener-extensions/controllers/provider-gcp/cmd/gardener-extension-provider-gcp/app"
+	providerlocal "github.com/gardener/gardener-extensions/controllers/provider-local/cmd/gardener-extension-provider-local/app"
+	provideropenstack "github.com/gardener/gardener-extensions/controllers/provider-openstack/cmd/gardener-extension-provider-openstack/app"
+	"github.com/gardener/gardener-extensions/pkg/controller"
+	controllercmd "github.com/gardener/gardener-extensions/pkg/controller/cmd"
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// AllName is the name of the controller manager running a selection of all extensions.
+const AllName = "extensions-all"
+
+// NewSwitchOptions creates new SwitchOptions with a Switch for every extension under this repository.
+func NewSwitchOptions() *controllercmd.SwitchOptions {
+	return controllercmd.NewSwitchOptions(
+		controllercmd.Switch{Name: coreos.Name, Option: coreos.NewOptions()},
+		controllercmd.Switch{Name: coreosalicloud.Name, Option: coreosalicloud.NewOptions()},
+		controllercmd.Switch{Name: provideraws.Name, Option: provideraws.NewOptions()},
+		controllercmd.Switch{Name: providerazure.Name, Option: providerazure.NewOptions()},
+		controllercmd.Switch{Name: providergcp.Name, Option: providergcp.NewOptions()},
+		controllercmd.Switch{Name: provideropenstack.Name, Option: provideropenstack.NewOptions()},
+		controllercmd.Switch{Name: provideralicloud.Name, Option: provideralicloud.NewOptions()},
+		controllercmd.Switch{Name: providerlocal.Name, Option: providerlocal.NewOptions()},
+	)
+}
+
+// NewAllCommand creates a new command that runs the enabled extensions in a single controller manager.
+// The extensions share the caches, the leader election and the REST configuration of the manager.
+func NewAllCommand(ctx context.Context) *cobra.Command {
+	var (
+		logOpts  = &controllercmd.LogOptions{}
+		restOpts = &controllercmd.RESTOptions{}
+		mgrOpts  = &controllercmd.ManagerOptions{
+			LeaderElection:          true,
+			LeaderElectionID:        controllercmd.LeaderElectionNameID(AllName),
+			LeaderElectionNamespace: os.Getenv("LEADER_ELECTION_NAMESPACE"),
+		}
+
+		switchOpts = NewSwitchOptions()
+
+		tracingOpts = &tracing.Options{ServiceName: AllName}
+
+		aggOption = controllercmd.NewOptionAggregator(logOpts, restOpts, mgrOpts, switchOpts, tracingOpts)
+	)
+
+	cmd := &cobra.Command{
+		Use:   "all",
+		Short: "Runs the enabled extensions in a single controller manager",
+
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := aggOption.Complete(); err != nil {
+				controllercmd.LogErrAndExit(err, "Error completing options")
+			}
+			tracingOpts.Completed().Apply()
+
+			mgr, err := manager.New(restOpts.Completed().Config, mgrOpts.Completed().Options())
+			if err != nil {
+				controllercmd.LogErrAndExit(err, "Could not instantiate manager")
+			}
+
+			if err := controller.AddToScheme(mgr.GetScheme()); err != nil {
+				controllercmd.LogErrAndExit(err, "Could not update manager scheme")
+			}
+
+			if err := switchOpts.Completed().AddToManager(mgr); err != nil {
+				controllercmd.LogErrAndExit(err, "Could not add controllers to manager")
+			}
+
+			if err := mgr.Start(ctx.Done()); err != nil {
+				controllercmd.LogErrAndExit(err, "Error running manager")
+			}
+
+			if err := tracing.GetTracer().Shutdown(context.Background()); err != nil {
+				controllercmd.LogErrAndExit(err, "Error shutting down tracer")
+			}
+		},
+	}
+
+	aggOption.AddFlags(cmd.Flags())
+
+	return cmd
+}
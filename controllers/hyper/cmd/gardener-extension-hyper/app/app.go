@@ -29,6 +29,7 @@ func NewHyperCommand(ctx context.Context) *cobra.Command {
 		provideropenstack.NewControllerManagerCommand(ctx),
 		provideralicloud.NewControllerManagerCommand(ctx),
 		providerlocal.NewControllerManagerCommand(ctx),
+		NewAllCommand(ctx),
 	)
 
 	return cmd
@@ -12,7 +12,9 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:generate ../../../../hack/generate-controller-registration.sh os-coreos-alicloud . ../../example/controller-registration.yaml OperatingSystemConfig:coreos-alicloud
+// The generator is run from the extension root because the packr box of the cloud-init templates would
+// otherwise resolve to the chart's own templates directory.
+//go:generate sh -c "cd ../.. && go run ./cmd/gardener-extension-os-coreos-alicloud registration --chart-dir charts/os-coreos-alicloud --version-file ../../VERSION --output example/controller-registration.yaml"
 
 // Package chart enables go:generate support for generating the correct controller registration.
 package chart
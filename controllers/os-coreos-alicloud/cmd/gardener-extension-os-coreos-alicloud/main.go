@@ -19,11 +19,9 @@ import (
 	"github.com/gardener/gardener-extensions/controllers/os-coreos-alicloud/cmd/gardener-extension-os-coreos-alicloud/app"
 	"github.com/gardener/gardener-extensions/pkg/controller"
 	"os"
-	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
 func main() {
-	log.SetLogger(log.ZapLogger(false))
 	cmd := app.NewControllerCommand(controller.SetupSignalHandlerContext())
 
 	if err := cmd.Execute(); err != nil {
@@ -15,8 +15,11 @@
 package coreos
 
 import (
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	"github.com/gardener/gardener-extensions/pkg/controller/operatingsystemconfig"
 
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -27,8 +30,19 @@ const Type = "coreos-alicloud"
 var (
 	// DefaultAddOptions are the default controller.Options for AddToManager.
 	DefaultAddOptions = AddOptions{}
+
+	registry extensionscontroller.Registry
+
+	// AddToManager adds a controller with the default Options.
+	AddToManager = registry.AddToManager
+	// ControllerResources returns the kinds and types of the resources handled by the controller of AddToManager.
+	ControllerResources = registry.ControllerResources
 )
 
+func init() {
+	registry.Register(extensionsv1alpha1.OperatingSystemConfigResource, Type, addToManager)
+}
+
 // AddOptions are the options for adding the controller to the manager.
 type AddOptions struct {
 	// Controller are the controller related options.
@@ -45,7 +59,6 @@ func AddToManagerWithOptions(mgr manager.Manager, opts AddOptions) error {
 	})
 }
 
-// AddToManager adds a controller with the default Options.
-func AddToManager(mgr manager.Manager) error {
+func addToManager(mgr manager.Manager) error {
 	return AddToManagerWithOptions(mgr, DefaultAddOptions)
 }
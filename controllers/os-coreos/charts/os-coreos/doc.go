@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:generate ../../../../hack/generate-controller-registration.sh os-coreos . ../../example/controller-registration.yaml OperatingSystemConfig:coreos
+//go:generate go run ../../cmd/gardener-extension-os-coreos registration --chart-dir . --version-file ../../../../VERSION --output ../../example/controller-registration.yaml
 
 // Package chart enables go:generate support for generating the correct controller registration.
 package chart
@@ -19,7 +19,10 @@ import (
 	"github.com/gardener/gardener-extensions/controllers/os-coreos/pkg/coreos"
 	"github.com/gardener/gardener-extensions/pkg/controller"
 	controllercmd "github.com/gardener/gardener-extensions/pkg/controller/cmd"
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -27,20 +30,56 @@ import (
 // Name is the name of the CoreOS controller.
 const Name = "os-coreos"
 
+// Options are the command line options of the CoreOS controller.
+type Options struct {
+	ctrlOpts *controllercmd.ControllerOptions
+}
+
+// NewOptions creates new Options for the CoreOS controller.
+func NewOptions() *Options {
+	return &Options{
+		ctrlOpts: &controllercmd.ControllerOptions{
+			MaxConcurrentReconciles: 5,
+		},
+	}
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	o.ctrlOpts.AddFlags(fs)
+}
+
+// Complete implements Completer.Complete.
+func (o *Options) Complete() error {
+	return o.ctrlOpts.Complete()
+}
+
+// AddToManager implements controllercmd.ExtensionOption.AddToManager.
+func (o *Options) AddToManager(mgr manager.Manager) error {
+	o.ctrlOpts.Completed().Apply(&coreos.DefaultAddOptions.Controller)
+	return coreos.AddToManager(mgr)
+}
+
+// ControllerResources implements controllercmd.ExtensionOption.ControllerResources.
+func (o *Options) ControllerResources() []gardencorev1alpha1.ControllerResource {
+	return coreos.ControllerResources()
+}
+
 // NewControllerCommand creates a new CoreOS controller command.
 func NewControllerCommand(ctx context.Context) *cobra.Command {
 	var (
+		logOpts  = &controllercmd.LogOptions{}
 		restOpts = &controllercmd.RESTOptions{}
 		mgrOpts  = &controllercmd.ManagerOptions{
 			LeaderElection:          true,
 			LeaderElectionID:        controllercmd.LeaderElectionNameID(Name),
 			LeaderElectionNamespace: os.Getenv("LEADER_ELECTION_NAMESPACE"),
 		}
-		ctrlOpts = &controllercmd.ControllerOptions{
-			MaxConcurrentReconciles: 5,
-		}
+		coreosOpts = NewOptions()
+
+		tracingOpts = &tracing.Options{ServiceName: Name}
 
-		aggOption = controllercmd.NewOptionAggregator(restOpts, mgrOpts, ctrlOpts)
+		aggOption = controllercmd.NewOptionAggregator(logOpts, restOpts, mgrOpts, coreosOpts, tracingOpts)
 	)
 
 	cmd := &cobra.Command{
@@ -50,6 +89,7 @@ func NewControllerCommand(ctx context.Context) *cobra.Command {
 			if err := aggOption.Complete(); err != nil {
 				controllercmd.LogErrAndExit(err, "Error completing options")
 			}
+			tracingOpts.Completed().Apply()
 
 			mgr, err := manager.New(restOpts.Completed().Config, mgrOpts.Completed().Options())
 			if err != nil {
@@ -60,19 +100,22 @@ func NewControllerCommand(ctx context.Context) *cobra.Command {
 				controllercmd.LogErrAndExit(err, "Could not update manager scheme")
 			}
 
-			ctrlOpts.Completed().Apply(&coreos.DefaultAddOptions.Controller)
-
-			if err := coreos.AddToManager(mgr); err != nil {
+			if err := coreosOpts.AddToManager(mgr); err != nil {
 				controllercmd.LogErrAndExit(err, "Could not add controller to manager")
 			}
 
 			if err := mgr.Start(ctx.Done()); err != nil {
 				controllercmd.LogErrAndExit(err, "Error running manager")
 			}
+
+			if err := tracing.GetTracer().Shutdown(context.Background()); err != nil {
+				controllercmd.LogErrAndExit(err, "Error shutting down tracer")
+			}
 		},
 	}
 
 	aggOption.AddFlags(cmd.Flags())
+	cmd.AddCommand(controllercmd.NewRegistrationCommand(Name, coreosOpts.ControllerResources()))
 
 	return cmd
 }
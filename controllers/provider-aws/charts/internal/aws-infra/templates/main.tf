@@ -1,7 +1,26 @@
 provider "aws" {
   access_key = "${var.ACCESS_KEY_ID}"
   secret_key = "${var.SECRET_ACCESS_KEY}"
+  token      = "${var.SESSION_TOKEN}"
   region     = "{{ required "aws.region is required" .Values.aws.region }}"
+  {{- if .Values.aws.assumeRole }}
+
+  assume_role {
+    role_arn     = "{{ required "aws.assumeRole.roleARN is required" .Values.aws.assumeRole.roleARN }}"
+    session_name = "{{ .Values.aws.assumeRole.sessionName }}"
+    {{- if .Values.aws.assumeRole.externalID }}
+    external_id  = "{{ .Values.aws.assumeRole.externalID }}"
+    {{- end }}
+  }
+  {{- end }}
+  {{- if .Values.aws.endpoints }}
+
+  endpoints {
+    {{- range $service, $endpoint := .Values.aws.endpoints }}
+    {{ $service }} = "{{ $endpoint }}"
+    {{- end }}
+  }
+  {{- end }}
 }
 
 //=====================================================================
@@ -36,6 +55,7 @@ resource "aws_internet_gateway" "igw" {
 }
 {{- end}}
 
+{{ if .Values.create.subnets -}}
 resource "aws_route_table" "routetable_main" {
   vpc_id = "{{ required "vpc.id is required" .Values.vpc.id }}"
 
@@ -47,6 +67,7 @@ resource "aws_route" "public" {
   destination_cidr_block = "0.0.0.0/0"
   gateway_id             = "{{ required "vpc.internetGatewayID is required" .Values.vpc.internetGatewayID }}"
 }
+{{- end}}
 
 resource "aws_security_group" "bastions" {
   name        = "{{ required "clusterName is required" .Values.clusterName }}-bastions"
@@ -126,8 +147,32 @@ resource "aws_security_group_rule" "nodes_egress_all" {
   cidr_blocks       = ["0.0.0.0/0"]
   security_group_id = "${aws_security_group.nodes.id}"
 }
+{{ range $index, $rule := .Values.ingressRules }}
+{{- if $rule.cidrs }}
+resource "aws_security_group_rule" "nodes_custom_{{ $index }}" {
+  type              = "ingress"
+  from_port         = {{ $rule.fromPort }}
+  to_port           = {{ $rule.toPort }}
+  protocol          = "{{ required "ingressRule.protocol is required" $rule.protocol }}"
+  cidr_blocks       = [{{ range $i, $cidr := $rule.cidrs }}{{ if $i }}, {{ end }}"{{ $cidr }}"{{ end }}]
+  security_group_id = "${aws_security_group.nodes.id}"
+}
+{{- end }}
+{{- range $groupIndex, $groupID := $rule.securityGroupIDs }}
+
+resource "aws_security_group_rule" "nodes_custom_{{ $index }}_sg{{ $groupIndex }}" {
+  type                     = "ingress"
+  from_port                = {{ $rule.fromPort }}
+  to_port                  = {{ $rule.toPort }}
+  protocol                 = "{{ required "ingressRule.protocol is required" $rule.protocol }}"
+  security_group_id        = "${aws_security_group.nodes.id}"
+  source_security_group_id = "{{ $groupID }}"
+}
+{{- end }}
+{{ end }}
 
 {{ range $index, $zone := .Values.zones }}
+{{- if $.Values.create.subnets }}
 resource "aws_subnet" "nodes_z{{ $index }}" {
   vpc_id            = "{{ required "vpc.id is required" $.Values.vpc.id }}"
   cidr_block        = "{{ required "zone.worker is required" $zone.worker }}"
@@ -135,11 +180,13 @@ resource "aws_subnet" "nodes_z{{ $index }}" {
 
 {{ include "aws-infra.tags-with-suffix" (set $.Values "suffix" (print "nodes-z" $index)) }}
 }
+{{- end }}
 
 output "{{ $.Values.outputKeys.subnetsNodesPrefix }}{{ $index }}" {
-  value = "${aws_subnet.nodes_z{{ $index }}.id}"
+  value = "{{ if $.Values.create.subnets }}${aws_subnet.nodes_z{{ $index }}.id}{{ else }}{{ required "zone.workerSubnetID is required" $zone.workerSubnetID }}{{ end }}"
 }
 
+{{- if $.Values.create.subnets }}
 resource "aws_subnet" "private_utility_z{{ $index }}" {
   vpc_id            = "{{ required "vpc.id is required" $.Values.vpc.id }}"
   cidr_block        = "{{ required "zone.internal is required" $zone.internal }}"
@@ -149,8 +196,16 @@ resource "aws_subnet" "private_utility_z{{ $index }}" {
     Name = "{{ required "clusterName is required" $.Values.clusterName }}-private-utility-z{{ $index }}"
     "kubernetes.io/cluster/{{ required "clusterName is required" $.Values.clusterName }}"  = "1"
     "kubernetes.io/role/internal-elb" = "use"
+{{- range $key, $value := $.Values.tags }}
+    "{{ $key }}" = "{{ $value }}"
+{{- end }}
   }
 }
+{{- end }}
+
+output "{{ $.Values.outputKeys.subnetsPrivatePrefix }}{{ $index }}" {
+  value = "{{ if $.Values.create.subnets }}${aws_subnet.private_utility_z{{ $index }}.id}{{ else }}{{ required "zone.internalSubnetID is required" $zone.internalSubnetID }}{{ end }}"
+}
 
 resource "aws_security_group_rule" "nodes_tcp_internal_z{{ $index }}" {
   type              = "ingress"
@@ -170,6 +225,7 @@ resource "aws_security_group_rule" "nodes_udp_internal_z{{ $index }}" {
   security_group_id = "${aws_security_group.nodes.id}"
 }
 
+{{- if $.Values.create.subnets }}
 resource "aws_subnet" "public_utility_z{{ $index }}" {
   vpc_id            = "{{ required "vpc.id is required" $.Values.vpc.id }}"
   cidr_block        = "{{ required "zone.public is required" $zone.public }}"
@@ -179,11 +235,15 @@ resource "aws_subnet" "public_utility_z{{ $index }}" {
     Name = "{{ required "clusterName is required" $.Values.clusterName }}-public-utility-z{{ $index }}"
     "kubernetes.io/cluster/{{ required "clusterName is required" $.Values.clusterName }}"  = "1"
     "kubernetes.io/role/elb" = "use"
+{{- range $key, $value := $.Values.tags }}
+    "{{ $key }}" = "{{ $value }}"
+{{- end }}
   }
 }
+{{- end }}
 
 output "{{ $.Values.outputKeys.subnetsPublicPrefix }}{{ $index }}" {
-  value = "${aws_subnet.public_utility_z{{ $index }}.id}"
+  value = "{{ if $.Values.create.subnets }}${aws_subnet.public_utility_z{{ $index }}.id}{{ else }}{{ required "zone.publicSubnetID is required" $zone.publicSubnetID }}{{ end }}"
 }
 
 resource "aws_security_group_rule" "nodes_tcp_public_z{{ $index }}" {
@@ -204,36 +264,73 @@ resource "aws_security_group_rule" "nodes_udp_public_z{{ $index }}" {
   security_group_id = "${aws_security_group.nodes.id}"
 }
 
+{{- if and $.Values.create.subnets (or (not $.Values.natGateway.single) (eq $index 0)) }}
+{{- if not $zone.elasticIPAllocationID }}
 resource "aws_eip" "eip_natgw_z{{ $index }}" {
   vpc = true
 
   tags {
     Name = "{{ required "clusterName is required" $.Values.clusterName }}-eip-natgw-z{{ $index }}"
     "kubernetes.io/cluster/{{ required "clusterName is required" $.Values.clusterName }}"  = "1"
+{{- range $key, $value := $.Values.tags }}
+    "{{ $key }}" = "{{ $value }}"
+{{- end }}
   }
 }
+{{- end }}
 
 resource "aws_nat_gateway" "natgw_z{{ $index }}" {
-  allocation_id = "${aws_eip.eip_natgw_z{{ $index }}.id}"
+  allocation_id = "{{ if $zone.elasticIPAllocationID }}{{ $zone.elasticIPAllocationID }}{{ else }}${aws_eip.eip_natgw_z{{ $index }}.id}{{ end }}"
   subnet_id     = "${aws_subnet.public_utility_z{{ $index }}.id}"
 
   tags {
     Name = "{{ required "clusterName is required" $.Values.clusterName }}-natgw-z{{ $index }}"
     "kubernetes.io/cluster/{{ required "clusterName is required" $.Values.clusterName }}"  = "1"
+{{- range $key, $value := $.Values.tags }}
+    "{{ $key }}" = "{{ $value }}"
+{{- end }}
   }
 }
 
+output "{{ $.Values.outputKeys.natGatewayIDPrefix }}{{ $index }}" {
+  value = "${aws_nat_gateway.natgw_z{{ $index }}.id}"
+}
+
+output "{{ $.Values.outputKeys.natGatewayPublicIPPrefix }}{{ $index }}" {
+  value = "${aws_nat_gateway.natgw_z{{ $index }}.public_ip}"
+}
+
+output "{{ $.Values.outputKeys.natGatewayEIPAllocationIDPrefix }}{{ $index }}" {
+  value = "${aws_nat_gateway.natgw_z{{ $index }}.allocation_id}"
+}
+{{- end }}
+{{- if $.Values.create.subnets }}
+
 resource "aws_route_table" "routetable_private_utility_z{{ $index }}" {
   vpc_id = "{{ required "vpc.id is required" $.Values.vpc.id }}"
 
 {{ include "aws-infra.tags-with-suffix" (set $.Values "suffix" (print "private-" $zone.name)) }}
 }
 
+output "{{ $.Values.outputKeys.routeTablesPrivatePrefix }}{{ $index }}" {
+  value = "${aws_route_table.routetable_private_utility_z{{ $index }}.id}"
+}
+
 resource "aws_route" "private_utility_z{{ $index }}_nat" {
   route_table_id         = "${aws_route_table.routetable_private_utility_z{{ $index }}.id}"
   destination_cidr_block = "0.0.0.0/0"
-  nat_gateway_id         = "${aws_nat_gateway.natgw_z{{ $index }}.id}"
+  nat_gateway_id         = "${aws_nat_gateway.natgw_z{{ if $.Values.natGateway.single }}0{{ else }}{{ $index }}{{ end }}.id}"
+}
+{{- range $peering := $.Values.peerings }}
+{{- range $cidrIndex, $cidr := $peering.cidrs }}
+
+resource "aws_route" "private_utility_z{{ $index }}_peering_{{ $peering.name }}_{{ $cidrIndex }}" {
+  route_table_id            = "${aws_route_table.routetable_private_utility_z{{ $index }}.id}"
+  destination_cidr_block    = "{{ $cidr }}"
+  vpc_peering_connection_id = "${aws_vpc_peering_connection.{{ $peering.name }}.id}"
 }
+{{- end }}
+{{- end }}
 
 resource "aws_route_table_association" "routetable_private_utility_z{{ $index }}_association_private_utility_z{{ $index }}" {
   subnet_id      = "${aws_subnet.private_utility_z{{ $index }}.id}"
@@ -249,8 +346,92 @@ resource "aws_route_table_association" "routetable_private_utility_z{{ $index }}
   subnet_id      = "${aws_subnet.nodes_z{{ $index }}.id}"
   route_table_id = "${aws_route_table.routetable_private_utility_z{{ $index }}.id}"
 }
+{{- end }}
 {{end}}
 
+//=====================================================================
+//= VPC Endpoints
+//=====================================================================
+{{ range $endpoint := .Values.vpc.endpoints }}
+resource "aws_vpc_endpoint" "{{ $endpoint.name }}" {
+  vpc_id            = "{{ required "vpc.id is required" $.Values.vpc.id }}"
+  service_name      = "{{ required "endpoint.serviceName is required" $endpoint.serviceName }}"
+  vpc_endpoint_type = "{{ required "endpoint.type is required" $endpoint.type }}"
+{{- if eq $endpoint.type "Gateway" }}
+  route_table_ids   = [{{ range $index, $zone := $.Values.zones }}{{ if $index }}, {{ end }}"${aws_route_table.routetable_private_utility_z{{ $index }}.id}"{{ end }}]
+{{- else }}
+  subnet_ids          = [{{ range $index, $zone := $.Values.zones }}{{ if $index }}, {{ end }}"{{ if $.Values.create.subnets }}${aws_subnet.nodes_z{{ $index }}.id}{{ else }}{{ $zone.workerSubnetID }}{{ end }}"{{ end }}]
+  security_group_ids  = ["${aws_security_group.nodes.id}"]
+  private_dns_enabled = true
+{{- end }}
+
+{{ include "aws-infra.tags-with-suffix" (set $.Values "suffix" (print "vpce-" $endpoint.service)) | indent 2 }}
+}
+
+output "{{ $.Values.outputKeys.vpcEndpointPrefix }}{{ $endpoint.name }}" {
+  value = "${aws_vpc_endpoint.{{ $endpoint.name }}.id}"
+}
+{{ end }}
+
+//=====================================================================
+//= VPC Peerings
+//=====================================================================
+{{ range $peering := .Values.peerings }}
+resource "aws_vpc_peering_connection" "{{ $peering.name }}" {
+  vpc_id        = "{{ required "vpc.id is required" $.Values.vpc.id }}"
+  peer_vpc_id   = "{{ required "peering.vpcID is required" $peering.vpcID }}"
+{{- if $peering.ownerID }}
+  peer_owner_id = "{{ $peering.ownerID }}"
+{{- end }}
+{{- if $peering.region }}
+  peer_region   = "{{ $peering.region }}"
+{{- else if $peering.accept }}
+  auto_accept   = true
+{{- end }}
+
+{{ include "aws-infra.tags-with-suffix" (set $.Values "suffix" (print "peering-" $peering.name)) | indent 2 }}
+}
+{{- if and $peering.accept $peering.region }}
+
+// Peering connections to other regions have to be accepted in the region of the other VPC.
+provider "aws" {
+  alias      = "peering_{{ $peering.name }}"
+  access_key = "${var.ACCESS_KEY_ID}"
+  secret_key = "${var.SECRET_ACCESS_KEY}"
+  token      = "${var.SESSION_TOKEN}"
+  region     = "{{ $peering.region }}"
+  {{- if $.Values.aws.assumeRole }}
+
+  assume_role {
+    role_arn     = "{{ required "aws.assumeRole.roleARN is required" $.Values.aws.assumeRole.roleARN }}"
+    session_name = "{{ $.Values.aws.assumeRole.sessionName }}"
+    {{- if $.Values.aws.assumeRole.externalID }}
+    external_id  = "{{ $.Values.aws.assumeRole.externalID }}"
+    {{- end }}
+  }
+  {{- end }}
+  {{- if $.Values.aws.endpoints }}
+
+  endpoints {
+    {{- range $service, $endpoint := $.Values.aws.endpoints }}
+    {{ $service }} = "{{ $endpoint }}"
+    {{- end }}
+  }
+  {{- end }}
+}
+
+resource "aws_vpc_peering_connection_accepter" "{{ $peering.name }}" {
+  provider                  = "aws.peering_{{ $peering.name }}"
+  vpc_peering_connection_id = "${aws_vpc_peering_connection.{{ $peering.name }}.id}"
+  auto_accept               = true
+}
+{{- end }}
+
+output "{{ $.Values.outputKeys.vpcPeeringConnectionPrefix }}{{ $peering.name }}" {
+  value = "${aws_vpc_peering_connection.{{ $peering.name }}.id}"
+}
+{{ end }}
+
 //=====================================================================
 //= IAM instance profiles
 //=====================================================================
@@ -260,18 +441,7 @@ resource "aws_iam_role" "bastions" {
   path = "/"
 
   assume_role_policy = <<EOF
-{
-  "Version": "2012-10-17",
-  "Statement": [
-    {
-      "Effect": "Allow",
-      "Principal": {
-        "Service": "ec2.amazonaws.com"
-      },
-      "Action": "sts:AssumeRole"
-    }
-  ]
-}
+{{ required "iam.assumeRolePolicy is required" .Values.iam.assumeRolePolicy | trim }}
 EOF
 }
 
@@ -285,20 +455,7 @@ resource "aws_iam_role_policy" "bastions" {
   role = "${aws_iam_role.bastions.id}"
 
   policy = <<EOF
-{
-  "Version": "2012-10-17",
-  "Statement": [
-    {
-      "Effect": "Allow",
-      "Action": [
-        "ec2:DescribeRegions"
-      ],
-      "Resource": [
-        "*"
-      ]
-    }
-  ]
-}
+{{ required "iam.bastionsPolicy is required" .Values.iam.bastionsPolicy | trim }}
 EOF
 }
 
@@ -307,18 +464,7 @@ resource "aws_iam_role" "nodes" {
   path = "/"
 
   assume_role_policy = <<EOF
-{
-  "Version": "2012-10-17",
-  "Statement": [
-    {
-      "Effect": "Allow",
-      "Principal": {
-        "Service": "ec2.amazonaws.com"
-      },
-      "Action": "sts:AssumeRole"
-    }
-  ]
-}
+{{ required "iam.assumeRolePolicy is required" .Values.iam.assumeRolePolicy | trim }}
 EOF
 }
 
@@ -332,35 +478,7 @@ resource "aws_iam_role_policy" "nodes" {
   role = "${aws_iam_role.nodes.id}"
 
   policy = <<EOF
-{
-  "Version": "2012-10-17",
-  "Statement": [
-    {
-      "Effect": "Allow",
-      "Action": [
-        "ec2:DescribeInstances"
-      ],
-      "Resource": [
-        "*"
-      ]
-    },
-    {
-      "Effect": "Allow",
-      "Action": [
-        "ecr:GetAuthorizationToken",
-        "ecr:BatchCheckLayerAvailability",
-        "ecr:GetDownloadUrlForLayer",
-        "ecr:GetRepositoryPolicy",
-        "ecr:DescribeRepositories",
-        "ecr:ListImages",
-        "ecr:BatchGetImage"
-      ],
-      "Resource": [
-        "*"
-      ]
-    }
-  ]
-}
+{{ required "iam.nodesPolicy is required" .Values.iam.nodesPolicy | trim }}
 EOF
 }
 
@@ -381,10 +499,24 @@ output "{{ .Values.outputKeys.vpcIdKey }}" {
   value = "{{ required "vpc.id is required" .Values.vpc.id }}"
 }
 
+{{- if .Values.create.subnets }}
+output "{{ .Values.outputKeys.internetGatewayID }}" {
+  value = "{{ required "vpc.internetGatewayID is required" .Values.vpc.internetGatewayID }}"
+}
+
+output "{{ .Values.outputKeys.routeTableMain }}" {
+  value = "${aws_route_table.routetable_main.id}"
+}
+{{- end }}
+
 output "{{ .Values.outputKeys.iamInstanceProfileNodes }}" {
   value = "${aws_iam_instance_profile.nodes.name}"
 }
 
+output "{{ .Values.outputKeys.iamInstanceProfileBastions }}" {
+  value = "${aws_iam_instance_profile.bastions.name}"
+}
+
 output "{{ .Values.outputKeys.sshKeyName }}" {
   value = "${aws_key_pair.kubernetes.key_name}"
 }
@@ -393,21 +525,35 @@ output "{{ .Values.outputKeys.securityGroupsNodes }}" {
   value = "${aws_security_group.nodes.id}"
 }
 
+output "{{ .Values.outputKeys.securityGroupsBastions }}" {
+  value = "${aws_security_group.bastions.id}"
+}
+
 output "{{ .Values.outputKeys.nodesRole }}" {
   value = "${aws_iam_role.nodes.arn}"
 }
 
+output "{{ .Values.outputKeys.bastionsRole }}" {
+  value = "${aws_iam_role.bastions.arn}"
+}
+
 
 {{- define "aws-infra.common-tags" -}}
 tags {
   Name = "{{ required "clusterName is required" .clusterName }}"
   "kubernetes.io/cluster/{{ required "clusterName is required" .clusterName }}" = "1"
+{{- range $key, $value := .tags }}
+  "{{ $key }}" = "{{ $value }}"
+{{- end }}
 }
 {{- end -}}
 {{- define "aws-infra.tags-with-suffix" -}}
 tags {
   Name = "{{ required "clusterName is required" .clusterName }}-{{ required "suffix is required" .suffix }}"
   "kubernetes.io/cluster/{{ required "clusterName is required" .clusterName }}" = "1"
+{{- range $key, $value := .tags }}
+  "{{ $key }}" = "{{ $value }}"
+{{- end }}
 }
 {{- end -}}
 
@@ -6,4 +6,10 @@ variable "ACCESS_KEY_ID" {
 variable "SECRET_ACCESS_KEY" {
   description = "AWS Secret Access Key of technical user"
   type        = "string"
+}
+
+variable "SESSION_TOKEN" {
+  description = "AWS session token of the temporary credentials of technical user"
+  type        = "string"
+  default     = ""
 }
\ No newline at end of file
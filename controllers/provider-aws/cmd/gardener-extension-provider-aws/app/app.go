@@ -17,17 +17,24 @@ package app
 import (
 	"context"
 	"fmt"
-	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws/install"
-	awscontroller "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/controller"
 	"os"
 
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws/install"
+	awsclient "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+	awscontroller "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/controller"
 	awscontrolplane "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/controller/controlplane"
 	awsinfrastructure "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/controller/infrastructure"
+	awsworker "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/controller/worker"
 	"github.com/gardener/gardener-extensions/pkg/controller"
 	controllercmd "github.com/gardener/gardener-extensions/pkg/controller/cmd"
 	"github.com/gardener/gardener-extensions/pkg/controller/infrastructure"
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -35,9 +42,98 @@ import (
 // Name is the name of the AWS provider controller.
 const Name = "provider-aws"
 
+// Options are the command line options of the AWS provider controllers.
+type Options struct {
+	infraCtrlOpts        *controllercmd.ControllerOptions
+	infraReconcileOpts   *infrastructure.ReconcilerOptions
+	volumeCleanupOpts    *awsinfrastructure.VolumeCleanupOptions
+	tagOpts              *awsinfrastructure.TagOptions
+	quotaOpts            *awsinfrastructure.QuotaOptions
+	endpointOpts         *awsclient.EndpointOptions
+	webIdentityOpts      *awsclient.WebIdentityOptions
+	controlPlaneCtrlOpts *controllercmd.ControllerOptions
+	workerCtrlOpts       *controllercmd.ControllerOptions
+
+	aggOption controllercmd.OptionAggregator
+}
+
+// NewOptions creates new Options for the AWS provider controllers.
+func NewOptions() *Options {
+	o := &Options{
+		infraCtrlOpts: &controllercmd.ControllerOptions{
+			MaxConcurrentReconciles: 5,
+		},
+		infraReconcileOpts: &infrastructure.ReconcilerOptions{
+			IgnoreOperationAnnotation: true,
+		},
+		volumeCleanupOpts: &awsinfrastructure.VolumeCleanupOptions{},
+		tagOpts:           &awsinfrastructure.TagOptions{},
+		quotaOpts: &awsinfrastructure.QuotaOptions{
+			VPCQuota: awsinfrastructure.DefaultVPCQuota,
+		},
+		endpointOpts:    &awsclient.EndpointOptions{},
+		webIdentityOpts: &awsclient.WebIdentityOptions{},
+		controlPlaneCtrlOpts: &controllercmd.ControllerOptions{
+			MaxConcurrentReconciles: 5,
+		},
+		workerCtrlOpts: &controllercmd.ControllerOptions{
+			MaxConcurrentReconciles: 5,
+		},
+	}
+
+	unprefixedInfraOpts := controllercmd.NewOptionAggregator(o.infraCtrlOpts, o.infraReconcileOpts, o.volumeCleanupOpts, o.tagOpts, o.quotaOpts)
+	o.aggOption = controllercmd.NewOptionAggregator(
+		controllercmd.PrefixOption(awsinfrastructure.FlagPrefix, &unprefixedInfraOpts),
+		controllercmd.PrefixOption("controlplane-", o.controlPlaneCtrlOpts),
+		controllercmd.PrefixOption("worker-", o.workerCtrlOpts),
+		o.endpointOpts,
+		o.webIdentityOpts,
+	)
+	return o
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	o.aggOption.AddFlags(fs)
+}
+
+// Complete implements Completer.Complete.
+func (o *Options) Complete() error {
+	return o.aggOption.Complete()
+}
+
+// AddToManager implements controllercmd.ExtensionOption.AddToManager.
+func (o *Options) AddToManager(mgr manager.Manager) error {
+	if err := install.AddToScheme(mgr.GetScheme()); err != nil {
+		return fmt.Errorf("could not update manager scheme: %v", err)
+	}
+	if err := machinev1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return fmt.Errorf("could not update manager scheme: %v", err)
+	}
+
+	o.infraCtrlOpts.Completed().Apply(&awsinfrastructure.DefaultAddOptions.Controller)
+	o.infraReconcileOpts.Completed().Apply(&awsinfrastructure.DefaultAddOptions.IgnoreOperationAnnotation)
+	o.volumeCleanupOpts.Completed().Apply(&awsinfrastructure.DefaultAddOptions.VolumeCleanup)
+	o.tagOpts.Completed().Apply(&awsinfrastructure.DefaultAddOptions.DefaultTags)
+	o.tagOpts.Completed().Apply(&awsworker.DefaultTags)
+	o.quotaOpts.Completed().Apply(&awsinfrastructure.DefaultAddOptions.VPCQuota)
+	o.endpointOpts.Completed().Apply(&awsinfrastructure.DefaultAddOptions.Endpoints)
+	o.webIdentityOpts.Completed().Apply(&awsinfrastructure.DefaultAddOptions.WebIdentity)
+	o.controlPlaneCtrlOpts.Completed().Apply(&awscontrolplane.Options)
+	o.workerCtrlOpts.Completed().Apply(&awsworker.Options)
+
+	return awscontroller.AddToManager(mgr)
+}
+
+// ControllerResources implements controllercmd.ExtensionOption.ControllerResources.
+func (o *Options) ControllerResources() []gardencorev1alpha1.ControllerResource {
+	return awscontroller.ControllerResources()
+}
+
 // NewControllerManagerCommand creates a new command for running a AWS provider controller.
 func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 	var (
+		logOpts  = &controllercmd.LogOptions{}
 		restOpts = &controllercmd.RESTOptions{}
 		mgrOpts  = &controllercmd.ManagerOptions{
 			LeaderElection:          true,
@@ -45,21 +141,11 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 			LeaderElectionNamespace: os.Getenv("LEADER_ELECTION_NAMESPACE"),
 		}
 
-		infraCtrlOpts = &controllercmd.ControllerOptions{
-			MaxConcurrentReconciles: 5,
-		}
-		infraReconcileOpts = &infrastructure.ReconcilerOptions{
-			IgnoreOperationAnnotation: true,
-		}
-		unprefixedInfraOpts = controllercmd.NewOptionAggregator(infraCtrlOpts, infraReconcileOpts)
-		infraOpts           = controllercmd.PrefixOption("infrastructure-", &unprefixedInfraOpts)
+		awsOpts = NewOptions()
 
-		controlPlaneCtrlOpts = &controllercmd.ControllerOptions{
-			MaxConcurrentReconciles: 5,
-		}
-		controlPlaneOpts = controllercmd.PrefixOption("controlplane-", controlPlaneCtrlOpts)
+		tracingOpts = &tracing.Options{ServiceName: Name}
 
-		aggOption = controllercmd.NewOptionAggregator(restOpts, mgrOpts, infraOpts, controlPlaneOpts)
+		aggOption = controllercmd.NewOptionAggregator(logOpts, restOpts, mgrOpts, awsOpts, tracingOpts)
 	)
 
 	cmd := &cobra.Command{
@@ -69,6 +155,7 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 			if err := aggOption.Complete(); err != nil {
 				controllercmd.LogErrAndExit(err, "Error completing options")
 			}
+			tracingOpts.Completed().Apply()
 
 			mgr, err := manager.New(restOpts.Completed().Config, mgrOpts.Completed().Options())
 			if err != nil {
@@ -79,25 +166,22 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 				controllercmd.LogErrAndExit(err, "Could not update manager scheme")
 			}
 
-			if err := install.AddToScheme(mgr.GetScheme()); err != nil {
-				controllercmd.LogErrAndExit(err, "Could not update manager scheme")
-			}
-
-			infraCtrlOpts.Completed().Apply(&awsinfrastructure.DefaultAddOptions.Controller)
-			infraReconcileOpts.Completed().Apply(&awsinfrastructure.DefaultAddOptions.IgnoreOperationAnnotation)
-			controlPlaneCtrlOpts.Completed().Apply(&awscontrolplane.Options)
-
-			if err := awscontroller.AddToManager(mgr); err != nil {
-				controllercmd.LogErrAndExit(err, "Could not add infrastructure controller to manager")
+			if err := awsOpts.AddToManager(mgr); err != nil {
+				controllercmd.LogErrAndExit(err, "Could not add controllers to manager")
 			}
 
 			if err := mgr.Start(ctx.Done()); err != nil {
 				controllercmd.LogErrAndExit(err, "Error running manager")
 			}
+
+			if err := tracing.GetTracer().Shutdown(context.Background()); err != nil {
+				controllercmd.LogErrAndExit(err, "Error shutting down tracer")
+			}
 		},
 	}
 
 	aggOption.AddFlags(cmd.Flags())
+	cmd.AddCommand(controllercmd.NewRegistrationCommand(Name, awsOpts.ControllerResources()))
 
 	return cmd
 }
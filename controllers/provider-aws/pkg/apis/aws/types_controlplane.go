@@ -16,6 +16,7 @@ package aws
 
 import (
 	gardenv1beta1 "github.com/gardener/gardener/pkg/apis/garden/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -28,9 +29,37 @@ type ControlPlaneConfig struct {
 	// CloudControllerManager contains configuration settings for the cloud-controller-manager.
 	// +optional
 	CloudControllerManager *CloudControllerManagerConfig `json:"cloudControllerManager,omitempty"`
+	// CloudProvider contains settings of the AWS cloud provider configuration used by the cloud-controller-manager.
+	// +optional
+	CloudProvider *CloudProviderConfig `json:"cloudProvider,omitempty"`
 }
 
 // CloudControllerManagerConfig contains configuration settings for the cloud-controller-manager.
 type CloudControllerManagerConfig struct {
 	gardenv1beta1.KubernetesConfig `json:",inline"`
+
+	// Verbosity is the log level of the cloud-controller-manager. Defaults to 2.
+	// +optional
+	Verbosity *int32 `json:"verbosity,omitempty"`
+	// Resources are the compute resources of the cloud-controller-manager. Defaults to requests of 100m CPU and
+	// 64Mi memory and limits of 500m CPU and 512Mi memory.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// CloudProviderConfig contains settings of the AWS cloud provider configuration.
+type CloudProviderConfig struct {
+	// ElbSecurityGroup is the id of an existing security group that is attached to all load balancers instead of
+	// a security group created per load balancer.
+	// +optional
+	ElbSecurityGroup *string `json:"elbSecurityGroup,omitempty"`
+	// RoleARN is the ARN of a role the cloud-controller-manager assumes for all AWS API calls. It is not supported
+	// yet and must not be set, because the IAM user of the cloud-controller-manager is not allowed to assume roles.
+	// +optional
+	RoleARN *string `json:"roleARN,omitempty"`
+	// KubernetesClusterTag is the value of the legacy `KubernetesCluster` tag of the AWS resources of the cluster.
+	// It is not supported yet and must not be set, because the infrastructure and the machines are always tagged with
+	// the name of the cluster.
+	// +optional
+	KubernetesClusterTag *string `json:"kubernetesClusterTag,omitempty"`
 }
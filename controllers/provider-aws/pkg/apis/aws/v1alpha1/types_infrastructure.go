@@ -28,8 +28,32 @@ type InfrastructureConfig struct {
 
 	// Networks is the AWS specific network configuration (VPC, subnets, etc.)
 	Networks Networks `json:"networks"`
+	// VolumeCleanup configures the cleanup of EBS volumes and snapshots left behind by the shoot when
+	// its infrastructure is deleted. If not set, the default of the controller is used.
+	// +optional
+	VolumeCleanup *VolumeCleanup `json:"volumeCleanup,omitempty"`
+	// Tags are added to all AWS resources created for the shoot. They take precedence over the default tags of the
+	// controller.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+	// Reconciler selects how the infrastructure resources are managed, either with Terraform (`Terraform`) or
+	// directly with the AWS API (`Native`). Both produce the same InfrastructureStatus. Defaults to `Terraform`. It
+	// cannot be changed once the infrastructure exists.
+	// +optional
+	Reconciler InfrastructureReconciler `json:"reconciler,omitempty"`
 }
 
+// InfrastructureReconciler is the way the infrastructure resources of a shoot are managed.
+type InfrastructureReconciler string
+
+const (
+	// InfrastructureReconcilerTerraform manages the infrastructure resources with a Terraformer pod.
+	InfrastructureReconcilerTerraform InfrastructureReconciler = "Terraform"
+	// InfrastructureReconcilerNative manages the infrastructure resources directly with the AWS API. It finds the
+	// resources it manages by their names and tags.
+	InfrastructureReconcilerNative InfrastructureReconciler = "Native"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // InfrastructureStatus contains information about created infrastructure resources.
@@ -42,6 +66,22 @@ type InfrastructureStatus struct {
 	IAM IAM `json:"iam"`
 	// VPC contains information about the created AWS VPC and some related resources.
 	VPC VPCStatus `json:"vpc"`
+	// Reconciler is the reconciler that manages the infrastructure resources. It is empty for infrastructures whose
+	// status was written before it was recorded, those are managed by Terraform.
+	// +optional
+	Reconciler InfrastructureReconciler `json:"reconciler,omitempty"`
+}
+
+// VolumeCleanup configures the cleanup of EBS volumes and snapshots tagged as owned by the shoot.
+type VolumeCleanup struct {
+	// DeleteVolumes specifies whether available EBS volumes are deleted.
+	DeleteVolumes bool `json:"deleteVolumes"`
+	// DeleteSnapshots specifies whether EBS snapshots are deleted.
+	// +optional
+	DeleteSnapshots bool `json:"deleteSnapshots,omitempty"`
+	// DryRun specifies that volumes and snapshots are only reported via events instead of being deleted.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // Networks holds information about the Kubernetes and infrastructure networks.
@@ -50,6 +90,76 @@ type Networks struct {
 	VPC VPC `json:"vpc"`
 	// Zones belonging to the same region
 	Zones []Zone `json:"zones"`
+	// NATGateway configures the NAT gateways of the zones. If not set, one NAT gateway is created per zone.
+	// +optional
+	NATGateway *NATGateway `json:"natGateway,omitempty"`
+	// IngressRules are additional ingress rules of the security group of the nodes. The native reconciler adds
+	// missing rules but does not revoke rules that are removed.
+	// +optional
+	IngressRules []IngressRule `json:"ingressRules,omitempty"`
+	// Peerings are VPC peering connections to other VPCs, e.g. of shared services. They require the subnet mode
+	// `Create`.
+	// +optional
+	Peerings []VPCPeering `json:"peerings,omitempty"`
+}
+
+// VPCPeering is a VPC peering connection from the VPC of the shoot to another VPC. The peering connection is
+// accepted if the other VPC belongs to the same AWS account, otherwise it has to be accepted by the owner of the
+// other VPC.
+type VPCPeering struct {
+	// Name is the unique name of the peering connection, it must be a DNS label.
+	Name string `json:"name"`
+	// VPCID is the id of the other VPC.
+	VPCID string `json:"vpcID"`
+	// OwnerID is the id of the AWS account of the other VPC. Defaults to the account of the shoot.
+	// +optional
+	OwnerID *string `json:"ownerID,omitempty"`
+	// Region is the region of the other VPC. Defaults to the region of the shoot.
+	// +optional
+	Region *string `json:"region,omitempty"`
+	// CIDRs are the IPv4 CIDR blocks of the other VPC that are routed through the peering connection from the
+	// subnets of the workers. They must not overlap with the VPC of the shoot.
+	CIDRs []gardencorev1alpha1.CIDR `json:"cidrs"`
+}
+
+// IngressRule is an ingress rule of the security group of the nodes. Sources 0.0.0.0/0 are rejected for sensitive
+// ports, e.g. SSH or the kubelet, unless public access is explicitly allowed.
+type IngressRule struct {
+	// Protocol is the IP protocol, one of `tcp`, `udp`, `icmp` and `-1` for all protocols.
+	Protocol string `json:"protocol"`
+	// FromPort is the start of the port range, or the ICMP type for protocol `icmp`. It must be 0 for protocol `-1`.
+	// +optional
+	FromPort int `json:"fromPort,omitempty"`
+	// ToPort is the end of the port range, or the ICMP code for protocol `icmp`. It must be 0 for protocol `-1`.
+	// +optional
+	ToPort int `json:"toPort,omitempty"`
+	// CIDRs are the IPv4 CIDR blocks of the sources.
+	// +optional
+	CIDRs []gardencorev1alpha1.CIDR `json:"cidrs,omitempty"`
+	// SecurityGroupIDs are the ids of the security groups of the sources.
+	// +optional
+	SecurityGroupIDs []string `json:"securityGroupIDs,omitempty"`
+	// AllowPublicAccess allows sources with more than 65536 public IPv4 addresses, e.g. 0.0.0.0/0, for sensitive ports.
+	// +optional
+	AllowPublicAccess bool `json:"allowPublicAccess,omitempty"`
+}
+
+// NATGatewayMode is the topology of the NAT gateways.
+type NATGatewayMode string
+
+const (
+	// NATGatewayModePerZone creates one NAT gateway per zone, every zone routes its egress traffic through its own
+	// NAT gateway.
+	NATGatewayModePerZone NATGatewayMode = "PerZone"
+	// NATGatewayModeSingle creates a single NAT gateway in the first zone that is shared by all zones.
+	NATGatewayModeSingle NATGatewayMode = "Single"
+)
+
+// NATGateway configures the NAT gateways.
+type NATGateway struct {
+	// Mode is the topology of the NAT gateways, either `PerZone` or `Single`. Defaults to `PerZone`.
+	// +optional
+	Mode NATGatewayMode `json:"mode,omitempty"`
 }
 
 // Zone describes the properties of a zone
@@ -62,6 +172,28 @@ type Zone struct {
 	Public gardencorev1alpha1.CIDR `json:"public"`
 	// Workers is the  workers  subnet range  to create (used for the VMs).
 	Workers gardencorev1alpha1.CIDR `json:"workers"`
+	// ElasticIPAllocationID is the allocation ID of a pre-allocated Elastic IP used by the NAT gateway of this zone.
+	// If not set, an Elastic IP is allocated and released together with the NAT gateway.
+	// +optional
+	ElasticIPAllocationID *string `json:"elasticIPAllocationID,omitempty"`
+	// Subnets references existing subnets of this zone by their ids if the subnet mode of the VPC is `Existing`.
+	// Subnets that are not referenced are discovered by the tag `aws.provider.extensions.gardener.cloud/purpose`. The
+	// subnet ranges of the zone are ignored in this case.
+	// +optional
+	Subnets *ZoneSubnets `json:"subnets,omitempty"`
+}
+
+// ZoneSubnets references existing subnets of a zone.
+type ZoneSubnets struct {
+	// Workers is the id of the subnet used for the VMs.
+	// +optional
+	Workers *string `json:"workers,omitempty"`
+	// Public is the id of the subnet used for bastions and public load balancers.
+	// +optional
+	Public *string `json:"public,omitempty"`
+	// Internal is the id of the subnet used for internal load balancers.
+	// +optional
+	Internal *string `json:"internal,omitempty"`
 }
 
 // EC2 contains information about the  AWS EC2 resources.
@@ -86,6 +218,50 @@ type VPC struct {
 	// gardencorev1alpha1.CIDR is the VPC gardencorev1alpha1.CIDR
 	// +optional
 	CIDR *gardencorev1alpha1.CIDR `json:"cidr,omitempty"`
+	// Endpoints is a list of VPC endpoints through which the nodes reach AWS services without leaving the VPC.
+	// +optional
+	Endpoints []VPCEndpoint `json:"endpoints,omitempty"`
+	// SubnetMode specifies whether the subnets of the zones are created (`Create`) or whether existing subnets of the
+	// VPC given by ID are used (`Existing`). Defaults to `Create`.
+	// +optional
+	SubnetMode SubnetMode `json:"subnetMode,omitempty"`
+}
+
+// SubnetMode is the mode of the subnets of a VPC.
+type SubnetMode string
+
+const (
+	// SubnetModeCreate creates the subnets, route tables and NAT gateways of the zones.
+	SubnetModeCreate SubnetMode = "Create"
+	// SubnetModeExisting uses existing subnets and the existing routing of the VPC. Only the security groups and IAM
+	// resources are created.
+	SubnetModeExisting SubnetMode = "Existing"
+)
+
+// SubnetPurposeTagKey is the key of the tag by which existing subnets are discovered. Its value is the purpose of
+// the subnet, i.e. `nodes`, `public` or `internal`.
+const SubnetPurposeTagKey = "aws.provider.extensions.gardener.cloud/purpose"
+
+// VPCEndpointType is the type of a VPC endpoint.
+type VPCEndpointType string
+
+const (
+	// VPCEndpointTypeGateway is a gateway endpoint that is added to the route tables of the worker subnets. It is
+	// only available for S3 and DynamoDB.
+	VPCEndpointTypeGateway VPCEndpointType = "Gateway"
+	// VPCEndpointTypeInterface is an interface endpoint that is placed into the worker subnets.
+	VPCEndpointTypeInterface VPCEndpointType = "Interface"
+)
+
+// VPCEndpoint is a VPC endpoint for an AWS service.
+type VPCEndpoint struct {
+	// Service is the name of the AWS service in the region of the shoot, e.g. `s3`, `ecr.api`, `ecr.dkr`, `sts` or
+	// `logs`.
+	Service string `json:"service"`
+	// Type is the type of the endpoint, either `Gateway` or `Interface`. Defaults to `Gateway` for `s3` and
+	// `dynamodb` and to `Interface` for all other services.
+	// +optional
+	Type VPCEndpointType `json:"type,omitempty"`
 }
 
 // VPCStatus contains information about a generated VPC or resources inside an existing VPC.
@@ -94,8 +270,23 @@ type VPCStatus struct {
 	ID string `json:"id"`
 	// Subnets is a list of subnets that have been created.
 	Subnets []Subnet `json:"subnets"`
+	// RouteTables is a list of route tables that have been created.
+	// +optional
+	RouteTables []RouteTable `json:"routeTables,omitempty"`
+	// InternetGatewayID is the id of the internet gateway of the VPC.
+	// +optional
+	InternetGatewayID string `json:"internetGatewayID,omitempty"`
 	// SecurityGroups is a list of security groups that have been created.
 	SecurityGroups []SecurityGroup `json:"securityGroups"`
+	// NATGateways is a list of NAT gateways that have been created.
+	// +optional
+	NATGateways []NATGatewayStatus `json:"natGateways,omitempty"`
+	// Endpoints is a list of VPC endpoints that have been created.
+	// +optional
+	Endpoints []VPCEndpointStatus `json:"endpoints,omitempty"`
+	// Peerings is a list of VPC peering connections that have been created.
+	// +optional
+	Peerings []VPCPeeringStatus `json:"peerings,omitempty"`
 }
 
 const (
@@ -105,6 +296,11 @@ const (
 	PurposePublic string = "public"
 	// PurposeInternal is a constant describing that the respective resource is used for internal load balancers.
 	PurposeInternal string = "internal"
+	// PurposeBastions is a constant describing that the respective resource is used for bastions.
+	PurposeBastions string = "bastions"
+	// PurposePrivate is a constant describing that the respective resource is used for the private subnets, i.e.
+	// the nodes and internal load balancers.
+	PurposePrivate string = "private"
 )
 
 // InstanceProfile is an AWS IAM instance profile.
@@ -133,6 +329,18 @@ type Subnet struct {
 	Zone string `json:"zone"`
 }
 
+// RouteTable is an AWS route table related to a VPC.
+type RouteTable struct {
+	// Purpose is a logical description of the route table.
+	Purpose string `json:"purpose"`
+	// ID is the route table id.
+	ID string `json:"id"`
+	// Zone is the availability zone of the subnets associated with the route table. It is empty if the route table
+	// is associated with the subnets of all zones.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+}
+
 // SecurityGroup is an AWS security group related to a VPC.
 type SecurityGroup struct {
 	// Purpose is a logical description of the security group.
@@ -140,3 +348,35 @@ type SecurityGroup struct {
 	// ID is the subnet id.
 	ID string `json:"id"`
 }
+
+// NATGatewayStatus is an AWS NAT gateway related to a VPC.
+type NATGatewayStatus struct {
+	// ID is the NAT gateway id.
+	ID string `json:"id"`
+	// Zone is the availability zone into which the NAT gateway has been created.
+	Zone string `json:"zone"`
+	// PublicIP is the egress IP address of the NAT gateway.
+	PublicIP string `json:"publicIP"`
+	// ElasticIPAllocationID is the allocation ID of the Elastic IP used by the NAT gateway.
+	ElasticIPAllocationID string `json:"elasticIPAllocationID"`
+}
+
+// VPCEndpointStatus is an AWS VPC endpoint.
+type VPCEndpointStatus struct {
+	// Service is the name of the AWS service.
+	Service string `json:"service"`
+	// Type is the type of the endpoint.
+	Type VPCEndpointType `json:"type"`
+	// ID is the VPC endpoint id.
+	ID string `json:"id"`
+}
+
+// VPCPeeringStatus is an AWS VPC peering connection.
+type VPCPeeringStatus struct {
+	// Name is the name of the peering connection.
+	Name string `json:"name"`
+	// VPCID is the id of the other VPC.
+	VPCID string `json:"vpcID"`
+	// ID is the VPC peering connection id.
+	ID string `json:"id"`
+}
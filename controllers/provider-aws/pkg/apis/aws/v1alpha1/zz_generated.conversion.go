@@ -26,6 +26,7 @@ import (
 	aws "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
 	core "github.com/gardener/gardener/pkg/apis/core"
 	corev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	v1 "k8s.io/api/core/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -47,6 +48,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*CloudProviderConfig)(nil), (*aws.CloudProviderConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_CloudProviderConfig_To_aws_CloudProviderConfig(a.(*CloudProviderConfig), b.(*aws.CloudProviderConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*aws.CloudProviderConfig)(nil), (*CloudProviderConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_aws_CloudProviderConfig_To_v1alpha1_CloudProviderConfig(a.(*aws.CloudProviderConfig), b.(*CloudProviderConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ControlPlaneConfig)(nil), (*aws.ControlPlaneConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ControlPlaneConfig_To_aws_ControlPlaneConfig(a.(*ControlPlaneConfig), b.(*aws.ControlPlaneConfig), scope)
 	}); err != nil {
@@ -97,6 +108,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*IngressRule)(nil), (*aws.IngressRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_IngressRule_To_aws_IngressRule(a.(*IngressRule), b.(*aws.IngressRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*aws.IngressRule)(nil), (*IngressRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_aws_IngressRule_To_v1alpha1_IngressRule(a.(*aws.IngressRule), b.(*IngressRule), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InstanceProfile)(nil), (*aws.InstanceProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_InstanceProfile_To_aws_InstanceProfile(a.(*InstanceProfile), b.(*aws.InstanceProfile), scope)
 	}); err != nil {
@@ -107,6 +128,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NATGateway)(nil), (*aws.NATGateway)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NATGateway_To_aws_NATGateway(a.(*NATGateway), b.(*aws.NATGateway), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*aws.NATGateway)(nil), (*NATGateway)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_aws_NATGateway_To_v1alpha1_NATGateway(a.(*aws.NATGateway), b.(*NATGateway), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NATGatewayStatus)(nil), (*aws.NATGatewayStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NATGatewayStatus_To_aws_NATGatewayStatus(a.(*NATGatewayStatus), b.(*aws.NATGatewayStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*aws.NATGatewayStatus)(nil), (*NATGatewayStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_aws_NATGatewayStatus_To_v1alpha1_NATGatewayStatus(a.(*aws.NATGatewayStatus), b.(*NATGatewayStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Networks)(nil), (*aws.Networks)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_Networks_To_aws_Networks(a.(*Networks), b.(*aws.Networks), scope)
 	}); err != nil {
@@ -127,6 +168,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RouteTable)(nil), (*aws.RouteTable)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RouteTable_To_aws_RouteTable(a.(*RouteTable), b.(*aws.RouteTable), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*aws.RouteTable)(nil), (*RouteTable)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_aws_RouteTable_To_v1alpha1_RouteTable(a.(*aws.RouteTable), b.(*RouteTable), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SecurityGroup)(nil), (*aws.SecurityGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_SecurityGroup_To_aws_SecurityGroup(a.(*SecurityGroup), b.(*aws.SecurityGroup), scope)
 	}); err != nil {
@@ -157,6 +208,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*VPCEndpoint)(nil), (*aws.VPCEndpoint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_VPCEndpoint_To_aws_VPCEndpoint(a.(*VPCEndpoint), b.(*aws.VPCEndpoint), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*aws.VPCEndpoint)(nil), (*VPCEndpoint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_aws_VPCEndpoint_To_v1alpha1_VPCEndpoint(a.(*aws.VPCEndpoint), b.(*VPCEndpoint), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VPCEndpointStatus)(nil), (*aws.VPCEndpointStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_VPCEndpointStatus_To_aws_VPCEndpointStatus(a.(*VPCEndpointStatus), b.(*aws.VPCEndpointStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*aws.VPCEndpointStatus)(nil), (*VPCEndpointStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_aws_VPCEndpointStatus_To_v1alpha1_VPCEndpointStatus(a.(*aws.VPCEndpointStatus), b.(*VPCEndpointStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VPCPeering)(nil), (*aws.VPCPeering)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_VPCPeering_To_aws_VPCPeering(a.(*VPCPeering), b.(*aws.VPCPeering), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*aws.VPCPeering)(nil), (*VPCPeering)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_aws_VPCPeering_To_v1alpha1_VPCPeering(a.(*aws.VPCPeering), b.(*VPCPeering), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VPCPeeringStatus)(nil), (*aws.VPCPeeringStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_VPCPeeringStatus_To_aws_VPCPeeringStatus(a.(*VPCPeeringStatus), b.(*aws.VPCPeeringStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*aws.VPCPeeringStatus)(nil), (*VPCPeeringStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_aws_VPCPeeringStatus_To_v1alpha1_VPCPeeringStatus(a.(*aws.VPCPeeringStatus), b.(*VPCPeeringStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*VPCStatus)(nil), (*aws.VPCStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_VPCStatus_To_aws_VPCStatus(a.(*VPCStatus), b.(*aws.VPCStatus), scope)
 	}); err != nil {
@@ -167,6 +258,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*VolumeCleanup)(nil), (*aws.VolumeCleanup)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_VolumeCleanup_To_aws_VolumeCleanup(a.(*VolumeCleanup), b.(*aws.VolumeCleanup), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*aws.VolumeCleanup)(nil), (*VolumeCleanup)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_aws_VolumeCleanup_To_v1alpha1_VolumeCleanup(a.(*aws.VolumeCleanup), b.(*VolumeCleanup), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Zone)(nil), (*aws.Zone)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_Zone_To_aws_Zone(a.(*Zone), b.(*aws.Zone), scope)
 	}); err != nil {
@@ -177,11 +278,23 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ZoneSubnets)(nil), (*aws.ZoneSubnets)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ZoneSubnets_To_aws_ZoneSubnets(a.(*ZoneSubnets), b.(*aws.ZoneSubnets), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*aws.ZoneSubnets)(nil), (*ZoneSubnets)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_aws_ZoneSubnets_To_v1alpha1_ZoneSubnets(a.(*aws.ZoneSubnets), b.(*ZoneSubnets), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
 func autoConvert_v1alpha1_CloudControllerManagerConfig_To_aws_CloudControllerManagerConfig(in *CloudControllerManagerConfig, out *aws.CloudControllerManagerConfig, s conversion.Scope) error {
 	out.KubernetesConfig = in.KubernetesConfig
+	out.Verbosity = (*int32)(unsafe.Pointer(in.Verbosity))
+	out.Resources = (*v1.ResourceRequirements)(unsafe.Pointer(in.Resources))
 	return nil
 }
 
@@ -192,6 +305,8 @@ func Convert_v1alpha1_CloudControllerManagerConfig_To_aws_CloudControllerManager
 
 func autoConvert_aws_CloudControllerManagerConfig_To_v1alpha1_CloudControllerManagerConfig(in *aws.CloudControllerManagerConfig, out *CloudControllerManagerConfig, s conversion.Scope) error {
 	out.KubernetesConfig = in.KubernetesConfig
+	out.Verbosity = (*int32)(unsafe.Pointer(in.Verbosity))
+	out.Resources = (*v1.ResourceRequirements)(unsafe.Pointer(in.Resources))
 	return nil
 }
 
@@ -200,8 +315,33 @@ func Convert_aws_CloudControllerManagerConfig_To_v1alpha1_CloudControllerManager
 	return autoConvert_aws_CloudControllerManagerConfig_To_v1alpha1_CloudControllerManagerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha1_CloudProviderConfig_To_aws_CloudProviderConfig(in *CloudProviderConfig, out *aws.CloudProviderConfig, s conversion.Scope) error {
+	out.ElbSecurityGroup = (*string)(unsafe.Pointer(in.ElbSecurityGroup))
+	out.RoleARN = (*string)(unsafe.Pointer(in.RoleARN))
+	out.KubernetesClusterTag = (*string)(unsafe.Pointer(in.KubernetesClusterTag))
+	return nil
+}
+
+// Convert_v1alpha1_CloudProviderConfig_To_aws_CloudProviderConfig is an autogenerated conversion function.
+func Convert_v1alpha1_CloudProviderConfig_To_aws_CloudProviderConfig(in *CloudProviderConfig, out *aws.CloudProviderConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_CloudProviderConfig_To_aws_CloudProviderConfig(in, out, s)
+}
+
+func autoConvert_aws_CloudProviderConfig_To_v1alpha1_CloudProviderConfig(in *aws.CloudProviderConfig, out *CloudProviderConfig, s conversion.Scope) error {
+	out.ElbSecurityGroup = (*string)(unsafe.Pointer(in.ElbSecurityGroup))
+	out.RoleARN = (*string)(unsafe.Pointer(in.RoleARN))
+	out.KubernetesClusterTag = (*string)(unsafe.Pointer(in.KubernetesClusterTag))
+	return nil
+}
+
+// Convert_aws_CloudProviderConfig_To_v1alpha1_CloudProviderConfig is an autogenerated conversion function.
+func Convert_aws_CloudProviderConfig_To_v1alpha1_CloudProviderConfig(in *aws.CloudProviderConfig, out *CloudProviderConfig, s conversion.Scope) error {
+	return autoConvert_aws_CloudProviderConfig_To_v1alpha1_CloudProviderConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_ControlPlaneConfig_To_aws_ControlPlaneConfig(in *ControlPlaneConfig, out *aws.ControlPlaneConfig, s conversion.Scope) error {
 	out.CloudControllerManager = (*aws.CloudControllerManagerConfig)(unsafe.Pointer(in.CloudControllerManager))
+	out.CloudProvider = (*aws.CloudProviderConfig)(unsafe.Pointer(in.CloudProvider))
 	return nil
 }
 
@@ -212,6 +352,7 @@ func Convert_v1alpha1_ControlPlaneConfig_To_aws_ControlPlaneConfig(in *ControlPl
 
 func autoConvert_aws_ControlPlaneConfig_To_v1alpha1_ControlPlaneConfig(in *aws.ControlPlaneConfig, out *ControlPlaneConfig, s conversion.Scope) error {
 	out.CloudControllerManager = (*CloudControllerManagerConfig)(unsafe.Pointer(in.CloudControllerManager))
+	out.CloudProvider = (*CloudProviderConfig)(unsafe.Pointer(in.CloudProvider))
 	return nil
 }
 
@@ -266,6 +407,9 @@ func autoConvert_v1alpha1_InfrastructureConfig_To_aws_InfrastructureConfig(in *I
 	if err := Convert_v1alpha1_Networks_To_aws_Networks(&in.Networks, &out.Networks, s); err != nil {
 		return err
 	}
+	out.VolumeCleanup = (*aws.VolumeCleanup)(unsafe.Pointer(in.VolumeCleanup))
+	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
+	out.Reconciler = aws.InfrastructureReconciler(in.Reconciler)
 	return nil
 }
 
@@ -278,6 +422,9 @@ func autoConvert_aws_InfrastructureConfig_To_v1alpha1_InfrastructureConfig(in *a
 	if err := Convert_aws_Networks_To_v1alpha1_Networks(&in.Networks, &out.Networks, s); err != nil {
 		return err
 	}
+	out.VolumeCleanup = (*VolumeCleanup)(unsafe.Pointer(in.VolumeCleanup))
+	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
+	out.Reconciler = InfrastructureReconciler(in.Reconciler)
 	return nil
 }
 
@@ -296,6 +443,7 @@ func autoConvert_v1alpha1_InfrastructureStatus_To_aws_InfrastructureStatus(in *I
 	if err := Convert_v1alpha1_VPCStatus_To_aws_VPCStatus(&in.VPC, &out.VPC, s); err != nil {
 		return err
 	}
+	out.Reconciler = aws.InfrastructureReconciler(in.Reconciler)
 	return nil
 }
 
@@ -314,6 +462,7 @@ func autoConvert_aws_InfrastructureStatus_To_v1alpha1_InfrastructureStatus(in *a
 	if err := Convert_aws_VPCStatus_To_v1alpha1_VPCStatus(&in.VPC, &out.VPC, s); err != nil {
 		return err
 	}
+	out.Reconciler = InfrastructureReconciler(in.Reconciler)
 	return nil
 }
 
@@ -322,6 +471,36 @@ func Convert_aws_InfrastructureStatus_To_v1alpha1_InfrastructureStatus(in *aws.I
 	return autoConvert_aws_InfrastructureStatus_To_v1alpha1_InfrastructureStatus(in, out, s)
 }
 
+func autoConvert_v1alpha1_IngressRule_To_aws_IngressRule(in *IngressRule, out *aws.IngressRule, s conversion.Scope) error {
+	out.Protocol = in.Protocol
+	out.FromPort = in.FromPort
+	out.ToPort = in.ToPort
+	out.CIDRs = *(*[]core.CIDR)(unsafe.Pointer(&in.CIDRs))
+	out.SecurityGroupIDs = *(*[]string)(unsafe.Pointer(&in.SecurityGroupIDs))
+	out.AllowPublicAccess = in.AllowPublicAccess
+	return nil
+}
+
+// Convert_v1alpha1_IngressRule_To_aws_IngressRule is an autogenerated conversion function.
+func Convert_v1alpha1_IngressRule_To_aws_IngressRule(in *IngressRule, out *aws.IngressRule, s conversion.Scope) error {
+	return autoConvert_v1alpha1_IngressRule_To_aws_IngressRule(in, out, s)
+}
+
+func autoConvert_aws_IngressRule_To_v1alpha1_IngressRule(in *aws.IngressRule, out *IngressRule, s conversion.Scope) error {
+	out.Protocol = in.Protocol
+	out.FromPort = in.FromPort
+	out.ToPort = in.ToPort
+	out.CIDRs = *(*[]corev1alpha1.CIDR)(unsafe.Pointer(&in.CIDRs))
+	out.SecurityGroupIDs = *(*[]string)(unsafe.Pointer(&in.SecurityGroupIDs))
+	out.AllowPublicAccess = in.AllowPublicAccess
+	return nil
+}
+
+// Convert_aws_IngressRule_To_v1alpha1_IngressRule is an autogenerated conversion function.
+func Convert_aws_IngressRule_To_v1alpha1_IngressRule(in *aws.IngressRule, out *IngressRule, s conversion.Scope) error {
+	return autoConvert_aws_IngressRule_To_v1alpha1_IngressRule(in, out, s)
+}
+
 func autoConvert_v1alpha1_InstanceProfile_To_aws_InstanceProfile(in *InstanceProfile, out *aws.InstanceProfile, s conversion.Scope) error {
 	out.Purpose = in.Purpose
 	out.Name = in.Name
@@ -344,11 +523,60 @@ func Convert_aws_InstanceProfile_To_v1alpha1_InstanceProfile(in *aws.InstancePro
 	return autoConvert_aws_InstanceProfile_To_v1alpha1_InstanceProfile(in, out, s)
 }
 
+func autoConvert_v1alpha1_NATGateway_To_aws_NATGateway(in *NATGateway, out *aws.NATGateway, s conversion.Scope) error {
+	out.Mode = aws.NATGatewayMode(in.Mode)
+	return nil
+}
+
+// Convert_v1alpha1_NATGateway_To_aws_NATGateway is an autogenerated conversion function.
+func Convert_v1alpha1_NATGateway_To_aws_NATGateway(in *NATGateway, out *aws.NATGateway, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NATGateway_To_aws_NATGateway(in, out, s)
+}
+
+func autoConvert_aws_NATGateway_To_v1alpha1_NATGateway(in *aws.NATGateway, out *NATGateway, s conversion.Scope) error {
+	out.Mode = NATGatewayMode(in.Mode)
+	return nil
+}
+
+// Convert_aws_NATGateway_To_v1alpha1_NATGateway is an autogenerated conversion function.
+func Convert_aws_NATGateway_To_v1alpha1_NATGateway(in *aws.NATGateway, out *NATGateway, s conversion.Scope) error {
+	return autoConvert_aws_NATGateway_To_v1alpha1_NATGateway(in, out, s)
+}
+
+func autoConvert_v1alpha1_NATGatewayStatus_To_aws_NATGatewayStatus(in *NATGatewayStatus, out *aws.NATGatewayStatus, s conversion.Scope) error {
+	out.ID = in.ID
+	out.Zone = in.Zone
+	out.PublicIP = in.PublicIP
+	out.ElasticIPAllocationID = in.ElasticIPAllocationID
+	return nil
+}
+
+// Convert_v1alpha1_NATGatewayStatus_To_aws_NATGatewayStatus is an autogenerated conversion function.
+func Convert_v1alpha1_NATGatewayStatus_To_aws_NATGatewayStatus(in *NATGatewayStatus, out *aws.NATGatewayStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NATGatewayStatus_To_aws_NATGatewayStatus(in, out, s)
+}
+
+func autoConvert_aws_NATGatewayStatus_To_v1alpha1_NATGatewayStatus(in *aws.NATGatewayStatus, out *NATGatewayStatus, s conversion.Scope) error {
+	out.ID = in.ID
+	out.Zone = in.Zone
+	out.PublicIP = in.PublicIP
+	out.ElasticIPAllocationID = in.ElasticIPAllocationID
+	return nil
+}
+
+// Convert_aws_NATGatewayStatus_To_v1alpha1_NATGatewayStatus is an autogenerated conversion function.
+func Convert_aws_NATGatewayStatus_To_v1alpha1_NATGatewayStatus(in *aws.NATGatewayStatus, out *NATGatewayStatus, s conversion.Scope) error {
+	return autoConvert_aws_NATGatewayStatus_To_v1alpha1_NATGatewayStatus(in, out, s)
+}
+
 func autoConvert_v1alpha1_Networks_To_aws_Networks(in *Networks, out *aws.Networks, s conversion.Scope) error {
 	if err := Convert_v1alpha1_VPC_To_aws_VPC(&in.VPC, &out.VPC, s); err != nil {
 		return err
 	}
 	out.Zones = *(*[]aws.Zone)(unsafe.Pointer(&in.Zones))
+	out.NATGateway = (*aws.NATGateway)(unsafe.Pointer(in.NATGateway))
+	out.IngressRules = *(*[]aws.IngressRule)(unsafe.Pointer(&in.IngressRules))
+	out.Peerings = *(*[]aws.VPCPeering)(unsafe.Pointer(&in.Peerings))
 	return nil
 }
 
@@ -362,6 +590,9 @@ func autoConvert_aws_Networks_To_v1alpha1_Networks(in *aws.Networks, out *Networ
 		return err
 	}
 	out.Zones = *(*[]Zone)(unsafe.Pointer(&in.Zones))
+	out.NATGateway = (*NATGateway)(unsafe.Pointer(in.NATGateway))
+	out.IngressRules = *(*[]IngressRule)(unsafe.Pointer(&in.IngressRules))
+	out.Peerings = *(*[]VPCPeering)(unsafe.Pointer(&in.Peerings))
 	return nil
 }
 
@@ -392,6 +623,30 @@ func Convert_aws_Role_To_v1alpha1_Role(in *aws.Role, out *Role, s conversion.Sco
 	return autoConvert_aws_Role_To_v1alpha1_Role(in, out, s)
 }
 
+func autoConvert_v1alpha1_RouteTable_To_aws_RouteTable(in *RouteTable, out *aws.RouteTable, s conversion.Scope) error {
+	out.Purpose = in.Purpose
+	out.ID = in.ID
+	out.Zone = in.Zone
+	return nil
+}
+
+// Convert_v1alpha1_RouteTable_To_aws_RouteTable is an autogenerated conversion function.
+func Convert_v1alpha1_RouteTable_To_aws_RouteTable(in *RouteTable, out *aws.RouteTable, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RouteTable_To_aws_RouteTable(in, out, s)
+}
+
+func autoConvert_aws_RouteTable_To_v1alpha1_RouteTable(in *aws.RouteTable, out *RouteTable, s conversion.Scope) error {
+	out.Purpose = in.Purpose
+	out.ID = in.ID
+	out.Zone = in.Zone
+	return nil
+}
+
+// Convert_aws_RouteTable_To_v1alpha1_RouteTable is an autogenerated conversion function.
+func Convert_aws_RouteTable_To_v1alpha1_RouteTable(in *aws.RouteTable, out *RouteTable, s conversion.Scope) error {
+	return autoConvert_aws_RouteTable_To_v1alpha1_RouteTable(in, out, s)
+}
+
 func autoConvert_v1alpha1_SecurityGroup_To_aws_SecurityGroup(in *SecurityGroup, out *aws.SecurityGroup, s conversion.Scope) error {
 	out.Purpose = in.Purpose
 	out.ID = in.ID
@@ -441,6 +696,8 @@ func Convert_aws_Subnet_To_v1alpha1_Subnet(in *aws.Subnet, out *Subnet, s conver
 func autoConvert_v1alpha1_VPC_To_aws_VPC(in *VPC, out *aws.VPC, s conversion.Scope) error {
 	out.ID = (*string)(unsafe.Pointer(in.ID))
 	out.CIDR = (*core.CIDR)(unsafe.Pointer(in.CIDR))
+	out.Endpoints = *(*[]aws.VPCEndpoint)(unsafe.Pointer(&in.Endpoints))
+	out.SubnetMode = aws.SubnetMode(in.SubnetMode)
 	return nil
 }
 
@@ -452,6 +709,8 @@ func Convert_v1alpha1_VPC_To_aws_VPC(in *VPC, out *aws.VPC, s conversion.Scope)
 func autoConvert_aws_VPC_To_v1alpha1_VPC(in *aws.VPC, out *VPC, s conversion.Scope) error {
 	out.ID = (*string)(unsafe.Pointer(in.ID))
 	out.CIDR = (*corev1alpha1.CIDR)(unsafe.Pointer(in.CIDR))
+	out.Endpoints = *(*[]VPCEndpoint)(unsafe.Pointer(&in.Endpoints))
+	out.SubnetMode = SubnetMode(in.SubnetMode)
 	return nil
 }
 
@@ -460,10 +719,113 @@ func Convert_aws_VPC_To_v1alpha1_VPC(in *aws.VPC, out *VPC, s conversion.Scope)
 	return autoConvert_aws_VPC_To_v1alpha1_VPC(in, out, s)
 }
 
+func autoConvert_v1alpha1_VPCEndpoint_To_aws_VPCEndpoint(in *VPCEndpoint, out *aws.VPCEndpoint, s conversion.Scope) error {
+	out.Service = in.Service
+	out.Type = aws.VPCEndpointType(in.Type)
+	return nil
+}
+
+// Convert_v1alpha1_VPCEndpoint_To_aws_VPCEndpoint is an autogenerated conversion function.
+func Convert_v1alpha1_VPCEndpoint_To_aws_VPCEndpoint(in *VPCEndpoint, out *aws.VPCEndpoint, s conversion.Scope) error {
+	return autoConvert_v1alpha1_VPCEndpoint_To_aws_VPCEndpoint(in, out, s)
+}
+
+func autoConvert_aws_VPCEndpoint_To_v1alpha1_VPCEndpoint(in *aws.VPCEndpoint, out *VPCEndpoint, s conversion.Scope) error {
+	out.Service = in.Service
+	out.Type = VPCEndpointType(in.Type)
+	return nil
+}
+
+// Convert_aws_VPCEndpoint_To_v1alpha1_VPCEndpoint is an autogenerated conversion function.
+func Convert_aws_VPCEndpoint_To_v1alpha1_VPCEndpoint(in *aws.VPCEndpoint, out *VPCEndpoint, s conversion.Scope) error {
+	return autoConvert_aws_VPCEndpoint_To_v1alpha1_VPCEndpoint(in, out, s)
+}
+
+func autoConvert_v1alpha1_VPCEndpointStatus_To_aws_VPCEndpointStatus(in *VPCEndpointStatus, out *aws.VPCEndpointStatus, s conversion.Scope) error {
+	out.Service = in.Service
+	out.Type = aws.VPCEndpointType(in.Type)
+	out.ID = in.ID
+	return nil
+}
+
+// Convert_v1alpha1_VPCEndpointStatus_To_aws_VPCEndpointStatus is an autogenerated conversion function.
+func Convert_v1alpha1_VPCEndpointStatus_To_aws_VPCEndpointStatus(in *VPCEndpointStatus, out *aws.VPCEndpointStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_VPCEndpointStatus_To_aws_VPCEndpointStatus(in, out, s)
+}
+
+func autoConvert_aws_VPCEndpointStatus_To_v1alpha1_VPCEndpointStatus(in *aws.VPCEndpointStatus, out *VPCEndpointStatus, s conversion.Scope) error {
+	out.Service = in.Service
+	out.Type = VPCEndpointType(in.Type)
+	out.ID = in.ID
+	return nil
+}
+
+// Convert_aws_VPCEndpointStatus_To_v1alpha1_VPCEndpointStatus is an autogenerated conversion function.
+func Convert_aws_VPCEndpointStatus_To_v1alpha1_VPCEndpointStatus(in *aws.VPCEndpointStatus, out *VPCEndpointStatus, s conversion.Scope) error {
+	return autoConvert_aws_VPCEndpointStatus_To_v1alpha1_VPCEndpointStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_VPCPeering_To_aws_VPCPeering(in *VPCPeering, out *aws.VPCPeering, s conversion.Scope) error {
+	out.Name = in.Name
+	out.VPCID = in.VPCID
+	out.OwnerID = (*string)(unsafe.Pointer(in.OwnerID))
+	out.Region = (*string)(unsafe.Pointer(in.Region))
+	out.CIDRs = *(*[]core.CIDR)(unsafe.Pointer(&in.CIDRs))
+	return nil
+}
+
+// Convert_v1alpha1_VPCPeering_To_aws_VPCPeering is an autogenerated conversion function.
+func Convert_v1alpha1_VPCPeering_To_aws_VPCPeering(in *VPCPeering, out *aws.VPCPeering, s conversion.Scope) error {
+	return autoConvert_v1alpha1_VPCPeering_To_aws_VPCPeering(in, out, s)
+}
+
+func autoConvert_aws_VPCPeering_To_v1alpha1_VPCPeering(in *aws.VPCPeering, out *VPCPeering, s conversion.Scope) error {
+	out.Name = in.Name
+	out.VPCID = in.VPCID
+	out.OwnerID = (*string)(unsafe.Pointer(in.OwnerID))
+	out.Region = (*string)(unsafe.Pointer(in.Region))
+	out.CIDRs = *(*[]corev1alpha1.CIDR)(unsafe.Pointer(&in.CIDRs))
+	return nil
+}
+
+// Convert_aws_VPCPeering_To_v1alpha1_VPCPeering is an autogenerated conversion function.
+func Convert_aws_VPCPeering_To_v1alpha1_VPCPeering(in *aws.VPCPeering, out *VPCPeering, s conversion.Scope) error {
+	return autoConvert_aws_VPCPeering_To_v1alpha1_VPCPeering(in, out, s)
+}
+
+func autoConvert_v1alpha1_VPCPeeringStatus_To_aws_VPCPeeringStatus(in *VPCPeeringStatus, out *aws.VPCPeeringStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.VPCID = in.VPCID
+	out.ID = in.ID
+	return nil
+}
+
+// Convert_v1alpha1_VPCPeeringStatus_To_aws_VPCPeeringStatus is an autogenerated conversion function.
+func Convert_v1alpha1_VPCPeeringStatus_To_aws_VPCPeeringStatus(in *VPCPeeringStatus, out *aws.VPCPeeringStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_VPCPeeringStatus_To_aws_VPCPeeringStatus(in, out, s)
+}
+
+func autoConvert_aws_VPCPeeringStatus_To_v1alpha1_VPCPeeringStatus(in *aws.VPCPeeringStatus, out *VPCPeeringStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.VPCID = in.VPCID
+	out.ID = in.ID
+	return nil
+}
+
+// Convert_aws_VPCPeeringStatus_To_v1alpha1_VPCPeeringStatus is an autogenerated conversion function.
+func Convert_aws_VPCPeeringStatus_To_v1alpha1_VPCPeeringStatus(in *aws.VPCPeeringStatus, out *VPCPeeringStatus, s conversion.Scope) error {
+	return autoConvert_aws_VPCPeeringStatus_To_v1alpha1_VPCPeeringStatus(in, out, s)
+}
+
 func autoConvert_v1alpha1_VPCStatus_To_aws_VPCStatus(in *VPCStatus, out *aws.VPCStatus, s conversion.Scope) error {
 	out.ID = in.ID
 	out.Subnets = *(*[]aws.Subnet)(unsafe.Pointer(&in.Subnets))
+	out.RouteTables = *(*[]aws.RouteTable)(unsafe.Pointer(&in.RouteTables))
+	out.InternetGatewayID = in.InternetGatewayID
 	out.SecurityGroups = *(*[]aws.SecurityGroup)(unsafe.Pointer(&in.SecurityGroups))
+	out.NATGateways = *(*[]aws.NATGatewayStatus)(unsafe.Pointer(&in.NATGateways))
+	out.Endpoints = *(*[]aws.VPCEndpointStatus)(unsafe.Pointer(&in.Endpoints))
+	out.Peerings = *(*[]aws.VPCPeeringStatus)(unsafe.Pointer(&in.Peerings))
 	return nil
 }
 
@@ -475,7 +837,12 @@ func Convert_v1alpha1_VPCStatus_To_aws_VPCStatus(in *VPCStatus, out *aws.VPCStat
 func autoConvert_aws_VPCStatus_To_v1alpha1_VPCStatus(in *aws.VPCStatus, out *VPCStatus, s conversion.Scope) error {
 	out.ID = in.ID
 	out.Subnets = *(*[]Subnet)(unsafe.Pointer(&in.Subnets))
+	out.RouteTables = *(*[]RouteTable)(unsafe.Pointer(&in.RouteTables))
+	out.InternetGatewayID = in.InternetGatewayID
 	out.SecurityGroups = *(*[]SecurityGroup)(unsafe.Pointer(&in.SecurityGroups))
+	out.NATGateways = *(*[]NATGatewayStatus)(unsafe.Pointer(&in.NATGateways))
+	out.Endpoints = *(*[]VPCEndpointStatus)(unsafe.Pointer(&in.Endpoints))
+	out.Peerings = *(*[]VPCPeeringStatus)(unsafe.Pointer(&in.Peerings))
 	return nil
 }
 
@@ -484,11 +851,37 @@ func Convert_aws_VPCStatus_To_v1alpha1_VPCStatus(in *aws.VPCStatus, out *VPCStat
 	return autoConvert_aws_VPCStatus_To_v1alpha1_VPCStatus(in, out, s)
 }
 
+func autoConvert_v1alpha1_VolumeCleanup_To_aws_VolumeCleanup(in *VolumeCleanup, out *aws.VolumeCleanup, s conversion.Scope) error {
+	out.DeleteVolumes = in.DeleteVolumes
+	out.DeleteSnapshots = in.DeleteSnapshots
+	out.DryRun = in.DryRun
+	return nil
+}
+
+// Convert_v1alpha1_VolumeCleanup_To_aws_VolumeCleanup is an autogenerated conversion function.
+func Convert_v1alpha1_VolumeCleanup_To_aws_VolumeCleanup(in *VolumeCleanup, out *aws.VolumeCleanup, s conversion.Scope) error {
+	return autoConvert_v1alpha1_VolumeCleanup_To_aws_VolumeCleanup(in, out, s)
+}
+
+func autoConvert_aws_VolumeCleanup_To_v1alpha1_VolumeCleanup(in *aws.VolumeCleanup, out *VolumeCleanup, s conversion.Scope) error {
+	out.DeleteVolumes = in.DeleteVolumes
+	out.DeleteSnapshots = in.DeleteSnapshots
+	out.DryRun = in.DryRun
+	return nil
+}
+
+// Convert_aws_VolumeCleanup_To_v1alpha1_VolumeCleanup is an autogenerated conversion function.
+func Convert_aws_VolumeCleanup_To_v1alpha1_VolumeCleanup(in *aws.VolumeCleanup, out *VolumeCleanup, s conversion.Scope) error {
+	return autoConvert_aws_VolumeCleanup_To_v1alpha1_VolumeCleanup(in, out, s)
+}
+
 func autoConvert_v1alpha1_Zone_To_aws_Zone(in *Zone, out *aws.Zone, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Internal = core.CIDR(in.Internal)
 	out.Public = core.CIDR(in.Public)
 	out.Workers = core.CIDR(in.Workers)
+	out.ElasticIPAllocationID = (*string)(unsafe.Pointer(in.ElasticIPAllocationID))
+	out.Subnets = (*aws.ZoneSubnets)(unsafe.Pointer(in.Subnets))
 	return nil
 }
 
@@ -502,6 +895,8 @@ func autoConvert_aws_Zone_To_v1alpha1_Zone(in *aws.Zone, out *Zone, s conversion
 	out.Internal = corev1alpha1.CIDR(in.Internal)
 	out.Public = corev1alpha1.CIDR(in.Public)
 	out.Workers = corev1alpha1.CIDR(in.Workers)
+	out.ElasticIPAllocationID = (*string)(unsafe.Pointer(in.ElasticIPAllocationID))
+	out.Subnets = (*ZoneSubnets)(unsafe.Pointer(in.Subnets))
 	return nil
 }
 
@@ -509,3 +904,27 @@ func autoConvert_aws_Zone_To_v1alpha1_Zone(in *aws.Zone, out *Zone, s conversion
 func Convert_aws_Zone_To_v1alpha1_Zone(in *aws.Zone, out *Zone, s conversion.Scope) error {
 	return autoConvert_aws_Zone_To_v1alpha1_Zone(in, out, s)
 }
+
+func autoConvert_v1alpha1_ZoneSubnets_To_aws_ZoneSubnets(in *ZoneSubnets, out *aws.ZoneSubnets, s conversion.Scope) error {
+	out.Workers = (*string)(unsafe.Pointer(in.Workers))
+	out.Public = (*string)(unsafe.Pointer(in.Public))
+	out.Internal = (*string)(unsafe.Pointer(in.Internal))
+	return nil
+}
+
+// Convert_v1alpha1_ZoneSubnets_To_aws_ZoneSubnets is an autogenerated conversion function.
+func Convert_v1alpha1_ZoneSubnets_To_aws_ZoneSubnets(in *ZoneSubnets, out *aws.ZoneSubnets, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ZoneSubnets_To_aws_ZoneSubnets(in, out, s)
+}
+
+func autoConvert_aws_ZoneSubnets_To_v1alpha1_ZoneSubnets(in *aws.ZoneSubnets, out *ZoneSubnets, s conversion.Scope) error {
+	out.Workers = (*string)(unsafe.Pointer(in.Workers))
+	out.Public = (*string)(unsafe.Pointer(in.Public))
+	out.Internal = (*string)(unsafe.Pointer(in.Internal))
+	return nil
+}
+
+// Convert_aws_ZoneSubnets_To_v1alpha1_ZoneSubnets is an autogenerated conversion function.
+func Convert_aws_ZoneSubnets_To_v1alpha1_ZoneSubnets(in *aws.ZoneSubnets, out *ZoneSubnets, s conversion.Scope) error {
+	return autoConvert_aws_ZoneSubnets_To_v1alpha1_ZoneSubnets(in, out, s)
+}
@@ -17,39 +17,96 @@ package client
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/sts"
 )
 
+const (
+	// loadBalancersPageSize is the maximum page size of the ELB DescribeLoadBalancers call.
+	loadBalancersPageSize = 400
+	// describeTagsBatchSize is the maximum number of load balancers a single ELB DescribeTags call accepts.
+	describeTagsBatchSize = 20
+	// securityGroupsPageSize is the maximum page size of the EC2 DescribeSecurityGroups call.
+	securityGroupsPageSize = 1000
+	// volumesPageSize is the maximum page size of the EC2 DescribeVolumes call.
+	volumesPageSize = 500
+	// snapshotsPageSize is the maximum page size of the EC2 DescribeSnapshots call.
+	snapshotsPageSize = 1000
+)
+
 // NewClient creates a new Client for the given AWS credentials <accessKeyID>, <secretAccessKey>, and
 // the AWS region <region>.
 // It initializes the clients for the various services like EC2, ELB, etc.
 func NewClient(accessKeyID, secretAccessKey, region string) (Interface, error) {
-	var (
-		awsConfig = &aws.Config{
-			Credentials: credentials.NewStaticCredentials(accessKeyID, secretAccessKey, ""),
-		}
-		config = &aws.Config{Region: aws.String(region)}
-	)
+	return NewClientWithEndpoints(accessKeyID, secretAccessKey, region, Endpoints{})
+}
 
-	s, err := session.NewSession(awsConfig)
+// NewClientWithEndpoints creates a new Client like NewClient, but talks to the given <endpoints> instead of
+// the default endpoints of the services for which an endpoint is set.
+func NewClientWithEndpoints(accessKeyID, secretAccessKey, region string, endpoints Endpoints) (Interface, error) {
+	return NewClientWithCredentials(Credentials{AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey}, region, endpoints)
+}
+
+// NewClientWithCredentials creates a new Client like NewClientWithEndpoints, but authenticates with the given
+// <credentials>, i.e. it possibly assumes a role or uses the web identity of the extension.
+func NewClientWithCredentials(credentials Credentials, region string, endpoints Endpoints) (Interface, error) {
+	if err := endpoints.Validate(); err != nil {
+		return nil, err
+	}
+
+	s, err := credentials.newSession(region, endpoints)
 	if err != nil {
 		return nil, err
 	}
+	config := &aws.Config{Region: aws.String(region)}
 
 	return &Client{
-		EC2: ec2.New(s, config),
-		ELB: elb.New(s, config),
-		STS: sts.New(s, config),
+		EC2:   ec2.New(s, config, endpointConfig(endpoints.EC2)),
+		ELB:   elb.New(s, config, endpointConfig(endpoints.ELB)),
+		ELBV2: elbv2.New(s, config, endpointConfig(endpoints.ELB)),
+		STS:   sts.New(s, config, endpointConfig(endpoints.STS)),
+		IAM:   iam.New(s, config, endpointConfig(endpoints.IAM)),
 	}, nil
 }
 
+type requestSpanContextKey struct{}
+
+// addTracingHandlers adds request handlers that record every AWS API call as a child span of the span
+// carried by the context of the call.
+func addTracingHandlers(handlers *request.Handlers) {
+	handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: "gardener-extensions.tracing.Start",
+		Fn: func(r *request.Request) {
+			ctx, span := tracing.Start(r.Context(), fmt.Sprintf("AWS %s.%s", r.ClientInfo.ServiceName, r.Operation.Name),
+				"aws.service", r.ClientInfo.ServiceName,
+				"aws.operation", r.Operation.Name,
+				"aws.region", aws.StringValue(r.Config.Region),
+			)
+			r.SetContext(context.WithValue(ctx, requestSpanContextKey{}, span))
+		},
+	})
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "gardener-extensions.tracing.End",
+		Fn: func(r *request.Request) {
+			if span, ok := r.Context().Value(requestSpanContextKey{}).(*tracing.Span); ok {
+				span.SetAttributes("aws.requestId", r.RequestID, "aws.retryCount", r.RetryCount)
+				span.End(r.Error)
+			}
+		},
+	})
+}
+
 // GetAccountID returns the ID of the AWS account the Client is interacting with.
 func (c *Client) GetAccountID(ctx context.Context) (string, error) {
 	getCallerIdentityOutput, err := c.STS.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
@@ -59,6 +116,34 @@ func (c *Client) GetAccountID(ctx context.Context) (string, error) {
 	return *getCallerIdentityOutput.Account, nil
 }
 
+// GetCallerARN returns the ARN of the identity the Client is interacting as, e.g. an IAM user or an assumed role.
+func (c *Client) GetCallerARN(ctx context.Context) (string, error) {
+	getCallerIdentityOutput, err := c.STS.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(getCallerIdentityOutput.Arn), nil
+}
+
+// GetAccountAttributes returns the values of the EC2 account attributes with the given <names>, e.g. the maximum
+// number of Elastic IPs `vpc-max-elastic-ips`.
+func (c *Client) GetAccountAttributes(ctx context.Context, names ...string) (map[string][]string, error) {
+	output, err := c.EC2.DescribeAccountAttributesWithContext(ctx, &ec2.DescribeAccountAttributesInput{AttributeNames: aws.StringSlice(names)})
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make(map[string][]string, len(output.AccountAttributes))
+	for _, attribute := range output.AccountAttributes {
+		var values []string
+		for _, value := range attribute.AttributeValues {
+			values = append(values, aws.StringValue(value.AttributeValue))
+		}
+		attributes[aws.StringValue(attribute.AttributeName)] = values
+	}
+	return attributes, nil
+}
+
 // GetInternetGateway returns the ID of the internet gateway attached to the given VPC <vpcID>.
 // If there is no internet gateway attached, the returned string will be empty.
 func (c *Client) GetInternetGateway(ctx context.Context, vpcID string) (string, error) {
@@ -77,8 +162,8 @@ func (c *Client) GetInternetGateway(ctx context.Context, vpcID string) (string,
 		return "", err
 	}
 
-	if describeInternetGatewaysOutput.InternetGateways != nil {
-		if *describeInternetGatewaysOutput.InternetGateways[0].InternetGatewayId == "" {
+	if len(describeInternetGatewaysOutput.InternetGateways) > 0 {
+		if aws.StringValue(describeInternetGatewaysOutput.InternetGateways[0].InternetGatewayId) == "" {
 			return "", fmt.Errorf("no attached internet gateway found for vpc %s", vpcID)
 		}
 		return *describeInternetGatewaysOutput.InternetGateways[0].InternetGatewayId, nil
@@ -86,31 +171,114 @@ func (c *Client) GetInternetGateway(ctx context.Context, vpcID string) (string,
 	return "", fmt.Errorf("no attached internet gateway found for vpc %s", vpcID)
 }
 
-// The following functions are only temporary needed due to https://github.com/gardener/gardener/issues/129.
+// GetSubnets returns the subnets with the given <ids>. It fails if one of the subnets does not exist.
+func (c *Client) GetSubnets(ctx context.Context, ids []string) ([]Subnet, error) {
+	return c.describeSubnets(ctx, &ec2.DescribeSubnetsInput{SubnetIds: aws.StringSlice(ids)})
+}
 
-// ListKubernetesELBs returns the list of load balancers in the given <vpcID> tagged with <clusterName>.
-func (c *Client) ListKubernetesELBs(ctx context.Context, vpcID, clusterName string) ([]string, error) {
-	output, err := c.ELB.DescribeLoadBalancersWithContext(ctx, &elb.DescribeLoadBalancersInput{})
+// FindSubnets returns the subnets in the given <vpcID> and availability <zone> that carry all of the given <tags>.
+// Subnets in all zones are returned if <zone> is empty.
+func (c *Client) FindSubnets(ctx context.Context, vpcID, zone string, tags map[string]string) ([]Subnet, error) {
+	filters := []*ec2.Filter{
+		{
+			Name:   aws.String("vpc-id"),
+			Values: []*string{aws.String(vpcID)},
+		},
+	}
+	if zone != "" {
+		filters = append(filters, &ec2.Filter{
+			Name:   aws.String("availability-zone"),
+			Values: []*string{aws.String(zone)},
+		})
+	}
+	filters = append(filters, tagFilters(tags)...)
+
+	return c.describeSubnets(ctx, &ec2.DescribeSubnetsInput{Filters: filters})
+}
+
+// tagFilters returns EC2 filters that match resources carrying all of the given <tags>, sorted by tag key.
+func tagFilters(tags map[string]string) []*ec2.Filter {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var filters []*ec2.Filter
+	for _, key := range keys {
+		filters = append(filters, &ec2.Filter{
+			Name:   aws.String("tag:" + key),
+			Values: []*string{aws.String(tags[key])},
+		})
+	}
+	return filters
+}
+
+func (c *Client) describeSubnets(ctx context.Context, input *ec2.DescribeSubnetsInput) ([]Subnet, error) {
+	output, err := c.EC2.DescribeSubnetsWithContext(ctx, input)
 	if err != nil {
 		return nil, err
 	}
 
-	var results []string
-	for _, lb := range output.LoadBalancerDescriptions {
-		if lb.VPCId != nil && *lb.VPCId == vpcID {
-			// TODO: DescribeTagsWithContext can take multiple LoadBalancers,  make just 1 call to collect all Tags
-			tags, err := c.ELB.DescribeTagsWithContext(ctx, &elb.DescribeTagsInput{
-				LoadBalancerNames: []*string{lb.LoadBalancerName},
-			})
-			if err != nil {
-				return nil, err
+	var subnets []Subnet
+	for _, subnet := range output.Subnets {
+		subnets = append(subnets, Subnet{
+			ID:               aws.StringValue(subnet.SubnetId),
+			VPCID:            aws.StringValue(subnet.VpcId),
+			AvailabilityZone: aws.StringValue(subnet.AvailabilityZone),
+			CIDR:             aws.StringValue(subnet.CidrBlock),
+			Tags:             tagsOf(subnet.Tags),
+		})
+	}
+	return subnets, nil
+}
+
+// The following functions are only temporary needed due to https://github.com/gardener/gardener/issues/129.
+
+// ListKubernetesELBs returns the list of load balancers in the given <vpcID> tagged with <clusterName>.
+func (c *Client) ListKubernetesELBs(ctx context.Context, vpcID, clusterName string) ([]string, error) {
+	var names []string
+	input := &elb.DescribeLoadBalancersInput{PageSize: aws.Int64(loadBalancersPageSize)}
+	for {
+		output, err := c.ELB.DescribeLoadBalancersWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, lb := range output.LoadBalancerDescriptions {
+			if lb.VPCId != nil && *lb.VPCId == vpcID {
+				names = append(names, aws.StringValue(lb.LoadBalancerName))
 			}
+		}
+
+		if aws.StringValue(output.NextMarker) == "" {
+			break
+		}
+		input.Marker = output.NextMarker
+	}
+
+	var (
+		results    []string
+		clusterTag = fmt.Sprintf("kubernetes.io/cluster/%s", clusterName)
+	)
+	for start := 0; start < len(names); start += describeTagsBatchSize {
+		end := start + describeTagsBatchSize
+		if end > len(names) {
+			end = len(names)
+		}
+
+		tags, err := c.ELB.DescribeTagsWithContext(ctx, &elb.DescribeTagsInput{
+			LoadBalancerNames: aws.StringSlice(names[start:end]),
+		})
+		if err != nil {
+			return nil, err
+		}
 
-			for _, description := range tags.TagDescriptions {
-				for _, tag := range description.Tags {
-					if tag.Key != nil && *tag.Key == fmt.Sprintf("kubernetes.io/cluster/%s", clusterName) && tag.Value != nil && *tag.Value == "owned" {
-						results = append(results, *lb.LoadBalancerName)
-					}
+		for _, description := range tags.TagDescriptions {
+			for _, tag := range description.Tags {
+				if aws.StringValue(tag.Key) == clusterTag && aws.StringValue(tag.Value) == "owned" {
+					results = append(results, aws.StringValue(description.LoadBalancerName))
+					break
 				}
 			}
 		}
@@ -133,29 +301,35 @@ func (c *Client) DeleteELB(ctx context.Context, name string) error {
 
 // ListKubernetesSecurityGroups returns the list of security groups in the given <vpcID> tagged with <clusterName>.
 func (c *Client) ListKubernetesSecurityGroups(ctx context.Context, vpcID, clusterName string) ([]string, error) {
-	groups, err := c.EC2.DescribeSecurityGroupsWithContext(ctx, &ec2.DescribeSecurityGroupsInput{
+	input := &ec2.DescribeSecurityGroupsInput{
 		Filters: []*ec2.Filter{
 			{
 				Name:   aws.String("vpc-id"),
 				Values: []*string{aws.String(vpcID)},
 			},
 			{
-				Name:   aws.String("tag-key"),
-				Values: []*string{aws.String(fmt.Sprintf("kubernetes.io/cluster/%s", clusterName))},
-			},
-			{
-				Name:   aws.String("tag-value"),
+				Name:   aws.String(fmt.Sprintf("tag:kubernetes.io/cluster/%s", clusterName)),
 				Values: []*string{aws.String("owned")},
 			},
 		},
-	})
-	if err != nil {
-		return nil, err
+		MaxResults: aws.Int64(securityGroupsPageSize),
 	}
 
 	var results []string
-	for _, group := range groups.SecurityGroups {
-		results = append(results, *group.GroupId)
+	for {
+		groups, err := c.EC2.DescribeSecurityGroupsWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range groups.SecurityGroups {
+			results = append(results, *group.GroupId)
+		}
+
+		if aws.StringValue(groups.NextToken) == "" {
+			break
+		}
+		input.NextToken = groups.NextToken
 	}
 
 	return results, nil
@@ -172,3 +346,268 @@ func (c *Client) DeleteSecurityGroup(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// ListKubernetesNLBs returns the ARNs of the network load balancers in the given <vpcID> tagged with <clusterName>.
+func (c *Client) ListKubernetesNLBs(ctx context.Context, vpcID, clusterName string) ([]string, error) {
+	var arns []string
+	input := &elbv2.DescribeLoadBalancersInput{PageSize: aws.Int64(loadBalancersPageSize)}
+	for {
+		output, err := c.ELBV2.DescribeLoadBalancersWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, lb := range output.LoadBalancers {
+			if aws.StringValue(lb.VpcId) == vpcID && aws.StringValue(lb.Type) == elbv2.LoadBalancerTypeEnumNetwork {
+				arns = append(arns, aws.StringValue(lb.LoadBalancerArn))
+			}
+		}
+
+		if aws.StringValue(output.NextMarker) == "" {
+			break
+		}
+		input.Marker = output.NextMarker
+	}
+
+	return c.filterOwnedELBV2Resources(ctx, arns, clusterName)
+}
+
+// DeleteNLB deletes the network load balancer with the specific <arn>. If it does not exist,
+// no error is returned.
+func (c *Client) DeleteNLB(ctx context.Context, arn string) error {
+	if _, err := c.ELBV2.DeleteLoadBalancerWithContext(ctx, &elbv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String(arn)}); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == elbv2.ErrCodeLoadBalancerNotFoundException {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// ListKubernetesTargetGroups returns the ARNs of the target groups in the given <vpcID> tagged with <clusterName>.
+func (c *Client) ListKubernetesTargetGroups(ctx context.Context, vpcID, clusterName string) ([]string, error) {
+	var arns []string
+	input := &elbv2.DescribeTargetGroupsInput{PageSize: aws.Int64(loadBalancersPageSize)}
+	for {
+		output, err := c.ELBV2.DescribeTargetGroupsWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range output.TargetGroups {
+			if aws.StringValue(group.VpcId) == vpcID {
+				arns = append(arns, aws.StringValue(group.TargetGroupArn))
+			}
+		}
+
+		if aws.StringValue(output.NextMarker) == "" {
+			break
+		}
+		input.Marker = output.NextMarker
+	}
+
+	return c.filterOwnedELBV2Resources(ctx, arns, clusterName)
+}
+
+// DeleteTargetGroup deletes the target group with the specific <arn>. If it does not exist,
+// no error is returned.
+func (c *Client) DeleteTargetGroup(ctx context.Context, arn string) error {
+	if _, err := c.ELBV2.DeleteTargetGroupWithContext(ctx, &elbv2.DeleteTargetGroupInput{TargetGroupArn: aws.String(arn)}); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == elbv2.ErrCodeTargetGroupNotFoundException {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// filterOwnedELBV2Resources returns those of the given ELB v2 resource <arns> that are tagged as owned by <clusterName>.
+func (c *Client) filterOwnedELBV2Resources(ctx context.Context, arns []string, clusterName string) ([]string, error) {
+	var (
+		results    []string
+		clusterTag = fmt.Sprintf("kubernetes.io/cluster/%s", clusterName)
+	)
+	for start := 0; start < len(arns); start += describeTagsBatchSize {
+		end := start + describeTagsBatchSize
+		if end > len(arns) {
+			end = len(arns)
+		}
+
+		tags, err := c.ELBV2.DescribeTagsWithContext(ctx, &elbv2.DescribeTagsInput{
+			ResourceArns: aws.StringSlice(arns[start:end]),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, description := range tags.TagDescriptions {
+			for _, tag := range description.Tags {
+				if aws.StringValue(tag.Key) == clusterTag && aws.StringValue(tag.Value) == "owned" {
+					results = append(results, aws.StringValue(description.ResourceArn))
+					break
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// ListKubernetesENIs returns the IDs of the detached network interfaces in the given <vpcID> that are tagged
+// with <clusterName>, either by the Kubernetes cloud provider or by the AWS VPC CNI plugin. An error is returned as
+// long as any of these network interfaces is still attached or not yet available, so that the caller retries until
+// all of them can be deleted. The EC2 API version of the vendored SDK does not paginate DescribeNetworkInterfaces,
+// all matching network interfaces are returned in a single response.
+func (c *Client) ListKubernetesENIs(ctx context.Context, vpcID, clusterName string) ([]string, error) {
+	var (
+		results []string
+		pending []string
+		seen    = map[string]bool{}
+	)
+	for _, tagFilter := range []*ec2.Filter{
+		{
+			Name:   aws.String(fmt.Sprintf("tag:kubernetes.io/cluster/%s", clusterName)),
+			Values: []*string{aws.String("owned")},
+		},
+		{
+			Name:   aws.String("tag:cluster.k8s.amazonaws.com/name"),
+			Values: []*string{aws.String(clusterName)},
+		},
+	} {
+		output, err := c.EC2.DescribeNetworkInterfacesWithContext(ctx, &ec2.DescribeNetworkInterfacesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("vpc-id"),
+					Values: []*string{aws.String(vpcID)},
+				},
+				tagFilter,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, networkInterface := range output.NetworkInterfaces {
+			id := aws.StringValue(networkInterface.NetworkInterfaceId)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if aws.StringValue(networkInterface.Status) != ec2.NetworkInterfaceStatusAvailable || networkInterface.Attachment != nil {
+				pending = append(pending, id)
+				continue
+			}
+			results = append(results, id)
+		}
+	}
+
+	if len(pending) > 0 {
+		return nil, fmt.Errorf("waiting until the network interfaces %s are detached and available", strings.Join(pending, ", "))
+	}
+	return results, nil
+}
+
+// DeleteENI deletes the network interface with the specific <id>. If it does not exist,
+// no error is returned.
+func (c *Client) DeleteENI(ctx context.Context, id string) error {
+	if _, err := c.EC2.DeleteNetworkInterfaceWithContext(ctx, &ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: aws.String(id)}); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "InvalidNetworkInterfaceID.NotFound" {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// ListKubernetesVolumes returns the IDs of the available (i.e., detached) EBS volumes tagged with <clusterName>.
+func (c *Client) ListKubernetesVolumes(ctx context.Context, clusterName string) ([]string, error) {
+	input := &ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String(fmt.Sprintf("tag:kubernetes.io/cluster/%s", clusterName)),
+				Values: []*string{aws.String("owned")},
+			},
+			{
+				Name:   aws.String("status"),
+				Values: []*string{aws.String(ec2.VolumeStateAvailable)},
+			},
+		},
+		MaxResults: aws.Int64(volumesPageSize),
+	}
+
+	var results []string
+	for {
+		output, err := c.EC2.DescribeVolumesWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, volume := range output.Volumes {
+			results = append(results, aws.StringValue(volume.VolumeId))
+		}
+
+		if aws.StringValue(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	return results, nil
+}
+
+// DeleteVolume deletes the EBS volume with the specific <id>. If it does not exist,
+// no error is returned.
+func (c *Client) DeleteVolume(ctx context.Context, id string) error {
+	if _, err := c.EC2.DeleteVolumeWithContext(ctx, &ec2.DeleteVolumeInput{VolumeId: aws.String(id)}); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "InvalidVolume.NotFound" {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// ListKubernetesSnapshots returns the IDs of the EBS snapshots of the own account tagged with <clusterName>.
+func (c *Client) ListKubernetesSnapshots(ctx context.Context, clusterName string) ([]string, error) {
+	input := &ec2.DescribeSnapshotsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String(fmt.Sprintf("tag:kubernetes.io/cluster/%s", clusterName)),
+				Values: []*string{aws.String("owned")},
+			},
+		},
+		OwnerIds:   []*string{aws.String("self")},
+		MaxResults: aws.Int64(snapshotsPageSize),
+	}
+
+	var results []string
+	for {
+		output, err := c.EC2.DescribeSnapshotsWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, snapshot := range output.Snapshots {
+			results = append(results, aws.StringValue(snapshot.SnapshotId))
+		}
+
+		if aws.StringValue(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	return results, nil
+}
+
+// DeleteSnapshot deletes the EBS snapshot with the specific <id>. If it does not exist,
+// no error is returned.
+func (c *Client) DeleteSnapshot(ctx context.Context, id string) error {
+	if _, err := c.EC2.DeleteSnapshotWithContext(ctx, &ec2.DeleteSnapshotInput{SnapshotId: aws.String(id)}); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "InvalidSnapshot.NotFound" {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
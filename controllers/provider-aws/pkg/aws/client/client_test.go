@@ -0,0 +1,513 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		ctx     context.Context
+		backend *fake.Backend
+		client  Interface
+
+		clusterName = "shoot--foo--bar"
+		clusterTag  = "kubernetes.io/cluster/" + clusterName
+		fakeErr     = errors.New("fake")
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		backend = fake.NewBackend()
+		client = fake.NewClient(backend)
+	})
+
+	Describe("#GetAccountID", func() {
+		It("should return the account ID of the caller", func() {
+			backend.SetAccountID("000000000042")
+
+			Expect(client.GetAccountID(ctx)).To(Equal("000000000042"))
+		})
+
+		It("should return the error of the API call", func() {
+			backend.InjectError("GetCallerIdentity", fakeErr)
+
+			_, err := client.GetAccountID(ctx)
+			Expect(err).To(Equal(fakeErr))
+		})
+	})
+
+	Describe("#GetInternetGateway", func() {
+		It("should return the internet gateway attached to the VPC", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			otherVPCID := backend.CreateVPC("10.251.0.0/16", nil)
+			backend.CreateInternetGateway(otherVPCID, nil)
+			igwID := backend.CreateInternetGateway(vpcID, nil)
+
+			Expect(client.GetInternetGateway(ctx, vpcID)).To(Equal(igwID))
+		})
+
+		It("should fail if no internet gateway is attached to the VPC", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			backend.CreateInternetGateway("", nil)
+
+			_, err := client.GetInternetGateway(ctx, vpcID)
+			Expect(err).To(MatchError(ContainSubstring("no attached internet gateway found")))
+		})
+	})
+
+	Describe("#GetSubnets", func() {
+		It("should return the subnets with the given ids", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			subnetID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.0.0/19", nil)
+			backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.32.0/19", nil)
+
+			Expect(client.GetSubnets(ctx, []string{subnetID})).To(Equal([]Subnet{
+				{ID: subnetID, VPCID: vpcID, AvailabilityZone: "eu-west-1a", CIDR: "10.250.0.0/19"},
+			}))
+		})
+
+		It("should fail if a subnet does not exist", func() {
+			_, err := client.GetSubnets(ctx, []string{"subnet-unknown"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#FindSubnets", func() {
+		It("should only return the tagged subnets of the VPC in the zone", func() {
+			purposeTag := map[string]string{"purpose": "nodes"}
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			otherVPCID := backend.CreateVPC("10.251.0.0/16", nil)
+			subnetID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.0.0/19", purposeTag)
+			backend.CreateSubnet(vpcID, "eu-west-1b", "10.250.32.0/19", purposeTag)
+			backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.64.0/19", map[string]string{"purpose": "public"})
+			backend.CreateSubnet(otherVPCID, "eu-west-1a", "10.251.0.0/19", purposeTag)
+
+			Expect(client.FindSubnets(ctx, vpcID, "eu-west-1a", purposeTag)).To(Equal([]Subnet{
+				{ID: subnetID, VPCID: vpcID, AvailabilityZone: "eu-west-1a", CIDR: "10.250.0.0/19", Tags: purposeTag},
+			}))
+		})
+	})
+
+	Describe("#CreateVPC", func() {
+		It("should create and tag the VPC", func() {
+			tags := map[string]string{"Name": clusterName, clusterTag: "1"}
+
+			vpcID, err := client.CreateVPC(ctx, "10.250.0.0/16", tags)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.FindVPCs(ctx, tags)).To(Equal([]VPC{
+				{ID: vpcID, CIDR: "10.250.0.0/16", DHCPOptionsID: "default", Tags: tags},
+			}))
+			Expect(backend.Calls("ModifyVpcAttribute")).To(Equal(2))
+		})
+	})
+
+	Describe("#FindRouteTables", func() {
+		It("should return the routes and associations of the tagged route tables", func() {
+			tags := map[string]string{"Name": clusterName}
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			subnetID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.0.0/19", nil)
+			igwID := backend.CreateInternetGateway(vpcID, nil)
+
+			routeTableID, err := client.CreateRouteTable(ctx, vpcID, tags)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.CreateRoute(ctx, routeTableID, Route{DestinationCIDR: "0.0.0.0/0", GatewayID: igwID})).To(Succeed())
+			Expect(client.AssociateRouteTable(ctx, routeTableID, subnetID)).To(Succeed())
+
+			routeTables, err := client.FindRouteTables(ctx, vpcID, tags)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routeTables).To(HaveLen(1))
+			Expect(routeTables[0].ID).To(Equal(routeTableID))
+			Expect(routeTables[0].Routes).To(ConsistOf(
+				Route{DestinationCIDR: "10.250.0.0/16", GatewayID: "local"},
+				Route{DestinationCIDR: "0.0.0.0/0", GatewayID: igwID},
+			))
+			Expect(routeTables[0].Associations).To(HaveLen(1))
+			Expect(routeTables[0].Associations[0].SubnetID).To(Equal(subnetID))
+		})
+	})
+
+	Describe("#AuthorizeSecurityGroupRules", func() {
+		It("should add missing rules and ignore existing ones", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			groupID, err := client.CreateSecurityGroup(ctx, vpcID, clusterName+"-nodes", "Security group for nodes", map[string]string{"Name": clusterName + "-nodes"})
+			Expect(err).NotTo(HaveOccurred())
+
+			rules := []SecurityGroupRule{
+				{Type: SecurityGroupRuleTypeIngress, Protocol: "-1", SecurityGroupID: groupID},
+				{Type: SecurityGroupRuleTypeIngress, Protocol: "tcp", FromPort: 30000, ToPort: 32767, CIDR: "0.0.0.0/0"},
+				{Type: SecurityGroupRuleTypeEgress, Protocol: "-1", CIDR: "0.0.0.0/0"},
+			}
+			Expect(client.AuthorizeSecurityGroupRules(ctx, groupID, rules)).To(Succeed())
+			Expect(client.AuthorizeSecurityGroupRules(ctx, groupID, rules)).To(Succeed())
+
+			groups, err := client.FindSecurityGroups(ctx, vpcID, map[string]string{"Name": clusterName + "-nodes"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0].Name).To(Equal(clusterName + "-nodes"))
+			Expect(groups[0].Rules).To(ConsistOf(rules))
+		})
+	})
+
+	Describe("#RevokeSecurityGroupRules", func() {
+		It("should remove the rules and ignore missing ones", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			groupID, err := client.CreateSecurityGroup(ctx, vpcID, clusterName+"-nodes", "Security group for nodes", map[string]string{"Name": clusterName + "-nodes"})
+			Expect(err).NotTo(HaveOccurred())
+
+			kept := SecurityGroupRule{Type: SecurityGroupRuleTypeIngress, Protocol: "tcp", FromPort: 30000, ToPort: 32767, CIDR: "0.0.0.0/0", Description: "kept"}
+			revoked := []SecurityGroupRule{
+				{Type: SecurityGroupRuleTypeIngress, Protocol: "-1", SecurityGroupID: groupID, Description: "revoked"},
+				{Type: SecurityGroupRuleTypeEgress, Protocol: "-1", CIDR: "0.0.0.0/0"},
+			}
+			Expect(client.AuthorizeSecurityGroupRules(ctx, groupID, append(revoked, kept))).To(Succeed())
+			Expect(client.RevokeSecurityGroupRules(ctx, groupID, revoked)).To(Succeed())
+			Expect(client.RevokeSecurityGroupRules(ctx, groupID, revoked)).To(Succeed())
+
+			groups, err := client.FindSecurityGroups(ctx, vpcID, map[string]string{"Name": clusterName + "-nodes"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0].Rules).To(ConsistOf(kept))
+		})
+	})
+
+	Describe("#CreateNATGateway", func() {
+		It("should create a NAT gateway that uses the Elastic IP", func() {
+			tags := map[string]string{"Name": clusterName + "-natgw-z0"}
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			subnetID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.96.0/22", nil)
+
+			address, err := client.AllocateElasticIP(ctx, map[string]string{"Name": clusterName + "-eip-natgw-z0"})
+			Expect(err).NotTo(HaveOccurred())
+			natGatewayID, err := client.CreateNATGateway(ctx, subnetID, address.AllocationID, tags)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.FindNATGateways(ctx, vpcID, tags)).To(Equal([]NATGateway{
+				{ID: natGatewayID, SubnetID: subnetID, AllocationID: address.AllocationID, State: "available", Tags: tags},
+			}))
+			Expect(client.ReleaseElasticIP(ctx, address.AllocationID)).NotTo(Succeed())
+
+			Expect(client.DeleteNATGateway(ctx, natGatewayID)).To(Succeed())
+			Expect(client.FindNATGateways(ctx, vpcID, tags)).To(BeEmpty())
+			Expect(client.ReleaseElasticIP(ctx, address.AllocationID)).To(Succeed())
+			Expect(client.GetElasticIP(ctx, address.AllocationID)).To(BeNil())
+		})
+	})
+
+	Describe("#DeleteVPCEndpoint", func() {
+		It("should not fail if the endpoint does not exist", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			endpointID, err := client.CreateVPCEndpoint(ctx, VPCEndpoint{VPCID: vpcID, ServiceName: "com.amazonaws.eu-west-1.s3", Type: "Gateway"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.DeleteVPCEndpoint(ctx, endpointID)).To(Succeed())
+			Expect(client.DeleteVPCEndpoint(ctx, endpointID)).To(Succeed())
+			Expect(client.FindVPCEndpoints(ctx, vpcID)).To(BeEmpty())
+		})
+	})
+
+	Describe("#CreateVPCPeeringConnection", func() {
+		It("should request, accept and delete a peering connection to a VPC of the same account", func() {
+			tags := map[string]string{"Name": clusterName + "-peering-shared"}
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			peerVPCID := backend.CreateVPC("10.0.0.0/16", nil)
+
+			id, err := client.CreateVPCPeeringConnection(ctx, VPCPeeringConnection{VPCID: vpcID, PeerVPCID: peerVPCID, Tags: tags})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.FindVPCPeeringConnections(ctx, vpcID, tags)).To(Equal([]VPCPeeringConnection{
+				{ID: id, VPCID: vpcID, PeerVPCID: peerVPCID, State: "pending-acceptance", Tags: tags},
+			}))
+
+			Expect(client.AcceptVPCPeeringConnection(ctx, id)).To(Succeed())
+			connections, err := client.FindVPCPeeringConnections(ctx, vpcID, tags)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(connections).To(HaveLen(1))
+			Expect(connections[0].State).To(Equal("active"))
+
+			Expect(client.DeleteVPCPeeringConnection(ctx, id)).To(Succeed())
+			Expect(client.DeleteVPCPeeringConnection(ctx, id)).To(Succeed())
+			Expect(client.FindVPCPeeringConnections(ctx, vpcID, tags)).To(BeEmpty())
+		})
+
+		It("should report the owner and region of the peer VPC if they differ", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+
+			id, err := client.CreateVPCPeeringConnection(ctx, VPCPeeringConnection{VPCID: vpcID, PeerVPCID: "vpc-other", PeerOwnerID: "210987654321", PeerRegion: "eu-central-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.FindVPCPeeringConnections(ctx, vpcID, nil)).To(Equal([]VPCPeeringConnection{
+				{ID: id, VPCID: vpcID, PeerVPCID: "vpc-other", PeerOwnerID: "210987654321", PeerRegion: "eu-central-1", State: "pending-acceptance"},
+			}))
+			Expect(client.AcceptVPCPeeringConnection(ctx, id)).NotTo(Succeed())
+		})
+	})
+
+	Describe("#ImportKeyPair", func() {
+		It("should import the key pair with the fingerprint of the public key", func() {
+			publicKey := "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAAAgQDk test@example.com"
+			fingerprint, err := PublicKeyFingerprint(publicKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fingerprint).To(MatchRegexp(`^([0-9a-f]{2}:){15}[0-9a-f]{2}$`))
+
+			Expect(client.GetKeyPair(ctx, clusterName+"-ssh-publickey")).To(BeNil())
+			Expect(client.ImportKeyPair(ctx, clusterName+"-ssh-publickey", publicKey)).To(Succeed())
+			Expect(client.GetKeyPair(ctx, clusterName+"-ssh-publickey")).To(Equal(&KeyPair{Name: clusterName + "-ssh-publickey", Fingerprint: fingerprint}))
+		})
+
+		It("should reject malformed public keys", func() {
+			_, err := PublicKeyFingerprint("ssh-rsa")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#DeleteRole", func() {
+		It("should delete the inline policies, the instance profile and the role", func() {
+			role, err := client.CreateRole(ctx, clusterName+"-nodes", "{}")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(role.ARN).To(Equal("arn:aws:iam::" + fake.DefaultAccountID + ":role/" + clusterName + "-nodes"))
+			Expect(client.PutRolePolicy(ctx, role.Name, role.Name, "{}")).To(Succeed())
+			Expect(client.CreateInstanceProfile(ctx, role.Name)).To(Succeed())
+			Expect(client.AddRoleToInstanceProfile(ctx, role.Name, role.Name)).To(Succeed())
+			Expect(client.GetInstanceProfile(ctx, role.Name)).To(Equal(&InstanceProfile{Name: role.Name, RoleNames: []string{role.Name}}))
+
+			Expect(client.DeleteInstanceProfile(ctx, role.Name)).To(Succeed())
+			Expect(client.DeleteRole(ctx, role.Name)).To(Succeed())
+			Expect(client.DeleteRole(ctx, role.Name)).To(Succeed())
+			Expect(client.GetRole(ctx, role.Name)).To(BeNil())
+			Expect(backend.InstanceProfileNames()).To(BeEmpty())
+		})
+	})
+
+	Describe("#DeleteUser", func() {
+		It("should delete the access keys, the inline policies and the user", func() {
+			name := clusterName + "-cloud-controller-manager"
+			user, err := client.CreateUser(ctx, name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.ARN).To(Equal("arn:aws:iam::" + fake.DefaultAccountID + ":user/" + name))
+			Expect(client.GetUser(ctx, name)).To(Equal(user))
+			Expect(client.PutUserPolicy(ctx, name, name, "{}")).To(Succeed())
+			accessKey, err := client.CreateAccessKey(ctx, name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accessKey.Secret).NotTo(BeEmpty())
+			Expect(client.ListAccessKeys(ctx, name)).To(ConsistOf(accessKey.ID))
+
+			Expect(client.DeleteUser(ctx, name)).To(Succeed())
+			Expect(client.DeleteUser(ctx, name)).To(Succeed())
+			Expect(client.GetUser(ctx, name)).To(BeNil())
+			Expect(backend.AccessKeys(name)).To(BeEmpty())
+		})
+	})
+
+	Describe("#SimulatePrincipalPolicy", func() {
+		It("should only return the actions that are not allowed", func() {
+			backend.DenyActions("iam:CreateUser")
+
+			Expect(client.SimulatePrincipalPolicy(ctx, "arn:aws:iam::"+fake.DefaultAccountID+":user/gardener", []string{"ec2:CreateVpc", "iam:CreateUser"})).To(ConsistOf("iam:CreateUser"))
+		})
+	})
+
+	Describe("#GetAccountAttributes", func() {
+		It("should return the values of the requested attributes", func() {
+			Expect(client.GetAccountAttributes(ctx, "vpc-max-elastic-ips")).To(Equal(map[string][]string{"vpc-max-elastic-ips": {fake.DefaultMaxElasticIPs}}))
+		})
+	})
+
+	Describe("#ListKubernetesELBs", func() {
+		It("should only return owned load balancers in the VPC", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			otherVPCID := backend.CreateVPC("10.251.0.0/16", nil)
+			backend.CreateLoadBalancer("owned", vpcID, map[string]string{clusterTag: "owned"})
+			backend.CreateLoadBalancer("shared", vpcID, map[string]string{clusterTag: "shared"})
+			backend.CreateLoadBalancer("untagged", vpcID, nil)
+			backend.CreateLoadBalancer("other-cluster", vpcID, map[string]string{"kubernetes.io/cluster/other": "owned"})
+			backend.CreateLoadBalancer("other-vpc", otherVPCID, map[string]string{clusterTag: "owned"})
+
+			Expect(client.ListKubernetesELBs(ctx, vpcID, clusterName)).To(ConsistOf("owned"))
+		})
+
+		It("should page through all load balancers and batch the tag lookups", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			otherVPCID := backend.CreateVPC("10.251.0.0/16", nil)
+
+			var owned []string
+			for i := 0; i < 450; i++ {
+				name := fmt.Sprintf("lb-%03d", i)
+				switch {
+				case i >= 300:
+					backend.CreateLoadBalancer(name, otherVPCID, map[string]string{clusterTag: "owned"})
+				case i%50 == 0:
+					backend.CreateLoadBalancer(name, vpcID, map[string]string{clusterTag: "owned"})
+					owned = append(owned, name)
+				default:
+					backend.CreateLoadBalancer(name, vpcID, nil)
+				}
+			}
+
+			Expect(client.ListKubernetesELBs(ctx, vpcID, clusterName)).To(ConsistOf(owned))
+			Expect(backend.Calls("DescribeLoadBalancers")).To(Equal(2))
+			// 300 of the 450 load balancers are in the VPC, their tags are described in batches of 20.
+			Expect(backend.Calls("DescribeTags")).To(Equal(15))
+		})
+
+		It("should not describe any tags if there is no load balancer in the VPC", func() {
+			backend.CreateLoadBalancer("other-vpc", "vpc-other", map[string]string{clusterTag: "owned"})
+
+			Expect(client.ListKubernetesELBs(ctx, "vpc", clusterName)).To(BeEmpty())
+			Expect(backend.Calls("DescribeTags")).To(BeZero())
+		})
+
+		It("should return the error of the API call", func() {
+			backend.InjectError("DescribeLoadBalancers", fakeErr)
+
+			_, err := client.ListKubernetesELBs(ctx, "vpc", clusterName)
+			Expect(err).To(Equal(fakeErr))
+		})
+	})
+
+	Describe("#ListKubernetesSecurityGroups", func() {
+		It("should only return owned security groups in the VPC", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			otherVPCID := backend.CreateVPC("10.251.0.0/16", nil)
+			ownedID := backend.CreateSecurityGroup(vpcID, "owned", map[string]string{clusterTag: "owned"})
+			backend.CreateSecurityGroup(vpcID, "shared", map[string]string{clusterTag: "shared", "kubernetes.io/cluster/other": "owned"})
+			backend.CreateSecurityGroup(vpcID, "untagged", nil)
+			backend.CreateSecurityGroup(otherVPCID, "other-vpc", map[string]string{clusterTag: "owned"})
+
+			Expect(client.ListKubernetesSecurityGroups(ctx, vpcID, clusterName)).To(ConsistOf(ownedID))
+		})
+
+		It("should page through all security groups", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+
+			var owned []string
+			for i := 0; i < 1500; i++ {
+				owned = append(owned, backend.CreateSecurityGroup(vpcID, fmt.Sprintf("sg-%04d", i), map[string]string{clusterTag: "owned"}))
+			}
+
+			Expect(client.ListKubernetesSecurityGroups(ctx, vpcID, clusterName)).To(Equal(owned))
+			Expect(backend.Calls("DescribeSecurityGroups")).To(Equal(2))
+		})
+	})
+
+	Describe("#DeleteELB", func() {
+		It("should delete the load balancer", func() {
+			backend.CreateLoadBalancer("lb", "vpc", nil)
+
+			Expect(client.DeleteELB(ctx, "lb")).To(Succeed())
+			Expect(backend.LoadBalancerNames()).To(BeEmpty())
+		})
+
+		It("should succeed if the load balancer does not exist", func() {
+			Expect(client.DeleteELB(ctx, "lb")).To(Succeed())
+		})
+	})
+
+	Describe("#DeleteSecurityGroup", func() {
+		It("should delete the security group", func() {
+			id := backend.CreateSecurityGroup("vpc", "group", nil)
+
+			Expect(client.DeleteSecurityGroup(ctx, id)).To(Succeed())
+			Expect(backend.SecurityGroupIDs()).To(BeEmpty())
+		})
+
+		It("should succeed if the security group does not exist", func() {
+			Expect(client.DeleteSecurityGroup(ctx, "sg-unknown")).To(Succeed())
+		})
+
+		It("should return other errors", func() {
+			backend.InjectError("DeleteSecurityGroup", fakeErr)
+
+			Expect(client.DeleteSecurityGroup(ctx, "sg-unknown")).To(Equal(fakeErr))
+		})
+	})
+
+	Describe("#ListKubernetesNLBs", func() {
+		It("should only return owned network load balancers in the VPC", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			ownedARN := backend.CreateNetworkLoadBalancer("owned", vpcID, map[string]string{clusterTag: "owned"})
+			backend.CreateNetworkLoadBalancer("untagged", vpcID, nil)
+			backend.CreateNetworkLoadBalancer("other-vpc", "vpc-other", map[string]string{clusterTag: "owned"})
+
+			Expect(client.ListKubernetesNLBs(ctx, vpcID, clusterName)).To(ConsistOf(ownedARN))
+		})
+	})
+
+	Describe("#DeleteNLB", func() {
+		It("should succeed if the network load balancer does not exist", func() {
+			Expect(client.DeleteNLB(ctx, "arn:aws:elasticloadbalancing:eu-west-1:123456789012:loadbalancer/net/lb/1")).To(Succeed())
+		})
+	})
+
+	Describe("#ListKubernetesTargetGroups", func() {
+		It("should only return owned target groups in the VPC", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			ownedARN := backend.CreateTargetGroup("owned", vpcID, "", map[string]string{clusterTag: "owned"})
+			backend.CreateTargetGroup("shared", vpcID, "", map[string]string{clusterTag: "shared"})
+			backend.CreateTargetGroup("other-vpc", "vpc-other", "", map[string]string{clusterTag: "owned"})
+
+			Expect(client.ListKubernetesTargetGroups(ctx, vpcID, clusterName)).To(ConsistOf(ownedARN))
+		})
+	})
+
+	Describe("#DeleteTargetGroup", func() {
+		It("should return the error if the target group is still in use", func() {
+			arn := backend.CreateTargetGroup("owned", "vpc", backend.CreateNetworkLoadBalancer("lb", "vpc", nil), nil)
+
+			Expect(client.DeleteTargetGroup(ctx, arn)).NotTo(Succeed())
+		})
+
+		It("should succeed if the target group does not exist", func() {
+			Expect(client.DeleteTargetGroup(ctx, "arn:aws:elasticloadbalancing:eu-west-1:123456789012:targetgroup/tg/1")).To(Succeed())
+		})
+	})
+
+	Describe("#ListKubernetesENIs", func() {
+		It("should return the network interfaces tagged by the cloud provider or the VPC CNI in the VPC", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			ownedID := backend.CreateNetworkInterface(vpcID, nil, map[string]string{clusterTag: "owned"})
+			cniID := backend.CreateNetworkInterface(vpcID, nil, map[string]string{"cluster.k8s.amazonaws.com/name": clusterName})
+			bothID := backend.CreateNetworkInterface(vpcID, nil, map[string]string{clusterTag: "owned", "cluster.k8s.amazonaws.com/name": clusterName})
+			backend.CreateNetworkInterface(vpcID, nil, map[string]string{"cluster.k8s.amazonaws.com/name": "other"})
+			backend.CreateNetworkInterface("vpc-other", nil, map[string]string{clusterTag: "owned"})
+
+			Expect(client.ListKubernetesENIs(ctx, vpcID, clusterName)).To(ConsistOf(ownedID, cniID, bothID))
+		})
+
+		It("should fail while a network interface is still attached", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			backend.CreateNetworkInterface(vpcID, nil, map[string]string{clusterTag: "owned"})
+			attachedID := backend.CreateNetworkInterface(vpcID, nil, map[string]string{"cluster.k8s.amazonaws.com/name": clusterName})
+			backend.AttachNetworkInterface(attachedID, "i-12345")
+
+			_, err := client.ListKubernetesENIs(ctx, vpcID, clusterName)
+			Expect(err).To(MatchError(ContainSubstring(attachedID)))
+		})
+	})
+
+	Describe("#DeleteENI", func() {
+		It("should succeed if the network interface does not exist", func() {
+			Expect(client.DeleteENI(ctx, "eni-unknown")).To(Succeed())
+		})
+	})
+})
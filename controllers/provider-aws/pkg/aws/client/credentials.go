@@ -0,0 +1,237 @@
+// Copyright (c) 2018 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// RoleSessionName is the session name used when the extension assumes an AWS role.
+const RoleSessionName = "gardener-extension-provider-aws"
+
+const (
+	// webIdentityProviderName is the name of the credentials provider assuming a role with a web identity token.
+	webIdentityProviderName = "WebIdentityProvider"
+	// webIdentityExpiryWindow is the time before their expiration at which the credentials of the web identity
+	// are refreshed.
+	webIdentityExpiryWindow = time.Minute
+)
+
+var (
+	// roleARNRegex matches the ARN of an IAM role. The role ARN of a cloud provider secret is rendered into the
+	// `assume_role` block of the Terraform configuration, so anything else must be rejected.
+	roleARNRegex = regexp.MustCompile(`^arn:aws[a-z-]*:iam::\d{12}:role/[\w+=,.@/-]+$`)
+	// externalIDRegex matches the characters STS allows for the external id of an AssumeRole call.
+	externalIDRegex = regexp.MustCompile(`^[\w+=,.@:/-]+$`)
+)
+
+const (
+	// minExternalIDLength is the minimum length of an external id allowed by STS.
+	minExternalIDLength = 2
+	// maxExternalIDLength is the maximum length of an external id allowed by STS.
+	maxExternalIDLength = 1224
+)
+
+// WebIdentity is the own identity of the extension: an AWS role that is assumed with the web identity token
+// stored in a file, e.g. a projected service account token.
+type WebIdentity struct {
+	// RoleARN is the ARN of the role that is assumed.
+	RoleARN string
+	// TokenFile is the path of the file containing the web identity token.
+	TokenFile string
+}
+
+// IsSet returns true if the web identity is configured.
+func (w WebIdentity) IsSet() bool {
+	return w.RoleARN != "" && w.TokenFile != ""
+}
+
+// Credentials are the credentials the AWS clients authenticate with. The access key is used if it is set,
+// otherwise the web identity of the extension. If a role ARN is set, this role is assumed via STS with these
+// credentials before calling any other AWS service.
+type Credentials struct {
+	// AccessKeyID is the id of a static access key.
+	AccessKeyID string
+	// SecretAccessKey is the secret of a static access key.
+	SecretAccessKey string
+	// RoleARN is the optional ARN of a role that is assumed, e.g. a cross-account role granted by the owner of
+	// the AWS account.
+	RoleARN string
+	// ExternalID is the optional external id passed when assuming the role.
+	ExternalID string
+	// WebIdentity is the web identity of the extension that is used if no access key is set.
+	WebIdentity WebIdentity
+}
+
+// CredentialsFromSecretData reads the credentials from the data of a cloud provider secret. It returns an error if
+// the role ARN or the external id are malformed.
+func CredentialsFromSecretData(data map[string][]byte) (Credentials, error) {
+	value := func(key string) string {
+		return strings.TrimSpace(string(data[key]))
+	}
+
+	credentials := Credentials{
+		AccessKeyID:     value(aws.AccessKeyID),
+		SecretAccessKey: value(aws.SecretAccessKey),
+		RoleARN:         value(aws.RoleARN),
+		ExternalID:      value(aws.ExternalID),
+	}
+	if credentials.RoleARN != "" && !roleARNRegex.MatchString(credentials.RoleARN) {
+		return Credentials{}, fmt.Errorf("the %s of the cloud provider secret is not a valid IAM role ARN", aws.RoleARN)
+	}
+	if id := credentials.ExternalID; id != "" && (len(id) < minExternalIDLength || len(id) > maxExternalIDLength || !externalIDRegex.MatchString(id)) {
+		return Credentials{}, fmt.Errorf("the %s of the cloud provider secret must consist of %d to %d letters, digits or any of _+=,.@:/-", aws.ExternalID, minExternalIDLength, maxExternalIDLength)
+	}
+	return credentials, nil
+}
+
+// Validate checks that the Credentials contain either a complete access key or a web identity, and that an
+// external id is only set together with a role ARN.
+func (c Credentials) Validate() error {
+	if (c.AccessKeyID == "") != (c.SecretAccessKey == "") {
+		return errors.New("the access key id and the secret access key must be set together")
+	}
+	if c.AccessKeyID == "" && !c.WebIdentity.IsSet() {
+		return errors.New("no access key is set and no web identity is configured")
+	}
+	if c.ExternalID != "" && c.RoleARN == "" {
+		return errors.New("the external id requires a role ARN")
+	}
+	return nil
+}
+
+// BaseValue retrieves the credentials that are used to assume the role. These are the access key, or the
+// temporary credentials of the web identity if no access key is set.
+func (c Credentials) BaseValue(region string, endpoints Endpoints) (credentials.Value, error) {
+	if err := c.Validate(); err != nil {
+		return credentials.Value{}, err
+	}
+
+	baseCredentials, err := c.baseCredentials(region, endpoints)
+	if err != nil {
+		return credentials.Value{}, err
+	}
+	return baseCredentials.Get()
+}
+
+// newSession creates a session authenticating with the Credentials in the given region.
+func (c Credentials) newSession(region string, endpoints Endpoints) (*session.Session, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	baseCredentials, err := c.baseCredentials(region, endpoints)
+	if err != nil {
+		return nil, err
+	}
+	if c.RoleARN == "" {
+		return newTracedSession(baseCredentials)
+	}
+
+	baseSession, err := newTracedSession(baseCredentials)
+	if err != nil {
+		return nil, err
+	}
+	assumeRoleClient := sts.New(baseSession, &awssdk.Config{Region: awssdk.String(region)}, endpointConfig(endpoints.STS))
+
+	return newTracedSession(stscreds.NewCredentialsWithClient(assumeRoleClient, c.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+		p.RoleSessionName = RoleSessionName
+		if c.ExternalID != "" {
+			p.ExternalID = awssdk.String(c.ExternalID)
+		}
+	}))
+}
+
+// baseCredentials returns the static credentials of the access key, or the credentials of the web identity if
+// no access key is set.
+func (c Credentials) baseCredentials(region string, endpoints Endpoints) (*credentials.Credentials, error) {
+	if c.AccessKeyID != "" {
+		return credentials.NewStaticCredentials(c.AccessKeyID, c.SecretAccessKey, ""), nil
+	}
+
+	// AssumeRoleWithWebIdentity calls are not signed, the web identity token is the only proof of identity.
+	anonymousSession, err := newTracedSession(credentials.AnonymousCredentials)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewCredentials(&webIdentityProvider{
+		client:    sts.New(anonymousSession, &awssdk.Config{Region: awssdk.String(region)}, endpointConfig(endpoints.STS)),
+		roleARN:   c.WebIdentity.RoleARN,
+		tokenFile: c.WebIdentity.TokenFile,
+	}), nil
+}
+
+// newTracedSession creates a session with the given credentials whose AWS API calls are traced.
+func newTracedSession(creds *credentials.Credentials) (*session.Session, error) {
+	s, err := session.NewSession(&awssdk.Config{Credentials: creds})
+	if err != nil {
+		return nil, err
+	}
+	addTracingHandlers(&s.Handlers)
+	return s, nil
+}
+
+// webIdentityRoleAssumer is the part of the STS client used by the webIdentityProvider.
+type webIdentityRoleAssumer interface {
+	AssumeRoleWithWebIdentity(*sts.AssumeRoleWithWebIdentityInput) (*sts.AssumeRoleWithWebIdentityOutput, error)
+}
+
+// webIdentityProvider retrieves temporary credentials by assuming a role with the web identity token read from
+// a file. The file is read again on every retrieval because the token is rotated.
+type webIdentityProvider struct {
+	credentials.Expiry
+
+	client    webIdentityRoleAssumer
+	roleARN   string
+	tokenFile string
+}
+
+// Retrieve implements credentials.Provider.
+func (p *webIdentityProvider) Retrieve() (credentials.Value, error) {
+	token, err := ioutil.ReadFile(p.tokenFile)
+	if err != nil {
+		return credentials.Value{ProviderName: webIdentityProviderName}, err
+	}
+
+	output, err := p.client.AssumeRoleWithWebIdentity(&sts.AssumeRoleWithWebIdentityInput{
+		RoleArn:          awssdk.String(p.roleARN),
+		RoleSessionName:  awssdk.String(RoleSessionName),
+		WebIdentityToken: awssdk.String(strings.TrimSpace(string(token))),
+	})
+	if err != nil {
+		return credentials.Value{ProviderName: webIdentityProviderName}, err
+	}
+
+	p.SetExpiration(awssdk.TimeValue(output.Credentials.Expiration), webIdentityExpiryWindow)
+	return credentials.Value{
+		AccessKeyID:     awssdk.StringValue(output.Credentials.AccessKeyId),
+		SecretAccessKey: awssdk.StringValue(output.Credentials.SecretAccessKey),
+		SessionToken:    awssdk.StringValue(output.Credentials.SessionToken),
+		ProviderName:    webIdentityProviderName,
+	}, nil
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2018 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
+	. "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Credentials", func() {
+	var webIdentity = WebIdentity{RoleARN: "arn:aws:iam::111111111111:role/extension", TokenFile: "/var/run/secrets/token"}
+
+	Describe("#CredentialsFromSecretData", func() {
+		It("should read the trimmed credentials", func() {
+			credentials, err := CredentialsFromSecretData(map[string][]byte{
+				aws.AccessKeyID:     []byte("id\n"),
+				aws.SecretAccessKey: []byte("secret"),
+				aws.RoleARN:         []byte("arn:aws:iam::123456789012:role/gardener"),
+				aws.ExternalID:      []byte(" external "),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentials).To(Equal(Credentials{
+				AccessKeyID:     "id",
+				SecretAccessKey: "secret",
+				RoleARN:         "arn:aws:iam::123456789012:role/gardener",
+				ExternalID:      "external",
+			}))
+		})
+
+		DescribeTable("should validate the role ARN and the external id",
+			func(roleARN, externalID string, matcher OmegaMatcher) {
+				_, err := CredentialsFromSecretData(map[string][]byte{
+					aws.AccessKeyID:     []byte("id"),
+					aws.SecretAccessKey: []byte("secret"),
+					aws.RoleARN:         []byte(roleARN),
+					aws.ExternalID:      []byte(externalID),
+				})
+				Expect(err).To(matcher)
+			},
+			Entry("role with path", "arn:aws:iam::123456789012:role/path/gardener", "", Succeed()),
+			Entry("role in another partition", "arn:aws-cn:iam::123456789012:role/gardener", "external:id", Succeed()),
+			Entry("user instead of role", "arn:aws:iam::123456789012:user/gardener", "", HaveOccurred()),
+			Entry("account id too short", "arn:aws:iam::1234:role/gardener", "", HaveOccurred()),
+			Entry("role ARN with quotes", `arn:aws:iam::123456789012:role/x"\n}\nresource "aws_iam_user" "u" {\n  name = "x`, "", HaveOccurred()),
+			Entry("role ARN with interpolation", "arn:aws:iam::123456789012:role/${file(\"/etc/passwd\")}", "", HaveOccurred()),
+			Entry("external id with quotes", "arn:aws:iam::123456789012:role/gardener", `x" }\nprovider "aws" { alias = "y`, HaveOccurred()),
+			Entry("external id with interpolation", "arn:aws:iam::123456789012:role/gardener", "${var.ACCESS_KEY_ID}", HaveOccurred()),
+			Entry("external id too short", "arn:aws:iam::123456789012:role/gardener", "x", HaveOccurred()),
+			Entry("external id too long", "arn:aws:iam::123456789012:role/gardener", strings.Repeat("x", 1225), HaveOccurred()),
+		)
+	})
+
+	DescribeTable("#Validate",
+		func(credentials Credentials, matcher OmegaMatcher) {
+			Expect(credentials.Validate()).To(matcher)
+		},
+		Entry("access key", Credentials{AccessKeyID: "id", SecretAccessKey: "secret"}, Succeed()),
+		Entry("web identity and role", Credentials{WebIdentity: webIdentity, RoleARN: "arn", ExternalID: "external"}, Succeed()),
+		Entry("incomplete access key", Credentials{AccessKeyID: "id", WebIdentity: webIdentity}, HaveOccurred()),
+		Entry("neither access key nor web identity", Credentials{RoleARN: "arn"}, HaveOccurred()),
+		Entry("external id without role", Credentials{AccessKeyID: "id", SecretAccessKey: "secret", ExternalID: "external"}, HaveOccurred()),
+	)
+
+	Describe("#NewClientWithCredentials", func() {
+		var (
+			server    *httptest.Server
+			requests  []*http.Request
+			tokenFile string
+			tmpDir    string
+		)
+
+		BeforeEach(func() {
+			requests = nil
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				Expect(r.ParseForm()).To(Succeed())
+				requests = append(requests, r)
+
+				action := r.Form.Get("Action")
+				switch action {
+				case "AssumeRoleWithWebIdentity":
+					w.Write([]byte(stsCredentialsResponse(action, "ASIAWEBIDENTITY")))
+				case "AssumeRole":
+					w.Write([]byte(stsCredentialsResponse(action, "ASIAROLE")))
+				case "GetCallerIdentity":
+					w.Write([]byte(`<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetCallerIdentityResult>
+    <Account>123456789012</Account>
+    <Arn>arn:aws:sts::123456789012:assumed-role/gardener/gardener-extension-provider-aws</Arn>
+    <UserId>AROA1:gardener-extension-provider-aws</UserId>
+  </GetCallerIdentityResult>
+</GetCallerIdentityResponse>`))
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+
+			var err error
+			tmpDir, err = ioutil.TempDir("", "web-identity")
+			Expect(err).NotTo(HaveOccurred())
+			tokenFile = filepath.Join(tmpDir, "token")
+			Expect(ioutil.WriteFile(tokenFile, []byte("service-account-token\n"), 0600)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			server.Close()
+			Expect(os.RemoveAll(tmpDir)).To(Succeed())
+		})
+
+		It("should assume the role with the web identity token", func() {
+			c, err := NewClientWithCredentials(Credentials{
+				RoleARN:     "arn:aws:iam::123456789012:role/gardener",
+				ExternalID:  "external",
+				WebIdentity: WebIdentity{RoleARN: "arn:aws:iam::111111111111:role/extension", TokenFile: tokenFile},
+			}, "eu-west-1", Endpoints{STS: server.URL})
+			Expect(err).NotTo(HaveOccurred())
+
+			accountID, err := c.GetAccountID(context.TODO())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accountID).To(Equal("123456789012"))
+
+			Expect(requests).To(HaveLen(3))
+
+			Expect(requests[0].Form.Get("Action")).To(Equal("AssumeRoleWithWebIdentity"))
+			Expect(requests[0].Form.Get("RoleArn")).To(Equal("arn:aws:iam::111111111111:role/extension"))
+			Expect(requests[0].Form.Get("WebIdentityToken")).To(Equal("service-account-token"))
+			Expect(requests[0].Header.Get("Authorization")).To(BeEmpty())
+
+			Expect(requests[1].Form.Get("Action")).To(Equal("AssumeRole"))
+			Expect(requests[1].Form.Get("RoleArn")).To(Equal("arn:aws:iam::123456789012:role/gardener"))
+			Expect(requests[1].Form.Get("RoleSessionName")).To(Equal(RoleSessionName))
+			Expect(requests[1].Form.Get("ExternalId")).To(Equal("external"))
+			Expect(requests[1].Header.Get("Authorization")).To(ContainSubstring("Credential=ASIAWEBIDENTITY/"))
+
+			Expect(requests[2].Form.Get("Action")).To(Equal("GetCallerIdentity"))
+			Expect(requests[2].Header.Get("Authorization")).To(ContainSubstring("Credential=ASIAROLE/"))
+		})
+
+		It("should sign with the access key if no role is set", func() {
+			c, err := NewClientWithCredentials(Credentials{AccessKeyID: "AKIASTATIC", SecretAccessKey: "secret"}, "eu-west-1", Endpoints{STS: server.URL})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = c.GetAccountID(context.TODO())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Header.Get("Authorization")).To(ContainSubstring("Credential=AKIASTATIC/"))
+		})
+
+		It("should return the temporary credentials of the web identity as base value", func() {
+			value, err := Credentials{
+				RoleARN:     "arn:aws:iam::123456789012:role/gardener",
+				WebIdentity: WebIdentity{RoleARN: "arn:aws:iam::111111111111:role/extension", TokenFile: tokenFile},
+			}.BaseValue("eu-west-1", Endpoints{STS: server.URL})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(value.AccessKeyID).To(Equal("ASIAWEBIDENTITY"))
+			Expect(value.SessionToken).To(Equal("ASIAWEBIDENTITY-token"))
+			Expect(requests).To(HaveLen(1))
+		})
+
+		It("should fail for invalid credentials", func() {
+			_, err := NewClientWithCredentials(Credentials{RoleARN: "arn:aws:iam::123456789012:role/gardener"}, "eu-west-1", Endpoints{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+func stsCredentialsResponse(action, accessKeyID string) string {
+	return strings.NewReplacer("ACTION", action, "ACCESSKEYID", accessKeyID).Replace(`<ACTIONResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <ACTIONResult>
+    <Credentials>
+      <AccessKeyId>ACCESSKEYID</AccessKeyId>
+      <SecretAccessKey>ACCESSKEYID-secret</SecretAccessKey>
+      <SessionToken>ACCESSKEYID-token</SessionToken>
+      <Expiration>2099-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </ACTIONResult>
+</ACTIONResponse>`)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+)
+
+// Endpoints are optional overrides of the AWS service endpoints, e.g. for AWS partitions whose endpoints are
+// not known to the SDK or for AWS-compatible APIs like LocalStack. Empty values mean the default endpoint.
+type Endpoints struct {
+	// EC2 is the endpoint of the EC2 service.
+	EC2 string
+	// ELB is the endpoint of the ELB service.
+	ELB string
+	// STS is the endpoint of the STS service.
+	STS string
+	// IAM is the endpoint of the IAM service.
+	IAM string
+}
+
+// EndpointsFromSecretData reads the endpoint overrides from the data of a cloud provider secret.
+func EndpointsFromSecretData(data map[string][]byte) Endpoints {
+	return Endpoints{
+		EC2: string(data[aws.EC2Endpoint]),
+		ELB: string(data[aws.ELBEndpoint]),
+		STS: string(data[aws.STSEndpoint]),
+		IAM: string(data[aws.IAMEndpoint]),
+	}
+}
+
+// Merge returns a copy of the Endpoints in which every endpoint set in <overrides> replaces the own one.
+func (e Endpoints) Merge(overrides Endpoints) Endpoints {
+	for _, endpoint := range []struct {
+		value    *string
+		override string
+	}{
+		{&e.EC2, overrides.EC2},
+		{&e.ELB, overrides.ELB},
+		{&e.STS, overrides.STS},
+		{&e.IAM, overrides.IAM},
+	} {
+		if endpoint.override != "" {
+			*endpoint.value = endpoint.override
+		}
+	}
+	return e
+}
+
+// Validate checks that all set endpoints are absolute URLs.
+func (e Endpoints) Validate() error {
+	for service, endpoint := range map[string]string{"EC2": e.EC2, "ELB": e.ELB, "STS": e.STS, "IAM": e.IAM} {
+		if endpoint == "" {
+			continue
+		}
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid %s endpoint %q: %v", service, endpoint, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid %s endpoint %q: must be an absolute URL", service, endpoint)
+		}
+	}
+	return nil
+}
+
+// TerraformValues returns the endpoints as values for the `endpoints` block of the Terraform AWS provider.
+// Only set endpoints are contained.
+func (e Endpoints) TerraformValues() map[string]interface{} {
+	values := map[string]interface{}{}
+	for service, endpoint := range map[string]string{"ec2": e.EC2, "elb": e.ELB, "sts": e.STS, "iam": e.IAM} {
+		if endpoint != "" {
+			values[service] = endpoint
+		}
+	}
+	return values
+}
+
+// endpointConfig returns an AWS config overriding the endpoint with the given one, if it is set.
+func endpointConfig(endpoint string) *awssdk.Config {
+	if endpoint == "" {
+		return &awssdk.Config{}
+	}
+	return &awssdk.Config{Endpoint: awssdk.String(endpoint)}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	. "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Endpoints", func() {
+	Describe("#Validate", func() {
+		It("should accept empty and absolute endpoints", func() {
+			Expect(Endpoints{EC2: "http://localstack:4566"}.Validate()).To(Succeed())
+		})
+
+		It("should reject relative endpoints", func() {
+			Expect(Endpoints{STS: "sts.amazonaws.com"}.Validate()).To(MatchError(ContainSubstring("STS endpoint")))
+		})
+	})
+
+	Describe("#TerraformValues", func() {
+		It("should only contain the set endpoints", func() {
+			Expect(Endpoints{ELB: "http://localhost:4566", IAM: "http://localhost:4593"}.TerraformValues()).To(Equal(map[string]interface{}{
+				"elb": "http://localhost:4566",
+				"iam": "http://localhost:4593",
+			}))
+		})
+	})
+
+	Describe("#NewClientWithEndpoints", func() {
+		It("should fail for invalid endpoints", func() {
+			_, err := NewClientWithEndpoints("id", "secret", "eu-west-1", Endpoints{EC2: "::"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,726 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fake provides an in-memory AWS backend that implements the parts of the EC2, ELB, STS and IAM APIs
+// used by the provider-aws client. It allows exercising client and actuator code without network access.
+package fake
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	awsclient "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// DefaultAccountID is the account ID a new Backend reports via STS.
+const DefaultAccountID = "123456789012"
+
+// DefaultMaxElasticIPs is the maximum number of Elastic IPs a new Backend reports via the EC2 account attributes.
+const DefaultMaxElasticIPs = "5"
+
+// DefaultMaxSecurityGroupsPerInterface is the maximum number of security groups per network interface a new Backend
+// reports via the EC2 account attributes.
+const DefaultMaxSecurityGroupsPerInterface = "5"
+
+// Backend is an in-memory store of AWS resources. It is safe for concurrent use.
+type Backend struct {
+	lock sync.Mutex
+
+	accountID         string
+	accountAttributes map[string][]string
+	deniedActions     map[string]bool
+	nextID            int
+
+	vpcs             map[string]*ec2.Vpc
+	internetGateways map[string]*ec2.InternetGateway
+	subnets          map[string]*ec2.Subnet
+	securityGroups   map[string]*ec2.SecurityGroup
+	loadBalancers    map[string]*elb.LoadBalancerDescription
+	loadBalancerTags map[string][]*elb.Tag
+
+	networkInterfaces    map[string]*ec2.NetworkInterface
+	volumes              map[string]*ec2.Volume
+	snapshots            map[string]*ec2.Snapshot
+	networkLoadBalancers map[string]*elbv2.LoadBalancer
+	targetGroups         map[string]*elbv2.TargetGroup
+	elbv2Tags            map[string][]*elbv2.Tag
+
+	dhcpOptions      map[string]*ec2.DhcpOptions
+	routeTables      map[string]*ec2.RouteTable
+	addresses        map[string]*ec2.Address
+	natGateways      map[string]*ec2.NatGateway
+	vpcEndpoints     map[string]*ec2.VpcEndpoint
+	vpcPeerings      map[string]*ec2.VpcPeeringConnection
+	keyPairs         map[string]*ec2.KeyPairInfo
+	roles            map[string]*iam.Role
+	rolePolicies     map[string]map[string]string
+	instanceProfiles map[string]*iam.InstanceProfile
+	users            map[string]*iam.User
+	userPolicies     map[string]map[string]string
+	accessKeys       map[string]*iam.AccessKey
+
+	errors map[string]error
+	calls  map[string]int
+}
+
+// NewBackend creates a new, empty Backend.
+func NewBackend() *Backend {
+	return &Backend{
+		accountID:        DefaultAccountID,
+		deniedActions:    make(map[string]bool),
+		vpcs:             make(map[string]*ec2.Vpc),
+		internetGateways: make(map[string]*ec2.InternetGateway),
+		subnets:          make(map[string]*ec2.Subnet),
+		securityGroups:   make(map[string]*ec2.SecurityGroup),
+		loadBalancers:    make(map[string]*elb.LoadBalancerDescription),
+		loadBalancerTags: make(map[string][]*elb.Tag),
+
+		networkInterfaces:    make(map[string]*ec2.NetworkInterface),
+		volumes:              make(map[string]*ec2.Volume),
+		snapshots:            make(map[string]*ec2.Snapshot),
+		networkLoadBalancers: make(map[string]*elbv2.LoadBalancer),
+		targetGroups:         make(map[string]*elbv2.TargetGroup),
+		elbv2Tags:            make(map[string][]*elbv2.Tag),
+		dhcpOptions:          make(map[string]*ec2.DhcpOptions),
+		routeTables:          make(map[string]*ec2.RouteTable),
+		addresses:            make(map[string]*ec2.Address),
+		natGateways:          make(map[string]*ec2.NatGateway),
+		vpcEndpoints:         make(map[string]*ec2.VpcEndpoint),
+		vpcPeerings:          make(map[string]*ec2.VpcPeeringConnection),
+		keyPairs:             make(map[string]*ec2.KeyPairInfo),
+		roles:                make(map[string]*iam.Role),
+		rolePolicies:         make(map[string]map[string]string),
+		instanceProfiles:     make(map[string]*iam.InstanceProfile),
+		users:                make(map[string]*iam.User),
+		userPolicies:         make(map[string]map[string]string),
+		accessKeys:           make(map[string]*iam.AccessKey),
+		accountAttributes: map[string][]string{
+			"vpc-max-elastic-ips":                   {DefaultMaxElasticIPs},
+			"vpc-max-security-groups-per-interface": {DefaultMaxSecurityGroupsPerInterface},
+		},
+		errors: make(map[string]error),
+		calls:  make(map[string]int),
+	}
+}
+
+// NewClient creates a new awsclient.Client whose service clients are backed by the given Backend.
+func NewClient(b *Backend) *awsclient.Client {
+	return &awsclient.Client{
+		EC2:   &ec2API{b},
+		ELB:   &elbAPI{b},
+		ELBV2: &elbv2API{b},
+		STS:   &stsAPI{b},
+		IAM:   &iamAPI{b},
+	}
+}
+
+// SetAccountID sets the account ID reported via STS.
+func (b *Backend) SetAccountID(accountID string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.accountID = accountID
+}
+
+// SetAccountAttribute sets the values of the EC2 account attribute with the given <name>.
+func (b *Backend) SetAccountAttribute(name string, values ...string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.accountAttributes[name] = values
+}
+
+// DenyActions makes the IAM policy simulation report the given <actions> as not allowed for every principal.
+func (b *Backend) DenyActions(actions ...string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for _, action := range actions {
+		b.deniedActions[action] = true
+	}
+}
+
+// InjectError makes every subsequent call of the given API <operation> (e.g. "DescribeSecurityGroups")
+// fail with <err>. Passing a nil error removes a previously injected error. ELB v2 operations that share their
+// name with an ELB operation carry the suffix "V2", e.g. "DescribeLoadBalancersV2".
+func (b *Backend) InjectError(operation string, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if err == nil {
+		delete(b.errors, operation)
+		return
+	}
+	b.errors[operation] = err
+}
+
+// Calls returns how often the given API <operation> has been called.
+func (b *Backend) Calls(operation string) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.calls[operation]
+}
+
+// CreateVPC adds a VPC with the given CIDR block and tags and returns its ID. Like in EC2, the VPC gets an untagged
+// main route table.
+func (b *Backend) CreateVPC(cidr string, tags map[string]string) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.createVPC(cidr, tags)
+}
+
+// createVPC adds a VPC with its main route table. The lock must be held by the caller.
+func (b *Backend) createVPC(cidr string, tags map[string]string) string {
+	id := b.newID("vpc")
+	b.vpcs[id] = &ec2.Vpc{
+		VpcId:         aws.String(id),
+		CidrBlock:     aws.String(cidr),
+		DhcpOptionsId: aws.String("default"),
+		State:         aws.String(ec2.VpcStateAvailable),
+		Tags:          ec2Tags(tags),
+	}
+
+	routeTableID := b.newID("rtb")
+	b.routeTables[routeTableID] = &ec2.RouteTable{
+		RouteTableId: aws.String(routeTableID),
+		VpcId:        aws.String(id),
+		Routes: []*ec2.Route{
+			{DestinationCidrBlock: aws.String(cidr), GatewayId: aws.String("local")},
+		},
+		Associations: []*ec2.RouteTableAssociation{
+			{
+				RouteTableAssociationId: aws.String(b.newID("rtbassoc")),
+				RouteTableId:            aws.String(routeTableID),
+				Main:                    aws.Bool(true),
+			},
+		},
+	}
+	return id
+}
+
+// CreateInternetGateway adds an internet gateway with the given tags and returns its ID. If <vpcID> is
+// not empty, the gateway is attached to that VPC.
+func (b *Backend) CreateInternetGateway(vpcID string, tags map[string]string) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	id := b.newID("igw")
+	gateway := &ec2.InternetGateway{
+		InternetGatewayId: aws.String(id),
+		Tags:              ec2Tags(tags),
+	}
+	if vpcID != "" {
+		gateway.Attachments = []*ec2.InternetGatewayAttachment{
+			{
+				VpcId: aws.String(vpcID),
+				State: aws.String(ec2.AttachmentStatusAttached),
+			},
+		}
+	}
+	b.internetGateways[id] = gateway
+	return id
+}
+
+// CreateSubnet adds a subnet with the given CIDR block and tags to the VPC <vpcID> in the availability <zone> and
+// returns its ID.
+func (b *Backend) CreateSubnet(vpcID, zone, cidr string, tags map[string]string) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	id := b.newID("subnet")
+	b.subnets[id] = &ec2.Subnet{
+		SubnetId:         aws.String(id),
+		VpcId:            aws.String(vpcID),
+		AvailabilityZone: aws.String(zone),
+		CidrBlock:        aws.String(cidr),
+		State:            aws.String(ec2.SubnetStateAvailable),
+		Tags:             ec2Tags(tags),
+	}
+	return id
+}
+
+// CreateSecurityGroup adds a security group with the given name and tags to the VPC <vpcID> and returns its ID.
+func (b *Backend) CreateSecurityGroup(vpcID, name string, tags map[string]string) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	id := b.newID("sg")
+	b.securityGroups[id] = &ec2.SecurityGroup{
+		GroupId:     aws.String(id),
+		GroupName:   aws.String(name),
+		Description: aws.String(name),
+		VpcId:       aws.String(vpcID),
+		OwnerId:     aws.String(b.accountID),
+		Tags:        ec2Tags(tags),
+	}
+	return id
+}
+
+// CreateElasticIP adds an Elastic IP of the VPC domain with the given tags and returns its allocation ID.
+func (b *Backend) CreateElasticIP(tags map[string]string) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return aws.StringValue(b.allocateAddress(tags).AllocationId)
+}
+
+// allocateAddress adds an Elastic IP with a public IP of the documentation range 203.0.113.0/24. The lock must be
+// held by the caller.
+func (b *Backend) allocateAddress(tags map[string]string) *ec2.Address {
+	id := b.newID("eipalloc")
+	address := &ec2.Address{
+		AllocationId: aws.String(id),
+		Domain:       aws.String(ec2.DomainTypeVpc),
+		PublicIp:     aws.String(fmt.Sprintf("203.0.113.%d", b.nextID%256)),
+		Tags:         ec2Tags(tags),
+	}
+	b.addresses[id] = address
+	return address
+}
+
+// CreateLoadBalancer adds a classic load balancer with the given name and tags to the VPC <vpcID>.
+func (b *Backend) CreateLoadBalancer(name, vpcID string, tags map[string]string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.loadBalancers[name] = &elb.LoadBalancerDescription{
+		LoadBalancerName: aws.String(name),
+		DNSName:          aws.String(fmt.Sprintf("%s.elb.amazonaws.com", name)),
+		VPCId:            aws.String(vpcID),
+	}
+	b.loadBalancerTags[name] = elbTags(tags)
+}
+
+// CreateNetworkInterface adds a detached network interface with the given security groups and tags to the
+// VPC <vpcID> and returns its ID.
+func (b *Backend) CreateNetworkInterface(vpcID string, securityGroupIDs []string, tags map[string]string) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	id := b.newID("eni")
+	networkInterface := &ec2.NetworkInterface{
+		NetworkInterfaceId: aws.String(id),
+		VpcId:              aws.String(vpcID),
+		Status:             aws.String(ec2.NetworkInterfaceStatusAvailable),
+		TagSet:             ec2Tags(tags),
+	}
+	for _, groupID := range securityGroupIDs {
+		networkInterface.Groups = append(networkInterface.Groups, &ec2.GroupIdentifier{GroupId: aws.String(groupID)})
+	}
+	b.networkInterfaces[id] = networkInterface
+	return id
+}
+
+// AttachNetworkInterface attaches the network interface with the given <id> to the instance <instanceID>.
+func (b *Backend) AttachNetworkInterface(id, instanceID string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	networkInterface := b.networkInterfaces[id]
+	networkInterface.Status = aws.String(ec2.NetworkInterfaceStatusInUse)
+	networkInterface.Attachment = &ec2.NetworkInterfaceAttachment{InstanceId: aws.String(instanceID), Status: aws.String(ec2.AttachmentStatusAttached)}
+}
+
+// CreateVolume adds an EBS volume in the given <state> (e.g. "available" or "in-use") with the given tags and
+// returns its ID.
+func (b *Backend) CreateVolume(state string, tags map[string]string) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	id := b.newID("vol")
+	b.volumes[id] = &ec2.Volume{
+		VolumeId:         aws.String(id),
+		AvailabilityZone: aws.String("eu-west-1a"),
+		Size:             aws.Int64(1),
+		State:            aws.String(state),
+		Tags:             ec2Tags(tags),
+	}
+	return id
+}
+
+// CreateSnapshot adds an EBS snapshot of the volume <volumeID> with the given tags owned by <ownerID> and returns
+// its ID. An empty <ownerID> means the account of the Backend.
+func (b *Backend) CreateSnapshot(volumeID, ownerID string, tags map[string]string) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if ownerID == "" {
+		ownerID = b.accountID
+	}
+	id := b.newID("snap")
+	b.snapshots[id] = &ec2.Snapshot{
+		SnapshotId: aws.String(id),
+		VolumeId:   aws.String(volumeID),
+		OwnerId:    aws.String(ownerID),
+		State:      aws.String(ec2.SnapshotStateCompleted),
+		Tags:       ec2Tags(tags),
+	}
+	return id
+}
+
+// CreateNetworkLoadBalancer adds a network load balancer with the given name and tags to the VPC <vpcID> and
+// returns its ARN.
+func (b *Backend) CreateNetworkLoadBalancer(name, vpcID string, tags map[string]string) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	arn := fmt.Sprintf("arn:aws:elasticloadbalancing:eu-west-1:%s:loadbalancer/net/%s/%s", b.accountID, name, b.newID("lb"))
+	b.networkLoadBalancers[arn] = &elbv2.LoadBalancer{
+		LoadBalancerArn:  aws.String(arn),
+		LoadBalancerName: aws.String(name),
+		DNSName:          aws.String(fmt.Sprintf("%s.elb.eu-west-1.amazonaws.com", name)),
+		Type:             aws.String(elbv2.LoadBalancerTypeEnumNetwork),
+		VpcId:            aws.String(vpcID),
+	}
+	b.elbv2Tags[arn] = elbv2Tags(tags)
+	return arn
+}
+
+// CreateTargetGroup adds a target group with the given name and tags to the VPC <vpcID> and returns its ARN.
+// If <loadBalancerARN> is not empty, the target group is in use by that load balancer.
+func (b *Backend) CreateTargetGroup(name, vpcID, loadBalancerARN string, tags map[string]string) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	arn := fmt.Sprintf("arn:aws:elasticloadbalancing:eu-west-1:%s:targetgroup/%s/%s", b.accountID, name, b.newID("tg"))
+	targetGroup := &elbv2.TargetGroup{
+		TargetGroupArn:  aws.String(arn),
+		TargetGroupName: aws.String(name),
+		VpcId:           aws.String(vpcID),
+	}
+	if loadBalancerARN != "" {
+		targetGroup.LoadBalancerArns = []*string{aws.String(loadBalancerARN)}
+	}
+	b.targetGroups[arn] = targetGroup
+	b.elbv2Tags[arn] = elbv2Tags(tags)
+	return arn
+}
+
+// VPCIDs returns the sorted IDs of all VPCs.
+func (b *Backend) VPCIDs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.vpcs)
+}
+
+// DHCPOptionsIDs returns the sorted IDs of all DHCP options.
+func (b *Backend) DHCPOptionsIDs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.dhcpOptions)
+}
+
+// InternetGatewayIDs returns the sorted IDs of all internet gateways.
+func (b *Backend) InternetGatewayIDs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.internetGateways)
+}
+
+// SubnetIDs returns the sorted IDs of all subnets.
+func (b *Backend) SubnetIDs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.subnets)
+}
+
+// RouteTableIDs returns the sorted IDs of all route tables, including the main route tables of the VPCs.
+func (b *Backend) RouteTableIDs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.routeTables)
+}
+
+// ElasticIPAllocationIDs returns the sorted allocation IDs of all Elastic IPs.
+func (b *Backend) ElasticIPAllocationIDs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.addresses)
+}
+
+// NATGatewayIDs returns the sorted IDs of all NAT gateways that are not deleted.
+func (b *Backend) NATGatewayIDs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var ids []string
+	for _, id := range sortedKeys(b.natGateways) {
+		if aws.StringValue(b.natGateways[id].State) != ec2.NatGatewayStateDeleted {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// VPCEndpointIDs returns the sorted IDs of all VPC endpoints.
+func (b *Backend) VPCEndpointIDs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.vpcEndpoints)
+}
+
+// VPCPeeringConnectionIDs returns the sorted IDs of all VPC peering connections that are not deleted.
+func (b *Backend) VPCPeeringConnectionIDs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var ids []string
+	for _, id := range sortedKeys(b.vpcPeerings) {
+		if aws.StringValue(b.vpcPeerings[id].Status.Code) != ec2.VpcPeeringConnectionStateReasonCodeDeleted {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// KeyPairNames returns the sorted names of all key pairs.
+func (b *Backend) KeyPairNames() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.keyPairs)
+}
+
+// RoleNames returns the sorted names of all IAM roles.
+func (b *Backend) RoleNames() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.roles)
+}
+
+// RolePolicies returns the inline policies of the IAM role with the given name by their names.
+func (b *Backend) RolePolicies(roleName string) map[string]string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	policies := make(map[string]string)
+	for name, policy := range b.rolePolicies[roleName] {
+		policies[name] = policy
+	}
+	return policies
+}
+
+// InstanceProfileNames returns the sorted names of all IAM instance profiles.
+func (b *Backend) InstanceProfileNames() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.instanceProfiles)
+}
+
+// UserNames returns the sorted names of all IAM users.
+func (b *Backend) UserNames() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.users)
+}
+
+// UserPolicies returns the inline policies of the IAM user with the given name by their names.
+func (b *Backend) UserPolicies(userName string) map[string]string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	policies := make(map[string]string)
+	for name, policy := range b.userPolicies[userName] {
+		policies[name] = policy
+	}
+	return policies
+}
+
+// AccessKeys returns the secrets of the access keys of the IAM user with the given name by their ids.
+func (b *Backend) AccessKeys(userName string) map[string]string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	secrets := make(map[string]string)
+	for id, accessKey := range b.accessKeys {
+		if aws.StringValue(accessKey.UserName) == userName {
+			secrets[id] = aws.StringValue(accessKey.SecretAccessKey)
+		}
+	}
+	return secrets
+}
+
+// Tags returns the tags of the EC2 resource with the given ID, or nil if there is no such resource.
+func (b *Backend) Tags(id string) map[string]string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	tags := b.tagsOf(id)
+	if tags == nil {
+		return nil
+	}
+	out := make(map[string]string)
+	for _, tag := range *tags {
+		out[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return out
+}
+
+// tagsOf returns a pointer to the tags of the EC2 resource with the given ID, or nil if there is no such resource.
+// The lock must be held by the caller.
+func (b *Backend) tagsOf(id string) *[]*ec2.Tag {
+	if vpc, ok := b.vpcs[id]; ok {
+		return &vpc.Tags
+	}
+	if options, ok := b.dhcpOptions[id]; ok {
+		return &options.Tags
+	}
+	if gateway, ok := b.internetGateways[id]; ok {
+		return &gateway.Tags
+	}
+	if subnet, ok := b.subnets[id]; ok {
+		return &subnet.Tags
+	}
+	if routeTable, ok := b.routeTables[id]; ok {
+		return &routeTable.Tags
+	}
+	if group, ok := b.securityGroups[id]; ok {
+		return &group.Tags
+	}
+	if address, ok := b.addresses[id]; ok {
+		return &address.Tags
+	}
+	if gateway, ok := b.natGateways[id]; ok {
+		return &gateway.Tags
+	}
+	if connection, ok := b.vpcPeerings[id]; ok {
+		return &connection.Tags
+	}
+	if networkInterface, ok := b.networkInterfaces[id]; ok {
+		return &networkInterface.TagSet
+	}
+	if volume, ok := b.volumes[id]; ok {
+		return &volume.Tags
+	}
+	if snapshot, ok := b.snapshots[id]; ok {
+		return &snapshot.Tags
+	}
+	return nil
+}
+
+// LoadBalancerNames returns the sorted names of all classic load balancers.
+func (b *Backend) LoadBalancerNames() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.loadBalancers)
+}
+
+// SecurityGroupIDs returns the sorted IDs of all security groups.
+func (b *Backend) SecurityGroupIDs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.securityGroups)
+}
+
+// NetworkInterfaceIDs returns the sorted IDs of all network interfaces.
+func (b *Backend) NetworkInterfaceIDs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.networkInterfaces)
+}
+
+// VolumeIDs returns the sorted IDs of all EBS volumes.
+func (b *Backend) VolumeIDs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.volumes)
+}
+
+// SnapshotIDs returns the sorted IDs of all EBS snapshots.
+func (b *Backend) SnapshotIDs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.snapshots)
+}
+
+// NetworkLoadBalancerARNs returns the sorted ARNs of all network load balancers.
+func (b *Backend) NetworkLoadBalancerARNs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.networkLoadBalancers)
+}
+
+// TargetGroupARNs returns the sorted ARNs of all target groups.
+func (b *Backend) TargetGroupARNs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return sortedKeys(b.targetGroups)
+}
+
+// call records a call of the given operation and returns the error injected for it, if any.
+// The lock must be held by the caller.
+func (b *Backend) call(operation string) error {
+	b.calls[operation]++
+	return b.errors[operation]
+}
+
+// newID returns a new unique resource ID with the given prefix. The lock must be held by the caller.
+func (b *Backend) newID(prefix string) string {
+	b.nextID++
+	return fmt.Sprintf("%s-%08x", prefix, b.nextID)
+}
+
+func ec2Tags(tags map[string]string) []*ec2.Tag {
+	var out []*ec2.Tag
+	for _, key := range sortedKeys(tags) {
+		out = append(out, &ec2.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+	return out
+}
+
+func elbTags(tags map[string]string) []*elb.Tag {
+	var out []*elb.Tag
+	for _, key := range sortedKeys(tags) {
+		out = append(out, &elb.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+	return out
+}
+
+func elbv2Tags(tags map[string]string) []*elbv2.Tag {
+	var out []*elbv2.Tag
+	for _, key := range sortedKeys(tags) {
+		out = append(out, &elbv2.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+	return out
+}
+
+// copyOf returns a deep copy of the given AWS API shape so that callers cannot modify the backend's state.
+func copyOf(v interface{}) interface{} {
+	return awsutil.CopyOf(v)
+}
+
+// sortedKeys returns the sorted keys of the given map with string keys.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// containsAny reports whether any of the <candidates> is contained in <values>.
+func containsAny(values, candidates []string) bool {
+	for _, value := range values {
+		for _, candidate := range candidates {
+			if value == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
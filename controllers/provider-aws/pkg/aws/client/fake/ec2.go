@@ -0,0 +1,613 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+const (
+	// ErrCodeInvalidParameterValue is the error code returned for unsupported or malformed parameters.
+	ErrCodeInvalidParameterValue = "InvalidParameterValue"
+	// ErrCodeInvalidVpcIDNotFound is the error code returned if a VPC does not exist.
+	ErrCodeInvalidVpcIDNotFound = "InvalidVpcID.NotFound"
+	// ErrCodeInvalidInternetGatewayIDNotFound is the error code returned if an internet gateway does not exist.
+	ErrCodeInvalidInternetGatewayIDNotFound = "InvalidInternetGatewayID.NotFound"
+	// ErrCodeInvalidSubnetIDNotFound is the error code returned if a subnet does not exist.
+	ErrCodeInvalidSubnetIDNotFound = "InvalidSubnetID.NotFound"
+	// ErrCodeInvalidGroupNotFound is the error code returned if a security group does not exist.
+	ErrCodeInvalidGroupNotFound = "InvalidGroup.NotFound"
+	// ErrCodeInvalidNetworkInterfaceIDNotFound is the error code returned if a network interface does not exist.
+	ErrCodeInvalidNetworkInterfaceIDNotFound = "InvalidNetworkInterfaceID.NotFound"
+	// ErrCodeInvalidVolumeNotFound is the error code returned if an EBS volume does not exist.
+	ErrCodeInvalidVolumeNotFound = "InvalidVolume.NotFound"
+	// ErrCodeVolumeInUse is the error code returned if an EBS volume is attached.
+	ErrCodeVolumeInUse = "VolumeInUse"
+	// ErrCodeInvalidSnapshotNotFound is the error code returned if an EBS snapshot does not exist.
+	ErrCodeInvalidSnapshotNotFound = "InvalidSnapshot.NotFound"
+	// ErrCodeDependencyViolation is the error code returned if a resource is still referenced by another one.
+	ErrCodeDependencyViolation = "DependencyViolation"
+
+	// minSecurityGroupsMaxResults and maxSecurityGroupsMaxResults are the bounds of MaxResults of DescribeSecurityGroups.
+	minSecurityGroupsMaxResults = 5
+	maxSecurityGroupsMaxResults = 1000
+	// minVolumesMaxResults and maxVolumesMaxResults are the bounds of MaxResults of DescribeVolumes.
+	minVolumesMaxResults = 5
+	maxVolumesMaxResults = 500
+	// minSnapshotsMaxResults and maxSnapshotsMaxResults are the bounds of MaxResults of DescribeSnapshots.
+	minSnapshotsMaxResults = 5
+	maxSnapshotsMaxResults = 1000
+)
+
+// ec2API implements awsclient.EC2 on top of a Backend.
+type ec2API struct {
+	*Backend
+}
+
+// resource describes the attributes of an EC2 resource that filters can match on.
+type resource struct {
+	tags       []*ec2.Tag
+	attributes map[string][]string
+}
+
+func (r *resource) values(name string) ([]string, bool) {
+	switch {
+	case name == "tag-key":
+		var keys []string
+		for _, tag := range r.tags {
+			keys = append(keys, aws.StringValue(tag.Key))
+		}
+		return keys, true
+	case name == "tag-value":
+		var values []string
+		for _, tag := range r.tags {
+			values = append(values, aws.StringValue(tag.Value))
+		}
+		return values, true
+	case len(name) > len("tag:") && name[:len("tag:")] == "tag:":
+		var values []string
+		for _, tag := range r.tags {
+			if aws.StringValue(tag.Key) == name[len("tag:"):] {
+				values = append(values, aws.StringValue(tag.Value))
+			}
+		}
+		return values, true
+	}
+
+	values, ok := r.attributes[name]
+	return values, ok
+}
+
+// matches reports whether the resource matches all given filters. Like in EC2, different filters are
+// combined with a logical AND, and the values of a single filter with a logical OR.
+func (r *resource) matches(filters []*ec2.Filter) (bool, error) {
+	for _, filter := range filters {
+		name := aws.StringValue(filter.Name)
+		values, ok := r.values(name)
+		if !ok {
+			return false, awserr.New(ErrCodeInvalidParameterValue, fmt.Sprintf("The filter '%s' is invalid", name), nil)
+		}
+		if !containsAny(values, aws.StringValueSlice(filter.Values)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func vpcResource(vpc *ec2.Vpc) *resource {
+	return &resource{
+		tags: vpc.Tags,
+		attributes: map[string][]string{
+			"vpc-id": {aws.StringValue(vpc.VpcId)},
+			"cidr":   {aws.StringValue(vpc.CidrBlock)},
+			"state":  {aws.StringValue(vpc.State)},
+		},
+	}
+}
+
+func internetGatewayResource(gateway *ec2.InternetGateway) *resource {
+	r := &resource{
+		tags: gateway.Tags,
+		attributes: map[string][]string{
+			"internet-gateway-id": {aws.StringValue(gateway.InternetGatewayId)},
+			"attachment.vpc-id":   nil,
+			"attachment.state":    nil,
+		},
+	}
+	for _, attachment := range gateway.Attachments {
+		r.attributes["attachment.vpc-id"] = append(r.attributes["attachment.vpc-id"], aws.StringValue(attachment.VpcId))
+		r.attributes["attachment.state"] = append(r.attributes["attachment.state"], aws.StringValue(attachment.State))
+	}
+	return r
+}
+
+func subnetResource(subnet *ec2.Subnet) *resource {
+	return &resource{
+		tags: subnet.Tags,
+		attributes: map[string][]string{
+			"subnet-id":         {aws.StringValue(subnet.SubnetId)},
+			"vpc-id":            {aws.StringValue(subnet.VpcId)},
+			"availability-zone": {aws.StringValue(subnet.AvailabilityZone)},
+			"cidr-block":        {aws.StringValue(subnet.CidrBlock)},
+			"state":             {aws.StringValue(subnet.State)},
+		},
+	}
+}
+
+func securityGroupResource(group *ec2.SecurityGroup) *resource {
+	return &resource{
+		tags: group.Tags,
+		attributes: map[string][]string{
+			"group-id":    {aws.StringValue(group.GroupId)},
+			"group-name":  {aws.StringValue(group.GroupName)},
+			"description": {aws.StringValue(group.Description)},
+			"vpc-id":      {aws.StringValue(group.VpcId)},
+			"owner-id":    {aws.StringValue(group.OwnerId)},
+		},
+	}
+}
+
+func networkInterfaceResource(networkInterface *ec2.NetworkInterface) *resource {
+	r := &resource{
+		tags: networkInterface.TagSet,
+		attributes: map[string][]string{
+			"network-interface-id": {aws.StringValue(networkInterface.NetworkInterfaceId)},
+			"description":          {aws.StringValue(networkInterface.Description)},
+			"vpc-id":               {aws.StringValue(networkInterface.VpcId)},
+			"status":               {aws.StringValue(networkInterface.Status)},
+			"group-id":             nil,
+		},
+	}
+	for _, group := range networkInterface.Groups {
+		r.attributes["group-id"] = append(r.attributes["group-id"], aws.StringValue(group.GroupId))
+	}
+	return r
+}
+
+func volumeResource(volume *ec2.Volume) *resource {
+	return &resource{
+		tags: volume.Tags,
+		attributes: map[string][]string{
+			"volume-id":         {aws.StringValue(volume.VolumeId)},
+			"availability-zone": {aws.StringValue(volume.AvailabilityZone)},
+			"status":            {aws.StringValue(volume.State)},
+		},
+	}
+}
+
+func snapshotResource(snapshot *ec2.Snapshot) *resource {
+	return &resource{
+		tags: snapshot.Tags,
+		attributes: map[string][]string{
+			"snapshot-id": {aws.StringValue(snapshot.SnapshotId)},
+			"volume-id":   {aws.StringValue(snapshot.VolumeId)},
+			"owner-id":    {aws.StringValue(snapshot.OwnerId)},
+			"status":      {aws.StringValue(snapshot.State)},
+		},
+	}
+}
+
+// DescribeAccountAttributesWithContext implements awsclient.EC2. Without attribute names, all attributes set
+// in the Backend are returned.
+func (e *ec2API) DescribeAccountAttributesWithContext(_ aws.Context, input *ec2.DescribeAccountAttributesInput, _ ...request.Option) (*ec2.DescribeAccountAttributesOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeAccountAttributes"); err != nil {
+		return nil, err
+	}
+
+	names := aws.StringValueSlice(input.AttributeNames)
+	if len(names) == 0 {
+		names = sortedKeys(e.accountAttributes)
+	}
+
+	output := &ec2.DescribeAccountAttributesOutput{}
+	for _, name := range names {
+		values, ok := e.accountAttributes[name]
+		if !ok {
+			continue
+		}
+		attribute := &ec2.AccountAttribute{AttributeName: aws.String(name)}
+		for _, value := range values {
+			attribute.AttributeValues = append(attribute.AttributeValues, &ec2.AccountAttributeValue{AttributeValue: aws.String(value)})
+		}
+		output.AccountAttributes = append(output.AccountAttributes, attribute)
+	}
+	return output, nil
+}
+
+// DescribeVpcsWithContext implements awsclient.EC2.
+func (e *ec2API) DescribeVpcsWithContext(_ aws.Context, input *ec2.DescribeVpcsInput, _ ...request.Option) (*ec2.DescribeVpcsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeVpcs"); err != nil {
+		return nil, err
+	}
+
+	ids, err := lookup(e.vpcs, aws.StringValueSlice(input.VpcIds), ErrCodeInvalidVpcIDNotFound, "vpc ID")
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DescribeVpcsOutput{}
+	for _, id := range ids {
+		vpc := e.vpcs[id]
+		ok, err := vpcResource(vpc).matches(input.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			output.Vpcs = append(output.Vpcs, copyOf(vpc).(*ec2.Vpc))
+		}
+	}
+	return output, nil
+}
+
+// DescribeInternetGatewaysWithContext implements awsclient.EC2.
+func (e *ec2API) DescribeInternetGatewaysWithContext(_ aws.Context, input *ec2.DescribeInternetGatewaysInput, _ ...request.Option) (*ec2.DescribeInternetGatewaysOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeInternetGateways"); err != nil {
+		return nil, err
+	}
+
+	ids, err := lookup(e.internetGateways, aws.StringValueSlice(input.InternetGatewayIds), ErrCodeInvalidInternetGatewayIDNotFound, "internet gateway ID")
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DescribeInternetGatewaysOutput{}
+	for _, id := range ids {
+		gateway := e.internetGateways[id]
+		ok, err := internetGatewayResource(gateway).matches(input.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			output.InternetGateways = append(output.InternetGateways, copyOf(gateway).(*ec2.InternetGateway))
+		}
+	}
+	return output, nil
+}
+
+// DescribeSubnetsWithContext implements awsclient.EC2.
+func (e *ec2API) DescribeSubnetsWithContext(_ aws.Context, input *ec2.DescribeSubnetsInput, _ ...request.Option) (*ec2.DescribeSubnetsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeSubnets"); err != nil {
+		return nil, err
+	}
+
+	ids, err := lookup(e.subnets, aws.StringValueSlice(input.SubnetIds), ErrCodeInvalidSubnetIDNotFound, "subnet ID")
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DescribeSubnetsOutput{}
+	for _, id := range ids {
+		subnet := e.subnets[id]
+		ok, err := subnetResource(subnet).matches(input.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			output.Subnets = append(output.Subnets, copyOf(subnet).(*ec2.Subnet))
+		}
+	}
+	return output, nil
+}
+
+// DescribeSecurityGroupsWithContext implements awsclient.EC2. If MaxResults is set, the results are
+// paginated via NextToken.
+func (e *ec2API) DescribeSecurityGroupsWithContext(_ aws.Context, input *ec2.DescribeSecurityGroupsInput, _ ...request.Option) (*ec2.DescribeSecurityGroupsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeSecurityGroups"); err != nil {
+		return nil, err
+	}
+
+	ids, err := lookup(e.securityGroups, aws.StringValueSlice(input.GroupIds), ErrCodeInvalidGroupNotFound, "security group ID")
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*ec2.SecurityGroup
+	for _, id := range ids {
+		group := e.securityGroups[id]
+		if len(input.GroupNames) > 0 && !containsAny([]string{aws.StringValue(group.GroupName)}, aws.StringValueSlice(input.GroupNames)) {
+			continue
+		}
+		ok, err := securityGroupResource(group).matches(input.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			groups = append(groups, copyOf(group).(*ec2.SecurityGroup))
+		}
+	}
+
+	if input.MaxResults == nil {
+		return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: groups}, nil
+	}
+	maxResults := int(aws.Int64Value(input.MaxResults))
+	if maxResults < minSecurityGroupsMaxResults || maxResults > maxSecurityGroupsMaxResults || len(input.GroupIds) > 0 {
+		return nil, awserr.New(ErrCodeInvalidParameterValue, fmt.Sprintf("MaxResults must be between %d and %d and cannot be combined with GroupIds", minSecurityGroupsMaxResults, maxSecurityGroupsMaxResults), nil)
+	}
+
+	start, end, next, err := page(len(groups), aws.StringValue(input.NextToken), maxResults)
+	if err != nil {
+		return nil, err
+	}
+	output := &ec2.DescribeSecurityGroupsOutput{SecurityGroups: groups[start:end]}
+	if next != "" {
+		output.NextToken = aws.String(next)
+	}
+	return output, nil
+}
+
+// DeleteSecurityGroupWithContext implements awsclient.EC2. Like in EC2, deleting a security group that is used by a
+// network interface or referenced by a rule of another security group fails.
+func (e *ec2API) DeleteSecurityGroupWithContext(_ aws.Context, input *ec2.DeleteSecurityGroupInput, _ ...request.Option) (*ec2.DeleteSecurityGroupOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteSecurityGroup"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.GroupId)
+	if _, ok := e.securityGroups[id]; !ok {
+		return nil, awserr.New(ErrCodeInvalidGroupNotFound, fmt.Sprintf("The security group '%s' does not exist", id), nil)
+	}
+	for _, networkInterface := range e.networkInterfaces {
+		for _, group := range networkInterface.Groups {
+			if aws.StringValue(group.GroupId) == id {
+				return nil, awserr.New(ErrCodeDependencyViolation, fmt.Sprintf("resource %s has a dependent object", id), nil)
+			}
+		}
+	}
+	for otherID, group := range e.securityGroups {
+		if otherID == id {
+			continue
+		}
+		for _, permission := range append(group.IpPermissions, group.IpPermissionsEgress...) {
+			for _, pair := range permission.UserIdGroupPairs {
+				if aws.StringValue(pair.GroupId) == id {
+					return nil, awserr.New(ErrCodeDependencyViolation, fmt.Sprintf("resource %s has a dependent object", id), nil)
+				}
+			}
+		}
+	}
+	delete(e.securityGroups, id)
+	return &ec2.DeleteSecurityGroupOutput{}, nil
+}
+
+// DescribeNetworkInterfacesWithContext implements awsclient.EC2.
+func (e *ec2API) DescribeNetworkInterfacesWithContext(_ aws.Context, input *ec2.DescribeNetworkInterfacesInput, _ ...request.Option) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeNetworkInterfaces"); err != nil {
+		return nil, err
+	}
+
+	ids, err := lookup(e.networkInterfaces, aws.StringValueSlice(input.NetworkInterfaceIds), ErrCodeInvalidNetworkInterfaceIDNotFound, "network interface ID")
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DescribeNetworkInterfacesOutput{}
+	for _, id := range ids {
+		networkInterface := e.networkInterfaces[id]
+		ok, err := networkInterfaceResource(networkInterface).matches(input.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			output.NetworkInterfaces = append(output.NetworkInterfaces, copyOf(networkInterface).(*ec2.NetworkInterface))
+		}
+	}
+	return output, nil
+}
+
+// DeleteNetworkInterfaceWithContext implements awsclient.EC2.
+func (e *ec2API) DeleteNetworkInterfaceWithContext(_ aws.Context, input *ec2.DeleteNetworkInterfaceInput, _ ...request.Option) (*ec2.DeleteNetworkInterfaceOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteNetworkInterface"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.NetworkInterfaceId)
+	if _, ok := e.networkInterfaces[id]; !ok {
+		return nil, awserr.New(ErrCodeInvalidNetworkInterfaceIDNotFound, fmt.Sprintf("The networkInterface ID '%s' does not exist", id), nil)
+	}
+	delete(e.networkInterfaces, id)
+	return &ec2.DeleteNetworkInterfaceOutput{}, nil
+}
+
+// DescribeVolumesWithContext implements awsclient.EC2. If MaxResults is set, the results are paginated via NextToken.
+func (e *ec2API) DescribeVolumesWithContext(_ aws.Context, input *ec2.DescribeVolumesInput, _ ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeVolumes"); err != nil {
+		return nil, err
+	}
+
+	ids, err := lookup(e.volumes, aws.StringValueSlice(input.VolumeIds), ErrCodeInvalidVolumeNotFound, "volume")
+	if err != nil {
+		return nil, err
+	}
+
+	var volumes []*ec2.Volume
+	for _, id := range ids {
+		volume := e.volumes[id]
+		ok, err := volumeResource(volume).matches(input.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			volumes = append(volumes, copyOf(volume).(*ec2.Volume))
+		}
+	}
+
+	if input.MaxResults == nil {
+		return &ec2.DescribeVolumesOutput{Volumes: volumes}, nil
+	}
+	start, end, next, err := pageWithBounds(len(volumes), input.NextToken, input.MaxResults, minVolumesMaxResults, maxVolumesMaxResults)
+	if err != nil {
+		return nil, err
+	}
+	output := &ec2.DescribeVolumesOutput{Volumes: volumes[start:end]}
+	if next != "" {
+		output.NextToken = aws.String(next)
+	}
+	return output, nil
+}
+
+// DeleteVolumeWithContext implements awsclient.EC2. Like EC2, deleting an attached volume fails.
+func (e *ec2API) DeleteVolumeWithContext(_ aws.Context, input *ec2.DeleteVolumeInput, _ ...request.Option) (*ec2.DeleteVolumeOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteVolume"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.VolumeId)
+	volume, ok := e.volumes[id]
+	if !ok {
+		return nil, awserr.New(ErrCodeInvalidVolumeNotFound, fmt.Sprintf("The volume '%s' does not exist.", id), nil)
+	}
+	if aws.StringValue(volume.State) == ec2.VolumeStateInUse {
+		return nil, awserr.New(ErrCodeVolumeInUse, fmt.Sprintf("Volume %s is currently attached", id), nil)
+	}
+	delete(e.volumes, id)
+	return &ec2.DeleteVolumeOutput{}, nil
+}
+
+// DescribeSnapshotsWithContext implements awsclient.EC2. The owner "self" refers to the account of the Backend.
+// If MaxResults is set, the results are paginated via NextToken.
+func (e *ec2API) DescribeSnapshotsWithContext(_ aws.Context, input *ec2.DescribeSnapshotsInput, _ ...request.Option) (*ec2.DescribeSnapshotsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeSnapshots"); err != nil {
+		return nil, err
+	}
+
+	ids, err := lookup(e.snapshots, aws.StringValueSlice(input.SnapshotIds), ErrCodeInvalidSnapshotNotFound, "snapshot")
+	if err != nil {
+		return nil, err
+	}
+
+	var owners []string
+	for _, owner := range aws.StringValueSlice(input.OwnerIds) {
+		if owner == "self" {
+			owner = e.accountID
+		}
+		owners = append(owners, owner)
+	}
+
+	var snapshots []*ec2.Snapshot
+	for _, id := range ids {
+		snapshot := e.snapshots[id]
+		if len(owners) > 0 && !containsAny(owners, []string{aws.StringValue(snapshot.OwnerId)}) {
+			continue
+		}
+		ok, err := snapshotResource(snapshot).matches(input.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			snapshots = append(snapshots, copyOf(snapshot).(*ec2.Snapshot))
+		}
+	}
+
+	if input.MaxResults == nil {
+		return &ec2.DescribeSnapshotsOutput{Snapshots: snapshots}, nil
+	}
+	start, end, next, err := pageWithBounds(len(snapshots), input.NextToken, input.MaxResults, minSnapshotsMaxResults, maxSnapshotsMaxResults)
+	if err != nil {
+		return nil, err
+	}
+	output := &ec2.DescribeSnapshotsOutput{Snapshots: snapshots[start:end]}
+	if next != "" {
+		output.NextToken = aws.String(next)
+	}
+	return output, nil
+}
+
+// DeleteSnapshotWithContext implements awsclient.EC2.
+func (e *ec2API) DeleteSnapshotWithContext(_ aws.Context, input *ec2.DeleteSnapshotInput, _ ...request.Option) (*ec2.DeleteSnapshotOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteSnapshot"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.SnapshotId)
+	if _, ok := e.snapshots[id]; !ok {
+		return nil, awserr.New(ErrCodeInvalidSnapshotNotFound, fmt.Sprintf("The snapshot '%s' does not exist.", id), nil)
+	}
+	delete(e.snapshots, id)
+	return &ec2.DeleteSnapshotOutput{}, nil
+}
+
+// lookup returns the given <ids> if all of them exist in <m>, or all keys of <m> if no IDs are given.
+func lookup(m interface{}, ids []string, notFoundCode, kind string) ([]string, error) {
+	keys := sortedKeys(m)
+	if len(ids) == 0 {
+		return keys, nil
+	}
+	for _, id := range ids {
+		if !containsAny(keys, []string{id}) {
+			return nil, awserr.New(notFoundCode, fmt.Sprintf("The %s '%s' does not exist", kind, id), nil)
+		}
+	}
+	return ids, nil
+}
+
+// pageWithBounds computes the page like page, but validates that <maxResults> is within the given bounds.
+func pageWithBounds(total int, token *string, maxResults *int64, min, max int) (int, int, string, error) {
+	size := int(aws.Int64Value(maxResults))
+	if size < min || size > max {
+		return 0, 0, "", awserr.New(ErrCodeInvalidParameterValue, fmt.Sprintf("MaxResults must be between %d and %d", min, max), nil)
+	}
+	return page(total, aws.StringValue(token), size)
+}
+
+// page computes the bounds of the page starting at the opaque <token> with at most <size> of <total> items,
+// and the token of the next page, which is empty for the last page.
+func page(total int, token string, size int) (int, int, string, error) {
+	start := 0
+	if token != "" {
+		var err error
+		if start, err = strconv.Atoi(token); err != nil || start < 0 || start > total {
+			return 0, 0, "", awserr.New(ErrCodeInvalidParameterValue, fmt.Sprintf("The pagination token '%s' is invalid", token), nil)
+		}
+	}
+	if size <= 0 {
+		return 0, 0, "", awserr.New(ErrCodeInvalidParameterValue, fmt.Sprintf("The page size %d is invalid", size), nil)
+	}
+
+	end := start + size
+	if end >= total {
+		return start, total, "", nil
+	}
+	return start, end, strconv.Itoa(end), nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elb"
+)
+
+const (
+	// ErrCodeValidationError is the error code returned for invalid ELB requests.
+	ErrCodeValidationError = "ValidationError"
+
+	// maxDescribeTagsLoadBalancers is the maximum number of load balancers a single DescribeTags call accepts.
+	maxDescribeTagsLoadBalancers = 20
+	// maxLoadBalancerPageSize is the maximum and default page size of DescribeLoadBalancers.
+	maxLoadBalancerPageSize = 400
+)
+
+// elbAPI implements awsclient.ELB on top of a Backend.
+type elbAPI struct {
+	*Backend
+}
+
+// DescribeLoadBalancersWithContext implements awsclient.ELB. The results are paginated via Marker and PageSize.
+func (e *elbAPI) DescribeLoadBalancersWithContext(_ aws.Context, input *elb.DescribeLoadBalancersInput, _ ...request.Option) (*elb.DescribeLoadBalancersOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeLoadBalancers"); err != nil {
+		return nil, err
+	}
+
+	names, err := lookup(e.loadBalancers, aws.StringValueSlice(input.LoadBalancerNames), elb.ErrCodeAccessPointNotFoundException, "load balancer")
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := maxLoadBalancerPageSize
+	if input.PageSize != nil {
+		pageSize = int(*input.PageSize)
+	}
+	if pageSize < 1 || pageSize > maxLoadBalancerPageSize {
+		return nil, awserr.New(ErrCodeValidationError, fmt.Sprintf("PageSize must be between 1 and %d", maxLoadBalancerPageSize), nil)
+	}
+	start, end, next, err := page(len(names), aws.StringValue(input.Marker), pageSize)
+	if err != nil {
+		return nil, awserr.New(ErrCodeValidationError, err.(awserr.Error).Message(), nil)
+	}
+
+	output := &elb.DescribeLoadBalancersOutput{}
+	for _, name := range names[start:end] {
+		output.LoadBalancerDescriptions = append(output.LoadBalancerDescriptions, copyOf(e.loadBalancers[name]).(*elb.LoadBalancerDescription))
+	}
+	if next != "" {
+		output.NextMarker = aws.String(next)
+	}
+	return output, nil
+}
+
+// DescribeTagsWithContext implements awsclient.ELB. Like ELB, it accepts at most 20 load balancer names.
+func (e *elbAPI) DescribeTagsWithContext(_ aws.Context, input *elb.DescribeTagsInput, _ ...request.Option) (*elb.DescribeTagsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeTags"); err != nil {
+		return nil, err
+	}
+
+	names := aws.StringValueSlice(input.LoadBalancerNames)
+	if len(names) == 0 || len(names) > maxDescribeTagsLoadBalancers {
+		return nil, awserr.New(ErrCodeValidationError, fmt.Sprintf("LoadBalancerNames must contain between 1 and %d names", maxDescribeTagsLoadBalancers), nil)
+	}
+	if _, err := lookup(e.loadBalancers, names, elb.ErrCodeAccessPointNotFoundException, "load balancer"); err != nil {
+		return nil, err
+	}
+
+	output := &elb.DescribeTagsOutput{}
+	for _, name := range names {
+		description := &elb.TagDescription{LoadBalancerName: aws.String(name)}
+		for _, tag := range e.loadBalancerTags[name] {
+			description.Tags = append(description.Tags, copyOf(tag).(*elb.Tag))
+		}
+		output.TagDescriptions = append(output.TagDescriptions, description)
+	}
+	return output, nil
+}
+
+// DeleteLoadBalancerWithContext implements awsclient.ELB. Like ELB, deleting a non-existing load balancer succeeds.
+func (e *elbAPI) DeleteLoadBalancerWithContext(_ aws.Context, input *elb.DeleteLoadBalancerInput, _ ...request.Option) (*elb.DeleteLoadBalancerOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteLoadBalancer"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.LoadBalancerName)
+	delete(e.loadBalancers, name)
+	delete(e.loadBalancerTags, name)
+	return &elb.DeleteLoadBalancerOutput{}, nil
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// elbv2API implements awsclient.ELBV2 on top of a Backend.
+type elbv2API struct {
+	*Backend
+}
+
+// DescribeLoadBalancersWithContext implements awsclient.ELBV2. The results are paginated via Marker and PageSize.
+func (e *elbv2API) DescribeLoadBalancersWithContext(_ aws.Context, input *elbv2.DescribeLoadBalancersInput, _ ...request.Option) (*elbv2.DescribeLoadBalancersOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeLoadBalancersV2"); err != nil {
+		return nil, err
+	}
+
+	arns, err := lookup(e.networkLoadBalancers, aws.StringValueSlice(input.LoadBalancerArns), elbv2.ErrCodeLoadBalancerNotFoundException, "load balancer")
+	if err != nil {
+		return nil, err
+	}
+	start, end, next, err := e.page(len(arns), input.Marker, input.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &elbv2.DescribeLoadBalancersOutput{}
+	for _, arn := range arns[start:end] {
+		output.LoadBalancers = append(output.LoadBalancers, copyOf(e.networkLoadBalancers[arn]).(*elbv2.LoadBalancer))
+	}
+	if next != "" {
+		output.NextMarker = aws.String(next)
+	}
+	return output, nil
+}
+
+// DeleteLoadBalancerWithContext implements awsclient.ELBV2. Deleting a load balancer detaches it from its target groups.
+func (e *elbv2API) DeleteLoadBalancerWithContext(_ aws.Context, input *elbv2.DeleteLoadBalancerInput, _ ...request.Option) (*elbv2.DeleteLoadBalancerOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteLoadBalancerV2"); err != nil {
+		return nil, err
+	}
+
+	arn := aws.StringValue(input.LoadBalancerArn)
+	if _, ok := e.networkLoadBalancers[arn]; !ok {
+		return nil, awserr.New(elbv2.ErrCodeLoadBalancerNotFoundException, fmt.Sprintf("Load balancer '%s' not found", arn), nil)
+	}
+	delete(e.networkLoadBalancers, arn)
+	delete(e.elbv2Tags, arn)
+
+	for _, targetGroup := range e.targetGroups {
+		var loadBalancerARNs []*string
+		for _, loadBalancerARN := range targetGroup.LoadBalancerArns {
+			if aws.StringValue(loadBalancerARN) != arn {
+				loadBalancerARNs = append(loadBalancerARNs, loadBalancerARN)
+			}
+		}
+		targetGroup.LoadBalancerArns = loadBalancerARNs
+	}
+	return &elbv2.DeleteLoadBalancerOutput{}, nil
+}
+
+// DescribeTargetGroupsWithContext implements awsclient.ELBV2. The results are paginated via Marker and PageSize.
+func (e *elbv2API) DescribeTargetGroupsWithContext(_ aws.Context, input *elbv2.DescribeTargetGroupsInput, _ ...request.Option) (*elbv2.DescribeTargetGroupsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeTargetGroups"); err != nil {
+		return nil, err
+	}
+
+	arns, err := lookup(e.targetGroups, aws.StringValueSlice(input.TargetGroupArns), elbv2.ErrCodeTargetGroupNotFoundException, "target group")
+	if err != nil {
+		return nil, err
+	}
+	start, end, next, err := e.page(len(arns), input.Marker, input.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &elbv2.DescribeTargetGroupsOutput{}
+	for _, arn := range arns[start:end] {
+		output.TargetGroups = append(output.TargetGroups, copyOf(e.targetGroups[arn]).(*elbv2.TargetGroup))
+	}
+	if next != "" {
+		output.NextMarker = aws.String(next)
+	}
+	return output, nil
+}
+
+// DeleteTargetGroupWithContext implements awsclient.ELBV2. Like ELB, deleting a target group that is in use by a
+// load balancer fails.
+func (e *elbv2API) DeleteTargetGroupWithContext(_ aws.Context, input *elbv2.DeleteTargetGroupInput, _ ...request.Option) (*elbv2.DeleteTargetGroupOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteTargetGroup"); err != nil {
+		return nil, err
+	}
+
+	arn := aws.StringValue(input.TargetGroupArn)
+	targetGroup, ok := e.targetGroups[arn]
+	if !ok {
+		return nil, awserr.New(elbv2.ErrCodeTargetGroupNotFoundException, fmt.Sprintf("Target group '%s' not found", arn), nil)
+	}
+	if len(targetGroup.LoadBalancerArns) > 0 {
+		return nil, awserr.New(elbv2.ErrCodeResourceInUseException, fmt.Sprintf("Target group '%s' is currently in use by a listener or a rule", arn), nil)
+	}
+	delete(e.targetGroups, arn)
+	delete(e.elbv2Tags, arn)
+	return &elbv2.DeleteTargetGroupOutput{}, nil
+}
+
+// DescribeTagsWithContext implements awsclient.ELBV2. Like ELB, it accepts at most 20 resource ARNs.
+func (e *elbv2API) DescribeTagsWithContext(_ aws.Context, input *elbv2.DescribeTagsInput, _ ...request.Option) (*elbv2.DescribeTagsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeTagsV2"); err != nil {
+		return nil, err
+	}
+
+	arns := aws.StringValueSlice(input.ResourceArns)
+	if len(arns) == 0 || len(arns) > maxDescribeTagsLoadBalancers {
+		return nil, awserr.New(ErrCodeValidationError, fmt.Sprintf("ResourceArns must contain between 1 and %d ARNs", maxDescribeTagsLoadBalancers), nil)
+	}
+	if _, err := lookup(e.elbv2Tags, arns, elbv2.ErrCodeLoadBalancerNotFoundException, "resource"); err != nil {
+		return nil, err
+	}
+
+	output := &elbv2.DescribeTagsOutput{}
+	for _, arn := range arns {
+		description := &elbv2.TagDescription{ResourceArn: aws.String(arn)}
+		for _, tag := range e.elbv2Tags[arn] {
+			description.Tags = append(description.Tags, copyOf(tag).(*elbv2.Tag))
+		}
+		output.TagDescriptions = append(output.TagDescriptions, description)
+	}
+	return output, nil
+}
+
+// page computes the page of the ELB v2 describe calls like page, but returns ELB validation errors.
+func (e *elbv2API) page(total int, marker *string, pageSize *int64) (int, int, string, error) {
+	size := maxLoadBalancerPageSize
+	if pageSize != nil {
+		size = int(*pageSize)
+	}
+	if size < 1 || size > maxLoadBalancerPageSize {
+		return 0, 0, "", awserr.New(ErrCodeValidationError, fmt.Sprintf("PageSize must be between 1 and %d", maxLoadBalancerPageSize), nil)
+	}
+
+	start, end, next, err := page(total, aws.StringValue(marker), size)
+	if err != nil {
+		return 0, 0, "", awserr.New(ErrCodeValidationError, err.(awserr.Error).Message(), nil)
+	}
+	return start, end, next, nil
+}
@@ -0,0 +1,479 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// iamAPI implements awsclient.IAM on top of a Backend.
+type iamAPI struct {
+	*Backend
+}
+
+func noSuchEntity(kind, name string) error {
+	return awserr.New(iam.ErrCodeNoSuchEntityException, fmt.Sprintf("The %s with name %s cannot be found.", kind, name), nil)
+}
+
+// GetRoleWithContext implements awsclient.IAM.
+func (i *iamAPI) GetRoleWithContext(_ aws.Context, input *iam.GetRoleInput, _ ...request.Option) (*iam.GetRoleOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("GetRole"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.RoleName)
+	role, ok := i.roles[name]
+	if !ok {
+		return nil, noSuchEntity("role", name)
+	}
+	return &iam.GetRoleOutput{Role: copyOf(role).(*iam.Role)}, nil
+}
+
+// CreateRoleWithContext implements awsclient.IAM.
+func (i *iamAPI) CreateRoleWithContext(_ aws.Context, input *iam.CreateRoleInput, _ ...request.Option) (*iam.CreateRoleOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("CreateRole"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.RoleName)
+	if _, ok := i.roles[name]; ok {
+		return nil, awserr.New(iam.ErrCodeEntityAlreadyExistsException, fmt.Sprintf("Role with name %s already exists.", name), nil)
+	}
+	path := aws.StringValue(input.Path)
+	if path == "" {
+		path = "/"
+	}
+	i.roles[name] = &iam.Role{
+		Path:                     aws.String(path),
+		RoleName:                 aws.String(name),
+		RoleId:                   aws.String(i.newID("AROA")),
+		Arn:                      aws.String(fmt.Sprintf("arn:aws:iam::%s:role%s%s", i.accountID, path, name)),
+		AssumeRolePolicyDocument: input.AssumeRolePolicyDocument,
+	}
+	i.rolePolicies[name] = make(map[string]string)
+	return &iam.CreateRoleOutput{Role: copyOf(i.roles[name]).(*iam.Role)}, nil
+}
+
+// DeleteRoleWithContext implements awsclient.IAM. Like in IAM, deleting a role that still has inline policies or
+// belongs to an instance profile fails.
+func (i *iamAPI) DeleteRoleWithContext(_ aws.Context, input *iam.DeleteRoleInput, _ ...request.Option) (*iam.DeleteRoleOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("DeleteRole"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.RoleName)
+	if _, ok := i.roles[name]; !ok {
+		return nil, noSuchEntity("role", name)
+	}
+	deleteConflict := awserr.New(iam.ErrCodeDeleteConflictException, fmt.Sprintf("Cannot delete entity, role %s is still in use.", name), nil)
+	if len(i.rolePolicies[name]) > 0 {
+		return nil, deleteConflict
+	}
+	for _, profile := range i.instanceProfiles {
+		for _, role := range profile.Roles {
+			if aws.StringValue(role.RoleName) == name {
+				return nil, deleteConflict
+			}
+		}
+	}
+	delete(i.roles, name)
+	delete(i.rolePolicies, name)
+	return &iam.DeleteRoleOutput{}, nil
+}
+
+// PutRolePolicyWithContext implements awsclient.IAM.
+func (i *iamAPI) PutRolePolicyWithContext(_ aws.Context, input *iam.PutRolePolicyInput, _ ...request.Option) (*iam.PutRolePolicyOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("PutRolePolicy"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.RoleName)
+	if _, ok := i.roles[name]; !ok {
+		return nil, noSuchEntity("role", name)
+	}
+	i.rolePolicies[name][aws.StringValue(input.PolicyName)] = aws.StringValue(input.PolicyDocument)
+	return &iam.PutRolePolicyOutput{}, nil
+}
+
+// ListRolePoliciesWithContext implements awsclient.IAM. The results are not paginated.
+func (i *iamAPI) ListRolePoliciesWithContext(_ aws.Context, input *iam.ListRolePoliciesInput, _ ...request.Option) (*iam.ListRolePoliciesOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("ListRolePolicies"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.RoleName)
+	if _, ok := i.roles[name]; !ok {
+		return nil, noSuchEntity("role", name)
+	}
+	return &iam.ListRolePoliciesOutput{
+		PolicyNames: aws.StringSlice(sortedKeys(i.rolePolicies[name])),
+		IsTruncated: aws.Bool(false),
+	}, nil
+}
+
+// DeleteRolePolicyWithContext implements awsclient.IAM.
+func (i *iamAPI) DeleteRolePolicyWithContext(_ aws.Context, input *iam.DeleteRolePolicyInput, _ ...request.Option) (*iam.DeleteRolePolicyOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("DeleteRolePolicy"); err != nil {
+		return nil, err
+	}
+
+	name, policyName := aws.StringValue(input.RoleName), aws.StringValue(input.PolicyName)
+	if _, ok := i.rolePolicies[name][policyName]; !ok {
+		return nil, noSuchEntity("role policy", policyName)
+	}
+	delete(i.rolePolicies[name], policyName)
+	return &iam.DeleteRolePolicyOutput{}, nil
+}
+
+// GetInstanceProfileWithContext implements awsclient.IAM.
+func (i *iamAPI) GetInstanceProfileWithContext(_ aws.Context, input *iam.GetInstanceProfileInput, _ ...request.Option) (*iam.GetInstanceProfileOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("GetInstanceProfile"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.InstanceProfileName)
+	profile, ok := i.instanceProfiles[name]
+	if !ok {
+		return nil, noSuchEntity("instance profile", name)
+	}
+	return &iam.GetInstanceProfileOutput{InstanceProfile: copyOf(profile).(*iam.InstanceProfile)}, nil
+}
+
+// CreateInstanceProfileWithContext implements awsclient.IAM.
+func (i *iamAPI) CreateInstanceProfileWithContext(_ aws.Context, input *iam.CreateInstanceProfileInput, _ ...request.Option) (*iam.CreateInstanceProfileOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("CreateInstanceProfile"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.InstanceProfileName)
+	if _, ok := i.instanceProfiles[name]; ok {
+		return nil, awserr.New(iam.ErrCodeEntityAlreadyExistsException, fmt.Sprintf("Instance Profile %s already exists.", name), nil)
+	}
+	path := aws.StringValue(input.Path)
+	if path == "" {
+		path = "/"
+	}
+	i.instanceProfiles[name] = &iam.InstanceProfile{
+		Path:                aws.String(path),
+		InstanceProfileName: aws.String(name),
+		InstanceProfileId:   aws.String(i.newID("AIPA")),
+		Arn:                 aws.String(fmt.Sprintf("arn:aws:iam::%s:instance-profile%s%s", i.accountID, path, name)),
+	}
+	return &iam.CreateInstanceProfileOutput{InstanceProfile: copyOf(i.instanceProfiles[name]).(*iam.InstanceProfile)}, nil
+}
+
+// DeleteInstanceProfileWithContext implements awsclient.IAM. Like in IAM, deleting an instance profile that still has
+// a role fails.
+func (i *iamAPI) DeleteInstanceProfileWithContext(_ aws.Context, input *iam.DeleteInstanceProfileInput, _ ...request.Option) (*iam.DeleteInstanceProfileOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("DeleteInstanceProfile"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.InstanceProfileName)
+	profile, ok := i.instanceProfiles[name]
+	if !ok {
+		return nil, noSuchEntity("instance profile", name)
+	}
+	if len(profile.Roles) > 0 {
+		return nil, awserr.New(iam.ErrCodeDeleteConflictException, fmt.Sprintf("Cannot delete entity, instance profile %s still has roles.", name), nil)
+	}
+	delete(i.instanceProfiles, name)
+	return &iam.DeleteInstanceProfileOutput{}, nil
+}
+
+// AddRoleToInstanceProfileWithContext implements awsclient.IAM. Like in IAM, an instance profile can have at most one
+// role.
+func (i *iamAPI) AddRoleToInstanceProfileWithContext(_ aws.Context, input *iam.AddRoleToInstanceProfileInput, _ ...request.Option) (*iam.AddRoleToInstanceProfileOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("AddRoleToInstanceProfile"); err != nil {
+		return nil, err
+	}
+
+	name, roleName := aws.StringValue(input.InstanceProfileName), aws.StringValue(input.RoleName)
+	profile, ok := i.instanceProfiles[name]
+	if !ok {
+		return nil, noSuchEntity("instance profile", name)
+	}
+	role, ok := i.roles[roleName]
+	if !ok {
+		return nil, noSuchEntity("role", roleName)
+	}
+	if len(profile.Roles) > 0 {
+		return nil, awserr.New(iam.ErrCodeLimitExceededException, "Cannot exceed quota for InstanceSessionsPerInstanceProfile: 1", nil)
+	}
+	profile.Roles = append(profile.Roles, copyOf(role).(*iam.Role))
+	return &iam.AddRoleToInstanceProfileOutput{}, nil
+}
+
+// RemoveRoleFromInstanceProfileWithContext implements awsclient.IAM.
+func (i *iamAPI) RemoveRoleFromInstanceProfileWithContext(_ aws.Context, input *iam.RemoveRoleFromInstanceProfileInput, _ ...request.Option) (*iam.RemoveRoleFromInstanceProfileOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("RemoveRoleFromInstanceProfile"); err != nil {
+		return nil, err
+	}
+
+	name, roleName := aws.StringValue(input.InstanceProfileName), aws.StringValue(input.RoleName)
+	profile, ok := i.instanceProfiles[name]
+	if !ok {
+		return nil, noSuchEntity("instance profile", name)
+	}
+	for index, role := range profile.Roles {
+		if aws.StringValue(role.RoleName) == roleName {
+			profile.Roles = append(profile.Roles[:index], profile.Roles[index+1:]...)
+			return &iam.RemoveRoleFromInstanceProfileOutput{}, nil
+		}
+	}
+	return nil, noSuchEntity("role", roleName)
+}
+
+// GetUserWithContext implements awsclient.IAM.
+func (i *iamAPI) GetUserWithContext(_ aws.Context, input *iam.GetUserInput, _ ...request.Option) (*iam.GetUserOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("GetUser"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.UserName)
+	user, ok := i.users[name]
+	if !ok {
+		return nil, noSuchEntity("user", name)
+	}
+	return &iam.GetUserOutput{User: copyOf(user).(*iam.User)}, nil
+}
+
+// CreateUserWithContext implements awsclient.IAM.
+func (i *iamAPI) CreateUserWithContext(_ aws.Context, input *iam.CreateUserInput, _ ...request.Option) (*iam.CreateUserOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("CreateUser"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.UserName)
+	if _, ok := i.users[name]; ok {
+		return nil, awserr.New(iam.ErrCodeEntityAlreadyExistsException, fmt.Sprintf("User with name %s already exists.", name), nil)
+	}
+	path := aws.StringValue(input.Path)
+	if path == "" {
+		path = "/"
+	}
+	i.users[name] = &iam.User{
+		Path:     aws.String(path),
+		UserName: aws.String(name),
+		UserId:   aws.String(i.newID("AIDA")),
+		Arn:      aws.String(fmt.Sprintf("arn:aws:iam::%s:user%s%s", i.accountID, path, name)),
+	}
+	i.userPolicies[name] = make(map[string]string)
+	return &iam.CreateUserOutput{User: copyOf(i.users[name]).(*iam.User)}, nil
+}
+
+// DeleteUserWithContext implements awsclient.IAM. Like in IAM, deleting a user that still has inline policies or
+// access keys fails.
+func (i *iamAPI) DeleteUserWithContext(_ aws.Context, input *iam.DeleteUserInput, _ ...request.Option) (*iam.DeleteUserOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("DeleteUser"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.UserName)
+	if _, ok := i.users[name]; !ok {
+		return nil, noSuchEntity("user", name)
+	}
+	deleteConflict := awserr.New(iam.ErrCodeDeleteConflictException, fmt.Sprintf("Cannot delete entity, user %s is still in use.", name), nil)
+	if len(i.userPolicies[name]) > 0 {
+		return nil, deleteConflict
+	}
+	for _, accessKey := range i.accessKeys {
+		if aws.StringValue(accessKey.UserName) == name {
+			return nil, deleteConflict
+		}
+	}
+	delete(i.users, name)
+	delete(i.userPolicies, name)
+	return &iam.DeleteUserOutput{}, nil
+}
+
+// PutUserPolicyWithContext implements awsclient.IAM.
+func (i *iamAPI) PutUserPolicyWithContext(_ aws.Context, input *iam.PutUserPolicyInput, _ ...request.Option) (*iam.PutUserPolicyOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("PutUserPolicy"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.UserName)
+	if _, ok := i.users[name]; !ok {
+		return nil, noSuchEntity("user", name)
+	}
+	i.userPolicies[name][aws.StringValue(input.PolicyName)] = aws.StringValue(input.PolicyDocument)
+	return &iam.PutUserPolicyOutput{}, nil
+}
+
+// ListUserPoliciesWithContext implements awsclient.IAM. The results are not paginated.
+func (i *iamAPI) ListUserPoliciesWithContext(_ aws.Context, input *iam.ListUserPoliciesInput, _ ...request.Option) (*iam.ListUserPoliciesOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("ListUserPolicies"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.UserName)
+	if _, ok := i.users[name]; !ok {
+		return nil, noSuchEntity("user", name)
+	}
+	return &iam.ListUserPoliciesOutput{
+		PolicyNames: aws.StringSlice(sortedKeys(i.userPolicies[name])),
+		IsTruncated: aws.Bool(false),
+	}, nil
+}
+
+// DeleteUserPolicyWithContext implements awsclient.IAM.
+func (i *iamAPI) DeleteUserPolicyWithContext(_ aws.Context, input *iam.DeleteUserPolicyInput, _ ...request.Option) (*iam.DeleteUserPolicyOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("DeleteUserPolicy"); err != nil {
+		return nil, err
+	}
+
+	name, policyName := aws.StringValue(input.UserName), aws.StringValue(input.PolicyName)
+	if _, ok := i.userPolicies[name][policyName]; !ok {
+		return nil, noSuchEntity("user policy", policyName)
+	}
+	delete(i.userPolicies[name], policyName)
+	return &iam.DeleteUserPolicyOutput{}, nil
+}
+
+// CreateAccessKeyWithContext implements awsclient.IAM. Like in IAM, a user can have at most two access keys.
+func (i *iamAPI) CreateAccessKeyWithContext(_ aws.Context, input *iam.CreateAccessKeyInput, _ ...request.Option) (*iam.CreateAccessKeyOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("CreateAccessKey"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.UserName)
+	if _, ok := i.users[name]; !ok {
+		return nil, noSuchEntity("user", name)
+	}
+	count := 0
+	for _, accessKey := range i.accessKeys {
+		if aws.StringValue(accessKey.UserName) == name {
+			count++
+		}
+	}
+	if count >= 2 {
+		return nil, awserr.New(iam.ErrCodeLimitExceededException, "Cannot exceed quota for AccessKeysPerUser: 2", nil)
+	}
+
+	id := i.newID("AKIA")
+	i.accessKeys[id] = &iam.AccessKey{
+		UserName:        aws.String(name),
+		AccessKeyId:     aws.String(id),
+		Status:          aws.String("Active"),
+		SecretAccessKey: aws.String(i.newID("secret")),
+	}
+	return &iam.CreateAccessKeyOutput{AccessKey: copyOf(i.accessKeys[id]).(*iam.AccessKey)}, nil
+}
+
+// ListAccessKeysWithContext implements awsclient.IAM. The results are not paginated.
+func (i *iamAPI) ListAccessKeysWithContext(_ aws.Context, input *iam.ListAccessKeysInput, _ ...request.Option) (*iam.ListAccessKeysOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("ListAccessKeys"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.UserName)
+	if _, ok := i.users[name]; !ok {
+		return nil, noSuchEntity("user", name)
+	}
+	output := &iam.ListAccessKeysOutput{IsTruncated: aws.Bool(false)}
+	for _, id := range sortedKeys(i.accessKeys) {
+		if accessKey := i.accessKeys[id]; aws.StringValue(accessKey.UserName) == name {
+			output.AccessKeyMetadata = append(output.AccessKeyMetadata, &iam.AccessKeyMetadata{
+				UserName:    accessKey.UserName,
+				AccessKeyId: accessKey.AccessKeyId,
+				Status:      accessKey.Status,
+			})
+		}
+	}
+	return output, nil
+}
+
+// DeleteAccessKeyWithContext implements awsclient.IAM.
+func (i *iamAPI) DeleteAccessKeyWithContext(_ aws.Context, input *iam.DeleteAccessKeyInput, _ ...request.Option) (*iam.DeleteAccessKeyOutput, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("DeleteAccessKey"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.AccessKeyId)
+	if accessKey, ok := i.accessKeys[id]; !ok || aws.StringValue(accessKey.UserName) != aws.StringValue(input.UserName) {
+		return nil, noSuchEntity("access key", id)
+	}
+	delete(i.accessKeys, id)
+	return &iam.DeleteAccessKeyOutput{}, nil
+}
+
+// SimulatePrincipalPolicyWithContext implements awsclient.IAM. All actions are allowed unless they are denied with
+// Backend.DenyActions.
+func (i *iamAPI) SimulatePrincipalPolicyWithContext(_ aws.Context, input *iam.SimulatePrincipalPolicyInput, _ ...request.Option) (*iam.SimulatePolicyResponse, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if err := i.call("SimulatePrincipalPolicy"); err != nil {
+		return nil, err
+	}
+
+	output := &iam.SimulatePolicyResponse{IsTruncated: aws.Bool(false)}
+	for _, action := range input.ActionNames {
+		decision := iam.PolicyEvaluationDecisionTypeAllowed
+		if i.deniedActions[aws.StringValue(action)] {
+			decision = iam.PolicyEvaluationDecisionTypeImplicitDeny
+		}
+		output.EvaluationResults = append(output.EvaluationResults, &iam.EvaluationResult{
+			EvalActionName: aws.String(aws.StringValue(action)),
+			EvalDecision:   aws.String(decision),
+		})
+	}
+	return output, nil
+}
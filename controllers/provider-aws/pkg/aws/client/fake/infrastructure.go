@@ -0,0 +1,1353 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+
+	awsclient "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+const (
+	// ErrCodeInvalidDhcpOptionIDNotFound is the error code returned if DHCP options do not exist.
+	ErrCodeInvalidDhcpOptionIDNotFound = "InvalidDhcpOptionID.NotFound"
+	// ErrCodeInvalidRouteTableIDNotFound is the error code returned if a route table does not exist.
+	ErrCodeInvalidRouteTableIDNotFound = "InvalidRouteTableID.NotFound"
+	// ErrCodeInvalidAssociationIDNotFound is the error code returned if a route table association does not exist.
+	ErrCodeInvalidAssociationIDNotFound = "InvalidAssociationID.NotFound"
+	// ErrCodeInvalidRouteNotFound is the error code returned if a route to replace does not exist.
+	ErrCodeInvalidRouteNotFound = "InvalidRoute.NotFound"
+	// ErrCodeRouteAlreadyExists is the error code returned if a route with the same destination already exists.
+	ErrCodeRouteAlreadyExists = "RouteAlreadyExists"
+	// ErrCodeResourceAlreadyAssociated is the error code returned if a gateway or subnet is already associated.
+	ErrCodeResourceAlreadyAssociated = "Resource.AlreadyAssociated"
+	// ErrCodeGatewayNotAttached is the error code returned if an internet gateway is not attached to the VPC.
+	ErrCodeGatewayNotAttached = "Gateway.NotAttached"
+	// ErrCodeInvalidPermissionDuplicate is the error code returned if a security group rule already exists.
+	ErrCodeInvalidPermissionDuplicate = "InvalidPermission.Duplicate"
+	// ErrCodeInvalidPermissionNotFound is the error code returned if a security group rule that is revoked does not
+	// exist.
+	ErrCodeInvalidPermissionNotFound = "InvalidPermission.NotFound"
+	// ErrCodeInvalidGroupDuplicate is the error code returned if a security group with the same name exists.
+	ErrCodeInvalidGroupDuplicate = "InvalidGroup.Duplicate"
+	// ErrCodeInvalidAllocationIDNotFound is the error code returned if an Elastic IP does not exist.
+	ErrCodeInvalidAllocationIDNotFound = "InvalidAllocationID.NotFound"
+	// ErrCodeInvalidIPAddressInUse is the error code returned if an associated Elastic IP is released.
+	ErrCodeInvalidIPAddressInUse = "InvalidIPAddress.InUse"
+	// ErrCodeNatGatewayNotFound is the error code returned if a NAT gateway does not exist.
+	ErrCodeNatGatewayNotFound = "NatGatewayNotFound"
+	// ErrCodeInvalidVpcEndpointIDNotFound is the error code returned if a VPC endpoint does not exist.
+	ErrCodeInvalidVpcEndpointIDNotFound = "InvalidVpcEndpointId.NotFound"
+	// ErrCodeInvalidVpcPeeringConnectionIDNotFound is the error code returned if a VPC peering connection does not
+	// exist.
+	ErrCodeInvalidVpcPeeringConnectionIDNotFound = "InvalidVpcPeeringConnectionID.NotFound"
+	// ErrCodeInvalidStateTransition is the error code returned if a VPC peering connection cannot be accepted in its
+	// state.
+	ErrCodeInvalidStateTransition = "InvalidStateTransition"
+	// ErrCodeOperationNotPermitted is the error code returned if a VPC peering connection of another account is
+	// accepted.
+	ErrCodeOperationNotPermitted = "OperationNotPermitted"
+	// ErrCodeInvalidKeyPairNotFound is the error code returned if a key pair does not exist.
+	ErrCodeInvalidKeyPairNotFound = "InvalidKeyPair.NotFound"
+	// ErrCodeInvalidKeyPairDuplicate is the error code returned if a key pair with the same name exists.
+	ErrCodeInvalidKeyPairDuplicate = "InvalidKeyPair.Duplicate"
+	// ErrCodeInvalidIDNotFound is the error code returned if a resource to tag does not exist.
+	ErrCodeInvalidIDNotFound = "InvalidID"
+)
+
+func dhcpOptionsResource(options *ec2.DhcpOptions) *resource {
+	return &resource{
+		tags: options.Tags,
+		attributes: map[string][]string{
+			"dhcp-options-id": {aws.StringValue(options.DhcpOptionsId)},
+		},
+	}
+}
+
+func routeTableResource(routeTable *ec2.RouteTable) *resource {
+	r := &resource{
+		tags: routeTable.Tags,
+		attributes: map[string][]string{
+			"route-table-id":        {aws.StringValue(routeTable.RouteTableId)},
+			"vpc-id":                {aws.StringValue(routeTable.VpcId)},
+			"association.subnet-id": nil,
+			"association.main":      nil,
+		},
+	}
+	for _, association := range routeTable.Associations {
+		r.attributes["association.subnet-id"] = append(r.attributes["association.subnet-id"], aws.StringValue(association.SubnetId))
+		r.attributes["association.main"] = append(r.attributes["association.main"], fmt.Sprintf("%t", aws.BoolValue(association.Main)))
+	}
+	return r
+}
+
+func addressResource(address *ec2.Address) *resource {
+	return &resource{
+		tags: address.Tags,
+		attributes: map[string][]string{
+			"allocation-id":  {aws.StringValue(address.AllocationId)},
+			"association-id": {aws.StringValue(address.AssociationId)},
+			"domain":         {aws.StringValue(address.Domain)},
+			"public-ip":      {aws.StringValue(address.PublicIp)},
+		},
+	}
+}
+
+func natGatewayResource(gateway *ec2.NatGateway) *resource {
+	return &resource{
+		tags: gateway.Tags,
+		attributes: map[string][]string{
+			"nat-gateway-id": {aws.StringValue(gateway.NatGatewayId)},
+			"state":          {aws.StringValue(gateway.State)},
+			"subnet-id":      {aws.StringValue(gateway.SubnetId)},
+			"vpc-id":         {aws.StringValue(gateway.VpcId)},
+		},
+	}
+}
+
+func vpcEndpointResource(endpoint *ec2.VpcEndpoint) *resource {
+	return &resource{
+		attributes: map[string][]string{
+			"service-name":       {aws.StringValue(endpoint.ServiceName)},
+			"vpc-endpoint-id":    {aws.StringValue(endpoint.VpcEndpointId)},
+			"vpc-endpoint-state": {aws.StringValue(endpoint.State)},
+			"vpc-id":             {aws.StringValue(endpoint.VpcId)},
+		},
+	}
+}
+
+func vpcPeeringConnectionResource(connection *ec2.VpcPeeringConnection) *resource {
+	return &resource{
+		tags: connection.Tags,
+		attributes: map[string][]string{
+			"accepter-vpc-info.vpc-id":  {aws.StringValue(connection.AccepterVpcInfo.VpcId)},
+			"requester-vpc-info.vpc-id": {aws.StringValue(connection.RequesterVpcInfo.VpcId)},
+			"status-code":               {aws.StringValue(connection.Status.Code)},
+			"vpc-peering-connection-id": {aws.StringValue(connection.VpcPeeringConnectionId)},
+		},
+	}
+}
+
+// CreateTagsWithContext implements awsclient.EC2.
+func (e *ec2API) CreateTagsWithContext(_ aws.Context, input *ec2.CreateTagsInput, _ ...request.Option) (*ec2.CreateTagsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("CreateTags"); err != nil {
+		return nil, err
+	}
+
+	var targets []*[]*ec2.Tag
+	for _, id := range aws.StringValueSlice(input.Resources) {
+		tags := e.tagsOf(id)
+		if tags == nil {
+			return nil, awserr.New(ErrCodeInvalidIDNotFound, fmt.Sprintf("The ID '%s' is not valid", id), nil)
+		}
+		targets = append(targets, tags)
+	}
+
+	for _, tags := range targets {
+		merged := make(map[string]string)
+		for _, tag := range *tags {
+			merged[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		for _, tag := range input.Tags {
+			merged[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		*tags = ec2Tags(merged)
+	}
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+// CreateVpcWithContext implements awsclient.EC2. Like in EC2, the VPC gets a main route table.
+func (e *ec2API) CreateVpcWithContext(_ aws.Context, input *ec2.CreateVpcInput, _ ...request.Option) (*ec2.CreateVpcOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("CreateVpc"); err != nil {
+		return nil, err
+	}
+
+	id := e.createVPC(aws.StringValue(input.CidrBlock), nil)
+	return &ec2.CreateVpcOutput{Vpc: copyOf(e.vpcs[id]).(*ec2.Vpc)}, nil
+}
+
+// ModifyVpcAttributeWithContext implements awsclient.EC2. The attributes are not stored.
+func (e *ec2API) ModifyVpcAttributeWithContext(_ aws.Context, input *ec2.ModifyVpcAttributeInput, _ ...request.Option) (*ec2.ModifyVpcAttributeOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("ModifyVpcAttribute"); err != nil {
+		return nil, err
+	}
+
+	if _, err := lookup(e.vpcs, []string{aws.StringValue(input.VpcId)}, ErrCodeInvalidVpcIDNotFound, "vpc ID"); err != nil {
+		return nil, err
+	}
+	if (input.EnableDnsSupport == nil) == (input.EnableDnsHostnames == nil) {
+		return nil, awserr.New(ErrCodeInvalidParameterValue, "Exactly one attribute can be modified at a time", nil)
+	}
+	return &ec2.ModifyVpcAttributeOutput{}, nil
+}
+
+// DeleteVpcWithContext implements awsclient.EC2. Like in EC2, deleting a VPC that still contains subnets, security
+// groups, route tables other than the main route table, or attached internet gateways fails.
+func (e *ec2API) DeleteVpcWithContext(_ aws.Context, input *ec2.DeleteVpcInput, _ ...request.Option) (*ec2.DeleteVpcOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteVpc"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.VpcId)
+	if _, ok := e.vpcs[id]; !ok {
+		return nil, awserr.New(ErrCodeInvalidVpcIDNotFound, fmt.Sprintf("The vpc ID '%s' does not exist", id), nil)
+	}
+	dependencyViolation := awserr.New(ErrCodeDependencyViolation, fmt.Sprintf("The vpc '%s' has dependencies and cannot be deleted.", id), nil)
+	for _, subnet := range e.subnets {
+		if aws.StringValue(subnet.VpcId) == id {
+			return nil, dependencyViolation
+		}
+	}
+	for _, group := range e.securityGroups {
+		if aws.StringValue(group.VpcId) == id {
+			return nil, dependencyViolation
+		}
+	}
+	for _, gateway := range e.internetGateways {
+		for _, attachment := range gateway.Attachments {
+			if aws.StringValue(attachment.VpcId) == id {
+				return nil, dependencyViolation
+			}
+		}
+	}
+	var mainRouteTableID string
+	for routeTableID, routeTable := range e.routeTables {
+		if aws.StringValue(routeTable.VpcId) != id {
+			continue
+		}
+		if len(routeTable.Associations) == 0 || !aws.BoolValue(routeTable.Associations[0].Main) {
+			return nil, dependencyViolation
+		}
+		mainRouteTableID = routeTableID
+	}
+
+	delete(e.routeTables, mainRouteTableID)
+	delete(e.vpcs, id)
+	return &ec2.DeleteVpcOutput{}, nil
+}
+
+// DescribeDhcpOptionsWithContext implements awsclient.EC2.
+func (e *ec2API) DescribeDhcpOptionsWithContext(_ aws.Context, input *ec2.DescribeDhcpOptionsInput, _ ...request.Option) (*ec2.DescribeDhcpOptionsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeDhcpOptions"); err != nil {
+		return nil, err
+	}
+
+	ids, err := lookup(e.dhcpOptions, aws.StringValueSlice(input.DhcpOptionsIds), ErrCodeInvalidDhcpOptionIDNotFound, "dhcp option ID")
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DescribeDhcpOptionsOutput{}
+	for _, id := range ids {
+		options := e.dhcpOptions[id]
+		ok, err := dhcpOptionsResource(options).matches(input.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			output.DhcpOptions = append(output.DhcpOptions, copyOf(options).(*ec2.DhcpOptions))
+		}
+	}
+	return output, nil
+}
+
+// CreateDhcpOptionsWithContext implements awsclient.EC2.
+func (e *ec2API) CreateDhcpOptionsWithContext(_ aws.Context, input *ec2.CreateDhcpOptionsInput, _ ...request.Option) (*ec2.CreateDhcpOptionsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("CreateDhcpOptions"); err != nil {
+		return nil, err
+	}
+
+	id := e.newID("dopt")
+	options := &ec2.DhcpOptions{DhcpOptionsId: aws.String(id)}
+	for _, configuration := range input.DhcpConfigurations {
+		value := &ec2.DhcpConfiguration{Key: configuration.Key}
+		for _, v := range configuration.Values {
+			value.Values = append(value.Values, &ec2.AttributeValue{Value: v})
+		}
+		options.DhcpConfigurations = append(options.DhcpConfigurations, value)
+	}
+	e.dhcpOptions[id] = options
+	return &ec2.CreateDhcpOptionsOutput{DhcpOptions: copyOf(options).(*ec2.DhcpOptions)}, nil
+}
+
+// AssociateDhcpOptionsWithContext implements awsclient.EC2.
+func (e *ec2API) AssociateDhcpOptionsWithContext(_ aws.Context, input *ec2.AssociateDhcpOptionsInput, _ ...request.Option) (*ec2.AssociateDhcpOptionsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("AssociateDhcpOptions"); err != nil {
+		return nil, err
+	}
+
+	vpcID, optionsID := aws.StringValue(input.VpcId), aws.StringValue(input.DhcpOptionsId)
+	if _, err := lookup(e.vpcs, []string{vpcID}, ErrCodeInvalidVpcIDNotFound, "vpc ID"); err != nil {
+		return nil, err
+	}
+	if optionsID != "default" {
+		if _, err := lookup(e.dhcpOptions, []string{optionsID}, ErrCodeInvalidDhcpOptionIDNotFound, "dhcp option ID"); err != nil {
+			return nil, err
+		}
+	}
+	e.vpcs[vpcID].DhcpOptionsId = aws.String(optionsID)
+	return &ec2.AssociateDhcpOptionsOutput{}, nil
+}
+
+// DeleteDhcpOptionsWithContext implements awsclient.EC2. Like in EC2, deleting DHCP options that are associated with a
+// VPC fails.
+func (e *ec2API) DeleteDhcpOptionsWithContext(_ aws.Context, input *ec2.DeleteDhcpOptionsInput, _ ...request.Option) (*ec2.DeleteDhcpOptionsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteDhcpOptions"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.DhcpOptionsId)
+	if _, ok := e.dhcpOptions[id]; !ok {
+		return nil, awserr.New(ErrCodeInvalidDhcpOptionIDNotFound, fmt.Sprintf("The dhcpOption ID '%s' does not exist", id), nil)
+	}
+	for _, vpc := range e.vpcs {
+		if aws.StringValue(vpc.DhcpOptionsId) == id {
+			return nil, awserr.New(ErrCodeDependencyViolation, fmt.Sprintf("The dhcpOptions '%s' has dependencies and cannot be deleted.", id), nil)
+		}
+	}
+	delete(e.dhcpOptions, id)
+	return &ec2.DeleteDhcpOptionsOutput{}, nil
+}
+
+// CreateInternetGatewayWithContext implements awsclient.EC2.
+func (e *ec2API) CreateInternetGatewayWithContext(_ aws.Context, _ *ec2.CreateInternetGatewayInput, _ ...request.Option) (*ec2.CreateInternetGatewayOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("CreateInternetGateway"); err != nil {
+		return nil, err
+	}
+
+	id := e.newID("igw")
+	e.internetGateways[id] = &ec2.InternetGateway{InternetGatewayId: aws.String(id)}
+	return &ec2.CreateInternetGatewayOutput{InternetGateway: copyOf(e.internetGateways[id]).(*ec2.InternetGateway)}, nil
+}
+
+// AttachInternetGatewayWithContext implements awsclient.EC2.
+func (e *ec2API) AttachInternetGatewayWithContext(_ aws.Context, input *ec2.AttachInternetGatewayInput, _ ...request.Option) (*ec2.AttachInternetGatewayOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("AttachInternetGateway"); err != nil {
+		return nil, err
+	}
+
+	id, vpcID := aws.StringValue(input.InternetGatewayId), aws.StringValue(input.VpcId)
+	if _, err := lookup(e.internetGateways, []string{id}, ErrCodeInvalidInternetGatewayIDNotFound, "internet gateway ID"); err != nil {
+		return nil, err
+	}
+	if _, err := lookup(e.vpcs, []string{vpcID}, ErrCodeInvalidVpcIDNotFound, "vpc ID"); err != nil {
+		return nil, err
+	}
+	gateway := e.internetGateways[id]
+	if len(gateway.Attachments) > 0 {
+		return nil, awserr.New(ErrCodeResourceAlreadyAssociated, fmt.Sprintf("resource %s is already attached to network %s", id, aws.StringValue(gateway.Attachments[0].VpcId)), nil)
+	}
+	gateway.Attachments = []*ec2.InternetGatewayAttachment{
+		{
+			VpcId: aws.String(vpcID),
+			State: aws.String(ec2.AttachmentStatusAttached),
+		},
+	}
+	return &ec2.AttachInternetGatewayOutput{}, nil
+}
+
+// DetachInternetGatewayWithContext implements awsclient.EC2.
+func (e *ec2API) DetachInternetGatewayWithContext(_ aws.Context, input *ec2.DetachInternetGatewayInput, _ ...request.Option) (*ec2.DetachInternetGatewayOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DetachInternetGateway"); err != nil {
+		return nil, err
+	}
+
+	id, vpcID := aws.StringValue(input.InternetGatewayId), aws.StringValue(input.VpcId)
+	if _, err := lookup(e.internetGateways, []string{id}, ErrCodeInvalidInternetGatewayIDNotFound, "internet gateway ID"); err != nil {
+		return nil, err
+	}
+	gateway := e.internetGateways[id]
+	if len(gateway.Attachments) == 0 || aws.StringValue(gateway.Attachments[0].VpcId) != vpcID {
+		return nil, awserr.New(ErrCodeGatewayNotAttached, fmt.Sprintf("resource %s is not attached to network %s", id, vpcID), nil)
+	}
+	gateway.Attachments = nil
+	return &ec2.DetachInternetGatewayOutput{}, nil
+}
+
+// DeleteInternetGatewayWithContext implements awsclient.EC2. Like in EC2, deleting an attached internet gateway fails.
+func (e *ec2API) DeleteInternetGatewayWithContext(_ aws.Context, input *ec2.DeleteInternetGatewayInput, _ ...request.Option) (*ec2.DeleteInternetGatewayOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteInternetGateway"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.InternetGatewayId)
+	gateway, ok := e.internetGateways[id]
+	if !ok {
+		return nil, awserr.New(ErrCodeInvalidInternetGatewayIDNotFound, fmt.Sprintf("The internetGateway ID '%s' does not exist", id), nil)
+	}
+	if len(gateway.Attachments) > 0 {
+		return nil, awserr.New(ErrCodeDependencyViolation, fmt.Sprintf("The internetGateway '%s' has dependencies and cannot be deleted.", id), nil)
+	}
+	delete(e.internetGateways, id)
+	return &ec2.DeleteInternetGatewayOutput{}, nil
+}
+
+// CreateSubnetWithContext implements awsclient.EC2.
+func (e *ec2API) CreateSubnetWithContext(_ aws.Context, input *ec2.CreateSubnetInput, _ ...request.Option) (*ec2.CreateSubnetOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("CreateSubnet"); err != nil {
+		return nil, err
+	}
+
+	vpcID := aws.StringValue(input.VpcId)
+	if _, err := lookup(e.vpcs, []string{vpcID}, ErrCodeInvalidVpcIDNotFound, "vpc ID"); err != nil {
+		return nil, err
+	}
+	id := e.newID("subnet")
+	e.subnets[id] = &ec2.Subnet{
+		SubnetId:         aws.String(id),
+		VpcId:            aws.String(vpcID),
+		AvailabilityZone: input.AvailabilityZone,
+		CidrBlock:        input.CidrBlock,
+		State:            aws.String(ec2.SubnetStateAvailable),
+	}
+	return &ec2.CreateSubnetOutput{Subnet: copyOf(e.subnets[id]).(*ec2.Subnet)}, nil
+}
+
+// DeleteSubnetWithContext implements awsclient.EC2. Like in EC2, deleting a subnet that still contains NAT gateways
+// or VPC endpoints fails.
+func (e *ec2API) DeleteSubnetWithContext(_ aws.Context, input *ec2.DeleteSubnetInput, _ ...request.Option) (*ec2.DeleteSubnetOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteSubnet"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.SubnetId)
+	if _, ok := e.subnets[id]; !ok {
+		return nil, awserr.New(ErrCodeInvalidSubnetIDNotFound, fmt.Sprintf("The subnet ID '%s' does not exist", id), nil)
+	}
+	dependencyViolation := awserr.New(ErrCodeDependencyViolation, fmt.Sprintf("The subnet '%s' has dependencies and cannot be deleted.", id), nil)
+	for _, gateway := range e.natGateways {
+		if aws.StringValue(gateway.SubnetId) == id && aws.StringValue(gateway.State) != ec2.NatGatewayStateDeleted {
+			return nil, dependencyViolation
+		}
+	}
+	for _, endpoint := range e.vpcEndpoints {
+		if containsAny(aws.StringValueSlice(endpoint.SubnetIds), []string{id}) {
+			return nil, dependencyViolation
+		}
+	}
+	for _, routeTable := range e.routeTables {
+		var associations []*ec2.RouteTableAssociation
+		for _, association := range routeTable.Associations {
+			if aws.StringValue(association.SubnetId) != id {
+				associations = append(associations, association)
+			}
+		}
+		routeTable.Associations = associations
+	}
+	delete(e.subnets, id)
+	return &ec2.DeleteSubnetOutput{}, nil
+}
+
+// DescribeRouteTablesWithContext implements awsclient.EC2.
+func (e *ec2API) DescribeRouteTablesWithContext(_ aws.Context, input *ec2.DescribeRouteTablesInput, _ ...request.Option) (*ec2.DescribeRouteTablesOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeRouteTables"); err != nil {
+		return nil, err
+	}
+
+	ids, err := lookup(e.routeTables, aws.StringValueSlice(input.RouteTableIds), ErrCodeInvalidRouteTableIDNotFound, "route table ID")
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DescribeRouteTablesOutput{}
+	for _, id := range ids {
+		routeTable := e.routeTables[id]
+		ok, err := routeTableResource(routeTable).matches(input.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			output.RouteTables = append(output.RouteTables, copyOf(routeTable).(*ec2.RouteTable))
+		}
+	}
+	return output, nil
+}
+
+// CreateRouteTableWithContext implements awsclient.EC2.
+func (e *ec2API) CreateRouteTableWithContext(_ aws.Context, input *ec2.CreateRouteTableInput, _ ...request.Option) (*ec2.CreateRouteTableOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("CreateRouteTable"); err != nil {
+		return nil, err
+	}
+
+	vpcID := aws.StringValue(input.VpcId)
+	if _, err := lookup(e.vpcs, []string{vpcID}, ErrCodeInvalidVpcIDNotFound, "vpc ID"); err != nil {
+		return nil, err
+	}
+	id := e.newID("rtb")
+	e.routeTables[id] = &ec2.RouteTable{
+		RouteTableId: aws.String(id),
+		VpcId:        aws.String(vpcID),
+		Routes: []*ec2.Route{
+			{DestinationCidrBlock: e.vpcs[vpcID].CidrBlock, GatewayId: aws.String("local")},
+		},
+	}
+	return &ec2.CreateRouteTableOutput{RouteTable: copyOf(e.routeTables[id]).(*ec2.RouteTable)}, nil
+}
+
+// CreateRouteWithContext implements awsclient.EC2.
+func (e *ec2API) CreateRouteWithContext(_ aws.Context, input *ec2.CreateRouteInput, _ ...request.Option) (*ec2.CreateRouteOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("CreateRoute"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.RouteTableId)
+	routeTable, ok := e.routeTables[id]
+	if !ok {
+		return nil, awserr.New(ErrCodeInvalidRouteTableIDNotFound, fmt.Sprintf("The routeTable ID '%s' does not exist", id), nil)
+	}
+	for _, route := range routeTable.Routes {
+		if aws.StringValue(route.DestinationCidrBlock) == aws.StringValue(input.DestinationCidrBlock) {
+			return nil, awserr.New(ErrCodeRouteAlreadyExists, fmt.Sprintf("The route identified by %s already exists.", aws.StringValue(input.DestinationCidrBlock)), nil)
+		}
+	}
+	if input.VpcPeeringConnectionId != nil {
+		if _, err := lookup(e.vpcPeerings, []string{aws.StringValue(input.VpcPeeringConnectionId)}, ErrCodeInvalidVpcPeeringConnectionIDNotFound, "vpc peering connection ID"); err != nil {
+			return nil, err
+		}
+	}
+	routeTable.Routes = append(routeTable.Routes, &ec2.Route{
+		DestinationCidrBlock:   input.DestinationCidrBlock,
+		GatewayId:              input.GatewayId,
+		NatGatewayId:           input.NatGatewayId,
+		VpcPeeringConnectionId: input.VpcPeeringConnectionId,
+	})
+	return &ec2.CreateRouteOutput{Return: aws.Bool(true)}, nil
+}
+
+// ReplaceRouteWithContext implements awsclient.EC2.
+func (e *ec2API) ReplaceRouteWithContext(_ aws.Context, input *ec2.ReplaceRouteInput, _ ...request.Option) (*ec2.ReplaceRouteOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("ReplaceRoute"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.RouteTableId)
+	routeTable, ok := e.routeTables[id]
+	if !ok {
+		return nil, awserr.New(ErrCodeInvalidRouteTableIDNotFound, fmt.Sprintf("The routeTable ID '%s' does not exist", id), nil)
+	}
+	for _, route := range routeTable.Routes {
+		if aws.StringValue(route.DestinationCidrBlock) == aws.StringValue(input.DestinationCidrBlock) {
+			route.GatewayId = input.GatewayId
+			route.NatGatewayId = input.NatGatewayId
+			route.VpcPeeringConnectionId = input.VpcPeeringConnectionId
+			return &ec2.ReplaceRouteOutput{}, nil
+		}
+	}
+	return nil, awserr.New(ErrCodeInvalidRouteNotFound, fmt.Sprintf("no route with destination-cidr-block %s in route table %s", aws.StringValue(input.DestinationCidrBlock), id), nil)
+}
+
+// DeleteRouteWithContext implements awsclient.EC2.
+func (e *ec2API) DeleteRouteWithContext(_ aws.Context, input *ec2.DeleteRouteInput, _ ...request.Option) (*ec2.DeleteRouteOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteRoute"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.RouteTableId)
+	routeTable, ok := e.routeTables[id]
+	if !ok {
+		return nil, awserr.New(ErrCodeInvalidRouteTableIDNotFound, fmt.Sprintf("The routeTable ID '%s' does not exist", id), nil)
+	}
+	for i, route := range routeTable.Routes {
+		if aws.StringValue(route.DestinationCidrBlock) == aws.StringValue(input.DestinationCidrBlock) {
+			routeTable.Routes = append(routeTable.Routes[:i], routeTable.Routes[i+1:]...)
+			return &ec2.DeleteRouteOutput{}, nil
+		}
+	}
+	return nil, awserr.New(ErrCodeInvalidRouteNotFound, fmt.Sprintf("no route with destination-cidr-block %s in route table %s", aws.StringValue(input.DestinationCidrBlock), id), nil)
+}
+
+// AssociateRouteTableWithContext implements awsclient.EC2. Like in EC2, a subnet can only be explicitly associated
+// with one route table.
+func (e *ec2API) AssociateRouteTableWithContext(_ aws.Context, input *ec2.AssociateRouteTableInput, _ ...request.Option) (*ec2.AssociateRouteTableOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("AssociateRouteTable"); err != nil {
+		return nil, err
+	}
+
+	id, subnetID := aws.StringValue(input.RouteTableId), aws.StringValue(input.SubnetId)
+	if _, err := lookup(e.routeTables, []string{id}, ErrCodeInvalidRouteTableIDNotFound, "route table ID"); err != nil {
+		return nil, err
+	}
+	if _, err := lookup(e.subnets, []string{subnetID}, ErrCodeInvalidSubnetIDNotFound, "subnet ID"); err != nil {
+		return nil, err
+	}
+	for _, routeTable := range e.routeTables {
+		for _, association := range routeTable.Associations {
+			if aws.StringValue(association.SubnetId) == subnetID {
+				return nil, awserr.New(ErrCodeResourceAlreadyAssociated, fmt.Sprintf("the specified association for route table %s conflicts with an existing association", id), nil)
+			}
+		}
+	}
+
+	associationID := e.newID("rtbassoc")
+	e.routeTables[id].Associations = append(e.routeTables[id].Associations, &ec2.RouteTableAssociation{
+		RouteTableAssociationId: aws.String(associationID),
+		RouteTableId:            aws.String(id),
+		SubnetId:                aws.String(subnetID),
+		Main:                    aws.Bool(false),
+	})
+	return &ec2.AssociateRouteTableOutput{AssociationId: aws.String(associationID)}, nil
+}
+
+// ReplaceRouteTableAssociationWithContext implements awsclient.EC2.
+func (e *ec2API) ReplaceRouteTableAssociationWithContext(_ aws.Context, input *ec2.ReplaceRouteTableAssociationInput, _ ...request.Option) (*ec2.ReplaceRouteTableAssociationOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("ReplaceRouteTableAssociation"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.RouteTableId)
+	if _, err := lookup(e.routeTables, []string{id}, ErrCodeInvalidRouteTableIDNotFound, "route table ID"); err != nil {
+		return nil, err
+	}
+	association := e.removeRouteTableAssociation(aws.StringValue(input.AssociationId))
+	if association == nil {
+		return nil, awserr.New(ErrCodeInvalidAssociationIDNotFound, fmt.Sprintf("The association ID '%s' does not exist", aws.StringValue(input.AssociationId)), nil)
+	}
+
+	association.RouteTableAssociationId = aws.String(e.newID("rtbassoc"))
+	association.RouteTableId = aws.String(id)
+	e.routeTables[id].Associations = append(e.routeTables[id].Associations, association)
+	return &ec2.ReplaceRouteTableAssociationOutput{NewAssociationId: association.RouteTableAssociationId}, nil
+}
+
+// DisassociateRouteTableWithContext implements awsclient.EC2.
+func (e *ec2API) DisassociateRouteTableWithContext(_ aws.Context, input *ec2.DisassociateRouteTableInput, _ ...request.Option) (*ec2.DisassociateRouteTableOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DisassociateRouteTable"); err != nil {
+		return nil, err
+	}
+
+	if e.removeRouteTableAssociation(aws.StringValue(input.AssociationId)) == nil {
+		return nil, awserr.New(ErrCodeInvalidAssociationIDNotFound, fmt.Sprintf("The association ID '%s' does not exist", aws.StringValue(input.AssociationId)), nil)
+	}
+	return &ec2.DisassociateRouteTableOutput{}, nil
+}
+
+// removeRouteTableAssociation removes the subnet association with the given ID from its route table and returns it,
+// or nil if it does not exist. The lock must be held by the caller.
+func (e *ec2API) removeRouteTableAssociation(associationID string) *ec2.RouteTableAssociation {
+	for _, routeTable := range e.routeTables {
+		for i, association := range routeTable.Associations {
+			if aws.StringValue(association.RouteTableAssociationId) == associationID && !aws.BoolValue(association.Main) {
+				routeTable.Associations = append(routeTable.Associations[:i], routeTable.Associations[i+1:]...)
+				return association
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteRouteTableWithContext implements awsclient.EC2. Like in EC2, deleting a route table that is still associated
+// fails.
+func (e *ec2API) DeleteRouteTableWithContext(_ aws.Context, input *ec2.DeleteRouteTableInput, _ ...request.Option) (*ec2.DeleteRouteTableOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteRouteTable"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.RouteTableId)
+	routeTable, ok := e.routeTables[id]
+	if !ok {
+		return nil, awserr.New(ErrCodeInvalidRouteTableIDNotFound, fmt.Sprintf("The routeTable ID '%s' does not exist", id), nil)
+	}
+	if len(routeTable.Associations) > 0 {
+		return nil, awserr.New(ErrCodeDependencyViolation, fmt.Sprintf("The routeTable '%s' has dependencies and cannot be deleted.", id), nil)
+	}
+	delete(e.routeTables, id)
+	return &ec2.DeleteRouteTableOutput{}, nil
+}
+
+// CreateSecurityGroupWithContext implements awsclient.EC2. Like in EC2, the security group gets a rule that allows
+// all outbound traffic.
+func (e *ec2API) CreateSecurityGroupWithContext(_ aws.Context, input *ec2.CreateSecurityGroupInput, _ ...request.Option) (*ec2.CreateSecurityGroupOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("CreateSecurityGroup"); err != nil {
+		return nil, err
+	}
+
+	vpcID, name := aws.StringValue(input.VpcId), aws.StringValue(input.GroupName)
+	if _, err := lookup(e.vpcs, []string{vpcID}, ErrCodeInvalidVpcIDNotFound, "vpc ID"); err != nil {
+		return nil, err
+	}
+	for _, group := range e.securityGroups {
+		if aws.StringValue(group.VpcId) == vpcID && aws.StringValue(group.GroupName) == name {
+			return nil, awserr.New(ErrCodeInvalidGroupDuplicate, fmt.Sprintf("The security group '%s' already exists for VPC '%s'", name, vpcID), nil)
+		}
+	}
+
+	id := e.newID("sg")
+	e.securityGroups[id] = &ec2.SecurityGroup{
+		GroupId:     aws.String(id),
+		GroupName:   aws.String(name),
+		Description: input.Description,
+		VpcId:       aws.String(vpcID),
+		OwnerId:     aws.String(e.accountID),
+		IpPermissionsEgress: []*ec2.IpPermission{
+			{
+				IpProtocol: aws.String("-1"),
+				IpRanges:   []*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+			},
+		},
+	}
+	return &ec2.CreateSecurityGroupOutput{GroupId: aws.String(id)}, nil
+}
+
+// AuthorizeSecurityGroupIngressWithContext implements awsclient.EC2.
+func (e *ec2API) AuthorizeSecurityGroupIngressWithContext(_ aws.Context, input *ec2.AuthorizeSecurityGroupIngressInput, _ ...request.Option) (*ec2.AuthorizeSecurityGroupIngressOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("AuthorizeSecurityGroupIngress"); err != nil {
+		return nil, err
+	}
+
+	if err := e.authorize(aws.StringValue(input.GroupId), input.IpPermissions, true); err != nil {
+		return nil, err
+	}
+	return &ec2.AuthorizeSecurityGroupIngressOutput{}, nil
+}
+
+// AuthorizeSecurityGroupEgressWithContext implements awsclient.EC2.
+func (e *ec2API) AuthorizeSecurityGroupEgressWithContext(_ aws.Context, input *ec2.AuthorizeSecurityGroupEgressInput, _ ...request.Option) (*ec2.AuthorizeSecurityGroupEgressOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("AuthorizeSecurityGroupEgress"); err != nil {
+		return nil, err
+	}
+
+	if err := e.authorize(aws.StringValue(input.GroupId), input.IpPermissions, false); err != nil {
+		return nil, err
+	}
+	return &ec2.AuthorizeSecurityGroupEgressOutput{}, nil
+}
+
+// authorize adds the given permissions to the ingress or egress rules of the security group <id>. Like in EC2, it
+// fails without any change if one of the permissions already exists. The lock must be held by the caller.
+func (e *ec2API) authorize(id string, permissions []*ec2.IpPermission, ingress bool) error {
+	group, ok := e.securityGroups[id]
+	if !ok {
+		return awserr.New(ErrCodeInvalidGroupNotFound, fmt.Sprintf("The security group '%s' does not exist", id), nil)
+	}
+	existing := &group.IpPermissionsEgress
+	if ingress {
+		existing = &group.IpPermissions
+	}
+
+	keys := make(map[string]bool)
+	for _, key := range permissionKeys(*existing) {
+		keys[key] = true
+	}
+	for _, key := range permissionKeys(permissions) {
+		if keys[key] {
+			return awserr.New(ErrCodeInvalidPermissionDuplicate, fmt.Sprintf("the specified rule %q already exists", key), nil)
+		}
+	}
+	for _, permission := range permissions {
+		for _, pair := range permission.UserIdGroupPairs {
+			if _, ok := e.securityGroups[aws.StringValue(pair.GroupId)]; !ok {
+				return awserr.New(ErrCodeInvalidGroupNotFound, fmt.Sprintf("The security group '%s' does not exist", aws.StringValue(pair.GroupId)), nil)
+			}
+		}
+	}
+
+	for _, permission := range permissions {
+		*existing = append(*existing, copyOf(permission).(*ec2.IpPermission))
+	}
+	return nil
+}
+
+// RevokeSecurityGroupIngressWithContext implements awsclient.EC2.
+func (e *ec2API) RevokeSecurityGroupIngressWithContext(_ aws.Context, input *ec2.RevokeSecurityGroupIngressInput, _ ...request.Option) (*ec2.RevokeSecurityGroupIngressOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("RevokeSecurityGroupIngress"); err != nil {
+		return nil, err
+	}
+
+	if err := e.revoke(aws.StringValue(input.GroupId), input.IpPermissions, true); err != nil {
+		return nil, err
+	}
+	return &ec2.RevokeSecurityGroupIngressOutput{}, nil
+}
+
+// RevokeSecurityGroupEgressWithContext implements awsclient.EC2.
+func (e *ec2API) RevokeSecurityGroupEgressWithContext(_ aws.Context, input *ec2.RevokeSecurityGroupEgressInput, _ ...request.Option) (*ec2.RevokeSecurityGroupEgressOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("RevokeSecurityGroupEgress"); err != nil {
+		return nil, err
+	}
+
+	if err := e.revoke(aws.StringValue(input.GroupId), input.IpPermissions, false); err != nil {
+		return nil, err
+	}
+	return &ec2.RevokeSecurityGroupEgressOutput{}, nil
+}
+
+// revoke removes the given permissions from the ingress or egress rules of the security group <id>. Like in EC2, it
+// fails without any change if one of the permissions does not exist. The lock must be held by the caller.
+func (e *ec2API) revoke(id string, permissions []*ec2.IpPermission, ingress bool) error {
+	group, ok := e.securityGroups[id]
+	if !ok {
+		return awserr.New(ErrCodeInvalidGroupNotFound, fmt.Sprintf("The security group '%s' does not exist", id), nil)
+	}
+	existing := &group.IpPermissionsEgress
+	if ingress {
+		existing = &group.IpPermissions
+	}
+
+	revoked := make(map[string]bool)
+	for _, key := range permissionKeys(permissions) {
+		revoked[key] = true
+	}
+	found := 0
+	var remaining []*ec2.IpPermission
+	for _, permission := range *existing {
+		for _, single := range splitPermission(permission) {
+			key := permissionKeys([]*ec2.IpPermission{single})[0]
+			if revoked[key] {
+				found++
+				continue
+			}
+			remaining = append(remaining, single)
+		}
+	}
+	if found != len(revoked) {
+		return awserr.New(ErrCodeInvalidPermissionNotFound, "The specified rule does not exist in this security group.", nil)
+	}
+	*existing = remaining
+	return nil
+}
+
+// splitPermission splits the given permission into permissions with a single source or destination each.
+func splitPermission(permission *ec2.IpPermission) []*ec2.IpPermission {
+	var permissions []*ec2.IpPermission
+	for _, ipRange := range permission.IpRanges {
+		single := &ec2.IpPermission{IpProtocol: permission.IpProtocol, FromPort: permission.FromPort, ToPort: permission.ToPort}
+		single.IpRanges = []*ec2.IpRange{ipRange}
+		permissions = append(permissions, single)
+	}
+	for _, pair := range permission.UserIdGroupPairs {
+		single := &ec2.IpPermission{IpProtocol: permission.IpProtocol, FromPort: permission.FromPort, ToPort: permission.ToPort}
+		single.UserIdGroupPairs = []*ec2.UserIdGroupPair{pair}
+		permissions = append(permissions, single)
+	}
+	return permissions
+}
+
+// permissionKeys returns a key per source or destination of the given permissions.
+func permissionKeys(permissions []*ec2.IpPermission) []string {
+	var keys []string
+	for _, permission := range permissions {
+		prefix := fmt.Sprintf("%s:%d-%d:", aws.StringValue(permission.IpProtocol), aws.Int64Value(permission.FromPort), aws.Int64Value(permission.ToPort))
+		for _, ipRange := range permission.IpRanges {
+			keys = append(keys, prefix+aws.StringValue(ipRange.CidrIp))
+		}
+		for _, pair := range permission.UserIdGroupPairs {
+			keys = append(keys, prefix+aws.StringValue(pair.GroupId))
+		}
+	}
+	return keys
+}
+
+// DescribeAddressesWithContext implements awsclient.EC2.
+func (e *ec2API) DescribeAddressesWithContext(_ aws.Context, input *ec2.DescribeAddressesInput, _ ...request.Option) (*ec2.DescribeAddressesOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeAddresses"); err != nil {
+		return nil, err
+	}
+
+	ids, err := lookup(e.addresses, aws.StringValueSlice(input.AllocationIds), ErrCodeInvalidAllocationIDNotFound, "allocation ID")
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DescribeAddressesOutput{}
+	for _, id := range ids {
+		address := e.addresses[id]
+		ok, err := addressResource(address).matches(input.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			output.Addresses = append(output.Addresses, copyOf(address).(*ec2.Address))
+		}
+	}
+	return output, nil
+}
+
+// AllocateAddressWithContext implements awsclient.EC2. Only Elastic IPs of the VPC domain are supported.
+func (e *ec2API) AllocateAddressWithContext(_ aws.Context, input *ec2.AllocateAddressInput, _ ...request.Option) (*ec2.AllocateAddressOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("AllocateAddress"); err != nil {
+		return nil, err
+	}
+
+	if aws.StringValue(input.Domain) != ec2.DomainTypeVpc {
+		return nil, awserr.New(ErrCodeInvalidParameterValue, "only Elastic IPs of the vpc domain are supported", nil)
+	}
+	address := e.allocateAddress(nil)
+	return &ec2.AllocateAddressOutput{
+		AllocationId: address.AllocationId,
+		Domain:       address.Domain,
+		PublicIp:     address.PublicIp,
+	}, nil
+}
+
+// ReleaseAddressWithContext implements awsclient.EC2. Like in EC2, releasing an associated Elastic IP fails.
+func (e *ec2API) ReleaseAddressWithContext(_ aws.Context, input *ec2.ReleaseAddressInput, _ ...request.Option) (*ec2.ReleaseAddressOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("ReleaseAddress"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.AllocationId)
+	address, ok := e.addresses[id]
+	if !ok {
+		return nil, awserr.New(ErrCodeInvalidAllocationIDNotFound, fmt.Sprintf("The allocation ID '%s' does not exist", id), nil)
+	}
+	if aws.StringValue(address.AssociationId) != "" {
+		return nil, awserr.New(ErrCodeInvalidIPAddressInUse, fmt.Sprintf("Address %s is in use.", aws.StringValue(address.PublicIp)), nil)
+	}
+	delete(e.addresses, id)
+	return &ec2.ReleaseAddressOutput{}, nil
+}
+
+// DescribeNatGatewaysWithContext implements awsclient.EC2. Like in EC2, deleted NAT gateways are still returned.
+func (e *ec2API) DescribeNatGatewaysWithContext(_ aws.Context, input *ec2.DescribeNatGatewaysInput, _ ...request.Option) (*ec2.DescribeNatGatewaysOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeNatGateways"); err != nil {
+		return nil, err
+	}
+
+	ids, err := lookup(e.natGateways, aws.StringValueSlice(input.NatGatewayIds), ErrCodeNatGatewayNotFound, "NAT gateway")
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DescribeNatGatewaysOutput{}
+	for _, id := range ids {
+		gateway := e.natGateways[id]
+		ok, err := natGatewayResource(gateway).matches(input.Filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			output.NatGateways = append(output.NatGateways, copyOf(gateway).(*ec2.NatGateway))
+		}
+	}
+	return output, nil
+}
+
+// CreateNatGatewayWithContext implements awsclient.EC2. The NAT gateway is available immediately and associates its
+// Elastic IP.
+func (e *ec2API) CreateNatGatewayWithContext(_ aws.Context, input *ec2.CreateNatGatewayInput, _ ...request.Option) (*ec2.CreateNatGatewayOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("CreateNatGateway"); err != nil {
+		return nil, err
+	}
+
+	subnetID, allocationID := aws.StringValue(input.SubnetId), aws.StringValue(input.AllocationId)
+	if _, err := lookup(e.subnets, []string{subnetID}, ErrCodeInvalidSubnetIDNotFound, "subnet ID"); err != nil {
+		return nil, err
+	}
+	address, ok := e.addresses[allocationID]
+	if !ok {
+		return nil, awserr.New(ErrCodeInvalidAllocationIDNotFound, fmt.Sprintf("The allocation ID '%s' does not exist", allocationID), nil)
+	}
+	if aws.StringValue(address.AssociationId) != "" {
+		return nil, awserr.New(ErrCodeResourceAlreadyAssociated, fmt.Sprintf("Elastic IP address [%s] is already associated", allocationID), nil)
+	}
+
+	id := e.newID("nat")
+	address.AssociationId = aws.String(e.newID("eipassoc"))
+	e.natGateways[id] = &ec2.NatGateway{
+		NatGatewayId: aws.String(id),
+		SubnetId:     aws.String(subnetID),
+		VpcId:        e.subnets[subnetID].VpcId,
+		State:        aws.String(ec2.NatGatewayStateAvailable),
+		NatGatewayAddresses: []*ec2.NatGatewayAddress{
+			{AllocationId: address.AllocationId, PublicIp: address.PublicIp},
+		},
+	}
+	return &ec2.CreateNatGatewayOutput{NatGateway: copyOf(e.natGateways[id]).(*ec2.NatGateway)}, nil
+}
+
+// DeleteNatGatewayWithContext implements awsclient.EC2. The NAT gateway is deleted immediately and disassociates its
+// Elastic IP.
+func (e *ec2API) DeleteNatGatewayWithContext(_ aws.Context, input *ec2.DeleteNatGatewayInput, _ ...request.Option) (*ec2.DeleteNatGatewayOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteNatGateway"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.NatGatewayId)
+	gateway, ok := e.natGateways[id]
+	if !ok || aws.StringValue(gateway.State) == ec2.NatGatewayStateDeleted {
+		return nil, awserr.New(ErrCodeNatGatewayNotFound, fmt.Sprintf("NAT gateway %s was not found", id), nil)
+	}
+	gateway.State = aws.String(ec2.NatGatewayStateDeleted)
+	for _, natAddress := range gateway.NatGatewayAddresses {
+		if address, ok := e.addresses[aws.StringValue(natAddress.AllocationId)]; ok {
+			address.AssociationId = nil
+		}
+	}
+	return &ec2.DeleteNatGatewayOutput{NatGatewayId: aws.String(id)}, nil
+}
+
+// DescribeVpcEndpointsWithContext implements awsclient.EC2.
+func (e *ec2API) DescribeVpcEndpointsWithContext(_ aws.Context, input *ec2.DescribeVpcEndpointsInput, _ ...request.Option) (*ec2.DescribeVpcEndpointsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeVpcEndpoints"); err != nil {
+		return nil, err
+	}
+
+	ids, err := lookup(e.vpcEndpoints, aws.StringValueSlice(input.VpcEndpointIds), ErrCodeInvalidVpcEndpointIDNotFound, "VPC endpoint ID")
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DescribeVpcEndpointsOutput{}
+	for _, id := range ids {
+		endpoint := e.vpcEndpoints[id]
+		ok, err := vpcEndpointResource(endpoint).matches(input.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			output.VpcEndpoints = append(output.VpcEndpoints, copyOf(endpoint).(*ec2.VpcEndpoint))
+		}
+	}
+	return output, nil
+}
+
+// CreateVpcEndpointWithContext implements awsclient.EC2. The endpoint is available immediately.
+func (e *ec2API) CreateVpcEndpointWithContext(_ aws.Context, input *ec2.CreateVpcEndpointInput, _ ...request.Option) (*ec2.CreateVpcEndpointOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("CreateVpcEndpoint"); err != nil {
+		return nil, err
+	}
+
+	vpcID := aws.StringValue(input.VpcId)
+	if _, err := lookup(e.vpcs, []string{vpcID}, ErrCodeInvalidVpcIDNotFound, "vpc ID"); err != nil {
+		return nil, err
+	}
+	if _, err := lookup(e.routeTables, aws.StringValueSlice(input.RouteTableIds), ErrCodeInvalidRouteTableIDNotFound, "route table ID"); err != nil {
+		return nil, err
+	}
+	if _, err := lookup(e.subnets, aws.StringValueSlice(input.SubnetIds), ErrCodeInvalidSubnetIDNotFound, "subnet ID"); err != nil {
+		return nil, err
+	}
+
+	id := e.newID("vpce")
+	endpoint := &ec2.VpcEndpoint{
+		VpcEndpointId:     aws.String(id),
+		VpcId:             aws.String(vpcID),
+		ServiceName:       input.ServiceName,
+		VpcEndpointType:   input.VpcEndpointType,
+		RouteTableIds:     input.RouteTableIds,
+		SubnetIds:         input.SubnetIds,
+		PrivateDnsEnabled: input.PrivateDnsEnabled,
+		State:             aws.String(ec2.StateAvailable),
+	}
+	for _, groupID := range input.SecurityGroupIds {
+		endpoint.Groups = append(endpoint.Groups, &ec2.SecurityGroupIdentifier{GroupId: groupID})
+	}
+	e.vpcEndpoints[id] = endpoint
+	return &ec2.CreateVpcEndpointOutput{VpcEndpoint: copyOf(endpoint).(*ec2.VpcEndpoint)}, nil
+}
+
+// ModifyVpcEndpointWithContext implements awsclient.EC2. Only route tables and subnets can be modified.
+func (e *ec2API) ModifyVpcEndpointWithContext(_ aws.Context, input *ec2.ModifyVpcEndpointInput, _ ...request.Option) (*ec2.ModifyVpcEndpointOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("ModifyVpcEndpoint"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.VpcEndpointId)
+	endpoint, ok := e.vpcEndpoints[id]
+	if !ok {
+		return nil, awserr.New(ErrCodeInvalidVpcEndpointIDNotFound, fmt.Sprintf("The Vpc Endpoint Id '%s' does not exist", id), nil)
+	}
+	endpoint.RouteTableIds = modifyIDs(endpoint.RouteTableIds, input.AddRouteTableIds, input.RemoveRouteTableIds)
+	endpoint.SubnetIds = modifyIDs(endpoint.SubnetIds, input.AddSubnetIds, input.RemoveSubnetIds)
+	return &ec2.ModifyVpcEndpointOutput{Return: aws.Bool(true)}, nil
+}
+
+// modifyIDs returns <ids> without <remove> and with <add>.
+func modifyIDs(ids, add, remove []*string) []*string {
+	var out []*string
+	for _, id := range ids {
+		if !containsAny(aws.StringValueSlice(remove), []string{aws.StringValue(id)}) {
+			out = append(out, id)
+		}
+	}
+	for _, id := range add {
+		if !containsAny(aws.StringValueSlice(out), []string{aws.StringValue(id)}) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// DeleteVpcEndpointsWithContext implements awsclient.EC2. Like in EC2, endpoints that do not exist are reported as
+// unsuccessful items.
+func (e *ec2API) DeleteVpcEndpointsWithContext(_ aws.Context, input *ec2.DeleteVpcEndpointsInput, _ ...request.Option) (*ec2.DeleteVpcEndpointsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteVpcEndpoints"); err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DeleteVpcEndpointsOutput{}
+	for _, id := range aws.StringValueSlice(input.VpcEndpointIds) {
+		if _, ok := e.vpcEndpoints[id]; !ok {
+			output.Unsuccessful = append(output.Unsuccessful, &ec2.UnsuccessfulItem{
+				ResourceId: aws.String(id),
+				Error: &ec2.UnsuccessfulItemError{
+					Code:    aws.String(ErrCodeInvalidVpcEndpointIDNotFound),
+					Message: aws.String(fmt.Sprintf("The Vpc Endpoint Id '%s' does not exist", id)),
+				},
+			})
+			continue
+		}
+		delete(e.vpcEndpoints, id)
+	}
+	return output, nil
+}
+
+// DescribeVpcPeeringConnectionsWithContext implements awsclient.EC2.
+func (e *ec2API) DescribeVpcPeeringConnectionsWithContext(_ aws.Context, input *ec2.DescribeVpcPeeringConnectionsInput, _ ...request.Option) (*ec2.DescribeVpcPeeringConnectionsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeVpcPeeringConnections"); err != nil {
+		return nil, err
+	}
+
+	ids, err := lookup(e.vpcPeerings, aws.StringValueSlice(input.VpcPeeringConnectionIds), ErrCodeInvalidVpcPeeringConnectionIDNotFound, "vpc peering connection ID")
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DescribeVpcPeeringConnectionsOutput{}
+	for _, id := range ids {
+		connection := e.vpcPeerings[id]
+		ok, err := vpcPeeringConnectionResource(connection).matches(input.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			output.VpcPeeringConnections = append(output.VpcPeeringConnections, copyOf(connection).(*ec2.VpcPeeringConnection))
+		}
+	}
+	return output, nil
+}
+
+// CreateVpcPeeringConnectionWithContext implements awsclient.EC2. The peering connection is pending acceptance
+// immediately. The other VPC must exist if it belongs to the same account and no other region is given, other
+// accounts and regions are not modelled.
+func (e *ec2API) CreateVpcPeeringConnectionWithContext(_ aws.Context, input *ec2.CreateVpcPeeringConnectionInput, _ ...request.Option) (*ec2.CreateVpcPeeringConnectionOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("CreateVpcPeeringConnection"); err != nil {
+		return nil, err
+	}
+
+	vpcID := aws.StringValue(input.VpcId)
+	if _, err := lookup(e.vpcs, []string{vpcID}, ErrCodeInvalidVpcIDNotFound, "vpc ID"); err != nil {
+		return nil, err
+	}
+	peerOwnerID := e.accountID
+	if input.PeerOwnerId != nil {
+		peerOwnerID = aws.StringValue(input.PeerOwnerId)
+	}
+	peerVPCID := aws.StringValue(input.PeerVpcId)
+	accepter := &ec2.VpcPeeringConnectionVpcInfo{
+		OwnerId: aws.String(peerOwnerID),
+		Region:  input.PeerRegion,
+		VpcId:   aws.String(peerVPCID),
+	}
+	if peerOwnerID == e.accountID && input.PeerRegion == nil {
+		if _, err := lookup(e.vpcs, []string{peerVPCID}, ErrCodeInvalidVpcIDNotFound, "vpc ID"); err != nil {
+			return nil, err
+		}
+		accepter.CidrBlock = e.vpcs[peerVPCID].CidrBlock
+	}
+
+	id := e.newID("pcx")
+	connection := &ec2.VpcPeeringConnection{
+		VpcPeeringConnectionId: aws.String(id),
+		AccepterVpcInfo:        accepter,
+		RequesterVpcInfo: &ec2.VpcPeeringConnectionVpcInfo{
+			CidrBlock: e.vpcs[vpcID].CidrBlock,
+			OwnerId:   aws.String(e.accountID),
+			VpcId:     aws.String(vpcID),
+		},
+		Status: &ec2.VpcPeeringConnectionStateReason{Code: aws.String(ec2.VpcPeeringConnectionStateReasonCodePendingAcceptance)},
+	}
+	e.vpcPeerings[id] = connection
+	return &ec2.CreateVpcPeeringConnectionOutput{VpcPeeringConnection: copyOf(connection).(*ec2.VpcPeeringConnection)}, nil
+}
+
+// AcceptVpcPeeringConnectionWithContext implements awsclient.EC2. Like in EC2, only peering connections to VPCs of
+// the same account that are pending acceptance can be accepted.
+func (e *ec2API) AcceptVpcPeeringConnectionWithContext(_ aws.Context, input *ec2.AcceptVpcPeeringConnectionInput, _ ...request.Option) (*ec2.AcceptVpcPeeringConnectionOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("AcceptVpcPeeringConnection"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.VpcPeeringConnectionId)
+	connection, ok := e.vpcPeerings[id]
+	if !ok {
+		return nil, awserr.New(ErrCodeInvalidVpcPeeringConnectionIDNotFound, fmt.Sprintf("The vpcPeeringConnection ID '%s' does not exist", id), nil)
+	}
+	if aws.StringValue(connection.AccepterVpcInfo.OwnerId) != e.accountID {
+		return nil, awserr.New(ErrCodeOperationNotPermitted, fmt.Sprintf("User %s cannot accept peering %s", e.accountID, id), nil)
+	}
+	if state := aws.StringValue(connection.Status.Code); state != ec2.VpcPeeringConnectionStateReasonCodePendingAcceptance {
+		return nil, awserr.New(ErrCodeInvalidStateTransition, fmt.Sprintf("Invalid state transition for %s, attempted to transition from %s to active", id, state), nil)
+	}
+	connection.Status.Code = aws.String(ec2.VpcPeeringConnectionStateReasonCodeActive)
+	return &ec2.AcceptVpcPeeringConnectionOutput{VpcPeeringConnection: copyOf(connection).(*ec2.VpcPeeringConnection)}, nil
+}
+
+// DeleteVpcPeeringConnectionWithContext implements awsclient.EC2. Like in EC2, the peering connection remains visible
+// in state `deleted`.
+func (e *ec2API) DeleteVpcPeeringConnectionWithContext(_ aws.Context, input *ec2.DeleteVpcPeeringConnectionInput, _ ...request.Option) (*ec2.DeleteVpcPeeringConnectionOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteVpcPeeringConnection"); err != nil {
+		return nil, err
+	}
+
+	id := aws.StringValue(input.VpcPeeringConnectionId)
+	connection, ok := e.vpcPeerings[id]
+	if !ok || aws.StringValue(connection.Status.Code) == ec2.VpcPeeringConnectionStateReasonCodeDeleted {
+		return nil, awserr.New(ErrCodeInvalidVpcPeeringConnectionIDNotFound, fmt.Sprintf("The vpcPeeringConnection ID '%s' does not exist", id), nil)
+	}
+	connection.Status.Code = aws.String(ec2.VpcPeeringConnectionStateReasonCodeDeleted)
+	return &ec2.DeleteVpcPeeringConnectionOutput{Return: aws.Bool(true)}, nil
+}
+
+// DescribeKeyPairsWithContext implements awsclient.EC2.
+func (e *ec2API) DescribeKeyPairsWithContext(_ aws.Context, input *ec2.DescribeKeyPairsInput, _ ...request.Option) (*ec2.DescribeKeyPairsOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DescribeKeyPairs"); err != nil {
+		return nil, err
+	}
+
+	names, err := lookup(e.keyPairs, aws.StringValueSlice(input.KeyNames), ErrCodeInvalidKeyPairNotFound, "key pair")
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ec2.DescribeKeyPairsOutput{}
+	for _, name := range names {
+		output.KeyPairs = append(output.KeyPairs, copyOf(e.keyPairs[name]).(*ec2.KeyPairInfo))
+	}
+	return output, nil
+}
+
+// ImportKeyPairWithContext implements awsclient.EC2. The fingerprint is computed like EC2 does.
+func (e *ec2API) ImportKeyPairWithContext(_ aws.Context, input *ec2.ImportKeyPairInput, _ ...request.Option) (*ec2.ImportKeyPairOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("ImportKeyPair"); err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.KeyName)
+	if _, ok := e.keyPairs[name]; ok {
+		return nil, awserr.New(ErrCodeInvalidKeyPairDuplicate, fmt.Sprintf("The keypair '%s' already exists.", name), nil)
+	}
+	fingerprint, err := awsclient.PublicKeyFingerprint(string(input.PublicKeyMaterial))
+	if err != nil {
+		return nil, awserr.New(ErrCodeInvalidParameterValue, err.Error(), nil)
+	}
+	e.keyPairs[name] = &ec2.KeyPairInfo{KeyName: aws.String(name), KeyFingerprint: aws.String(fingerprint)}
+	return &ec2.ImportKeyPairOutput{KeyName: aws.String(name), KeyFingerprint: aws.String(fingerprint)}, nil
+}
+
+// DeleteKeyPairWithContext implements awsclient.EC2. Like in EC2, deleting a key pair that does not exist succeeds.
+func (e *ec2API) DeleteKeyPairWithContext(_ aws.Context, input *ec2.DeleteKeyPairInput, _ ...request.Option) (*ec2.DeleteKeyPairOutput, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.call("DeleteKeyPair"); err != nil {
+		return nil, err
+	}
+
+	delete(e.keyPairs, aws.StringValue(input.KeyName))
+	return &ec2.DeleteKeyPairOutput{}, nil
+}
@@ -0,0 +1,306 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// GetRole returns the IAM role with the given <name>, or nil if it does not exist.
+func (c *Client) GetRole(ctx context.Context, name string) (*Role, error) {
+	output, err := c.IAM.GetRoleWithContext(ctx, &iam.GetRoleInput{RoleName: aws.String(name)})
+	if err != nil {
+		if isErrorCode(err, iam.ErrCodeNoSuchEntityException) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return roleOf(output.Role), nil
+}
+
+// CreateRole creates an IAM role with the given <name> and trust policy <assumeRolePolicy> in the path `/`.
+func (c *Client) CreateRole(ctx context.Context, name, assumeRolePolicy string) (*Role, error) {
+	output, err := c.IAM.CreateRoleWithContext(ctx, &iam.CreateRoleInput{
+		Path:                     aws.String("/"),
+		RoleName:                 aws.String(name),
+		AssumeRolePolicyDocument: aws.String(assumeRolePolicy),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return roleOf(output.Role), nil
+}
+
+func roleOf(role *iam.Role) *Role {
+	return &Role{
+		Name: aws.StringValue(role.RoleName),
+		ARN:  aws.StringValue(role.Arn),
+	}
+}
+
+// PutRolePolicy adds or replaces the inline policy <policyName> of the IAM role <roleName> with <policy>.
+func (c *Client) PutRolePolicy(ctx context.Context, roleName, policyName, policy string) error {
+	_, err := c.IAM.PutRolePolicyWithContext(ctx, &iam.PutRolePolicyInput{
+		RoleName:       aws.String(roleName),
+		PolicyName:     aws.String(policyName),
+		PolicyDocument: aws.String(policy),
+	})
+	return err
+}
+
+// DeleteRole deletes the inline policies of the IAM role with the given <name> and the role itself. If it does not
+// exist, no error is returned.
+func (c *Client) DeleteRole(ctx context.Context, name string) error {
+	var policyNames []string
+	input := &iam.ListRolePoliciesInput{RoleName: aws.String(name)}
+	for {
+		output, err := c.IAM.ListRolePoliciesWithContext(ctx, input)
+		if err != nil {
+			return ignoreErrorCodes(err, iam.ErrCodeNoSuchEntityException)
+		}
+		policyNames = append(policyNames, aws.StringValueSlice(output.PolicyNames)...)
+
+		if !aws.BoolValue(output.IsTruncated) {
+			break
+		}
+		input.Marker = output.Marker
+	}
+
+	for _, policyName := range policyNames {
+		if _, err := c.IAM.DeleteRolePolicyWithContext(ctx, &iam.DeleteRolePolicyInput{
+			RoleName:   aws.String(name),
+			PolicyName: aws.String(policyName),
+		}); err != nil {
+			if err := ignoreErrorCodes(err, iam.ErrCodeNoSuchEntityException); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := c.IAM.DeleteRoleWithContext(ctx, &iam.DeleteRoleInput{RoleName: aws.String(name)})
+	return ignoreErrorCodes(err, iam.ErrCodeNoSuchEntityException)
+}
+
+// GetInstanceProfile returns the IAM instance profile with the given <name>, or nil if it does not exist.
+func (c *Client) GetInstanceProfile(ctx context.Context, name string) (*InstanceProfile, error) {
+	output, err := c.IAM.GetInstanceProfileWithContext(ctx, &iam.GetInstanceProfileInput{InstanceProfileName: aws.String(name)})
+	if err != nil {
+		if isErrorCode(err, iam.ErrCodeNoSuchEntityException) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	profile := &InstanceProfile{Name: aws.StringValue(output.InstanceProfile.InstanceProfileName)}
+	for _, role := range output.InstanceProfile.Roles {
+		profile.RoleNames = append(profile.RoleNames, aws.StringValue(role.RoleName))
+	}
+	return profile, nil
+}
+
+// CreateInstanceProfile creates an IAM instance profile with the given <name> in the path `/`.
+func (c *Client) CreateInstanceProfile(ctx context.Context, name string) error {
+	_, err := c.IAM.CreateInstanceProfileWithContext(ctx, &iam.CreateInstanceProfileInput{
+		Path:                aws.String("/"),
+		InstanceProfileName: aws.String(name),
+	})
+	return err
+}
+
+// AddRoleToInstanceProfile adds the IAM role <roleName> to the instance profile <instanceProfileName>.
+func (c *Client) AddRoleToInstanceProfile(ctx context.Context, instanceProfileName, roleName string) error {
+	_, err := c.IAM.AddRoleToInstanceProfileWithContext(ctx, &iam.AddRoleToInstanceProfileInput{
+		InstanceProfileName: aws.String(instanceProfileName),
+		RoleName:            aws.String(roleName),
+	})
+	return err
+}
+
+// DeleteInstanceProfile removes all roles from the IAM instance profile with the given <name> and deletes it. If it
+// does not exist, no error is returned.
+func (c *Client) DeleteInstanceProfile(ctx context.Context, name string) error {
+	profile, err := c.GetInstanceProfile(ctx, name)
+	if err != nil || profile == nil {
+		return err
+	}
+
+	for _, roleName := range profile.RoleNames {
+		if _, err := c.IAM.RemoveRoleFromInstanceProfileWithContext(ctx, &iam.RemoveRoleFromInstanceProfileInput{
+			InstanceProfileName: aws.String(name),
+			RoleName:            aws.String(roleName),
+		}); err != nil {
+			if err := ignoreErrorCodes(err, iam.ErrCodeNoSuchEntityException); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = c.IAM.DeleteInstanceProfileWithContext(ctx, &iam.DeleteInstanceProfileInput{InstanceProfileName: aws.String(name)})
+	return ignoreErrorCodes(err, iam.ErrCodeNoSuchEntityException)
+}
+
+// GetUser returns the IAM user with the given <name>, or nil if it does not exist.
+func (c *Client) GetUser(ctx context.Context, name string) (*User, error) {
+	output, err := c.IAM.GetUserWithContext(ctx, &iam.GetUserInput{UserName: aws.String(name)})
+	if err != nil {
+		if isErrorCode(err, iam.ErrCodeNoSuchEntityException) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return userOf(output.User), nil
+}
+
+// CreateUser creates an IAM user with the given <name> in the path `/`.
+func (c *Client) CreateUser(ctx context.Context, name string) (*User, error) {
+	output, err := c.IAM.CreateUserWithContext(ctx, &iam.CreateUserInput{
+		Path:     aws.String("/"),
+		UserName: aws.String(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userOf(output.User), nil
+}
+
+func userOf(user *iam.User) *User {
+	return &User{
+		Name: aws.StringValue(user.UserName),
+		ARN:  aws.StringValue(user.Arn),
+	}
+}
+
+// PutUserPolicy adds or replaces the inline policy <policyName> of the IAM user <userName> with <policy>.
+func (c *Client) PutUserPolicy(ctx context.Context, userName, policyName, policy string) error {
+	_, err := c.IAM.PutUserPolicyWithContext(ctx, &iam.PutUserPolicyInput{
+		UserName:       aws.String(userName),
+		PolicyName:     aws.String(policyName),
+		PolicyDocument: aws.String(policy),
+	})
+	return err
+}
+
+// DeleteUser deletes the access keys and inline policies of the IAM user with the given <name> and the user itself.
+// If it does not exist, no error is returned.
+func (c *Client) DeleteUser(ctx context.Context, name string) error {
+	accessKeyIDs, err := c.ListAccessKeys(ctx, name)
+	if err != nil {
+		return ignoreErrorCodes(err, iam.ErrCodeNoSuchEntityException)
+	}
+	for _, id := range accessKeyIDs {
+		if err := c.DeleteAccessKey(ctx, name, id); err != nil {
+			return err
+		}
+	}
+
+	var policyNames []string
+	input := &iam.ListUserPoliciesInput{UserName: aws.String(name)}
+	for {
+		output, err := c.IAM.ListUserPoliciesWithContext(ctx, input)
+		if err != nil {
+			return ignoreErrorCodes(err, iam.ErrCodeNoSuchEntityException)
+		}
+		policyNames = append(policyNames, aws.StringValueSlice(output.PolicyNames)...)
+
+		if !aws.BoolValue(output.IsTruncated) {
+			break
+		}
+		input.Marker = output.Marker
+	}
+
+	for _, policyName := range policyNames {
+		if _, err := c.IAM.DeleteUserPolicyWithContext(ctx, &iam.DeleteUserPolicyInput{
+			UserName:   aws.String(name),
+			PolicyName: aws.String(policyName),
+		}); err != nil {
+			if err := ignoreErrorCodes(err, iam.ErrCodeNoSuchEntityException); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = c.IAM.DeleteUserWithContext(ctx, &iam.DeleteUserInput{UserName: aws.String(name)})
+	return ignoreErrorCodes(err, iam.ErrCodeNoSuchEntityException)
+}
+
+// ListAccessKeys returns the ids of the access keys of the IAM user <userName>.
+func (c *Client) ListAccessKeys(ctx context.Context, userName string) ([]string, error) {
+	var ids []string
+	input := &iam.ListAccessKeysInput{UserName: aws.String(userName)}
+	for {
+		output, err := c.IAM.ListAccessKeysWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, accessKey := range output.AccessKeyMetadata {
+			ids = append(ids, aws.StringValue(accessKey.AccessKeyId))
+		}
+
+		if !aws.BoolValue(output.IsTruncated) {
+			return ids, nil
+		}
+		input.Marker = output.Marker
+	}
+}
+
+// SimulatePrincipalPolicy simulates the policies of the IAM user or role <principalARN> for the given <actions>
+// and returns the actions that are not allowed.
+func (c *Client) SimulatePrincipalPolicy(ctx context.Context, principalARN string, actions []string) ([]string, error) {
+	var denied []string
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalARN),
+		ActionNames:     aws.StringSlice(actions),
+	}
+	for {
+		output, err := c.IAM.SimulatePrincipalPolicyWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range output.EvaluationResults {
+			if aws.StringValue(result.EvalDecision) != iam.PolicyEvaluationDecisionTypeAllowed {
+				denied = append(denied, aws.StringValue(result.EvalActionName))
+			}
+		}
+
+		if !aws.BoolValue(output.IsTruncated) {
+			return denied, nil
+		}
+		input.Marker = output.Marker
+	}
+}
+
+// CreateAccessKey creates an access key of the IAM user <userName> and returns it including its secret.
+func (c *Client) CreateAccessKey(ctx context.Context, userName string) (*AccessKey, error) {
+	output, err := c.IAM.CreateAccessKeyWithContext(ctx, &iam.CreateAccessKeyInput{UserName: aws.String(userName)})
+	if err != nil {
+		return nil, err
+	}
+	return &AccessKey{
+		ID:     aws.StringValue(output.AccessKey.AccessKeyId),
+		Secret: aws.StringValue(output.AccessKey.SecretAccessKey),
+	}, nil
+}
+
+// DeleteAccessKey deletes the access key <id> of the IAM user <userName>. If it does not exist, no error is returned.
+func (c *Client) DeleteAccessKey(ctx context.Context, userName, id string) error {
+	_, err := c.IAM.DeleteAccessKeyWithContext(ctx, &iam.DeleteAccessKeyInput{
+		UserName:    aws.String(userName),
+		AccessKeyId: aws.String(id),
+	})
+	return ignoreErrorCodes(err, iam.ErrCodeNoSuchEntityException)
+}
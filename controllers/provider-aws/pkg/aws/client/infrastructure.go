@@ -0,0 +1,895 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// isErrorCode reports whether <err> is an AWS error with one of the given <codes>.
+func isErrorCode(err error, codes ...string) bool {
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+	for _, code := range codes {
+		if aerr.Code() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoreErrorCodes returns nil if <err> is an AWS error with one of the given <codes>, and <err> otherwise.
+func ignoreErrorCodes(err error, codes ...string) error {
+	if isErrorCode(err, codes...) {
+		return nil
+	}
+	return err
+}
+
+func tagsOf(tags []*ec2.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		out[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return out
+}
+
+// CreateTags adds or overwrites the given <tags> of the resources with the given <resourceIDs>.
+func (c *Client) CreateTags(ctx context.Context, resourceIDs []string, tags map[string]string) error {
+	if len(resourceIDs) == 0 || len(tags) == 0 {
+		return nil
+	}
+
+	var ec2Tags []*ec2.Tag
+	for _, filter := range tagFilters(tags) {
+		key := strings.TrimPrefix(aws.StringValue(filter.Name), "tag:")
+		ec2Tags = append(ec2Tags, &ec2.Tag{Key: aws.String(key), Value: filter.Values[0]})
+	}
+	_, err := c.EC2.CreateTagsWithContext(ctx, &ec2.CreateTagsInput{
+		Resources: aws.StringSlice(resourceIDs),
+		Tags:      ec2Tags,
+	})
+	return err
+}
+
+// FindVPCs returns the VPCs that carry all of the given <tags>.
+func (c *Client) FindVPCs(ctx context.Context, tags map[string]string) ([]VPC, error) {
+	output, err := c.EC2.DescribeVpcsWithContext(ctx, &ec2.DescribeVpcsInput{Filters: tagFilters(tags)})
+	if err != nil {
+		return nil, err
+	}
+
+	var vpcs []VPC
+	for _, vpc := range output.Vpcs {
+		vpcs = append(vpcs, VPC{
+			ID:            aws.StringValue(vpc.VpcId),
+			CIDR:          aws.StringValue(vpc.CidrBlock),
+			DHCPOptionsID: aws.StringValue(vpc.DhcpOptionsId),
+			Tags:          tagsOf(vpc.Tags),
+		})
+	}
+	return vpcs, nil
+}
+
+// CreateVPC creates a VPC with the given <cidr> and <tags>, enables DNS support and DNS hostnames for it, and
+// returns its ID.
+func (c *Client) CreateVPC(ctx context.Context, cidr string, tags map[string]string) (string, error) {
+	output, err := c.EC2.CreateVpcWithContext(ctx, &ec2.CreateVpcInput{CidrBlock: aws.String(cidr)})
+	if err != nil {
+		return "", err
+	}
+	id := aws.StringValue(output.Vpc.VpcId)
+
+	if err := c.CreateTags(ctx, []string{id}, tags); err != nil {
+		return id, err
+	}
+	// Both attributes cannot be modified with a single call.
+	for _, input := range []*ec2.ModifyVpcAttributeInput{
+		{VpcId: aws.String(id), EnableDnsSupport: &ec2.AttributeBooleanValue{Value: aws.Bool(true)}},
+		{VpcId: aws.String(id), EnableDnsHostnames: &ec2.AttributeBooleanValue{Value: aws.Bool(true)}},
+	} {
+		if _, err := c.EC2.ModifyVpcAttributeWithContext(ctx, input); err != nil {
+			return id, err
+		}
+	}
+	return id, nil
+}
+
+// DeleteVPC deletes the VPC with the given <id>. If it does not exist, no error is returned.
+func (c *Client) DeleteVPC(ctx context.Context, id string) error {
+	_, err := c.EC2.DeleteVpcWithContext(ctx, &ec2.DeleteVpcInput{VpcId: aws.String(id)})
+	return ignoreErrorCodes(err, "InvalidVpcID.NotFound")
+}
+
+// FindDHCPOptions returns the DHCP options that carry all of the given <tags>.
+func (c *Client) FindDHCPOptions(ctx context.Context, tags map[string]string) ([]DHCPOptions, error) {
+	output, err := c.EC2.DescribeDhcpOptionsWithContext(ctx, &ec2.DescribeDhcpOptionsInput{Filters: tagFilters(tags)})
+	if err != nil {
+		return nil, err
+	}
+
+	var options []DHCPOptions
+	for _, option := range output.DhcpOptions {
+		options = append(options, DHCPOptions{
+			ID:   aws.StringValue(option.DhcpOptionsId),
+			Tags: tagsOf(option.Tags),
+		})
+	}
+	return options, nil
+}
+
+// CreateDHCPOptions creates DHCP options with the given <domainName> and the Amazon provided DNS servers, tags them
+// with <tags>, and returns their ID.
+func (c *Client) CreateDHCPOptions(ctx context.Context, domainName string, tags map[string]string) (string, error) {
+	output, err := c.EC2.CreateDhcpOptionsWithContext(ctx, &ec2.CreateDhcpOptionsInput{
+		DhcpConfigurations: []*ec2.NewDhcpConfiguration{
+			{Key: aws.String("domain-name"), Values: []*string{aws.String(domainName)}},
+			{Key: aws.String("domain-name-servers"), Values: []*string{aws.String("AmazonProvidedDNS")}},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	id := aws.StringValue(output.DhcpOptions.DhcpOptionsId)
+	return id, c.CreateTags(ctx, []string{id}, tags)
+}
+
+// AssociateDHCPOptions associates the DHCP options <dhcpOptionsID> with the VPC <vpcID>.
+func (c *Client) AssociateDHCPOptions(ctx context.Context, vpcID, dhcpOptionsID string) error {
+	_, err := c.EC2.AssociateDhcpOptionsWithContext(ctx, &ec2.AssociateDhcpOptionsInput{
+		VpcId:         aws.String(vpcID),
+		DhcpOptionsId: aws.String(dhcpOptionsID),
+	})
+	return err
+}
+
+// DeleteDHCPOptions deletes the DHCP options with the given <id>. If they do not exist, no error is returned.
+func (c *Client) DeleteDHCPOptions(ctx context.Context, id string) error {
+	_, err := c.EC2.DeleteDhcpOptionsWithContext(ctx, &ec2.DeleteDhcpOptionsInput{DhcpOptionsId: aws.String(id)})
+	return ignoreErrorCodes(err, "InvalidDhcpOptionID.NotFound")
+}
+
+// FindInternetGateways returns the internet gateways that carry all of the given <tags>.
+func (c *Client) FindInternetGateways(ctx context.Context, tags map[string]string) ([]InternetGateway, error) {
+	output, err := c.EC2.DescribeInternetGatewaysWithContext(ctx, &ec2.DescribeInternetGatewaysInput{Filters: tagFilters(tags)})
+	if err != nil {
+		return nil, err
+	}
+
+	var gateways []InternetGateway
+	for _, gateway := range output.InternetGateways {
+		var vpcIDs []string
+		for _, attachment := range gateway.Attachments {
+			vpcIDs = append(vpcIDs, aws.StringValue(attachment.VpcId))
+		}
+		gateways = append(gateways, InternetGateway{
+			ID:     aws.StringValue(gateway.InternetGatewayId),
+			VPCIDs: vpcIDs,
+			Tags:   tagsOf(gateway.Tags),
+		})
+	}
+	return gateways, nil
+}
+
+// CreateInternetGateway creates an internet gateway with the given <tags> and returns its ID.
+func (c *Client) CreateInternetGateway(ctx context.Context, tags map[string]string) (string, error) {
+	output, err := c.EC2.CreateInternetGatewayWithContext(ctx, &ec2.CreateInternetGatewayInput{})
+	if err != nil {
+		return "", err
+	}
+	id := aws.StringValue(output.InternetGateway.InternetGatewayId)
+	return id, c.CreateTags(ctx, []string{id}, tags)
+}
+
+// AttachInternetGateway attaches the internet gateway <id> to the VPC <vpcID>.
+func (c *Client) AttachInternetGateway(ctx context.Context, id, vpcID string) error {
+	_, err := c.EC2.AttachInternetGatewayWithContext(ctx, &ec2.AttachInternetGatewayInput{
+		InternetGatewayId: aws.String(id),
+		VpcId:             aws.String(vpcID),
+	})
+	return err
+}
+
+// DetachInternetGateway detaches the internet gateway <id> from the VPC <vpcID>. If it does not exist or is not
+// attached, no error is returned.
+func (c *Client) DetachInternetGateway(ctx context.Context, id, vpcID string) error {
+	_, err := c.EC2.DetachInternetGatewayWithContext(ctx, &ec2.DetachInternetGatewayInput{
+		InternetGatewayId: aws.String(id),
+		VpcId:             aws.String(vpcID),
+	})
+	return ignoreErrorCodes(err, "InvalidInternetGatewayID.NotFound", "Gateway.NotAttached")
+}
+
+// DeleteInternetGateway deletes the internet gateway with the given <id>. If it does not exist, no error is
+// returned.
+func (c *Client) DeleteInternetGateway(ctx context.Context, id string) error {
+	_, err := c.EC2.DeleteInternetGatewayWithContext(ctx, &ec2.DeleteInternetGatewayInput{InternetGatewayId: aws.String(id)})
+	return ignoreErrorCodes(err, "InvalidInternetGatewayID.NotFound")
+}
+
+// CreateSubnet creates a subnet with the given <cidr> and <tags> in the VPC <vpcID> and availability <zone>, and
+// returns its ID.
+func (c *Client) CreateSubnet(ctx context.Context, vpcID, zone, cidr string, tags map[string]string) (string, error) {
+	output, err := c.EC2.CreateSubnetWithContext(ctx, &ec2.CreateSubnetInput{
+		VpcId:            aws.String(vpcID),
+		AvailabilityZone: aws.String(zone),
+		CidrBlock:        aws.String(cidr),
+	})
+	if err != nil {
+		return "", err
+	}
+	id := aws.StringValue(output.Subnet.SubnetId)
+	return id, c.CreateTags(ctx, []string{id}, tags)
+}
+
+// DeleteSubnet deletes the subnet with the given <id>. If it does not exist, no error is returned.
+func (c *Client) DeleteSubnet(ctx context.Context, id string) error {
+	_, err := c.EC2.DeleteSubnetWithContext(ctx, &ec2.DeleteSubnetInput{SubnetId: aws.String(id)})
+	return ignoreErrorCodes(err, "InvalidSubnetID.NotFound")
+}
+
+// FindRouteTables returns the route tables in the given <vpcID> that carry all of the given <tags>.
+func (c *Client) FindRouteTables(ctx context.Context, vpcID string, tags map[string]string) ([]RouteTable, error) {
+	filters := append([]*ec2.Filter{
+		{
+			Name:   aws.String("vpc-id"),
+			Values: []*string{aws.String(vpcID)},
+		},
+	}, tagFilters(tags)...)
+
+	output, err := c.EC2.DescribeRouteTablesWithContext(ctx, &ec2.DescribeRouteTablesInput{Filters: filters})
+	if err != nil {
+		return nil, err
+	}
+
+	var routeTables []RouteTable
+	for _, routeTable := range output.RouteTables {
+		var routes []Route
+		for _, route := range routeTable.Routes {
+			if route.DestinationCidrBlock == nil {
+				continue
+			}
+			routes = append(routes, Route{
+				DestinationCIDR:        aws.StringValue(route.DestinationCidrBlock),
+				GatewayID:              aws.StringValue(route.GatewayId),
+				NATGatewayID:           aws.StringValue(route.NatGatewayId),
+				VPCPeeringConnectionID: aws.StringValue(route.VpcPeeringConnectionId),
+			})
+		}
+
+		var associations []RouteTableAssociation
+		for _, association := range routeTable.Associations {
+			associations = append(associations, RouteTableAssociation{
+				ID:       aws.StringValue(association.RouteTableAssociationId),
+				SubnetID: aws.StringValue(association.SubnetId),
+				Main:     aws.BoolValue(association.Main),
+			})
+		}
+
+		routeTables = append(routeTables, RouteTable{
+			ID:           aws.StringValue(routeTable.RouteTableId),
+			Routes:       routes,
+			Associations: associations,
+			Tags:         tagsOf(routeTable.Tags),
+		})
+	}
+	return routeTables, nil
+}
+
+// CreateRouteTable creates a route table with the given <tags> in the VPC <vpcID> and returns its ID.
+func (c *Client) CreateRouteTable(ctx context.Context, vpcID string, tags map[string]string) (string, error) {
+	output, err := c.EC2.CreateRouteTableWithContext(ctx, &ec2.CreateRouteTableInput{VpcId: aws.String(vpcID)})
+	if err != nil {
+		return "", err
+	}
+	id := aws.StringValue(output.RouteTable.RouteTableId)
+	return id, c.CreateTags(ctx, []string{id}, tags)
+}
+
+// CreateRoute adds the given <route> to the route table <routeTableID>.
+func (c *Client) CreateRoute(ctx context.Context, routeTableID string, route Route) error {
+	input := &ec2.CreateRouteInput{
+		RouteTableId:         aws.String(routeTableID),
+		DestinationCidrBlock: aws.String(route.DestinationCIDR),
+	}
+	if route.GatewayID != "" {
+		input.GatewayId = aws.String(route.GatewayID)
+	}
+	if route.NATGatewayID != "" {
+		input.NatGatewayId = aws.String(route.NATGatewayID)
+	}
+	if route.VPCPeeringConnectionID != "" {
+		input.VpcPeeringConnectionId = aws.String(route.VPCPeeringConnectionID)
+	}
+	_, err := c.EC2.CreateRouteWithContext(ctx, input)
+	return err
+}
+
+// ReplaceRoute replaces the target of the route of the route table <routeTableID> with the destination of the
+// given <route>.
+func (c *Client) ReplaceRoute(ctx context.Context, routeTableID string, route Route) error {
+	input := &ec2.ReplaceRouteInput{
+		RouteTableId:         aws.String(routeTableID),
+		DestinationCidrBlock: aws.String(route.DestinationCIDR),
+	}
+	if route.GatewayID != "" {
+		input.GatewayId = aws.String(route.GatewayID)
+	}
+	if route.NATGatewayID != "" {
+		input.NatGatewayId = aws.String(route.NATGatewayID)
+	}
+	if route.VPCPeeringConnectionID != "" {
+		input.VpcPeeringConnectionId = aws.String(route.VPCPeeringConnectionID)
+	}
+	_, err := c.EC2.ReplaceRouteWithContext(ctx, input)
+	return err
+}
+
+// DeleteRoute deletes the route with the given <destinationCIDR> from the route table <routeTableID>. If it does not
+// exist, no error is returned.
+func (c *Client) DeleteRoute(ctx context.Context, routeTableID, destinationCIDR string) error {
+	_, err := c.EC2.DeleteRouteWithContext(ctx, &ec2.DeleteRouteInput{
+		RouteTableId:         aws.String(routeTableID),
+		DestinationCidrBlock: aws.String(destinationCIDR),
+	})
+	return ignoreErrorCodes(err, "InvalidRoute.NotFound")
+}
+
+// AssociateRouteTable associates the route table <routeTableID> with the subnet <subnetID>.
+func (c *Client) AssociateRouteTable(ctx context.Context, routeTableID, subnetID string) error {
+	_, err := c.EC2.AssociateRouteTableWithContext(ctx, &ec2.AssociateRouteTableInput{
+		RouteTableId: aws.String(routeTableID),
+		SubnetId:     aws.String(subnetID),
+	})
+	return err
+}
+
+// ReplaceRouteTableAssociation associates the subnet of the association <associationID> with the route table
+// <routeTableID> instead.
+func (c *Client) ReplaceRouteTableAssociation(ctx context.Context, associationID, routeTableID string) error {
+	_, err := c.EC2.ReplaceRouteTableAssociationWithContext(ctx, &ec2.ReplaceRouteTableAssociationInput{
+		AssociationId: aws.String(associationID),
+		RouteTableId:  aws.String(routeTableID),
+	})
+	return err
+}
+
+// DisassociateRouteTable removes the route table association <associationID>. If it does not exist, no error is
+// returned.
+func (c *Client) DisassociateRouteTable(ctx context.Context, associationID string) error {
+	_, err := c.EC2.DisassociateRouteTableWithContext(ctx, &ec2.DisassociateRouteTableInput{AssociationId: aws.String(associationID)})
+	return ignoreErrorCodes(err, "InvalidAssociationID.NotFound")
+}
+
+// DeleteRouteTable deletes the route table with the given <id>. If it does not exist, no error is returned.
+func (c *Client) DeleteRouteTable(ctx context.Context, id string) error {
+	_, err := c.EC2.DeleteRouteTableWithContext(ctx, &ec2.DeleteRouteTableInput{RouteTableId: aws.String(id)})
+	return ignoreErrorCodes(err, "InvalidRouteTableID.NotFound")
+}
+
+// FindSecurityGroups returns the security groups in the given <vpcID> that carry all of the given <tags>, including
+// their rules with one rule per source or destination.
+func (c *Client) FindSecurityGroups(ctx context.Context, vpcID string, tags map[string]string) ([]SecurityGroup, error) {
+	filters := append([]*ec2.Filter{
+		{
+			Name:   aws.String("vpc-id"),
+			Values: []*string{aws.String(vpcID)},
+		},
+	}, tagFilters(tags)...)
+
+	output, err := c.EC2.DescribeSecurityGroupsWithContext(ctx, &ec2.DescribeSecurityGroupsInput{Filters: filters})
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []SecurityGroup
+	for _, group := range output.SecurityGroups {
+		rules := securityGroupRulesOf(SecurityGroupRuleTypeIngress, group.IpPermissions)
+		rules = append(rules, securityGroupRulesOf(SecurityGroupRuleTypeEgress, group.IpPermissionsEgress)...)
+		groups = append(groups, SecurityGroup{
+			ID:    aws.StringValue(group.GroupId),
+			Name:  aws.StringValue(group.GroupName),
+			Rules: rules,
+			Tags:  tagsOf(group.Tags),
+		})
+	}
+	return groups, nil
+}
+
+func securityGroupRulesOf(ruleType SecurityGroupRuleType, permissions []*ec2.IpPermission) []SecurityGroupRule {
+	var rules []SecurityGroupRule
+	for _, permission := range permissions {
+		rule := SecurityGroupRule{
+			Type:     ruleType,
+			Protocol: aws.StringValue(permission.IpProtocol),
+			FromPort: aws.Int64Value(permission.FromPort),
+			ToPort:   aws.Int64Value(permission.ToPort),
+		}
+		if rule.Protocol == "-1" {
+			rule.FromPort, rule.ToPort = 0, 0
+		}
+		for _, ipRange := range permission.IpRanges {
+			cidrRule := rule
+			cidrRule.CIDR = aws.StringValue(ipRange.CidrIp)
+			cidrRule.Description = aws.StringValue(ipRange.Description)
+			rules = append(rules, cidrRule)
+		}
+		for _, pair := range permission.UserIdGroupPairs {
+			groupRule := rule
+			groupRule.SecurityGroupID = aws.StringValue(pair.GroupId)
+			groupRule.Description = aws.StringValue(pair.Description)
+			rules = append(rules, groupRule)
+		}
+	}
+	return rules
+}
+
+// CreateSecurityGroup creates a security group with the given <name>, <description> and <tags> in the VPC <vpcID>
+// and returns its ID.
+func (c *Client) CreateSecurityGroup(ctx context.Context, vpcID, name, description string, tags map[string]string) (string, error) {
+	output, err := c.EC2.CreateSecurityGroupWithContext(ctx, &ec2.CreateSecurityGroupInput{
+		VpcId:       aws.String(vpcID),
+		GroupName:   aws.String(name),
+		Description: aws.String(description),
+	})
+	if err != nil {
+		return "", err
+	}
+	id := aws.StringValue(output.GroupId)
+	return id, c.CreateTags(ctx, []string{id}, tags)
+}
+
+// ipPermissionOf returns the IP permission of the given security group <rule>.
+func ipPermissionOf(rule SecurityGroupRule) *ec2.IpPermission {
+	var description *string
+	if rule.Description != "" {
+		description = aws.String(rule.Description)
+	}
+
+	permission := &ec2.IpPermission{IpProtocol: aws.String(rule.Protocol)}
+	if rule.Protocol != "-1" {
+		permission.FromPort = aws.Int64(rule.FromPort)
+		permission.ToPort = aws.Int64(rule.ToPort)
+	}
+	if rule.CIDR != "" {
+		permission.IpRanges = []*ec2.IpRange{{CidrIp: aws.String(rule.CIDR), Description: description}}
+	}
+	if rule.SecurityGroupID != "" {
+		permission.UserIdGroupPairs = []*ec2.UserIdGroupPair{{GroupId: aws.String(rule.SecurityGroupID), Description: description}}
+	}
+	return permission
+}
+
+// AuthorizeSecurityGroupRules adds the given <rules> to the security group <id>. Rules that already exist are
+// ignored.
+func (c *Client) AuthorizeSecurityGroupRules(ctx context.Context, id string, rules []SecurityGroupRule) error {
+	for _, rule := range rules {
+		permission := ipPermissionOf(rule)
+
+		var err error
+		switch rule.Type {
+		case SecurityGroupRuleTypeIngress:
+			_, err = c.EC2.AuthorizeSecurityGroupIngressWithContext(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
+				GroupId:       aws.String(id),
+				IpPermissions: []*ec2.IpPermission{permission},
+			})
+		case SecurityGroupRuleTypeEgress:
+			_, err = c.EC2.AuthorizeSecurityGroupEgressWithContext(ctx, &ec2.AuthorizeSecurityGroupEgressInput{
+				GroupId:       aws.String(id),
+				IpPermissions: []*ec2.IpPermission{permission},
+			})
+		default:
+			err = fmt.Errorf("unknown security group rule type %q", rule.Type)
+		}
+		if err := ignoreErrorCodes(err, "InvalidPermission.Duplicate"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevokeSecurityGroupRules removes the given <rules> from the security group <id>. Rules that do not exist are
+// ignored.
+func (c *Client) RevokeSecurityGroupRules(ctx context.Context, id string, rules []SecurityGroupRule) error {
+	for _, rule := range rules {
+		permission := ipPermissionOf(rule)
+
+		var err error
+		switch rule.Type {
+		case SecurityGroupRuleTypeIngress:
+			_, err = c.EC2.RevokeSecurityGroupIngressWithContext(ctx, &ec2.RevokeSecurityGroupIngressInput{
+				GroupId:       aws.String(id),
+				IpPermissions: []*ec2.IpPermission{permission},
+			})
+		case SecurityGroupRuleTypeEgress:
+			_, err = c.EC2.RevokeSecurityGroupEgressWithContext(ctx, &ec2.RevokeSecurityGroupEgressInput{
+				GroupId:       aws.String(id),
+				IpPermissions: []*ec2.IpPermission{permission},
+			})
+		default:
+			err = fmt.Errorf("unknown security group rule type %q", rule.Type)
+		}
+		if err := ignoreErrorCodes(err, "InvalidPermission.NotFound"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindElasticIPs returns the Elastic IPs that carry all of the given <tags>.
+func (c *Client) FindElasticIPs(ctx context.Context, tags map[string]string) ([]ElasticIP, error) {
+	filters := append([]*ec2.Filter{
+		{
+			Name:   aws.String("domain"),
+			Values: []*string{aws.String(ec2.DomainTypeVpc)},
+		},
+	}, tagFilters(tags)...)
+	return c.describeAddresses(ctx, &ec2.DescribeAddressesInput{Filters: filters})
+}
+
+// GetElasticIP returns the Elastic IP with the given <allocationID>, or nil if it does not exist.
+func (c *Client) GetElasticIP(ctx context.Context, allocationID string) (*ElasticIP, error) {
+	addresses, err := c.describeAddresses(ctx, &ec2.DescribeAddressesInput{AllocationIds: []*string{aws.String(allocationID)}})
+	if err != nil {
+		if isErrorCode(err, "InvalidAllocationID.NotFound") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	return &addresses[0], nil
+}
+
+func (c *Client) describeAddresses(ctx context.Context, input *ec2.DescribeAddressesInput) ([]ElasticIP, error) {
+	output, err := c.EC2.DescribeAddressesWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var addresses []ElasticIP
+	for _, address := range output.Addresses {
+		addresses = append(addresses, ElasticIP{
+			AllocationID:  aws.StringValue(address.AllocationId),
+			PublicIP:      aws.StringValue(address.PublicIp),
+			AssociationID: aws.StringValue(address.AssociationId),
+			Tags:          tagsOf(address.Tags),
+		})
+	}
+	return addresses, nil
+}
+
+// AllocateElasticIP allocates an Elastic IP for use in VPCs and tags it with <tags>.
+func (c *Client) AllocateElasticIP(ctx context.Context, tags map[string]string) (*ElasticIP, error) {
+	output, err := c.EC2.AllocateAddressWithContext(ctx, &ec2.AllocateAddressInput{Domain: aws.String(ec2.DomainTypeVpc)})
+	if err != nil {
+		return nil, err
+	}
+	address := &ElasticIP{
+		AllocationID: aws.StringValue(output.AllocationId),
+		PublicIP:     aws.StringValue(output.PublicIp),
+		Tags:         tags,
+	}
+	return address, c.CreateTags(ctx, []string{address.AllocationID}, tags)
+}
+
+// ReleaseElasticIP releases the Elastic IP with the given <allocationID>. If it does not exist, no error is returned.
+func (c *Client) ReleaseElasticIP(ctx context.Context, allocationID string) error {
+	_, err := c.EC2.ReleaseAddressWithContext(ctx, &ec2.ReleaseAddressInput{AllocationId: aws.String(allocationID)})
+	return ignoreErrorCodes(err, "InvalidAllocationID.NotFound")
+}
+
+// FindNATGateways returns the NAT gateways in the given <vpcID> that carry all of the given <tags>. NAT gateways that
+// are already deleted are omitted.
+func (c *Client) FindNATGateways(ctx context.Context, vpcID string, tags map[string]string) ([]NATGateway, error) {
+	filters := append([]*ec2.Filter{
+		{
+			Name:   aws.String("vpc-id"),
+			Values: []*string{aws.String(vpcID)},
+		},
+	}, tagFilters(tags)...)
+
+	output, err := c.EC2.DescribeNatGatewaysWithContext(ctx, &ec2.DescribeNatGatewaysInput{Filter: filters})
+	if err != nil {
+		return nil, err
+	}
+
+	var gateways []NATGateway
+	for _, gateway := range output.NatGateways {
+		if aws.StringValue(gateway.State) == ec2.NatGatewayStateDeleted {
+			continue
+		}
+		natGateway := NATGateway{
+			ID:       aws.StringValue(gateway.NatGatewayId),
+			SubnetID: aws.StringValue(gateway.SubnetId),
+			State:    aws.StringValue(gateway.State),
+			Tags:     tagsOf(gateway.Tags),
+		}
+		if len(gateway.NatGatewayAddresses) > 0 {
+			natGateway.AllocationID = aws.StringValue(gateway.NatGatewayAddresses[0].AllocationId)
+		}
+		gateways = append(gateways, natGateway)
+	}
+	return gateways, nil
+}
+
+// CreateNATGateway creates a NAT gateway with the Elastic IP <allocationID> in the subnet <subnetID>, tags it with
+// <tags>, and returns its ID.
+func (c *Client) CreateNATGateway(ctx context.Context, subnetID, allocationID string, tags map[string]string) (string, error) {
+	output, err := c.EC2.CreateNatGatewayWithContext(ctx, &ec2.CreateNatGatewayInput{
+		SubnetId:     aws.String(subnetID),
+		AllocationId: aws.String(allocationID),
+	})
+	if err != nil {
+		return "", err
+	}
+	id := aws.StringValue(output.NatGateway.NatGatewayId)
+	return id, c.CreateTags(ctx, []string{id}, tags)
+}
+
+// DeleteNATGateway deletes the NAT gateway with the given <id>. If it does not exist, no error is returned.
+func (c *Client) DeleteNATGateway(ctx context.Context, id string) error {
+	_, err := c.EC2.DeleteNatGatewayWithContext(ctx, &ec2.DeleteNatGatewayInput{NatGatewayId: aws.String(id)})
+	return ignoreErrorCodes(err, "NatGatewayNotFound")
+}
+
+// FindVPCEndpoints returns the VPC endpoints in the given <vpcID>. Endpoints that are already deleted are omitted.
+func (c *Client) FindVPCEndpoints(ctx context.Context, vpcID string) ([]VPCEndpoint, error) {
+	output, err := c.EC2.DescribeVpcEndpointsWithContext(ctx, &ec2.DescribeVpcEndpointsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{aws.String(vpcID)},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []VPCEndpoint
+	for _, endpoint := range output.VpcEndpoints {
+		if state := strings.ToLower(aws.StringValue(endpoint.State)); state == "deleted" {
+			continue
+		}
+		var securityGroupIDs []string
+		for _, group := range endpoint.Groups {
+			securityGroupIDs = append(securityGroupIDs, aws.StringValue(group.GroupId))
+		}
+		endpoints = append(endpoints, VPCEndpoint{
+			ID:                aws.StringValue(endpoint.VpcEndpointId),
+			VPCID:             aws.StringValue(endpoint.VpcId),
+			ServiceName:       aws.StringValue(endpoint.ServiceName),
+			Type:              aws.StringValue(endpoint.VpcEndpointType),
+			RouteTableIDs:     aws.StringValueSlice(endpoint.RouteTableIds),
+			SubnetIDs:         aws.StringValueSlice(endpoint.SubnetIds),
+			SecurityGroupIDs:  securityGroupIDs,
+			PrivateDNSEnabled: aws.BoolValue(endpoint.PrivateDnsEnabled),
+			State:             aws.StringValue(endpoint.State),
+		})
+	}
+	return endpoints, nil
+}
+
+// CreateVPCEndpoint creates the given VPC <endpoint> and returns its ID. The id and state of the endpoint are
+// ignored.
+func (c *Client) CreateVPCEndpoint(ctx context.Context, endpoint VPCEndpoint) (string, error) {
+	input := &ec2.CreateVpcEndpointInput{
+		VpcId:           aws.String(endpoint.VPCID),
+		ServiceName:     aws.String(endpoint.ServiceName),
+		VpcEndpointType: aws.String(endpoint.Type),
+	}
+	if len(endpoint.RouteTableIDs) > 0 {
+		input.RouteTableIds = aws.StringSlice(endpoint.RouteTableIDs)
+	}
+	if len(endpoint.SubnetIDs) > 0 {
+		input.SubnetIds = aws.StringSlice(endpoint.SubnetIDs)
+		input.SecurityGroupIds = aws.StringSlice(endpoint.SecurityGroupIDs)
+		input.PrivateDnsEnabled = aws.Bool(endpoint.PrivateDNSEnabled)
+	}
+
+	output, err := c.EC2.CreateVpcEndpointWithContext(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(output.VpcEndpoint.VpcEndpointId), nil
+}
+
+// ModifyVPCEndpoint applies the given <modification> to the VPC endpoint <id>.
+func (c *Client) ModifyVPCEndpoint(ctx context.Context, id string, modification VPCEndpointModification) error {
+	input := &ec2.ModifyVpcEndpointInput{VpcEndpointId: aws.String(id)}
+	if len(modification.AddRouteTableIDs) > 0 {
+		input.AddRouteTableIds = aws.StringSlice(modification.AddRouteTableIDs)
+	}
+	if len(modification.RemoveRouteTableIDs) > 0 {
+		input.RemoveRouteTableIds = aws.StringSlice(modification.RemoveRouteTableIDs)
+	}
+	if len(modification.AddSubnetIDs) > 0 {
+		input.AddSubnetIds = aws.StringSlice(modification.AddSubnetIDs)
+	}
+	if len(modification.RemoveSubnetIDs) > 0 {
+		input.RemoveSubnetIds = aws.StringSlice(modification.RemoveSubnetIDs)
+	}
+	_, err := c.EC2.ModifyVpcEndpointWithContext(ctx, input)
+	return err
+}
+
+// DeleteVPCEndpoint deletes the VPC endpoint with the given <id>. If it does not exist, no error is returned.
+func (c *Client) DeleteVPCEndpoint(ctx context.Context, id string) error {
+	output, err := c.EC2.DeleteVpcEndpointsWithContext(ctx, &ec2.DeleteVpcEndpointsInput{VpcEndpointIds: []*string{aws.String(id)}})
+	if err != nil {
+		return ignoreErrorCodes(err, "InvalidVpcEndpointId.NotFound")
+	}
+	for _, item := range output.Unsuccessful {
+		if item.Error == nil || aws.StringValue(item.Error.Code) == "InvalidVpcEndpointId.NotFound" {
+			continue
+		}
+		return fmt.Errorf("could not delete VPC endpoint %s: %s", id, aws.StringValue(item.Error.Message))
+	}
+	return nil
+}
+
+// FindVPCPeeringConnections returns the VPC peering connections requested by the given <vpcID> that carry all of the
+// given <tags>. Peering connections that are deleted, rejected, failed or expired are omitted.
+func (c *Client) FindVPCPeeringConnections(ctx context.Context, vpcID string, tags map[string]string) ([]VPCPeeringConnection, error) {
+	filters := append([]*ec2.Filter{
+		{
+			Name:   aws.String("requester-vpc-info.vpc-id"),
+			Values: []*string{aws.String(vpcID)},
+		},
+	}, tagFilters(tags)...)
+
+	output, err := c.EC2.DescribeVpcPeeringConnectionsWithContext(ctx, &ec2.DescribeVpcPeeringConnectionsInput{Filters: filters})
+	if err != nil {
+		return nil, err
+	}
+
+	var connections []VPCPeeringConnection
+	for _, connection := range output.VpcPeeringConnections {
+		var state string
+		if connection.Status != nil {
+			state = aws.StringValue(connection.Status.Code)
+		}
+		switch state {
+		case ec2.VpcPeeringConnectionStateReasonCodeDeleted, ec2.VpcPeeringConnectionStateReasonCodeDeleting,
+			ec2.VpcPeeringConnectionStateReasonCodeRejected, ec2.VpcPeeringConnectionStateReasonCodeFailed,
+			ec2.VpcPeeringConnectionStateReasonCodeExpired:
+			continue
+		}
+
+		vpcPeeringConnection := VPCPeeringConnection{
+			ID:    aws.StringValue(connection.VpcPeeringConnectionId),
+			State: state,
+			Tags:  tagsOf(connection.Tags),
+		}
+		if requester := connection.RequesterVpcInfo; requester != nil {
+			vpcPeeringConnection.VPCID = aws.StringValue(requester.VpcId)
+			if accepter := connection.AccepterVpcInfo; accepter != nil {
+				vpcPeeringConnection.PeerVPCID = aws.StringValue(accepter.VpcId)
+				if ownerID := aws.StringValue(accepter.OwnerId); ownerID != aws.StringValue(requester.OwnerId) {
+					vpcPeeringConnection.PeerOwnerID = ownerID
+				}
+				if region := aws.StringValue(accepter.Region); region != aws.StringValue(requester.Region) {
+					vpcPeeringConnection.PeerRegion = region
+				}
+			}
+		}
+		connections = append(connections, vpcPeeringConnection)
+	}
+	return connections, nil
+}
+
+// CreateVPCPeeringConnection requests the given VPC peering <connection>, tags it with its tags, and returns its ID.
+// The id and state of the connection are ignored.
+func (c *Client) CreateVPCPeeringConnection(ctx context.Context, connection VPCPeeringConnection) (string, error) {
+	input := &ec2.CreateVpcPeeringConnectionInput{
+		VpcId:     aws.String(connection.VPCID),
+		PeerVpcId: aws.String(connection.PeerVPCID),
+	}
+	if connection.PeerOwnerID != "" {
+		input.PeerOwnerId = aws.String(connection.PeerOwnerID)
+	}
+	if connection.PeerRegion != "" {
+		input.PeerRegion = aws.String(connection.PeerRegion)
+	}
+
+	output, err := c.EC2.CreateVpcPeeringConnectionWithContext(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	id := aws.StringValue(output.VpcPeeringConnection.VpcPeeringConnectionId)
+	return id, c.CreateTags(ctx, []string{id}, connection.Tags)
+}
+
+// AcceptVPCPeeringConnection accepts the VPC peering connection with the given <id>. The Client must belong to the
+// account and region of the accepter VPC.
+func (c *Client) AcceptVPCPeeringConnection(ctx context.Context, id string) error {
+	_, err := c.EC2.AcceptVpcPeeringConnectionWithContext(ctx, &ec2.AcceptVpcPeeringConnectionInput{VpcPeeringConnectionId: aws.String(id)})
+	return err
+}
+
+// DeleteVPCPeeringConnection deletes the VPC peering connection with the given <id>. If it does not exist, no error
+// is returned.
+func (c *Client) DeleteVPCPeeringConnection(ctx context.Context, id string) error {
+	_, err := c.EC2.DeleteVpcPeeringConnectionWithContext(ctx, &ec2.DeleteVpcPeeringConnectionInput{VpcPeeringConnectionId: aws.String(id)})
+	return ignoreErrorCodes(err, "InvalidVpcPeeringConnectionID.NotFound")
+}
+
+// GetKeyPair returns the key pair with the given <name>, or nil if it does not exist.
+func (c *Client) GetKeyPair(ctx context.Context, name string) (*KeyPair, error) {
+	output, err := c.EC2.DescribeKeyPairsWithContext(ctx, &ec2.DescribeKeyPairsInput{KeyNames: []*string{aws.String(name)}})
+	if err != nil {
+		if isErrorCode(err, "InvalidKeyPair.NotFound") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(output.KeyPairs) == 0 {
+		return nil, nil
+	}
+	return &KeyPair{
+		Name:        aws.StringValue(output.KeyPairs[0].KeyName),
+		Fingerprint: aws.StringValue(output.KeyPairs[0].KeyFingerprint),
+	}, nil
+}
+
+// ImportKeyPair imports the given OpenSSH <publicKey> as key pair with the given <name>.
+func (c *Client) ImportKeyPair(ctx context.Context, name, publicKey string) error {
+	_, err := c.EC2.ImportKeyPairWithContext(ctx, &ec2.ImportKeyPairInput{
+		KeyName:           aws.String(name),
+		PublicKeyMaterial: []byte(publicKey),
+	})
+	return err
+}
+
+// DeleteKeyPair deletes the key pair with the given <name>. If it does not exist, no error is returned.
+func (c *Client) DeleteKeyPair(ctx context.Context, name string) error {
+	_, err := c.EC2.DeleteKeyPairWithContext(ctx, &ec2.DeleteKeyPairInput{KeyName: aws.String(name)})
+	return ignoreErrorCodes(err, "InvalidKeyPair.NotFound")
+}
+
+// PublicKeyFingerprint returns the fingerprint EC2 computes for an imported OpenSSH <publicKey>, i.e. the MD5 hash of
+// the key blob as colon-separated hex string.
+func PublicKeyFingerprint(publicKey string) (string, error) {
+	fields := strings.Fields(publicKey)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("invalid public key, expected the format '<type> <base64 key> [comment]'")
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %v", err)
+	}
+
+	sum := md5.Sum(blob)
+	hex := make([]string, 0, len(sum))
+	for _, b := range sum {
+		hex = append(hex, fmt.Sprintf("%02x", b))
+	}
+	return strings.Join(hex, ":"), nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"errors"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// EC2EndpointFlag is the name of the command line flag to override the AWS EC2 endpoint.
+	EC2EndpointFlag = "aws-ec2-endpoint"
+	// ELBEndpointFlag is the name of the command line flag to override the AWS ELB endpoint.
+	ELBEndpointFlag = "aws-elb-endpoint"
+	// STSEndpointFlag is the name of the command line flag to override the AWS STS endpoint.
+	STSEndpointFlag = "aws-sts-endpoint"
+	// IAMEndpointFlag is the name of the command line flag to override the AWS IAM endpoint.
+	IAMEndpointFlag = "aws-iam-endpoint"
+	// WebIdentityRoleARNFlag is the name of the command line flag for the ARN of the role of the web identity.
+	WebIdentityRoleARNFlag = "aws-web-identity-role-arn"
+	// WebIdentityTokenFileFlag is the name of the command line flag for the path of the web identity token file.
+	WebIdentityTokenFileFlag = "aws-web-identity-token-file"
+)
+
+// EndpointOptions are command line options to override the AWS service endpoints.
+type EndpointOptions struct {
+	// Endpoints are the endpoint overrides.
+	Endpoints Endpoints
+
+	config *EndpointConfig
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (o *EndpointOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Endpoints.EC2, EC2EndpointFlag, o.Endpoints.EC2, "The AWS EC2 endpoint to use instead of the default one.")
+	fs.StringVar(&o.Endpoints.ELB, ELBEndpointFlag, o.Endpoints.ELB, "The AWS ELB endpoint to use instead of the default one.")
+	fs.StringVar(&o.Endpoints.STS, STSEndpointFlag, o.Endpoints.STS, "The AWS STS endpoint to use instead of the default one.")
+	fs.StringVar(&o.Endpoints.IAM, IAMEndpointFlag, o.Endpoints.IAM, "The AWS IAM endpoint to use instead of the default one.")
+}
+
+// Complete implements Completer.Complete.
+func (o *EndpointOptions) Complete() error {
+	if err := o.Endpoints.Validate(); err != nil {
+		return err
+	}
+	o.config = &EndpointConfig{o.Endpoints}
+	return nil
+}
+
+// Completed returns the completed EndpointConfig. Only call this if `Complete` was successful.
+func (o *EndpointOptions) Completed() *EndpointConfig {
+	return o.config
+}
+
+// EndpointConfig is a completed endpoint configuration.
+type EndpointConfig struct {
+	// Endpoints are the endpoint overrides.
+	Endpoints Endpoints
+}
+
+// Apply sets the endpoints of this EndpointConfig in the given Endpoints.
+func (c *EndpointConfig) Apply(endpoints *Endpoints) {
+	*endpoints = c.Endpoints
+}
+
+// WebIdentityOptions are command line options for the web identity of the extension, which is used for cloud
+// provider secrets without an access key.
+type WebIdentityOptions struct {
+	// WebIdentity is the web identity of the extension.
+	WebIdentity WebIdentity
+
+	config *WebIdentityConfig
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (o *WebIdentityOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.WebIdentity.RoleARN, WebIdentityRoleARNFlag, o.WebIdentity.RoleARN, "The ARN of the AWS role assumed with the web identity token for cloud provider secrets without an access key.")
+	fs.StringVar(&o.WebIdentity.TokenFile, WebIdentityTokenFileFlag, o.WebIdentity.TokenFile, "The path of the file containing the web identity token, e.g. a projected service account token.")
+}
+
+// Complete implements Completer.Complete.
+func (o *WebIdentityOptions) Complete() error {
+	if (o.WebIdentity.RoleARN == "") != (o.WebIdentity.TokenFile == "") {
+		return errors.New("the web identity role ARN and token file must be set together")
+	}
+	o.config = &WebIdentityConfig{o.WebIdentity}
+	return nil
+}
+
+// Completed returns the completed WebIdentityConfig. Only call this if `Complete` was successful.
+func (o *WebIdentityOptions) Completed() *WebIdentityConfig {
+	return o.config
+}
+
+// WebIdentityConfig is a completed web identity configuration.
+type WebIdentityConfig struct {
+	// WebIdentity is the web identity of the extension.
+	WebIdentity WebIdentity
+}
+
+// Apply sets the web identity of this WebIdentityConfig in the given WebIdentity.
+func (c *WebIdentityConfig) Apply(webIdentity *WebIdentity) {
+	*webIdentity = c.WebIdentity
+}
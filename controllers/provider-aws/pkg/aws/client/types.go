@@ -17,29 +17,460 @@ package client
 import (
 	"context"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/sts"
 )
 
 // Interface is an interface which must be implemented by AWS clients.
 type Interface interface {
 	GetAccountID(ctx context.Context) (string, error)
+	GetCallerARN(ctx context.Context) (string, error)
+	GetAccountAttributes(ctx context.Context, names ...string) (map[string][]string, error)
+	SimulatePrincipalPolicy(ctx context.Context, principalARN string, actions []string) ([]string, error)
 	GetInternetGateway(ctx context.Context, vpcID string) (string, error)
+	GetSubnets(ctx context.Context, ids []string) ([]Subnet, error)
+	FindSubnets(ctx context.Context, vpcID, zone string, tags map[string]string) ([]Subnet, error)
+
+	// The following functions manage the resources of the infrastructure if they are not managed by Terraform.
+	CreateTags(ctx context.Context, resourceIDs []string, tags map[string]string) error
+	FindVPCs(ctx context.Context, tags map[string]string) ([]VPC, error)
+	CreateVPC(ctx context.Context, cidr string, tags map[string]string) (string, error)
+	DeleteVPC(ctx context.Context, id string) error
+	FindDHCPOptions(ctx context.Context, tags map[string]string) ([]DHCPOptions, error)
+	CreateDHCPOptions(ctx context.Context, domainName string, tags map[string]string) (string, error)
+	AssociateDHCPOptions(ctx context.Context, vpcID, dhcpOptionsID string) error
+	DeleteDHCPOptions(ctx context.Context, id string) error
+	FindInternetGateways(ctx context.Context, tags map[string]string) ([]InternetGateway, error)
+	CreateInternetGateway(ctx context.Context, tags map[string]string) (string, error)
+	AttachInternetGateway(ctx context.Context, id, vpcID string) error
+	DetachInternetGateway(ctx context.Context, id, vpcID string) error
+	DeleteInternetGateway(ctx context.Context, id string) error
+	CreateSubnet(ctx context.Context, vpcID, zone, cidr string, tags map[string]string) (string, error)
+	DeleteSubnet(ctx context.Context, id string) error
+	FindRouteTables(ctx context.Context, vpcID string, tags map[string]string) ([]RouteTable, error)
+	CreateRouteTable(ctx context.Context, vpcID string, tags map[string]string) (string, error)
+	CreateRoute(ctx context.Context, routeTableID string, route Route) error
+	ReplaceRoute(ctx context.Context, routeTableID string, route Route) error
+	DeleteRoute(ctx context.Context, routeTableID, destinationCIDR string) error
+	AssociateRouteTable(ctx context.Context, routeTableID, subnetID string) error
+	ReplaceRouteTableAssociation(ctx context.Context, associationID, routeTableID string) error
+	DisassociateRouteTable(ctx context.Context, associationID string) error
+	DeleteRouteTable(ctx context.Context, id string) error
+	FindSecurityGroups(ctx context.Context, vpcID string, tags map[string]string) ([]SecurityGroup, error)
+	CreateSecurityGroup(ctx context.Context, vpcID, name, description string, tags map[string]string) (string, error)
+	AuthorizeSecurityGroupRules(ctx context.Context, id string, rules []SecurityGroupRule) error
+	RevokeSecurityGroupRules(ctx context.Context, id string, rules []SecurityGroupRule) error
+	FindElasticIPs(ctx context.Context, tags map[string]string) ([]ElasticIP, error)
+	GetElasticIP(ctx context.Context, allocationID string) (*ElasticIP, error)
+	AllocateElasticIP(ctx context.Context, tags map[string]string) (*ElasticIP, error)
+	ReleaseElasticIP(ctx context.Context, allocationID string) error
+	FindNATGateways(ctx context.Context, vpcID string, tags map[string]string) ([]NATGateway, error)
+	CreateNATGateway(ctx context.Context, subnetID, allocationID string, tags map[string]string) (string, error)
+	DeleteNATGateway(ctx context.Context, id string) error
+	FindVPCEndpoints(ctx context.Context, vpcID string) ([]VPCEndpoint, error)
+	CreateVPCEndpoint(ctx context.Context, endpoint VPCEndpoint) (string, error)
+	ModifyVPCEndpoint(ctx context.Context, id string, modification VPCEndpointModification) error
+	DeleteVPCEndpoint(ctx context.Context, id string) error
+	FindVPCPeeringConnections(ctx context.Context, vpcID string, tags map[string]string) ([]VPCPeeringConnection, error)
+	CreateVPCPeeringConnection(ctx context.Context, connection VPCPeeringConnection) (string, error)
+	AcceptVPCPeeringConnection(ctx context.Context, id string) error
+	DeleteVPCPeeringConnection(ctx context.Context, id string) error
+	GetKeyPair(ctx context.Context, name string) (*KeyPair, error)
+	ImportKeyPair(ctx context.Context, name, publicKey string) error
+	DeleteKeyPair(ctx context.Context, name string) error
+	GetRole(ctx context.Context, name string) (*Role, error)
+	CreateRole(ctx context.Context, name, assumeRolePolicy string) (*Role, error)
+	PutRolePolicy(ctx context.Context, roleName, policyName, policy string) error
+	DeleteRole(ctx context.Context, name string) error
+	GetInstanceProfile(ctx context.Context, name string) (*InstanceProfile, error)
+	CreateInstanceProfile(ctx context.Context, name string) error
+	AddRoleToInstanceProfile(ctx context.Context, instanceProfileName, roleName string) error
+	DeleteInstanceProfile(ctx context.Context, name string) error
+	GetUser(ctx context.Context, name string) (*User, error)
+	CreateUser(ctx context.Context, name string) (*User, error)
+	PutUserPolicy(ctx context.Context, userName, policyName, policy string) error
+	DeleteUser(ctx context.Context, name string) error
+	ListAccessKeys(ctx context.Context, userName string) ([]string, error)
+	CreateAccessKey(ctx context.Context, userName string) (*AccessKey, error)
+	DeleteAccessKey(ctx context.Context, userName, id string) error
 
 	// The following functions are only temporary needed due to https://github.com/gardener/gardener/issues/129.
 	ListKubernetesELBs(ctx context.Context, vpcID, clusterName string) ([]string, error)
 	ListKubernetesSecurityGroups(ctx context.Context, vpcID, clusterName string) ([]string, error)
 	DeleteELB(ctx context.Context, name string) error
 	DeleteSecurityGroup(ctx context.Context, id string) error
+	ListKubernetesNLBs(ctx context.Context, vpcID, clusterName string) ([]string, error)
+	DeleteNLB(ctx context.Context, arn string) error
+	ListKubernetesTargetGroups(ctx context.Context, vpcID, clusterName string) ([]string, error)
+	DeleteTargetGroup(ctx context.Context, arn string) error
+	ListKubernetesENIs(ctx context.Context, vpcID, clusterName string) ([]string, error)
+	DeleteENI(ctx context.Context, id string) error
+	ListKubernetesVolumes(ctx context.Context, clusterName string) ([]string, error)
+	DeleteVolume(ctx context.Context, id string) error
+	ListKubernetesSnapshots(ctx context.Context, clusterName string) ([]string, error)
+	DeleteSnapshot(ctx context.Context, id string) error
 }
 
 // Client is a struct containing several clients for the different AWS services it needs to interact with.
 // * EC2 is the standard client for the EC2 service.
 // * ELB is the standard client for the ELB service.
+// * ELBV2 is the client for the ELB v2 service (network and application load balancers).
 // * STS is the standard client for the STS service.
+// * IAM is the client for the IAM service.
 type Client struct {
-	EC2 *ec2.EC2
-	ELB *elb.ELB
-	STS *sts.STS
+	EC2   EC2
+	ELB   ELB
+	ELBV2 ELBV2
+	STS   STS
+	IAM   IAM
+}
+
+// EC2 is the part of the EC2 API the Client uses. It is implemented by *ec2.EC2.
+type EC2 interface {
+	DescribeAccountAttributesWithContext(aws.Context, *ec2.DescribeAccountAttributesInput, ...request.Option) (*ec2.DescribeAccountAttributesOutput, error)
+	DescribeVpcsWithContext(aws.Context, *ec2.DescribeVpcsInput, ...request.Option) (*ec2.DescribeVpcsOutput, error)
+	DescribeInternetGatewaysWithContext(aws.Context, *ec2.DescribeInternetGatewaysInput, ...request.Option) (*ec2.DescribeInternetGatewaysOutput, error)
+	DescribeSubnetsWithContext(aws.Context, *ec2.DescribeSubnetsInput, ...request.Option) (*ec2.DescribeSubnetsOutput, error)
+	DescribeSecurityGroupsWithContext(aws.Context, *ec2.DescribeSecurityGroupsInput, ...request.Option) (*ec2.DescribeSecurityGroupsOutput, error)
+	DeleteSecurityGroupWithContext(aws.Context, *ec2.DeleteSecurityGroupInput, ...request.Option) (*ec2.DeleteSecurityGroupOutput, error)
+	DescribeNetworkInterfacesWithContext(aws.Context, *ec2.DescribeNetworkInterfacesInput, ...request.Option) (*ec2.DescribeNetworkInterfacesOutput, error)
+	DeleteNetworkInterfaceWithContext(aws.Context, *ec2.DeleteNetworkInterfaceInput, ...request.Option) (*ec2.DeleteNetworkInterfaceOutput, error)
+	DescribeVolumesWithContext(aws.Context, *ec2.DescribeVolumesInput, ...request.Option) (*ec2.DescribeVolumesOutput, error)
+	DeleteVolumeWithContext(aws.Context, *ec2.DeleteVolumeInput, ...request.Option) (*ec2.DeleteVolumeOutput, error)
+	DescribeSnapshotsWithContext(aws.Context, *ec2.DescribeSnapshotsInput, ...request.Option) (*ec2.DescribeSnapshotsOutput, error)
+	DeleteSnapshotWithContext(aws.Context, *ec2.DeleteSnapshotInput, ...request.Option) (*ec2.DeleteSnapshotOutput, error)
+
+	CreateTagsWithContext(aws.Context, *ec2.CreateTagsInput, ...request.Option) (*ec2.CreateTagsOutput, error)
+	CreateVpcWithContext(aws.Context, *ec2.CreateVpcInput, ...request.Option) (*ec2.CreateVpcOutput, error)
+	ModifyVpcAttributeWithContext(aws.Context, *ec2.ModifyVpcAttributeInput, ...request.Option) (*ec2.ModifyVpcAttributeOutput, error)
+	DeleteVpcWithContext(aws.Context, *ec2.DeleteVpcInput, ...request.Option) (*ec2.DeleteVpcOutput, error)
+	DescribeDhcpOptionsWithContext(aws.Context, *ec2.DescribeDhcpOptionsInput, ...request.Option) (*ec2.DescribeDhcpOptionsOutput, error)
+	CreateDhcpOptionsWithContext(aws.Context, *ec2.CreateDhcpOptionsInput, ...request.Option) (*ec2.CreateDhcpOptionsOutput, error)
+	AssociateDhcpOptionsWithContext(aws.Context, *ec2.AssociateDhcpOptionsInput, ...request.Option) (*ec2.AssociateDhcpOptionsOutput, error)
+	DeleteDhcpOptionsWithContext(aws.Context, *ec2.DeleteDhcpOptionsInput, ...request.Option) (*ec2.DeleteDhcpOptionsOutput, error)
+	CreateInternetGatewayWithContext(aws.Context, *ec2.CreateInternetGatewayInput, ...request.Option) (*ec2.CreateInternetGatewayOutput, error)
+	AttachInternetGatewayWithContext(aws.Context, *ec2.AttachInternetGatewayInput, ...request.Option) (*ec2.AttachInternetGatewayOutput, error)
+	DetachInternetGatewayWithContext(aws.Context, *ec2.DetachInternetGatewayInput, ...request.Option) (*ec2.DetachInternetGatewayOutput, error)
+	DeleteInternetGatewayWithContext(aws.Context, *ec2.DeleteInternetGatewayInput, ...request.Option) (*ec2.DeleteInternetGatewayOutput, error)
+	CreateSubnetWithContext(aws.Context, *ec2.CreateSubnetInput, ...request.Option) (*ec2.CreateSubnetOutput, error)
+	DeleteSubnetWithContext(aws.Context, *ec2.DeleteSubnetInput, ...request.Option) (*ec2.DeleteSubnetOutput, error)
+	DescribeRouteTablesWithContext(aws.Context, *ec2.DescribeRouteTablesInput, ...request.Option) (*ec2.DescribeRouteTablesOutput, error)
+	CreateRouteTableWithContext(aws.Context, *ec2.CreateRouteTableInput, ...request.Option) (*ec2.CreateRouteTableOutput, error)
+	CreateRouteWithContext(aws.Context, *ec2.CreateRouteInput, ...request.Option) (*ec2.CreateRouteOutput, error)
+	ReplaceRouteWithContext(aws.Context, *ec2.ReplaceRouteInput, ...request.Option) (*ec2.ReplaceRouteOutput, error)
+	DeleteRouteWithContext(aws.Context, *ec2.DeleteRouteInput, ...request.Option) (*ec2.DeleteRouteOutput, error)
+	AssociateRouteTableWithContext(aws.Context, *ec2.AssociateRouteTableInput, ...request.Option) (*ec2.AssociateRouteTableOutput, error)
+	ReplaceRouteTableAssociationWithContext(aws.Context, *ec2.ReplaceRouteTableAssociationInput, ...request.Option) (*ec2.ReplaceRouteTableAssociationOutput, error)
+	DisassociateRouteTableWithContext(aws.Context, *ec2.DisassociateRouteTableInput, ...request.Option) (*ec2.DisassociateRouteTableOutput, error)
+	DeleteRouteTableWithContext(aws.Context, *ec2.DeleteRouteTableInput, ...request.Option) (*ec2.DeleteRouteTableOutput, error)
+	CreateSecurityGroupWithContext(aws.Context, *ec2.CreateSecurityGroupInput, ...request.Option) (*ec2.CreateSecurityGroupOutput, error)
+	AuthorizeSecurityGroupIngressWithContext(aws.Context, *ec2.AuthorizeSecurityGroupIngressInput, ...request.Option) (*ec2.AuthorizeSecurityGroupIngressOutput, error)
+	AuthorizeSecurityGroupEgressWithContext(aws.Context, *ec2.AuthorizeSecurityGroupEgressInput, ...request.Option) (*ec2.AuthorizeSecurityGroupEgressOutput, error)
+	RevokeSecurityGroupIngressWithContext(aws.Context, *ec2.RevokeSecurityGroupIngressInput, ...request.Option) (*ec2.RevokeSecurityGroupIngressOutput, error)
+	RevokeSecurityGroupEgressWithContext(aws.Context, *ec2.RevokeSecurityGroupEgressInput, ...request.Option) (*ec2.RevokeSecurityGroupEgressOutput, error)
+	DescribeAddressesWithContext(aws.Context, *ec2.DescribeAddressesInput, ...request.Option) (*ec2.DescribeAddressesOutput, error)
+	AllocateAddressWithContext(aws.Context, *ec2.AllocateAddressInput, ...request.Option) (*ec2.AllocateAddressOutput, error)
+	ReleaseAddressWithContext(aws.Context, *ec2.ReleaseAddressInput, ...request.Option) (*ec2.ReleaseAddressOutput, error)
+	DescribeNatGatewaysWithContext(aws.Context, *ec2.DescribeNatGatewaysInput, ...request.Option) (*ec2.DescribeNatGatewaysOutput, error)
+	CreateNatGatewayWithContext(aws.Context, *ec2.CreateNatGatewayInput, ...request.Option) (*ec2.CreateNatGatewayOutput, error)
+	DeleteNatGatewayWithContext(aws.Context, *ec2.DeleteNatGatewayInput, ...request.Option) (*ec2.DeleteNatGatewayOutput, error)
+	DescribeVpcEndpointsWithContext(aws.Context, *ec2.DescribeVpcEndpointsInput, ...request.Option) (*ec2.DescribeVpcEndpointsOutput, error)
+	CreateVpcEndpointWithContext(aws.Context, *ec2.CreateVpcEndpointInput, ...request.Option) (*ec2.CreateVpcEndpointOutput, error)
+	ModifyVpcEndpointWithContext(aws.Context, *ec2.ModifyVpcEndpointInput, ...request.Option) (*ec2.ModifyVpcEndpointOutput, error)
+	DeleteVpcEndpointsWithContext(aws.Context, *ec2.DeleteVpcEndpointsInput, ...request.Option) (*ec2.DeleteVpcEndpointsOutput, error)
+	DescribeVpcPeeringConnectionsWithContext(aws.Context, *ec2.DescribeVpcPeeringConnectionsInput, ...request.Option) (*ec2.DescribeVpcPeeringConnectionsOutput, error)
+	CreateVpcPeeringConnectionWithContext(aws.Context, *ec2.CreateVpcPeeringConnectionInput, ...request.Option) (*ec2.CreateVpcPeeringConnectionOutput, error)
+	AcceptVpcPeeringConnectionWithContext(aws.Context, *ec2.AcceptVpcPeeringConnectionInput, ...request.Option) (*ec2.AcceptVpcPeeringConnectionOutput, error)
+	DeleteVpcPeeringConnectionWithContext(aws.Context, *ec2.DeleteVpcPeeringConnectionInput, ...request.Option) (*ec2.DeleteVpcPeeringConnectionOutput, error)
+	DescribeKeyPairsWithContext(aws.Context, *ec2.DescribeKeyPairsInput, ...request.Option) (*ec2.DescribeKeyPairsOutput, error)
+	ImportKeyPairWithContext(aws.Context, *ec2.ImportKeyPairInput, ...request.Option) (*ec2.ImportKeyPairOutput, error)
+	DeleteKeyPairWithContext(aws.Context, *ec2.DeleteKeyPairInput, ...request.Option) (*ec2.DeleteKeyPairOutput, error)
+}
+
+// Subnet is an AWS subnet.
+type Subnet struct {
+	// ID is the id of the subnet.
+	ID string
+	// VPCID is the id of the VPC of the subnet.
+	VPCID string
+	// AvailabilityZone is the availability zone of the subnet.
+	AvailabilityZone string
+	// CIDR is the IPv4 CIDR block of the subnet.
+	CIDR string
+	// Tags are the tags of the subnet.
+	Tags map[string]string
+}
+
+// VPC is an AWS VPC.
+type VPC struct {
+	// ID is the id of the VPC.
+	ID string
+	// CIDR is the IPv4 CIDR block of the VPC.
+	CIDR string
+	// DHCPOptionsID is the id of the DHCP options associated with the VPC.
+	DHCPOptionsID string
+	// Tags are the tags of the VPC.
+	Tags map[string]string
+}
+
+// DHCPOptions are AWS DHCP options.
+type DHCPOptions struct {
+	// ID is the id of the DHCP options.
+	ID string
+	// Tags are the tags of the DHCP options.
+	Tags map[string]string
+}
+
+// InternetGateway is an AWS internet gateway.
+type InternetGateway struct {
+	// ID is the id of the internet gateway.
+	ID string
+	// VPCIDs are the ids of the VPCs the internet gateway is attached to.
+	VPCIDs []string
+	// Tags are the tags of the internet gateway.
+	Tags map[string]string
+}
+
+// RouteTable is an AWS route table.
+type RouteTable struct {
+	// ID is the id of the route table.
+	ID string
+	// Routes are the routes of the route table.
+	Routes []Route
+	// Associations are the associations of the route table with subnets.
+	Associations []RouteTableAssociation
+	// Tags are the tags of the route table.
+	Tags map[string]string
+}
+
+// Route is a route of a route table. Exactly one of its targets is set.
+type Route struct {
+	// DestinationCIDR is the IPv4 CIDR block the route matches.
+	DestinationCIDR string
+	// GatewayID is the id of the internet gateway the traffic is routed to.
+	GatewayID string
+	// NATGatewayID is the id of the NAT gateway the traffic is routed to.
+	NATGatewayID string
+	// VPCPeeringConnectionID is the id of the VPC peering connection the traffic is routed to.
+	VPCPeeringConnectionID string
+}
+
+// RouteTableAssociation is the association of a route table with a subnet.
+type RouteTableAssociation struct {
+	// ID is the id of the association.
+	ID string
+	// SubnetID is the id of the associated subnet. It is empty for the association of the main route table.
+	SubnetID string
+	// Main reports whether the route table is the main route table of its VPC.
+	Main bool
+}
+
+// SecurityGroup is an AWS security group.
+type SecurityGroup struct {
+	// ID is the id of the security group.
+	ID string
+	// Name is the name of the security group.
+	Name string
+	// Rules are the ingress and egress rules of the security group.
+	Rules []SecurityGroupRule
+	// Tags are the tags of the security group.
+	Tags map[string]string
+}
+
+// SecurityGroupRuleType is the type of a security group rule.
+type SecurityGroupRuleType string
+
+const (
+	// SecurityGroupRuleTypeIngress is the type of rules for inbound traffic.
+	SecurityGroupRuleTypeIngress SecurityGroupRuleType = "ingress"
+	// SecurityGroupRuleTypeEgress is the type of rules for outbound traffic.
+	SecurityGroupRuleTypeEgress SecurityGroupRuleType = "egress"
+)
+
+// SecurityGroupRule is a rule of a security group with a single source or destination, either a CIDR block or a
+// security group.
+type SecurityGroupRule struct {
+	// Type is the type of the rule.
+	Type SecurityGroupRuleType
+	// Protocol is the IP protocol of the rule, e.g. `tcp`, `udp` or `-1` for all protocols.
+	Protocol string
+	// FromPort is the start of the port range of the rule. It is zero for all protocols.
+	FromPort int64
+	// ToPort is the end of the port range of the rule. It is zero for all protocols.
+	ToPort int64
+	// CIDR is the IPv4 CIDR block of the source or destination.
+	CIDR string
+	// SecurityGroupID is the id of the security group of the source or destination.
+	SecurityGroupID string
+	// Description is the optional description of the rule.
+	Description string
+}
+
+// ElasticIP is an AWS Elastic IP address of the VPC domain.
+type ElasticIP struct {
+	// AllocationID is the allocation id of the Elastic IP.
+	AllocationID string
+	// PublicIP is the public IPv4 address.
+	PublicIP string
+	// AssociationID is the id of the association with an instance or network interface, if any.
+	AssociationID string
+	// Tags are the tags of the Elastic IP.
+	Tags map[string]string
+}
+
+// NATGateway is an AWS NAT gateway.
+type NATGateway struct {
+	// ID is the id of the NAT gateway.
+	ID string
+	// SubnetID is the id of the subnet of the NAT gateway.
+	SubnetID string
+	// AllocationID is the allocation id of the Elastic IP of the NAT gateway.
+	AllocationID string
+	// State is the state of the NAT gateway, e.g. `pending`, `available` or `deleting`.
+	State string
+	// Tags are the tags of the NAT gateway.
+	Tags map[string]string
+}
+
+// VPCEndpoint is an AWS VPC endpoint. VPC endpoints cannot be tagged, they are identified by their VPC and service.
+type VPCEndpoint struct {
+	// ID is the id of the VPC endpoint.
+	ID string
+	// VPCID is the id of the VPC of the endpoint.
+	VPCID string
+	// ServiceName is the name of the service, e.g. `com.amazonaws.eu-west-1.s3`.
+	ServiceName string
+	// Type is the type of the endpoint, `Gateway` or `Interface`.
+	Type string
+	// RouteTableIDs are the ids of the route tables of a gateway endpoint.
+	RouteTableIDs []string
+	// SubnetIDs are the ids of the subnets of an interface endpoint.
+	SubnetIDs []string
+	// SecurityGroupIDs are the ids of the security groups of an interface endpoint.
+	SecurityGroupIDs []string
+	// PrivateDNSEnabled reports whether the service's DNS name resolves to the interface endpoint.
+	PrivateDNSEnabled bool
+	// State is the state of the endpoint, e.g. `pending`, `available` or `deleting`.
+	State string
+}
+
+// VPCEndpointModification changes the route tables or subnets of a VPC endpoint.
+type VPCEndpointModification struct {
+	// AddRouteTableIDs are the ids of the route tables to add to a gateway endpoint.
+	AddRouteTableIDs []string
+	// RemoveRouteTableIDs are the ids of the route tables to remove from a gateway endpoint.
+	RemoveRouteTableIDs []string
+	// AddSubnetIDs are the ids of the subnets to add to an interface endpoint.
+	AddSubnetIDs []string
+	// RemoveSubnetIDs are the ids of the subnets to remove from an interface endpoint.
+	RemoveSubnetIDs []string
+}
+
+// VPCPeeringConnection is an AWS VPC peering connection.
+type VPCPeeringConnection struct {
+	// ID is the id of the peering connection.
+	ID string
+	// VPCID is the id of the requester VPC.
+	VPCID string
+	// PeerVPCID is the id of the accepter VPC.
+	PeerVPCID string
+	// PeerOwnerID is the id of the AWS account of the accepter VPC. It is empty for the account of the requester.
+	PeerOwnerID string
+	// PeerRegion is the region of the accepter VPC. It is empty for the region of the requester.
+	PeerRegion string
+	// State is the state of the peering connection, e.g. `pending-acceptance` or `active`.
+	State string
+	// Tags are the tags of the peering connection.
+	Tags map[string]string
+}
+
+// KeyPair is an AWS EC2 key pair.
+type KeyPair struct {
+	// Name is the name of the key pair.
+	Name string
+	// Fingerprint is the fingerprint of the public key as computed by PublicKeyFingerprint.
+	Fingerprint string
+}
+
+// Role is an AWS IAM role.
+type Role struct {
+	// Name is the name of the role.
+	Name string
+	// ARN is the ARN of the role.
+	ARN string
+}
+
+// InstanceProfile is an AWS IAM instance profile.
+type InstanceProfile struct {
+	// Name is the name of the instance profile.
+	Name string
+	// RoleNames are the names of the roles of the instance profile.
+	RoleNames []string
+}
+
+// User is an AWS IAM user.
+type User struct {
+	// Name is the name of the user.
+	Name string
+	// ARN is the ARN of the user.
+	ARN string
+}
+
+// AccessKey is an access key of an AWS IAM user.
+type AccessKey struct {
+	// ID is the id of the access key.
+	ID string
+	// Secret is the secret access key. It is only known right after the creation of the access key.
+	Secret string
+}
+
+// ELB is the part of the ELB API the Client uses. It is implemented by *elb.ELB.
+type ELB interface {
+	DescribeLoadBalancersWithContext(aws.Context, *elb.DescribeLoadBalancersInput, ...request.Option) (*elb.DescribeLoadBalancersOutput, error)
+	DescribeTagsWithContext(aws.Context, *elb.DescribeTagsInput, ...request.Option) (*elb.DescribeTagsOutput, error)
+	DeleteLoadBalancerWithContext(aws.Context, *elb.DeleteLoadBalancerInput, ...request.Option) (*elb.DeleteLoadBalancerOutput, error)
+}
+
+// ELBV2 is the part of the ELB v2 API the Client uses. It is implemented by *elbv2.ELBV2.
+type ELBV2 interface {
+	DescribeLoadBalancersWithContext(aws.Context, *elbv2.DescribeLoadBalancersInput, ...request.Option) (*elbv2.DescribeLoadBalancersOutput, error)
+	DeleteLoadBalancerWithContext(aws.Context, *elbv2.DeleteLoadBalancerInput, ...request.Option) (*elbv2.DeleteLoadBalancerOutput, error)
+	DescribeTargetGroupsWithContext(aws.Context, *elbv2.DescribeTargetGroupsInput, ...request.Option) (*elbv2.DescribeTargetGroupsOutput, error)
+	DeleteTargetGroupWithContext(aws.Context, *elbv2.DeleteTargetGroupInput, ...request.Option) (*elbv2.DeleteTargetGroupOutput, error)
+	DescribeTagsWithContext(aws.Context, *elbv2.DescribeTagsInput, ...request.Option) (*elbv2.DescribeTagsOutput, error)
+}
+
+// STS is the part of the STS API the Client uses. It is implemented by *sts.STS.
+type STS interface {
+	GetCallerIdentityWithContext(aws.Context, *sts.GetCallerIdentityInput, ...request.Option) (*sts.GetCallerIdentityOutput, error)
+}
+
+// IAM is the part of the IAM API the Client uses. It is implemented by *iam.IAM.
+type IAM interface {
+	GetRoleWithContext(aws.Context, *iam.GetRoleInput, ...request.Option) (*iam.GetRoleOutput, error)
+	CreateRoleWithContext(aws.Context, *iam.CreateRoleInput, ...request.Option) (*iam.CreateRoleOutput, error)
+	DeleteRoleWithContext(aws.Context, *iam.DeleteRoleInput, ...request.Option) (*iam.DeleteRoleOutput, error)
+	PutRolePolicyWithContext(aws.Context, *iam.PutRolePolicyInput, ...request.Option) (*iam.PutRolePolicyOutput, error)
+	ListRolePoliciesWithContext(aws.Context, *iam.ListRolePoliciesInput, ...request.Option) (*iam.ListRolePoliciesOutput, error)
+	DeleteRolePolicyWithContext(aws.Context, *iam.DeleteRolePolicyInput, ...request.Option) (*iam.DeleteRolePolicyOutput, error)
+	GetInstanceProfileWithContext(aws.Context, *iam.GetInstanceProfileInput, ...request.Option) (*iam.GetInstanceProfileOutput, error)
+	CreateInstanceProfileWithContext(aws.Context, *iam.CreateInstanceProfileInput, ...request.Option) (*iam.CreateInstanceProfileOutput, error)
+	DeleteInstanceProfileWithContext(aws.Context, *iam.DeleteInstanceProfileInput, ...request.Option) (*iam.DeleteInstanceProfileOutput, error)
+	AddRoleToInstanceProfileWithContext(aws.Context, *iam.AddRoleToInstanceProfileInput, ...request.Option) (*iam.AddRoleToInstanceProfileOutput, error)
+	RemoveRoleFromInstanceProfileWithContext(aws.Context, *iam.RemoveRoleFromInstanceProfileInput, ...request.Option) (*iam.RemoveRoleFromInstanceProfileOutput, error)
+	GetUserWithContext(aws.Context, *iam.GetUserInput, ...request.Option) (*iam.GetUserOutput, error)
+	CreateUserWithContext(aws.Context, *iam.CreateUserInput, ...request.Option) (*iam.CreateUserOutput, error)
+	DeleteUserWithContext(aws.Context, *iam.DeleteUserInput, ...request.Option) (*iam.DeleteUserOutput, error)
+	PutUserPolicyWithContext(aws.Context, *iam.PutUserPolicyInput, ...request.Option) (*iam.PutUserPolicyOutput, error)
+	ListUserPoliciesWithContext(aws.Context, *iam.ListUserPoliciesInput, ...request.Option) (*iam.ListUserPoliciesOutput, error)
+	DeleteUserPolicyWithContext(aws.Context, *iam.DeleteUserPolicyInput, ...request.Option) (*iam.DeleteUserPolicyOutput, error)
+	CreateAccessKeyWithContext(aws.Context, *iam.CreateAccessKeyInput, ...request.Option) (*iam.CreateAccessKeyOutput, error)
+	ListAccessKeysWithContext(aws.Context, *iam.ListAccessKeysInput, ...request.Option) (*iam.ListAccessKeysOutput, error)
+	DeleteAccessKeyWithContext(aws.Context, *iam.DeleteAccessKeyInput, ...request.Option) (*iam.DeleteAccessKeyOutput, error)
+	SimulatePrincipalPolicyWithContext(aws.Context, *iam.SimulatePrincipalPolicyInput, ...request.Option) (*iam.SimulatePolicyResponse, error)
 }
@@ -19,12 +19,41 @@ import "path/filepath"
 const (
 	// TerraformerImageName is the name of the Terraformer image.
 	TerraformerImageName = "terraformer"
+	// CSIDriverImageName is the name of the AWS EBS CSI driver image.
+	CSIDriverImageName = "csi-driver-aws-ebs"
+	// CSIProvisionerImageName is the name of the CSI external-provisioner image.
+	CSIProvisionerImageName = "csi-provisioner"
+	// CSIAttacherImageName is the name of the CSI external-attacher image.
+	CSIAttacherImageName = "csi-attacher"
+	// CSIResizerImageName is the name of the CSI external-resizer image.
+	CSIResizerImageName = "csi-resizer"
+	// CSISnapshotterImageName is the name of the CSI external-snapshotter image.
+	CSISnapshotterImageName = "csi-snapshotter"
+	// CSILivenessProbeImageName is the name of the CSI liveness probe image.
+	CSILivenessProbeImageName = "csi-liveness-probe"
 	// AccessKeyID is a constant for the key in a cloud provider secret and backup secret that holds the AWS access key id.
 	AccessKeyID = "accessKeyID"
 	// SecretAccessKey is a constant for the key in a cloud provider secret and backup secret that holds the AWS secret access key.
 	SecretAccessKey = "secretAccessKey"
+	// RoleARN is a constant for the optional key in a cloud provider secret that holds the ARN of a role that is
+	// assumed with the credentials of the secret, or with the web identity of the extension if the secret has no access key.
+	RoleARN = "roleARN"
+	// ExternalID is a constant for the optional key in a cloud provider secret that holds the external id used when
+	// assuming the role.
+	ExternalID = "externalID"
 	// Region is a constant for the key in a backup secret that holds the AWS region.
 	Region = "region"
+	// EC2Endpoint is a constant for the optional key in a cloud provider secret that overrides the AWS EC2 endpoint.
+	EC2Endpoint = "ec2Endpoint"
+	// ELBEndpoint is a constant for the optional key in a cloud provider secret that overrides the AWS ELB endpoint.
+	ELBEndpoint = "elbEndpoint"
+	// STSEndpoint is a constant for the optional key in a cloud provider secret that overrides the AWS STS endpoint.
+	STSEndpoint = "stsEndpoint"
+	// IAMEndpoint is a constant for the optional key in a cloud provider secret that overrides the AWS IAM endpoint.
+	IAMEndpoint = "iamEndpoint"
+	// CloudControllerManagerCredentialsSecretName is the name of the secret in the shoot namespace that holds the
+	// access key of the IAM user of the cloud-controller-manager.
+	CloudControllerManagerCredentialsSecretName = "cloud-controller-manager-credentials"
 	// TerrformerPurposeInfra is a constant for the complete Terraform setup with purpose 'infrastructure'.
 	TerrformerPurposeInfra = "infra"
 	// VPCIDKey is the vpc_id tf state key
@@ -33,8 +62,28 @@ const (
 	SubnetPublicPrefix = "subnet_public_utility_z"
 	// SubnetNodesPrefix is the prefix for the subnets
 	SubnetNodesPrefix = "subnet_nodes_z"
+	// SubnetPrivatePrefix is the prefix for the private subnets used for internal load balancers
+	SubnetPrivatePrefix = "subnet_private_utility_z"
+	// InternetGatewayID is the key for accessing the internet gateway id from outputs in terraform
+	InternetGatewayID = "internet_gateway_id"
+	// RouteTableMain is the key for accessing the id of the main route table of the public subnets from outputs in terraform
+	RouteTableMain = "route_table_main"
+	// RouteTablePrivatePrefix is the prefix for the route tables of the private subnets
+	RouteTablePrivatePrefix = "route_table_private_utility_z"
+	// NATGatewayIDPrefix is the prefix for the NAT gateway ids
+	NATGatewayIDPrefix = "nat_gateway_id_z"
+	// NATGatewayPublicIPPrefix is the prefix for the public (egress) IPs of the NAT gateways
+	NATGatewayPublicIPPrefix = "nat_gateway_public_ip_z"
+	// NATGatewayEIPAllocationIDPrefix is the prefix for the allocation ids of the Elastic IPs of the NAT gateways
+	NATGatewayEIPAllocationIDPrefix = "nat_gateway_eip_allocation_id_z"
+	// VPCEndpointPrefix is the prefix for the VPC endpoint ids, followed by the name of the endpoint's service
+	VPCEndpointPrefix = "vpc_endpoint_"
+	// VPCPeeringConnectionPrefix is the prefix for the VPC peering connection ids, followed by the name of the peering
+	VPCPeeringConnectionPrefix = "vpc_peering_connection_"
 	// SecurityGroupsNodes is the key for accessing nodes security groups from outputs in terraform
 	SecurityGroupsNodes = "security_group_nodes"
+	// SecurityGroupsBastions is the key for accessing bastions security groups from outputs in terraform
+	SecurityGroupsBastions = "security_group_bastions"
 	// SSHKeyName key for accessing SSH key name from outputs in terraform
 	SSHKeyName = "keyName"
 	// IAMInstanceProfileNodes key for accessing Nodes Instance profile from outputs in terraform
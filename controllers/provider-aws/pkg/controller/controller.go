@@ -15,17 +15,28 @@
 package controller
 
 import (
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
 	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/controller/controlplane"
 	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/controller/infrastructure"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/controller/worker"
 	"github.com/gardener/gardener-extensions/pkg/controller"
+	genericinfrastructure "github.com/gardener/gardener-extensions/pkg/controller/infrastructure"
+	genericworker "github.com/gardener/gardener-extensions/pkg/controller/worker"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 )
 
 var (
-	addToManagerBuilder = controller.NewAddToManagerBuilder(
-		infrastructure.AddToManager,
-		controlplane.AddToManager,
-	)
+	registry controller.Registry
 
 	// AddToManager adds all provider controllers to the given manager.
-	AddToManager = addToManagerBuilder.AddToManager
+	AddToManager = registry.AddToManager
+	// ControllerResources returns the kinds and types of the resources handled by the provider controllers.
+	ControllerResources = registry.ControllerResources
 )
+
+func init() {
+	registry.Register(genericinfrastructure.InfrastructureResource, aws.Type, infrastructure.AddToManager)
+	registry.Register(extensionsv1alpha1.ControlPlaneResource, aws.Type, controlplane.AddToManager)
+	registry.Register(genericworker.WorkerResource, aws.Type, worker.AddToManager)
+}
@@ -16,7 +16,6 @@ package controlplane
 
 import (
 	"context"
-	"fmt"
 	"path/filepath"
 
 	apisaws "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
@@ -30,17 +29,21 @@ import (
 	gardenv1beta1 "github.com/gardener/gardener/pkg/apis/garden/v1beta1"
 	gardenerkubernetes "github.com/gardener/gardener/pkg/client/kubernetes"
 	"github.com/gardener/gardener/pkg/operation/common"
+	"github.com/gardener/gardener/pkg/utils"
 	"github.com/gardener/gardener/pkg/utils/chart"
 	"github.com/gardener/gardener/pkg/utils/secrets"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
@@ -49,8 +52,17 @@ import (
 const (
 	cloudControllerManagerDeploymentName = "cloud-controller-manager"
 	cloudControllerManagerServerName     = "cloud-controller-manager-server"
+	csiDriverControllerDeploymentName    = "csi-driver-controller"
+	csiProvisionerName                   = "csi-provisioner"
+	csiAttacherName                      = "csi-attacher"
+	csiResizerName                       = "csi-resizer"
+	csiSnapshotterName                   = "csi-snapshotter"
 )
 
+// csiMinimumKubernetesVersion is the minimum Kubernetes version of shoots for which the EBS CSI driver controller is
+// deployed.
+const csiMinimumKubernetesVersion = "1.14"
+
 var controlPlaneSecrets = &secrets.Secrets{
 	CertificateSecretConfigs: map[string]*secrets.CertificateSecretConfig{
 		gardencorev1alpha1.SecretNameCACluster: {
@@ -83,10 +95,36 @@ var controlPlaneSecrets = &secrets.Secrets{
 					SigningCA:  cas[gardencorev1alpha1.SecretNameCACluster],
 				},
 			},
+			csiSecretConfig(csiProvisionerName, cas, clusterName),
+			csiSecretConfig(csiAttacherName, cas, clusterName),
+			csiSecretConfig(csiResizerName, cas, clusterName),
+			csiSecretConfig(csiSnapshotterName, cas, clusterName),
 		}
 	},
 }
 
+// csiSecretConfig returns the config of the kubeconfig secret of the CSI sidecar with the given name. Each sidecar
+// authenticates as its own user, whose permissions are granted by the csiShootChart.
+func csiSecretConfig(name string, cas map[string]*secrets.Certificate, clusterName string) secrets.ConfigInterface {
+	return &secrets.ControlPlaneSecretConfig{
+		CertificateSecretConfig: &secrets.CertificateSecretConfig{
+			Name:       name,
+			CommonName: csiUserName(name),
+			CertType:   secrets.ClientCert,
+			SigningCA:  cas[gardencorev1alpha1.SecretNameCACluster],
+		},
+		KubeConfigRequest: &secrets.KubeConfigRequest{
+			ClusterName:  clusterName,
+			APIServerURL: common.KubeAPIServerDeploymentName,
+		},
+	}
+}
+
+// csiUserName returns the name of the user the CSI sidecar with the given name authenticates as.
+func csiUserName(name string) string {
+	return "system:" + name
+}
+
 var configChart = &chart.Chart{
 	Name: "cloud-provider-config",
 	Path: filepath.Join(aws.InternalChartsPath, "cloud-provider-config"),
@@ -111,38 +149,13 @@ var ccmChart = &chart.Chart{
 			"podNetwork":        extensionscontroller.GetPodNetwork(shoot),
 			"replicas":          extensionscontroller.GetReplicas(shoot, 1),
 			"podAnnotations": map[string]interface{}{
-				"checksum/secret-cloud-controller-manager":        checksums[cloudControllerManagerDeploymentName],
-				"checksum/secret-cloud-controller-manager-server": checksums[cloudControllerManagerServerName],
-				"checksum/secret-cloudprovider":                   checksums[common.CloudProviderSecretName],
-				"checksum/configmap-cloud-provider-config":        checksums[common.CloudProviderConfigName],
+				"checksum/secret-cloud-controller-manager":                           checksums[cloudControllerManagerDeploymentName],
+				"checksum/secret-cloud-controller-manager-server":                    checksums[cloudControllerManagerServerName],
+				"checksum/secret-" + aws.CloudControllerManagerCredentialsSecretName: checksums[aws.CloudControllerManagerCredentialsSecretName],
+				"checksum/configmap-cloud-provider-config":                           checksums[common.CloudProviderConfigName],
 			},
 			"configureRoutes": false,
-			"environment": []map[string]interface{}{
-				{
-					"name": "AWS_ACCESS_KEY_ID",
-					"valueFrom": map[string]interface{}{
-						"secretKeyRef": map[string]interface{}{
-							"key":  aws.AccessKeyID,
-							"name": common.CloudProviderSecretName,
-						},
-					},
-				},
-				{
-					"name": "AWS_SECRET_ACCESS_KEY",
-					"valueFrom": map[string]interface{}{
-						"secretKeyRef": map[string]interface{}{
-							"key":  aws.SecretAccessKey,
-							"name": common.CloudProviderSecretName,
-						},
-					},
-				},
-			},
-			"resources": map[string]interface{}{
-				"limits": map[string]interface{}{
-					"cpu":    "500m",
-					"memory": "512Mi",
-				},
-			},
+			"environment":     credentialsEnvironment(),
 		}, nil
 	},
 	Objects: []*chart.Object{
@@ -151,6 +164,76 @@ var ccmChart = &chart.Chart{
 	},
 }
 
+var csiChart = &chart.Chart{
+	Name: "csi-driver-controller",
+	Path: filepath.Join(aws.InternalChartsPath, "csi-driver-controller"),
+	Images: []string{
+		aws.CSIDriverImageName,
+		aws.CSIProvisionerImageName,
+		aws.CSIAttacherImageName,
+		aws.CSIResizerImageName,
+		aws.CSISnapshotterImageName,
+		aws.CSILivenessProbeImageName,
+	},
+	ValuesFunc: func(clusterName string, shoot *gardenv1beta1.Shoot, checksums map[string]string) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"replicas": extensionscontroller.GetReplicas(shoot, 1),
+			"podAnnotations": map[string]interface{}{
+				"checksum/secret-" + csiProvisionerName:                              checksums[csiProvisionerName],
+				"checksum/secret-" + csiAttacherName:                                 checksums[csiAttacherName],
+				"checksum/secret-" + csiResizerName:                                  checksums[csiResizerName],
+				"checksum/secret-" + csiSnapshotterName:                              checksums[csiSnapshotterName],
+				"checksum/secret-" + aws.CloudControllerManagerCredentialsSecretName: checksums[aws.CloudControllerManagerCredentialsSecretName],
+			},
+			"environment": credentialsEnvironment(),
+		}, nil
+	},
+	Objects: []*chart.Object{
+		{Type: &appsv1.Deployment{}, Name: csiDriverControllerDeploymentName},
+	},
+}
+
+// csiShootChart grants the CSI sidecars the permissions they need in the shoot cluster.
+var csiShootChart = &chart.Chart{
+	Name: "csi-driver-controller-shoot",
+	Path: filepath.Join(aws.InternalChartsPath, "csi-driver-controller-shoot"),
+	ValuesFunc: func(clusterName string, shoot *gardenv1beta1.Shoot, checksums map[string]string) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"users": map[string]interface{}{
+				"provisioner": csiUserName(csiProvisionerName),
+				"attacher":    csiUserName(csiAttacherName),
+				"resizer":     csiUserName(csiResizerName),
+				"snapshotter": csiUserName(csiSnapshotterName),
+			},
+		}, nil
+	},
+}
+
+// credentialsEnvironment returns the environment variables that pass the access key of the IAM user created for the
+// cloud-controller-manager by the infrastructure actuator. The user is shared with the EBS CSI driver controller.
+func credentialsEnvironment() []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"name": "AWS_ACCESS_KEY_ID",
+			"valueFrom": map[string]interface{}{
+				"secretKeyRef": map[string]interface{}{
+					"key":  aws.AccessKeyID,
+					"name": aws.CloudControllerManagerCredentialsSecretName,
+				},
+			},
+		},
+		{
+			"name": "AWS_SECRET_ACCESS_KEY",
+			"valueFrom": map[string]interface{}{
+				"secretKeyRef": map[string]interface{}{
+					"key":  aws.SecretAccessKey,
+					"name": aws.CloudControllerManagerCredentialsSecretName,
+				},
+			},
+		},
+	}
+}
+
 // NewActuator creates a new Actuator that acts upon and updates the status of ControlPlane resources.
 func NewActuator() controlplane.Actuator {
 	return &actuator{
@@ -216,11 +299,16 @@ func (a *actuator) Reconcile(
 	cp *extensionsv1alpha1.ControlPlane,
 	cluster *extensionscontroller.Cluster,
 ) error {
+	logger := extensionscontroller.LoggerFromContext(ctx, a.logger)
+
 	// Decode providerConfig
 	cpConfig := &apisaws.ControlPlaneConfig{}
 	if _, _, err := a.decoder.Decode(cp.Spec.ProviderConfig.Raw, nil, cpConfig); err != nil {
 		return errors.Wrapf(err, "could not decode providerConfig of controlplane '%s'", objectName(cp))
 	}
+	if err := validateControlPlaneConfig(cpConfig); err != nil {
+		return errors.Wrapf(err, "invalid providerConfig of controlplane '%s'", objectName(cp))
+	}
 
 	// Decode infrastructureProviderStatus
 	infraStatus := &apisaws.InfrastructureStatus{}
@@ -229,7 +317,7 @@ func (a *actuator) Reconcile(
 	}
 
 	// Deploy secrets
-	a.logger.Info("Deploying secrets", "controlplane", objectName(cp))
+	logger.Info("Deploying secrets", "controlplane", objectName(cp))
 	deployedSecrets, err := controlPlaneSecrets.Deploy(a.clientset, a.gardenerClientset, cp.Namespace)
 	if err != nil {
 		return errors.Wrapf(err, "could not deploy secrets for controlplane '%s'", objectName(cp))
@@ -243,11 +331,11 @@ func (a *actuator) Reconcile(
 
 	// Collect additional configuration chart values
 	values := map[string]interface{}{
-		"cloudProviderConfig": getCloudProviderConfig(infraStatus.VPC.ID, subnetID, zone, cp.Namespace),
+		"cloudProviderConfig": newCloudProviderConfig(cpConfig, infraStatus.VPC.ID, subnetID, zone, cp.Namespace).String(),
 	}
 
 	// Apply config chart
-	a.logger.Info("Applying configuration chart", "controlplane", objectName(cp), "chart", configChart.Name, "values", values)
+	logger.Info("Applying configuration chart", "controlplane", objectName(cp), "chart", configChart.Name, "values", values)
 	if err := configChart.Apply(ctx, a.gardenerClientset, a.chartApplier, cp.Namespace, cluster.Shoot, nil, nil, values); err != nil {
 		return errors.Wrapf(err, "could not apply configuration chart for controlplane '%s'", objectName(cp))
 	}
@@ -259,17 +347,33 @@ func (a *actuator) Reconcile(
 	}
 
 	// Collect additional CCM chart values
-	values = make(map[string]interface{})
-	if cpConfig.CloudControllerManager != nil {
-		values["featureGates"] = cpConfig.CloudControllerManager.FeatureGates
-	}
+	values = getCCMChartValues(cpConfig)
 
 	// Apply CCM chart
-	a.logger.Info("Applying CCM chart", "controlplane", objectName(cp), "chart", ccmChart.Name, "values", values)
+	logger.Info("Applying CCM chart", "controlplane", objectName(cp), "chart", ccmChart.Name, "values", values)
 	if err := ccmChart.Apply(ctx, a.gardenerClientset, a.chartApplier, cp.Namespace, cluster.Shoot, imagevector.ImageVector(), checksums, values); err != nil {
 		return errors.Wrapf(err, "could not apply CCM chart for controlplane '%s'", objectName(cp))
 	}
 
+	// Apply CSI chart
+	useCSI, err := utils.CheckVersionMeetsConstraint(cluster.Shoot.Spec.Kubernetes.Version, ">= "+csiMinimumKubernetesVersion)
+	if err != nil {
+		return errors.Wrapf(err, "could not check Kubernetes version of controlplane '%s'", objectName(cp))
+	}
+	if useCSI {
+		if err := a.applyCSIShootChart(ctx, cp, cluster); err != nil {
+			return err
+		}
+
+		values = map[string]interface{}{
+			"region": cp.Spec.Region,
+		}
+		logger.Info("Applying CSI chart", "controlplane", objectName(cp), "chart", csiChart.Name, "values", values)
+		if err := csiChart.Apply(ctx, a.gardenerClientset, a.chartApplier, cp.Namespace, cluster.Shoot, imagevector.ImageVector(), checksums, values); err != nil {
+			return errors.Wrapf(err, "could not apply CSI chart for controlplane '%s'", objectName(cp))
+		}
+	}
+
 	return nil
 }
 
@@ -280,20 +384,28 @@ func (a *actuator) Delete(
 	cp *extensionsv1alpha1.ControlPlane,
 	cluster *extensionscontroller.Cluster,
 ) error {
+	logger := extensionscontroller.LoggerFromContext(ctx, a.logger)
+
+	// Delete CSI objects
+	logger.Info("Deleting CSI objects", "controlplane", objectName(cp))
+	if err := csiChart.Delete(ctx, a.client, cp.Namespace); err != nil {
+		return errors.Wrapf(err, "could not delete CSI objects for controlplane '%s'", objectName(cp))
+	}
+
 	// Delete CCM objects
-	a.logger.Info("Deleting CCM objects", "controlplane", objectName(cp))
+	logger.Info("Deleting CCM objects", "controlplane", objectName(cp))
 	if err := ccmChart.Delete(ctx, a.client, cp.Namespace); err != nil {
 		return errors.Wrapf(err, "could not delete CCM objects for controlplane '%s'", objectName(cp))
 	}
 
 	// Delete config objects
-	a.logger.Info("Deleting configuration objects", "controlplane", objectName(cp))
+	logger.Info("Deleting configuration objects", "controlplane", objectName(cp))
 	if err := configChart.Delete(ctx, a.client, cp.Namespace); err != nil {
 		return errors.Wrapf(err, "could not delete configuration objects for controlplane '%s'", objectName(cp))
 	}
 
 	// Delete secrets
-	a.logger.Info("Deleting secrets", "controlplane", objectName(cp))
+	logger.Info("Deleting secrets", "controlplane", objectName(cp))
 	if err := controlPlaneSecrets.Delete(a.clientset, cp.Namespace); err != nil {
 		return errors.Wrapf(err, "could not delete secrets for controlplane '%s'", objectName(cp))
 	}
@@ -301,18 +413,50 @@ func (a *actuator) Delete(
 	return nil
 }
 
+// applyCSIShootChart applies the csiShootChart to the shoot cluster of the given controlplane. It is skipped if the
+// kube-apiserver of the shoot is not accessible yet, i.e. while the kubeconfig secret of Gardener does not exist,
+// and applied with the next reconciliation. The objects are not deleted explicitly, they are removed together with
+// the shoot cluster.
+func (a *actuator) applyCSIShootChart(ctx context.Context, cp *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster) error {
+	logger := extensionscontroller.LoggerFromContext(ctx, a.logger)
+
+	kubeconfigSecret := &corev1.Secret{}
+	if err := a.client.Get(ctx, client.ObjectKey{Namespace: cp.Namespace, Name: gardencorev1alpha1.SecretNameGardener}, kubeconfigSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Skipping CSI shoot chart because the shoot is not accessible yet", "controlplane", objectName(cp))
+			return nil
+		}
+		return errors.Wrapf(err, "could not get secret '%s'", objectName(kubeconfigSecret))
+	}
+
+	shootConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfigSecret.Data["kubeconfig"])
+	if err != nil {
+		return errors.Wrapf(err, "could not create REST config for the shoot of controlplane '%s'", objectName(cp))
+	}
+	shootChartApplier, err := gardenerkubernetes.NewChartApplierForConfig(shootConfig)
+	if err != nil {
+		return errors.Wrapf(err, "could not create chart applier for the shoot of controlplane '%s'", objectName(cp))
+	}
+
+	logger.Info("Applying CSI shoot chart", "controlplane", objectName(cp), "chart", csiShootChart.Name)
+	if err := csiShootChart.Apply(ctx, a.gardenerClientset, shootChartApplier, metav1.NamespaceSystem, cluster.Shoot, nil, nil, nil); err != nil {
+		return errors.Wrapf(err, "could not apply CSI shoot chart for controlplane '%s'", objectName(cp))
+	}
+	return nil
+}
+
 // computeChecksums computes and returns all needed checksums. This includes the checksums for the given deployed secrets,
-// as well as the cloud provider secret and configmap that are fetched from the cluster.
+// as well as the credentials secret of the CCM and the cloud provider configmap that are fetched from the cluster.
 func (a *actuator) computeChecksums(
 	ctx context.Context,
 	deployedSecrets map[string]*corev1.Secret,
 	namespace string,
 ) (map[string]string, error) {
-	// Get cloud provider secret and configmap from cluster
-	cpSecret := &corev1.Secret{}
-	err := a.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: common.CloudProviderSecretName}, cpSecret)
+	// Get the CCM credentials secret created by the infrastructure actuator and the cloud provider configmap from cluster
+	ccmSecret := &corev1.Secret{}
+	err := a.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: aws.CloudControllerManagerCredentialsSecretName}, ccmSecret)
 	if err != nil {
-		return nil, errors.Wrapf(err, "could not get secret '%s'", objectName(cpSecret))
+		return nil, errors.Wrapf(err, "could not get secret '%s'", objectName(ccmSecret))
 	}
 	cpConfigMap := &corev1.ConfigMap{}
 	err = a.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: common.CloudProviderConfigName}, cpConfigMap)
@@ -322,7 +466,7 @@ func (a *actuator) computeChecksums(
 
 	// Compute checksums
 	csSecrets := controlplane.MergeSecretMaps(deployedSecrets, map[string]*corev1.Secret{
-		common.CloudProviderSecretName: cpSecret,
+		aws.CloudControllerManagerCredentialsSecretName: ccmSecret,
 	})
 	csConfigMaps := map[string]*corev1.ConfigMap{
 		common.CloudProviderConfigName: cpConfigMap,
@@ -341,18 +485,29 @@ func getSubnetIDAndZone(infraStatus *apisaws.InfrastructureStatus) (string, stri
 	return "", "", errors.Errorf("subnet with purpose 'public' not found")
 }
 
-// getCloudProviderConfig builds and returns a AWS config from the given parameters.
-func getCloudProviderConfig(vpcID, subnetID, zone, clusterID string) string {
-	return fmt.Sprintf(
-		`[Global]
-VPC=%q
-SubnetID=%q
-DisableSecurityGroupIngress=true
-KubernetesClusterTag=%q
-KubernetesClusterID=%q
-Zone=%q
-`,
-		vpcID, subnetID, clusterID, clusterID, zone)
+// getCCMChartValues returns the CCM chart values that are configured in the given control plane config, falling back
+// to the default verbosity and resources.
+func getCCMChartValues(cpConfig *apisaws.ControlPlaneConfig) map[string]interface{} {
+	var (
+		values = map[string]interface{}{
+			"verbosity": defaultCCMVerbosity,
+			"resources": defaultCCMResources,
+		}
+		ccm = cpConfig.CloudControllerManager
+	)
+
+	if ccm == nil {
+		return values
+	}
+
+	values["featureGates"] = ccm.FeatureGates
+	if ccm.Verbosity != nil {
+		values["verbosity"] = *ccm.Verbosity
+	}
+	if ccm.Resources != nil {
+		values["resources"] = *ccm.Resources
+	}
+	return values
 }
 
 func objectName(obj runtime.Object) string {
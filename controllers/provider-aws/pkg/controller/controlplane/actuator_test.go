@@ -0,0 +1,117 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"path/filepath"
+
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	gardenv1beta1 "github.com/gardener/gardener/pkg/apis/garden/v1beta1"
+	"github.com/gardener/gardener/pkg/chartrenderer"
+	"github.com/gardener/gardener/pkg/utils/secrets"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/version"
+)
+
+var _ = Describe("Actuator", func() {
+	Describe("#controlPlaneSecrets", func() {
+		It("should contain the kubeconfig secrets of the CSI sidecars", func() {
+			cas := map[string]*secrets.Certificate{gardencorev1alpha1.SecretNameCACluster: {}}
+
+			var kubeconfigs []string
+			for _, config := range controlPlaneSecrets.SecretConfigsFunc(cas, "shoot--foo--bar") {
+				if c, ok := config.(*secrets.ControlPlaneSecretConfig); ok && c.KubeConfigRequest != nil {
+					kubeconfigs = append(kubeconfigs, c.Name)
+				}
+			}
+
+			Expect(kubeconfigs).To(ConsistOf(
+				cloudControllerManagerDeploymentName,
+				csiProvisionerName,
+				csiAttacherName,
+				csiResizerName,
+				csiSnapshotterName,
+			))
+		})
+
+		It("should not add the CSI sidecars to the privileged group", func() {
+			cas := map[string]*secrets.Certificate{gardencorev1alpha1.SecretNameCACluster: {}}
+
+			for _, config := range controlPlaneSecrets.SecretConfigsFunc(cas, "shoot--foo--bar") {
+				if c, ok := config.(*secrets.ControlPlaneSecretConfig); ok && c.Name != cloudControllerManagerDeploymentName {
+					Expect(c.Organization).NotTo(ContainElement(user.SystemPrivilegedGroup), c.Name)
+				}
+			}
+		})
+	})
+
+	Describe("#csiShootChart", func() {
+		It("should bind the roles of the CSI sidecars to their users", func() {
+			values, err := csiShootChart.ValuesFunc("shoot--foo--bar", &gardenv1beta1.Shoot{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{TillerVersion: version.GetVersionProto()})
+			chart, err := renderer.Render(filepath.Join("..", "..", "..", "charts", "internal", "csi-driver-controller-shoot"), csiShootChart.Name, "kube-system", values)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, name := range []string{csiProvisionerName, csiAttacherName, csiResizerName, csiSnapshotterName} {
+				Expect(chart.FileContent(name + ".yaml")).To(ContainSubstring("kind: ClusterRoleBinding"))
+				Expect(chart.FileContent(name+".yaml")).To(ContainSubstring("name: "+csiUserName(name)), name)
+			}
+		})
+	})
+
+	Describe("#csiChart", func() {
+		It("should annotate the pods with the checksums of the secrets and pass the credentials", func() {
+			shoot := &gardenv1beta1.Shoot{}
+			checksums := map[string]string{
+				csiProvisionerName: "1",
+				csiAttacherName:    "2",
+				csiResizerName:     "3",
+				csiSnapshotterName: "4",
+				aws.CloudControllerManagerCredentialsSecretName: "5",
+			}
+
+			values, err := csiChart.ValuesFunc("shoot--foo--bar", shoot, checksums)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(HaveKeyWithValue("podAnnotations", map[string]interface{}{
+				"checksum/secret-csi-provisioner":                      "1",
+				"checksum/secret-csi-attacher":                         "2",
+				"checksum/secret-csi-resizer":                          "3",
+				"checksum/secret-csi-snapshotter":                      "4",
+				"checksum/secret-cloud-controller-manager-credentials": "5",
+			}))
+			Expect(values).To(HaveKeyWithValue("environment", credentialsEnvironment()))
+		})
+
+		It("should tag the volumes like the ones the volume cleanup of the infrastructure deletes", func() {
+			values, err := csiChart.ValuesFunc("shoot--foo--bar", &gardenv1beta1.Shoot{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{TillerVersion: version.GetVersionProto()})
+			chart, err := renderer.Render(filepath.Join("..", "..", "..", "charts", "internal", "csi-driver-controller"), csiChart.Name, "shoot--foo--bar", values)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(chart.FileContent("csi-driver-controller.yaml")).To(ContainSubstring("--extra-volume-tags=kubernetes.io/cluster/shoot--foo--bar=owned"))
+		})
+	})
+})
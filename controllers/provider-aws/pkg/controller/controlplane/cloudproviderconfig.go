@@ -0,0 +1,146 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	apisaws "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// defaultCCMVerbosity is the log level of the cloud-controller-manager if none is configured.
+	defaultCCMVerbosity = 2
+)
+
+// defaultCCMResources are the compute resources of the cloud-controller-manager if none are configured.
+var defaultCCMResources = corev1.ResourceRequirements{
+	Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("100m"),
+		corev1.ResourceMemory: resource.MustParse("64Mi"),
+	},
+	Limits: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("512Mi"),
+	},
+}
+
+// cloudProviderConfig is the `[Global]` section of the configuration file of the AWS cloud provider.
+type cloudProviderConfig struct {
+	VPC                         string
+	SubnetID                    string
+	DisableSecurityGroupIngress bool
+	KubernetesClusterTag        string
+	KubernetesClusterID         string
+	ElbSecurityGroup            string
+	Zone                        string
+}
+
+// newCloudProviderConfig returns the cloud provider configuration for the given cluster, VPC, subnet and zone,
+// taking the overrides of the given control plane config into account.
+func newCloudProviderConfig(cpConfig *apisaws.ControlPlaneConfig, vpcID, subnetID, zone, clusterID string) *cloudProviderConfig {
+	config := &cloudProviderConfig{
+		VPC:                         vpcID,
+		SubnetID:                    subnetID,
+		DisableSecurityGroupIngress: true,
+		KubernetesClusterTag:        clusterID,
+		KubernetesClusterID:         clusterID,
+		Zone:                        zone,
+	}
+
+	if cloudProvider := cpConfig.CloudProvider; cloudProvider != nil {
+		if cloudProvider.ElbSecurityGroup != nil {
+			config.ElbSecurityGroup = *cloudProvider.ElbSecurityGroup
+		}
+	}
+
+	return config
+}
+
+// gcfgEscaper escapes the characters that are not allowed verbatim in a quoted gcfg value.
+var gcfgEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+
+// String renders the configuration in the INI format read by the AWS cloud provider. Empty optional values are omitted.
+func (c *cloudProviderConfig) String() string {
+	var b strings.Builder
+	b.WriteString("[Global]\n")
+
+	writeString := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s=\"%s\"\n", key, gcfgEscaper.Replace(value))
+		}
+	}
+
+	writeString("VPC", c.VPC)
+	writeString("SubnetID", c.SubnetID)
+	fmt.Fprintf(&b, "DisableSecurityGroupIngress=%s\n", strconv.FormatBool(c.DisableSecurityGroupIngress))
+	writeString("KubernetesClusterTag", c.KubernetesClusterTag)
+	writeString("KubernetesClusterID", c.KubernetesClusterID)
+	writeString("ElbSecurityGroup", c.ElbSecurityGroup)
+	writeString("Zone", c.Zone)
+
+	return b.String()
+}
+
+// validateControlPlaneConfig checks that the given control plane config only contains values that can be passed to
+// the cloud-controller-manager.
+func validateControlPlaneConfig(cpConfig *apisaws.ControlPlaneConfig) error {
+	if ccm := cpConfig.CloudControllerManager; ccm != nil {
+		if ccm.Verbosity != nil && *ccm.Verbosity < 0 {
+			return fmt.Errorf("invalid cloud-controller-manager verbosity %d, it must not be negative", *ccm.Verbosity)
+		}
+		if ccm.Resources != nil {
+			if err := validateResources(ccm.Resources); err != nil {
+				return fmt.Errorf("invalid cloud-controller-manager resources: %v", err)
+			}
+		}
+	}
+
+	if cloudProvider := cpConfig.CloudProvider; cloudProvider != nil {
+		if id := cloudProvider.ElbSecurityGroup; id != nil {
+			if !strings.HasPrefix(*id, "sg-") || containsControlCharacter(*id) {
+				return fmt.Errorf("invalid ELB security group id %q", *id)
+			}
+		}
+		if cloudProvider.RoleARN != nil {
+			return fmt.Errorf("a role ARN is not supported, the IAM user of the cloud-controller-manager is not allowed to assume roles")
+		}
+		if cloudProvider.KubernetesClusterTag != nil {
+			return fmt.Errorf("overriding the KubernetesCluster tag is not supported, the infrastructure and the machines are tagged with the name of the cluster")
+		}
+	}
+
+	return nil
+}
+
+// validateResources checks that no resource request exceeds its limit.
+func validateResources(resources *corev1.ResourceRequirements) error {
+	for name, request := range resources.Requests {
+		if limit, ok := resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return fmt.Errorf("request %s of %s exceeds limit %s", request.String(), name, limit.String())
+		}
+	}
+	return nil
+}
+
+func containsControlCharacter(s string) bool {
+	return strings.IndexFunc(s, unicode.IsControl) >= 0
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	apisaws "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+
+	gardenv1beta1 "github.com/gardener/gardener/pkg/apis/garden/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("Cloud provider config", func() {
+	var (
+		stringPtr = func(s string) *string { return &s }
+		int32Ptr  = func(i int32) *int32 { return &i }
+	)
+
+	Describe("#String", func() {
+		It("should render the default config", func() {
+			config := newCloudProviderConfig(&apisaws.ControlPlaneConfig{}, "vpc-1234", "subnet-1234", "eu-west-1a", "shoot--foo--bar")
+
+			Expect(config.String()).To(Equal(`[Global]
+VPC="vpc-1234"
+SubnetID="subnet-1234"
+DisableSecurityGroupIngress=true
+KubernetesClusterTag="shoot--foo--bar"
+KubernetesClusterID="shoot--foo--bar"
+Zone="eu-west-1a"
+`))
+		})
+
+		It("should render the configured overrides", func() {
+			config := newCloudProviderConfig(&apisaws.ControlPlaneConfig{
+				CloudProvider: &apisaws.CloudProviderConfig{
+					ElbSecurityGroup: stringPtr("sg-1234"),
+				},
+			}, "vpc-1234", "subnet-1234", "eu-west-1a", "shoot--foo--bar")
+
+			Expect(config.String()).To(Equal(`[Global]
+VPC="vpc-1234"
+SubnetID="subnet-1234"
+DisableSecurityGroupIngress=true
+KubernetesClusterTag="shoot--foo--bar"
+KubernetesClusterID="shoot--foo--bar"
+ElbSecurityGroup="sg-1234"
+Zone="eu-west-1a"
+`))
+		})
+
+		It("should escape quotes, backslashes and line breaks", func() {
+			config := &cloudProviderConfig{KubernetesClusterTag: "a\"b\\c\nZone=\"evil\""}
+
+			Expect(config.String()).To(Equal(`[Global]
+DisableSecurityGroupIngress=false
+KubernetesClusterTag="a\"b\\c\nZone=\"evil\""
+`))
+		})
+	})
+
+	Describe("#validateControlPlaneConfig", func() {
+		It("should allow an empty config", func() {
+			Expect(validateControlPlaneConfig(&apisaws.ControlPlaneConfig{})).To(Succeed())
+		})
+
+		It("should allow a complete config", func() {
+			Expect(validateControlPlaneConfig(&apisaws.ControlPlaneConfig{
+				CloudControllerManager: &apisaws.CloudControllerManagerConfig{
+					Verbosity: int32Ptr(4),
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+						Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					},
+				},
+				CloudProvider: &apisaws.CloudProviderConfig{
+					ElbSecurityGroup: stringPtr("sg-1234"),
+				},
+			})).To(Succeed())
+		})
+
+		DescribeTable("should reject invalid configs",
+			func(cpConfig *apisaws.ControlPlaneConfig, message string) {
+				Expect(validateControlPlaneConfig(cpConfig)).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("negative verbosity", &apisaws.ControlPlaneConfig{
+				CloudControllerManager: &apisaws.CloudControllerManagerConfig{Verbosity: int32Ptr(-1)},
+			}, "verbosity"),
+			Entry("request exceeding its limit", &apisaws.ControlPlaneConfig{
+				CloudControllerManager: &apisaws.CloudControllerManagerConfig{
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+					},
+				},
+			}, "exceeds limit"),
+			Entry("invalid security group id", &apisaws.ControlPlaneConfig{
+				CloudProvider: &apisaws.CloudProviderConfig{ElbSecurityGroup: stringPtr("elb-1234")},
+			}, "invalid ELB security group id"),
+			Entry("role ARN", &apisaws.ControlPlaneConfig{
+				CloudProvider: &apisaws.CloudProviderConfig{RoleARN: stringPtr("arn:aws:iam::123456789012:role/ccm")},
+			}, "role ARN is not supported"),
+			Entry("cluster tag", &apisaws.ControlPlaneConfig{
+				CloudProvider: &apisaws.CloudProviderConfig{KubernetesClusterTag: stringPtr("shoot--foo--bar")},
+			}, "KubernetesCluster tag is not supported"),
+		)
+	})
+
+	Describe("#getCCMChartValues", func() {
+		It("should return the defaults if nothing is configured", func() {
+			Expect(getCCMChartValues(&apisaws.ControlPlaneConfig{})).To(Equal(map[string]interface{}{
+				"verbosity": defaultCCMVerbosity,
+				"resources": defaultCCMResources,
+			}))
+		})
+
+		It("should return the configured values", func() {
+			resources := corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			}
+
+			Expect(getCCMChartValues(&apisaws.ControlPlaneConfig{
+				CloudControllerManager: &apisaws.CloudControllerManagerConfig{
+					KubernetesConfig: gardenv1beta1.KubernetesConfig{FeatureGates: map[string]bool{"Foo": true}},
+					Verbosity:        int32Ptr(4),
+					Resources:        &resources,
+				},
+			})).To(Equal(map[string]interface{}{
+				"featureGates": map[string]bool{"Foo": true},
+				"verbosity":    int32(4),
+				"resources":    resources,
+			}))
+		})
+	})
+})
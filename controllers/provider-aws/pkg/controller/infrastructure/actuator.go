@@ -16,15 +16,16 @@ package infrastructure
 
 import (
 	"context"
+	"fmt"
 	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/imagevector"
 
-	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
+	awsapi "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+	awsclient "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	"github.com/gardener/gardener-extensions/pkg/controller/infrastructure"
+	"github.com/gardener/gardener-extensions/pkg/terraformer"
 
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
-	glogger "github.com/gardener/gardener/pkg/logger"
-	"github.com/gardener/gardener/pkg/operation/terraformer"
 
 	"github.com/go-logr/logr"
 
@@ -32,15 +33,27 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
-type actuator struct {
-	logger logr.Logger
+// ActuatorName is the name of the AWS infrastructure actuator.
+const ActuatorName = "aws-infrastructure-actuator"
 
-	restConfig *rest.Config
+type actuator struct {
+	logger   logr.Logger
+	recorder record.EventRecorder
+
+	restConfig         *rest.Config
+	terraformerFactory terraformer.Factory
+	newAWSClient       func(credentials awsclient.Credentials, region string, endpoints awsclient.Endpoints) (awsclient.Interface, error)
+	endpoints          awsclient.Endpoints
+	webIdentity        awsclient.WebIdentity
+	volumeCleanup      awsapi.VolumeCleanup
+	defaultTags        map[string]string
+	vpcQuota           int
 
 	client  client.Client
 	scheme  *runtime.Scheme
@@ -48,9 +61,22 @@ type actuator struct {
 }
 
 // NewActuator creates a new Actuator that updates the status of the handled Infrastructure resources.
-func NewActuator() infrastructure.Actuator {
+// The given <endpoints> are used unless they are overridden in the cloud provider secret, the <webIdentity> is
+// used for cloud provider secrets without an access key, the given <volumeCleanup> is used unless it is
+// overridden in the InfrastructureConfig. The <defaultTags> are added to all
+// AWS resources, tags of the InfrastructureConfig take precedence. The <vpcQuota> is the maximum number of VPCs
+// per region checked before a VPC is created, 0 disables the check. Events are recorded with <recorder>.
+func NewActuator(recorder record.EventRecorder, endpoints awsclient.Endpoints, webIdentity awsclient.WebIdentity, volumeCleanup awsapi.VolumeCleanup, defaultTags map[string]string, vpcQuota int) infrastructure.Actuator {
 	return &actuator{
-		logger: log.Log.WithName("infrastructure-actuator"),
+		logger:             log.Log.WithName("infrastructure-actuator"),
+		recorder:           recorder,
+		terraformerFactory: terraformer.DefaultFactory(),
+		newAWSClient:       awsclient.NewClientWithCredentials,
+		endpoints:          endpoints,
+		webIdentity:        webIdentity,
+		volumeCleanup:      volumeCleanup,
+		defaultTags:        defaultTags,
+		vpcQuota:           vpcQuota,
 	}
 }
 
@@ -80,13 +106,58 @@ func (a *actuator) Delete(ctx context.Context, config *extensionsv1alpha1.Infras
 
 // Helper functions
 
-func (a *actuator) newTerraformer(purpose, namespace, name string) (*terraformer.Terraformer, error) {
-	return terraformer.NewForConfig(glogger.NewLogger("info"), a.restConfig, purpose, namespace, name, imagevector.TerraformerImage())
+func (a *actuator) newTerraformer(logger logr.Logger, purpose, namespace, name string) (terraformer.Interface, error) {
+	return a.terraformerFactory.NewForConfig(extensionscontroller.NewLogrusLogger(logger), a.restConfig, purpose, namespace, name, imagevector.TerraformerImage())
 }
 
-func generateTerraformInfraVariablesEnvironment(secret *corev1.Secret) map[string]string {
-	return terraformer.GenerateVariablesEnvironment(secret, map[string]string{
-		"ACCESS_KEY_ID":     aws.AccessKeyID,
-		"SECRET_ACCESS_KEY": aws.SecretAccessKey,
-	})
+// endpointsFromSecret returns the configured endpoints, overridden by the ones in the given cloud provider secret.
+func (a *actuator) endpointsFromSecret(secret *corev1.Secret) awsclient.Endpoints {
+	return a.endpoints.Merge(awsclient.EndpointsFromSecretData(secret.Data))
+}
+
+// credentialsFromSecret returns the credentials of the given cloud provider secret, completed with the web identity
+// of the extension.
+func (a *actuator) credentialsFromSecret(secret *corev1.Secret) (awsclient.Credentials, error) {
+	credentials, err := awsclient.CredentialsFromSecretData(secret.Data)
+	if err != nil {
+		return awsclient.Credentials{}, err
+	}
+	credentials.WebIdentity = a.webIdentity
+	return credentials, nil
+}
+
+func (a *actuator) newAWSClientFromSecret(secret *corev1.Secret, region string) (awsclient.Interface, error) {
+	credentials, err := a.credentialsFromSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	return a.newAWSClient(credentials, region, a.endpointsFromSecret(secret))
+}
+
+// regionalClientFunc returns a function that creates AWS clients for other regions with the given cloud provider secret.
+func (a *actuator) regionalClientFunc(secret *corev1.Secret) regionalClientFunc {
+	return func(region string) (awsclient.Interface, error) {
+		return a.newAWSClientFromSecret(secret, region)
+	}
+}
+
+// generateTerraformInfraVariablesEnvironment returns the Terraform variables containing the credentials the AWS
+// provider authenticates with before it assumes the role of the cloud provider secret, if any. Without an access key
+// in the secret, these are the temporary credentials of the web identity of the extension.
+func (a *actuator) generateTerraformInfraVariablesEnvironment(secret *corev1.Secret, region string) (map[string]string, error) {
+	credentials, err := a.credentialsFromSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := credentials.BaseValue(region, a.endpointsFromSecret(secret))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve the AWS credentials for Terraform: %+v", err)
+	}
+
+	return map[string]string{
+		"TF_VAR_ACCESS_KEY_ID":     value.AccessKeyID,
+		"TF_VAR_SECRET_ACCESS_KEY": value.SecretAccessKey,
+		"TF_VAR_SESSION_TOKEN":     value.SessionToken,
+	}, nil
 }
@@ -17,17 +17,19 @@ package infrastructure
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	awsapi "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
 	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
 	awsclient "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	controllererrors "github.com/gardener/gardener-extensions/pkg/controller/error"
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
+	"github.com/gardener/gardener-extensions/pkg/terraformer"
 
 	gardencorev1alpha1helper "github.com/gardener/gardener/pkg/apis/core/v1alpha1/helper"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
-	glogger "github.com/gardener/gardener/pkg/logger"
-	"github.com/gardener/gardener/pkg/operation/terraformer"
 	"github.com/gardener/gardener/pkg/utils/flow"
 	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
 
@@ -35,8 +37,35 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
+// EventVolumeCleanup is an event reason to describe the cleanup of EBS volumes and snapshots.
+const EventVolumeCleanup = "VolumeCleanup"
+
 func (a *actuator) delete(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) error {
-	tf, err := a.newTerraformer(aws.TerrformerPurposeInfra, infrastructure.Namespace, infrastructure.Name)
+	logger := extensionscontroller.LoggerFromContext(ctx, a.logger)
+
+	infrastructureConfig, err := a.infrastructureConfigOf(infrastructure)
+	if err != nil {
+		return err
+	}
+	reconciler, err := a.deletingReconcilerOf(infrastructure, infrastructureConfig)
+	if err != nil {
+		return err
+	}
+	if reconciler == awsapi.InfrastructureReconcilerNative {
+		return a.deleteNative(ctx, infrastructure)
+	}
+
+	providerSecret := &corev1.Secret{}
+	if err := a.client.Get(ctx, kutil.Key(infrastructure.Spec.SecretRef.Namespace, infrastructure.Spec.SecretRef.Name), providerSecret); err != nil {
+		return err
+	}
+
+	awsClient, err := a.newAWSClientFromSecret(providerSecret, infrastructure.Spec.Region)
+	if err != nil {
+		return err
+	}
+
+	tf, err := a.newTerraformer(logger, aws.TerrformerPurposeInfra, infrastructure.Namespace, infrastructure.Name)
 	if err != nil {
 		return fmt.Errorf("could not create the Terraformer: %+v", err)
 	}
@@ -49,19 +78,73 @@ func (a *actuator) delete(ctx context.Context, infrastructure *extensionsv1alpha
 	stateVariables, err := tf.GetStateOutputVariables(aws.VPCIDKey)
 	if err != nil {
 		if apierrors.IsNotFound(err) || terraformer.IsVariablesNotFoundError(err) {
-			a.logger.Info("Skipping explicit AWS load balancer and security group deletion because not all variables have been found in the Terraform state.")
-			return nil
+			logger.Info("Skipping explicit AWS load balancer and security group deletion because not all variables have been found in the Terraform state.")
+			return a.destroy(ctx, infrastructure, awsClient, "", false, flow.EmptyTaskFn)
 		}
 		return err
 	}
 	vpcID := stateVariables[aws.VPCIDKey]
 
+	destroyTerraform := func(ctx context.Context) error {
+		variablesEnvironment, err := a.generateTerraformInfraVariablesEnvironment(providerSecret, infrastructure.Spec.Region)
+		if err != nil {
+			return err
+		}
+		return tf.SetVariablesEnvironment(variablesEnvironment).Destroy()
+	}
+
+	return a.destroy(ctx, infrastructure, awsClient, vpcID, configExists, tracing.TaskFn("Terraformer destroy", destroyTerraform))
+}
+
+// deleteNative deletes the infrastructure resources that were managed by the native reconciler. The resources are
+// found by their tags and the provider status, the InfrastructureConfig is not validated again so that a config that
+// has become stale in the meantime does not block the deletion.
+func (a *actuator) deleteNative(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure) error {
+	status, err := a.infrastructureStatusOf(infrastructure)
+	if err != nil {
+		return err
+	}
+
 	providerSecret := &corev1.Secret{}
 	if err := a.client.Get(ctx, kutil.Key(infrastructure.Spec.SecretRef.Namespace, infrastructure.Spec.SecretRef.Name), providerSecret); err != nil {
 		return err
 	}
 
-	awsClient, err := awsclient.NewClient(string(providerSecret.Data[aws.AccessKeyID]), string(providerSecret.Data[aws.SecretAccessKey]), infrastructure.Spec.Region)
+	awsClient, err := a.newAWSClientFromSecret(providerSecret, infrastructure.Spec.Region)
+	if err != nil {
+		return err
+	}
+
+	values := &infrastructureValues{clusterName: infrastructure.Namespace, createVPC: true}
+	r := newNativeReconciler(extensionscontroller.LoggerFromContext(ctx, a.logger), awsClient, a.regionalClientFunc(providerSecret), values)
+	vpcID, err := r.findVPC(ctx)
+	if err != nil {
+		return err
+	}
+
+	var endpointIDs []string
+	if vpcID == "" && status != nil {
+		// No VPC named after the cluster exists, hence the infrastructure either used an existing VPC or its VPC is
+		// already gone. Only the resources created in the VPC of the status are deleted then.
+		values.createVPC = false
+		vpcID = status.VPC.ID
+		for _, endpoint := range status.VPC.Endpoints {
+			endpointIDs = append(endpointIDs, endpoint.ID)
+		}
+	}
+
+	return a.destroy(ctx, infrastructure, awsClient, vpcID, vpcID != "", tracing.TaskFn("Native delete", func(ctx context.Context) error {
+		return r.delete(ctx, vpcID, endpointIDs)
+	}))
+}
+
+// destroy deletes the resources created by Kubernetes in the VPC <vpcID> if <cleanupKubernetesResources> is true,
+// cleans up the EBS volumes and snapshots once the load balancers and network interfaces are gone, and finally destroys
+// the infrastructure with <destroyInfrastructure>.
+func (a *actuator) destroy(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, awsClient awsclient.Interface, vpcID string, cleanupKubernetesResources bool, destroyInfrastructure flow.TaskFn) error {
+	logger := extensionscontroller.LoggerFromContext(ctx, a.logger)
+
+	volumeCleanup, err := a.volumeCleanupFor(infrastructure)
 	if err != nil {
 		return err
 	}
@@ -69,26 +152,88 @@ func (a *actuator) delete(ctx context.Context, infrastructure *extensionsv1alpha
 	var (
 		g = flow.NewGraph("AWS infrastructure destruction")
 
-		destroyKubernetesLoadBalancersAndSecurityGroups = g.Add(flow.Task{
-			Name: "Destroying Kubernetes load balancers and security groups",
-			Fn: flow.TaskFn(func(ctx context.Context) error {
-				if err := a.destroyKubernetesLoadBalancersAndSecurityGroups(ctx, awsClient, vpcID, infrastructure.Namespace); err != nil {
-					return gardencorev1alpha1helper.DetermineError(fmt.Sprintf("Failed to destroy load balancers and security groups: %+v", err.Error()))
+		destroyKubernetesLoadBalancers = g.Add(flow.Task{
+			Name: "Destroying Kubernetes load balancers",
+			Fn: tracing.TaskFn("Destroying Kubernetes load balancers", func(ctx context.Context) error {
+				if err := a.destroyKubernetesLoadBalancers(ctx, awsClient, vpcID, infrastructure.Namespace); err != nil {
+					return gardencorev1alpha1helper.DetermineError(fmt.Sprintf("Failed to destroy load balancers: %+v", err.Error()))
+				}
+				return nil
+			}).RetryUntilTimeout(10*time.Second, 5*time.Minute).DoIf(cleanupKubernetesResources),
+		})
+
+		destroyKubernetesNetworkLoadBalancers = g.Add(flow.Task{
+			Name: "Destroying Kubernetes network load balancers",
+			Fn: tracing.TaskFn("Destroying Kubernetes network load balancers", func(ctx context.Context) error {
+				if err := a.destroyKubernetesNetworkLoadBalancers(ctx, awsClient, vpcID, infrastructure.Namespace); err != nil {
+					return gardencorev1alpha1helper.DetermineError(fmt.Sprintf("Failed to destroy network load balancers: %+v", err.Error()))
+				}
+				return nil
+			}).RetryUntilTimeout(10*time.Second, 5*time.Minute).DoIf(cleanupKubernetesResources),
+		})
+
+		destroyKubernetesTargetGroups = g.Add(flow.Task{
+			Name: "Destroying Kubernetes target groups",
+			Fn: tracing.TaskFn("Destroying Kubernetes target groups", func(ctx context.Context) error {
+				if err := a.destroyKubernetesTargetGroups(ctx, awsClient, vpcID, infrastructure.Namespace); err != nil {
+					return gardencorev1alpha1helper.DetermineError(fmt.Sprintf("Failed to destroy target groups: %+v", err.Error()))
+				}
+				return nil
+			}).RetryUntilTimeout(10*time.Second, 5*time.Minute).DoIf(cleanupKubernetesResources),
+			Dependencies: flow.NewTaskIDs(destroyKubernetesNetworkLoadBalancers),
+		})
+
+		destroyKubernetesNetworkInterfaces = g.Add(flow.Task{
+			Name: "Destroying Kubernetes network interfaces",
+			Fn: tracing.TaskFn("Destroying Kubernetes network interfaces", func(ctx context.Context) error {
+				if err := a.destroyKubernetesNetworkInterfaces(ctx, awsClient, vpcID, infrastructure.Namespace); err != nil {
+					return gardencorev1alpha1helper.DetermineError(fmt.Sprintf("Failed to destroy network interfaces: %+v", err.Error()))
 				}
 				return nil
-			}).RetryUntilTimeout(10*time.Second, 5*time.Minute).DoIf(configExists),
+			}).RetryUntilTimeout(10*time.Second, 5*time.Minute).DoIf(cleanupKubernetesResources),
+			Dependencies: flow.NewTaskIDs(destroyKubernetesLoadBalancers, destroyKubernetesNetworkLoadBalancers),
+		})
+
+		destroyKubernetesSecurityGroups = g.Add(flow.Task{
+			Name: "Destroying Kubernetes security groups",
+			Fn: tracing.TaskFn("Destroying Kubernetes security groups", func(ctx context.Context) error {
+				if err := a.destroyKubernetesSecurityGroups(ctx, awsClient, vpcID, infrastructure.Namespace); err != nil {
+					return gardencorev1alpha1helper.DetermineError(fmt.Sprintf("Failed to destroy security groups: %+v", err.Error()))
+				}
+				return nil
+			}).RetryUntilTimeout(10*time.Second, 5*time.Minute).DoIf(cleanupKubernetesResources),
+			Dependencies: flow.NewTaskIDs(destroyKubernetesNetworkInterfaces),
+		})
+
+		_ = g.Add(flow.Task{
+			Name: "Cleaning up Kubernetes EBS volumes and snapshots",
+			Fn: tracing.TaskFn("Cleaning up Kubernetes EBS volumes and snapshots", func(ctx context.Context) error {
+				if err := a.cleanupKubernetesVolumesAndSnapshots(ctx, awsClient, infrastructure, volumeCleanup); err != nil {
+					return gardencorev1alpha1helper.DetermineError(fmt.Sprintf("Failed to clean up EBS volumes and snapshots: %+v", err.Error()))
+				}
+				return nil
+			}).RetryUntilTimeout(10*time.Second, 5*time.Minute).DoIf(volumeCleanup.DeleteVolumes || volumeCleanup.DeleteSnapshots),
+			Dependencies: flow.NewTaskIDs(destroyKubernetesLoadBalancers, destroyKubernetesNetworkLoadBalancers, destroyKubernetesNetworkInterfaces),
+		})
+
+		_ = g.Add(flow.Task{
+			Name: "Deleting the credentials of the cloud-controller-manager",
+			Fn: tracing.TaskFn("Deleting the credentials of the cloud-controller-manager", func(ctx context.Context) error {
+				return a.deleteCloudControllerManagerCredentials(ctx, awsClient, infrastructure.Namespace)
+			}).RetryUntilTimeout(10*time.Second, 5*time.Minute),
+			Dependencies: flow.NewTaskIDs(destroyKubernetesTargetGroups, destroyKubernetesSecurityGroups),
 		})
 
 		_ = g.Add(flow.Task{
 			Name:         "Destroying Shoot infrastructure",
-			Fn:           flow.SimpleTaskFn(tf.SetVariablesEnvironment(generateTerraformInfraVariablesEnvironment(providerSecret)).Destroy),
-			Dependencies: flow.NewTaskIDs(destroyKubernetesLoadBalancersAndSecurityGroups),
+			Fn:           destroyInfrastructure,
+			Dependencies: flow.NewTaskIDs(destroyKubernetesTargetGroups, destroyKubernetesSecurityGroups),
 		})
 
 		f = g.Compile()
 	)
 
-	if err := f.Run(flow.Opts{Context: ctx, Logger: glogger.NewFieldLogger(glogger.NewLogger("info"), "infrastructure", infrastructure.Name)}); err != nil {
+	if err := f.Run(flow.Opts{Context: ctx, Logger: extensionscontroller.NewLogrusLogger(logger)}); err != nil {
 		return &controllererrors.RequeueAfterError{
 			Cause:        flow.Causes(err),
 			RequeueAfter: 30 * time.Second,
@@ -98,26 +243,181 @@ func (a *actuator) delete(ctx context.Context, infrastructure *extensionsv1alpha
 	return nil
 }
 
-func (a *actuator) destroyKubernetesLoadBalancersAndSecurityGroups(ctx context.Context, awsClient awsclient.Interface, vpcID, clusterName string) error {
+func (a *actuator) destroyKubernetesLoadBalancers(ctx context.Context, awsClient awsclient.Interface, vpcID, clusterName string) error {
 	loadBalancers, err := awsClient.ListKubernetesELBs(ctx, vpcID, clusterName)
 	if err != nil {
 		return err
 	}
-	securityGroups, err := awsClient.ListKubernetesSecurityGroups(ctx, vpcID, clusterName)
+
+	for _, loadBalancerName := range loadBalancers {
+		if err := awsClient.DeleteELB(ctx, loadBalancerName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *actuator) destroyKubernetesNetworkLoadBalancers(ctx context.Context, awsClient awsclient.Interface, vpcID, clusterName string) error {
+	loadBalancers, err := awsClient.ListKubernetesNLBs(ctx, vpcID, clusterName)
 	if err != nil {
 		return err
 	}
 
-	for _, loadBalancerName := range loadBalancers {
-		if err := awsClient.DeleteELB(ctx, loadBalancerName); err != nil {
+	for _, loadBalancerARN := range loadBalancers {
+		if err := awsClient.DeleteNLB(ctx, loadBalancerARN); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *actuator) destroyKubernetesTargetGroups(ctx context.Context, awsClient awsclient.Interface, vpcID, clusterName string) error {
+	targetGroups, err := awsClient.ListKubernetesTargetGroups(ctx, vpcID, clusterName)
+	if err != nil {
+		return err
+	}
+
+	for _, targetGroupARN := range targetGroups {
+		if err := awsClient.DeleteTargetGroup(ctx, targetGroupARN); err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+func (a *actuator) destroyKubernetesNetworkInterfaces(ctx context.Context, awsClient awsclient.Interface, vpcID, clusterName string) error {
+	networkInterfaces, err := awsClient.ListKubernetesENIs(ctx, vpcID, clusterName)
+	if err != nil {
+		return err
+	}
+
+	for _, networkInterfaceID := range networkInterfaces {
+		if err := awsClient.DeleteENI(ctx, networkInterfaceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *actuator) destroyKubernetesSecurityGroups(ctx context.Context, awsClient awsclient.Interface, vpcID, clusterName string) error {
+	securityGroups, err := awsClient.ListKubernetesSecurityGroups(ctx, vpcID, clusterName)
+	if err != nil {
+		return err
+	}
+
 	for _, securityGroupID := range securityGroups {
 		if err := awsClient.DeleteSecurityGroup(ctx, securityGroupID); err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+// infrastructureConfigOf decodes the InfrastructureConfig of the given infrastructure. It returns nil if the
+// infrastructure has no provider config.
+func (a *actuator) infrastructureConfigOf(infrastructure *extensionsv1alpha1.Infrastructure) (*awsapi.InfrastructureConfig, error) {
+	if infrastructure.Spec.ProviderConfig == nil {
+		return nil, nil
+	}
+
+	infrastructureConfig := &awsapi.InfrastructureConfig{}
+	if _, _, err := a.decoder.Decode(infrastructure.Spec.ProviderConfig.Raw, nil, infrastructureConfig); err != nil {
+		return nil, fmt.Errorf("could not decode provider config: %+v", err)
+	}
+	return infrastructureConfig, nil
+}
+
+// infrastructureStatusOf returns the InfrastructureStatus of the given infrastructure, or nil if it has none yet.
+func (a *actuator) infrastructureStatusOf(infrastructure *extensionsv1alpha1.Infrastructure) (*awsapi.InfrastructureStatus, error) {
+	if infrastructure.Status.ProviderStatus == nil || len(infrastructure.Status.ProviderStatus.Raw) == 0 {
+		return nil, nil
+	}
 
+	infrastructureStatus := &awsapi.InfrastructureStatus{}
+	if _, _, err := a.decoder.Decode(infrastructure.Status.ProviderStatus.Raw, nil, infrastructureStatus); err != nil {
+		return nil, fmt.Errorf("could not decode provider status: %+v", err)
+	}
+	return infrastructureStatus, nil
+}
+
+// deletingReconcilerOf returns the reconciler that deletes the given infrastructure. It is the one recorded in the
+// provider status if the infrastructure exists, regardless of the InfrastructureConfig.
+func (a *actuator) deletingReconcilerOf(infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *awsapi.InfrastructureConfig) (awsapi.InfrastructureReconciler, error) {
+	status, err := a.infrastructureStatusOf(infrastructure)
+	if err != nil {
+		return "", err
+	}
+	if status != nil {
+		return recordedReconcilerOf(status), nil
+	}
+	if infrastructureConfig == nil {
+		return awsapi.InfrastructureReconcilerTerraform, nil
+	}
+	return reconcilerOf(infrastructureConfig)
+}
+
+// volumeCleanupFor returns the volume cleanup configured in the InfrastructureConfig of the given infrastructure,
+// or the default of the actuator if it does not configure one.
+func (a *actuator) volumeCleanupFor(infrastructure *extensionsv1alpha1.Infrastructure) (awsapi.VolumeCleanup, error) {
+	infrastructureConfig, err := a.infrastructureConfigOf(infrastructure)
+	if err != nil {
+		return awsapi.VolumeCleanup{}, err
+	}
+	if infrastructureConfig == nil || infrastructureConfig.VolumeCleanup == nil {
+		return a.volumeCleanup, nil
+	}
+	return *infrastructureConfig.VolumeCleanup, nil
+}
+
+func (a *actuator) cleanupKubernetesVolumesAndSnapshots(ctx context.Context, awsClient awsclient.Interface, infrastructure *extensionsv1alpha1.Infrastructure, volumeCleanup awsapi.VolumeCleanup) error {
+	clusterName := infrastructure.Namespace
+
+	if volumeCleanup.DeleteVolumes {
+		volumes, err := awsClient.ListKubernetesVolumes(ctx, clusterName)
+		if err != nil {
+			return err
+		}
+		if err := a.cleanup(infrastructure, "EBS volumes", volumes, volumeCleanup.DryRun, func(id string) error {
+			return awsClient.DeleteVolume(ctx, id)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if volumeCleanup.DeleteSnapshots {
+		snapshots, err := awsClient.ListKubernetesSnapshots(ctx, clusterName)
+		if err != nil {
+			return err
+		}
+		if err := a.cleanup(infrastructure, "EBS snapshots", snapshots, volumeCleanup.DryRun, func(id string) error {
+			return awsClient.DeleteSnapshot(ctx, id)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cleanup deletes the resources with the given <ids> of the given <kind> and reports them via an event. In
+// dry-run mode, the resources are only reported.
+func (a *actuator) cleanup(infrastructure *extensionsv1alpha1.Infrastructure, kind string, ids []string, dryRun bool, deleteFn func(id string) error) error {
+	if dryRun {
+		if len(ids) == 0 {
+			a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, EventVolumeCleanup, "Dry run: no %s would be deleted", kind)
+			return nil
+		}
+		a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, EventVolumeCleanup, "Dry run: %d %s would be deleted: %s", len(ids), kind, strings.Join(ids, ", "))
+		return nil
+	}
+
+	for _, id := range ids {
+		if err := deleteFn(id); err != nil {
+			return err
+		}
+	}
+	if len(ids) > 0 {
+		a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, EventVolumeCleanup, "Deleted %d %s: %s", len(ids), kind, strings.Join(ids, ", "))
+	}
 	return nil
 }
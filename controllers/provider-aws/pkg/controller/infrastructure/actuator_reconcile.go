@@ -18,7 +18,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
-	"strconv"
+	"regexp"
 	"strings"
 	"time"
 
@@ -28,15 +28,17 @@ import (
 	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	controllererrors "github.com/gardener/gardener-extensions/pkg/controller/error"
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
+	"github.com/gardener/gardener-extensions/pkg/terraformer"
 
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/gardener/gardener/pkg/chartrenderer"
-	"github.com/gardener/gardener/pkg/operation/terraformer"
 	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 func (a *actuator) reconcile(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) error {
@@ -45,216 +47,904 @@ func (a *actuator) reconcile(ctx context.Context, infrastructure *extensionsv1al
 		return fmt.Errorf("could not decode provider config: %+v", err)
 	}
 
+	reconciler, err := a.reconcilerFor(infrastructure, infrastructureConfig)
+	if err != nil {
+		return err
+	}
+
 	providerSecret := &corev1.Secret{}
 	if err := a.client.Get(ctx, kutil.Key(infrastructure.Spec.SecretRef.Namespace, infrastructure.Spec.SecretRef.Name), providerSecret); err != nil {
 		return err
 	}
 
-	terraformConfig, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, providerSecret)
+	awsClient, err := a.newAWSClientFromSecret(providerSecret, infrastructure.Spec.Region)
+	if err != nil {
+		return err
+	}
+
+	if err := a.preflightCheck(ctx, infrastructure, infrastructureConfig, awsClient); err != nil {
+		return err
+	}
+
+	var output terraformer.Outputs
+	switch reconciler {
+	case awsapi.InfrastructureReconcilerNative:
+		output, err = a.reconcileNative(ctx, infrastructure, infrastructureConfig, awsClient, a.regionalClientFunc(providerSecret))
+	default:
+		output, err = a.reconcileTerraform(ctx, infrastructure, infrastructureConfig, providerSecret, awsClient)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := a.reconcileCloudControllerManagerCredentials(ctx, awsClient, infrastructure.Namespace); err != nil {
+		return fmt.Errorf("failed to reconcile the credentials of the cloud-controller-manager: %+v", err)
+	}
+
+	if err := a.updateProviderStatus(ctx, infrastructure, infrastructureConfig, output); err != nil {
+		return fmt.Errorf("failed to update the provider status in the Infrastructure resource: %+v", err)
+	}
+
+	return nil
+}
+
+// reconcileTerraform applies the Terraform configuration of the infrastructure and returns its outputs.
+func (a *actuator) reconcileTerraform(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *awsapi.InfrastructureConfig, providerSecret *corev1.Secret, awsClient client.Interface) (terraformer.Outputs, error) {
+	credentials, err := a.credentialsFromSecret(providerSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	terraformConfig, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, awsClient, a.endpointsFromSecret(providerSecret), credentials, a.defaultTags)
 	if err != nil {
-		return fmt.Errorf("failed to generate Terraform config: %+v", err)
+		return nil, fmt.Errorf("failed to generate Terraform config: %+v", err)
 	}
 
 	chartRenderer, err := chartrenderer.NewForConfig(a.restConfig)
 	if err != nil {
-		return fmt.Errorf("could not create chart renderer: %+v", err)
+		return nil, fmt.Errorf("could not create chart renderer: %+v", err)
 	}
 
-	release, err := chartRenderer.Render(filepath.Join(aws.InternalChartsPath, "aws-infra"), "aws-infra", infrastructure.Namespace, terraformConfig)
+	terraformFiles, err := terraformer.RenderChart(chartRenderer, filepath.Join(aws.InternalChartsPath, "aws-infra"), "aws-infra", infrastructure.Namespace, terraformConfig)
 	if err != nil {
-		return fmt.Errorf("could not render Terraform chart: %+v", err)
+		return nil, fmt.Errorf("could not render Terraform chart: %+v", err)
 	}
 
-	tf, err := a.newTerraformer(aws.TerrformerPurposeInfra, infrastructure.Namespace, infrastructure.Name)
+	tf, err := a.newTerraformer(extensionscontroller.LoggerFromContext(ctx, a.logger), aws.TerrformerPurposeInfra, infrastructure.Namespace, infrastructure.Name)
 	if err != nil {
-		return fmt.Errorf("could not create terraformer object: %+v", err)
+		return nil, fmt.Errorf("could not create terraformer object: %+v", err)
+	}
+
+	variablesEnvironment, err := a.generateTerraformInfraVariablesEnvironment(providerSecret, infrastructure.Spec.Region)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := tf.
-		SetVariablesEnvironment(generateTerraformInfraVariablesEnvironment(providerSecret)).
-		InitializeWith(terraformer.DefaultInitializer(
-			a.client,
-			release.FileContent("main.tf"),
-			release.FileContent("variables.tf"),
-			[]byte(release.FileContent("terraform.tfvars"))),
-		).
-		Apply(); err != nil {
+	if err := tracing.Trace(ctx, "Terraformer apply", func(ctx context.Context) error {
+		return tf.
+			SetVariablesEnvironment(variablesEnvironment).
+			InitializeWith(terraformFiles.Initializer(a.client)).
+			Apply()
+	}, "terraformer.purpose", aws.TerrformerPurposeInfra); err != nil {
 
-		return &controllererrors.RequeueAfterError{
+		return nil, &controllererrors.RequeueAfterError{
 			Cause:        err,
 			RequeueAfter: 30 * time.Second,
 		}
 	}
 
-	if err := a.updateProviderStatus(ctx, tf, infrastructure, infrastructureConfig); err != nil {
-		return fmt.Errorf("failed to update the provider status in the Infrastructure resource: %+v", err)
+	outputVarKeys, err := providerStatusOutputKeys(infrastructureConfig)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	var output terraformer.Outputs
+	if err := tracing.Trace(ctx, "Terraformer state output variables", func(context.Context) error {
+		var err error
+		output, err = terraformer.GetOutputs(tf, outputVarKeys...)
+		return err
+	}, "terraformer.purpose", aws.TerrformerPurposeInfra); err != nil {
+		return nil, err
+	}
+
+	return output, nil
 }
 
-func generateTerraformInfraConfig(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *awsapi.InfrastructureConfig, providerSecret *corev1.Secret) (map[string]interface{}, error) {
-	var (
-		dhcpDomainName    = "ec2.internal"
-		createVPC         = true
-		vpcID             = "${aws_vpc.vpc.id}"
-		vpcCIDR           = ""
-		internetGatewayID = "${aws_internet_gateway.igw.id}"
-	)
+// reconcileNative reconciles the infrastructure directly with the AWS API and returns the same outputs as the
+// Terraform configuration.
+func (a *actuator) reconcileNative(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *awsapi.InfrastructureConfig, awsClient client.Interface, newRegionalClient regionalClientFunc) (terraformer.Outputs, error) {
+	values, err := computeInfrastructureValues(ctx, infrastructure, infrastructureConfig, awsClient, a.defaultTags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute the infrastructure values: %+v", err)
+	}
+
+	var output terraformer.Outputs
+	if err := tracing.Trace(ctx, "Native reconcile", func(ctx context.Context) error {
+		var err error
+		output, err = newNativeReconciler(extensionscontroller.LoggerFromContext(ctx, a.logger), awsClient, newRegionalClient, values).reconcile(ctx)
+		return err
+	}); err != nil {
+		return nil, &controllererrors.RequeueAfterError{
+			Cause:        err,
+			RequeueAfter: 30 * time.Second,
+		}
+	}
+
+	return output, nil
+}
+
+// infrastructureValues are the values the infrastructure of a shoot is reconciled with, regardless of whether it is
+// reconciled by Terraform or natively.
+type infrastructureValues struct {
+	region         string
+	clusterName    string
+	sshPublicKey   string
+	dhcpDomainName string
+	tags           map[string]string
+
+	createVPC     bool
+	createSubnets bool
+
+	// vpcID and internetGatewayID are empty if the VPC is created.
+	vpcID             string
+	vpcCIDR           string
+	internetGatewayID string
+
+	natGatewayMode awsapi.NATGatewayMode
+	// natGatewayZoneIndices are the indices of the zones that contain a NAT gateway.
+	natGatewayZoneIndices []int
+	vpcEndpoints          []vpcEndpoint
+	vpcPeerings           []vpcPeering
+	zones                 []zoneValues
+	ingressRules          []awsapi.IngressRule
+}
+
+// zoneValues are the values of a zone. The subnet ids are only set if the subnet mode is `Existing`.
+type zoneValues struct {
+	name                  string
+	worker                string
+	public                string
+	internal              string
+	workerSubnetID        string
+	publicSubnetID        string
+	internalSubnetID      string
+	elasticIPAllocationID string
+}
+
+// serviceName returns the name of the service of the given <endpoint> in the region of the values.
+func (v *infrastructureValues) serviceName(endpoint vpcEndpoint) string {
+	return fmt.Sprintf("com.amazonaws.%s.%s", v.region, endpoint.service)
+}
+
+// elasticIPAllocationIDRegex matches the ids of Elastic IP allocations, e.g. `eipalloc-0123456789abcdef0`. The id is
+// quoted as `allocation_id` of the NAT gateway of the zone, where a quote or an interpolation would alter the
+// Terraform configuration.
+var elasticIPAllocationIDRegex = regexp.MustCompile(`^eipalloc-[0-9a-f]+$`)
+
+// computeInfrastructureValues validates the given InfrastructureConfig and computes the values of the infrastructure
+// from it. The AWS API is only called to look up an existing VPC and its subnets.
+func computeInfrastructureValues(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *awsapi.InfrastructureConfig, awsClient client.Interface, defaultTags map[string]string) (*infrastructureValues, error) {
+	values := &infrastructureValues{
+		region:         infrastructure.Spec.Region,
+		clusterName:    infrastructure.Namespace,
+		sshPublicKey:   string(infrastructure.Spec.SSHPublicKey),
+		dhcpDomainName: "ec2.internal",
+		tags:           MergeTags(defaultTags, infrastructureConfig.Tags),
+		createVPC:      true,
+	}
 
 	if infrastructure.Spec.Region != "us-east-1" {
-		dhcpDomainName = fmt.Sprintf("%s.compute.internal", infrastructure.Spec.Region)
+		values.dhcpDomainName = fmt.Sprintf("%s.compute.internal", infrastructure.Spec.Region)
+	}
+
+	if err := validateTags(values.tags); err != nil {
+		return nil, err
 	}
 
-	awsClient, err := client.NewClient(string(providerSecret.Data[aws.AccessKeyID]), string(providerSecret.Data[aws.SecretAccessKey]), infrastructure.Spec.Region)
+	if err := validateIngressRules(infrastructureConfig.Networks.IngressRules); err != nil {
+		return nil, err
+	}
+	values.ingressRules = infrastructureConfig.Networks.IngressRules
+
+	subnetMode, err := subnetModeOf(infrastructureConfig)
 	if err != nil {
 		return nil, err
 	}
+	values.createSubnets = subnetMode == awsapi.SubnetModeCreate
 
 	switch {
 	case infrastructureConfig.Networks.VPC.ID != nil:
-		createVPC = false
-		vpcID = *infrastructureConfig.Networks.VPC.ID
-		igwID, err := awsClient.GetInternetGateway(ctx, vpcID)
-		if err != nil {
-			return nil, err
+		values.createVPC = false
+		values.vpcID = *infrastructureConfig.Networks.VPC.ID
+		if values.createSubnets {
+			igwID, err := awsClient.GetInternetGateway(ctx, values.vpcID)
+			if err != nil {
+				return nil, err
+			}
+			values.internetGatewayID = igwID
 		}
-		internetGatewayID = igwID
 	case infrastructureConfig.Networks.VPC.CIDR != nil:
-		vpcCIDR = string(*infrastructureConfig.Networks.VPC.CIDR)
+		values.vpcCIDR = string(*infrastructureConfig.Networks.VPC.CIDR)
 	}
 
-	var zones []map[string]interface{}
-	for _, zone := range infrastructureConfig.Networks.Zones {
-		zones = append(zones, map[string]interface{}{
-			"name":     zone.Name,
-			"worker":   zone.Workers,
-			"public":   zone.Public,
-			"internal": zone.Internal,
+	values.natGatewayMode, err = natGatewayModeOf(infrastructureConfig)
+	if err != nil {
+		return nil, err
+	}
+	values.natGatewayZoneIndices = natGatewayZoneIndices(infrastructureConfig, values.natGatewayMode)
+
+	values.vpcEndpoints, err = vpcEndpointsOf(infrastructureConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateVPCPeerings(infrastructureConfig.Networks.Peerings, values.vpcCIDR); err != nil {
+		return nil, err
+	}
+	values.vpcPeerings, err = vpcPeeringsOf(ctx, awsClient, infrastructureConfig.Networks.Peerings, values.region)
+	if err != nil {
+		return nil, err
+	}
+
+	if !values.createSubnets {
+		if infrastructureConfig.Networks.NATGateway != nil {
+			return nil, fmt.Errorf("NAT gateways cannot be configured if the subnet mode is %q", awsapi.SubnetModeExisting)
+		}
+		if len(values.vpcPeerings) > 0 {
+			return nil, fmt.Errorf("VPC peerings cannot be configured if the subnet mode is %q", awsapi.SubnetModeExisting)
+		}
+		for _, endpoint := range values.vpcEndpoints {
+			if endpoint.endpointType == awsapi.VPCEndpointTypeGateway {
+				return nil, fmt.Errorf("VPC endpoints of type %q cannot be used if the subnet mode is %q", awsapi.VPCEndpointTypeGateway, awsapi.SubnetModeExisting)
+			}
+		}
+	}
+
+	elasticIPAllocationIDs := make(map[string]string)
+	for zoneIndex, zone := range infrastructureConfig.Networks.Zones {
+		if !values.createSubnets {
+			if zone.ElasticIPAllocationID != nil {
+				return nil, fmt.Errorf("zone %s: an Elastic IP allocation id cannot be set if the subnet mode is %q", zone.Name, awsapi.SubnetModeExisting)
+			}
+
+			subnets, err := existingZoneSubnets(ctx, awsClient, values.vpcID, zone)
+			if err != nil {
+				return nil, err
+			}
+
+			values.zones = append(values.zones, zoneValues{
+				name:             zone.Name,
+				worker:           subnets[awsapi.PurposeNodes].CIDR,
+				public:           subnets[awsapi.PurposePublic].CIDR,
+				internal:         subnets[awsapi.PurposeInternal].CIDR,
+				workerSubnetID:   subnets[awsapi.PurposeNodes].ID,
+				publicSubnetID:   subnets[awsapi.PurposePublic].ID,
+				internalSubnetID: subnets[awsapi.PurposeInternal].ID,
+			})
+			continue
+		}
+
+		if zone.Subnets != nil {
+			return nil, fmt.Errorf("zone %s: subnets can only be referenced if the subnet mode is %q", zone.Name, awsapi.SubnetModeExisting)
+		}
+
+		var elasticIPAllocationID string
+		if zone.ElasticIPAllocationID != nil {
+			elasticIPAllocationID = *zone.ElasticIPAllocationID
+			if !elasticIPAllocationIDRegex.MatchString(elasticIPAllocationID) {
+				return nil, fmt.Errorf("zone %s: invalid Elastic IP allocation id %q", zone.Name, elasticIPAllocationID)
+			}
+			if values.natGatewayMode == awsapi.NATGatewayModeSingle && zoneIndex > 0 {
+				return nil, fmt.Errorf("zone %s: an Elastic IP allocation id can only be set for the first zone if a single NAT gateway is used", zone.Name)
+			}
+			if otherZone, ok := elasticIPAllocationIDs[elasticIPAllocationID]; ok {
+				return nil, fmt.Errorf("zone %s: Elastic IP allocation id %s is already used by zone %s", zone.Name, elasticIPAllocationID, otherZone)
+			}
+			elasticIPAllocationIDs[elasticIPAllocationID] = zone.Name
+		}
+
+		values.zones = append(values.zones, zoneValues{
+			name:                  zone.Name,
+			worker:                string(zone.Workers),
+			public:                string(zone.Public),
+			internal:              string(zone.Internal),
+			elasticIPAllocationID: elasticIPAllocationID,
+		})
+	}
+
+	return values, nil
+}
+
+func generateTerraformInfraConfig(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *awsapi.InfrastructureConfig, awsClient client.Interface, endpoints client.Endpoints, credentials client.Credentials, defaultTags map[string]string) (map[string]interface{}, error) {
+	values, err := computeInfrastructureValues(ctx, infrastructure, infrastructureConfig, awsClient, defaultTags)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		vpcID             = values.vpcID
+		internetGatewayID = values.internetGatewayID
+	)
+	if values.createVPC {
+		vpcID = "${aws_vpc.vpc.id}"
+		internetGatewayID = "${aws_internet_gateway.igw.id}"
+	}
+
+	var endpointValues []map[string]interface{}
+	for _, endpoint := range values.vpcEndpoints {
+		endpointValues = append(endpointValues, map[string]interface{}{
+			"name":        endpoint.name,
+			"service":     endpoint.service,
+			"serviceName": values.serviceName(endpoint),
+			"type":        string(endpoint.endpointType),
+		})
+	}
+
+	var peeringValues []map[string]interface{}
+	for _, peering := range values.vpcPeerings {
+		peeringValues = append(peeringValues, map[string]interface{}{
+			"name":    peering.name,
+			"vpcID":   peering.vpcID,
+			"ownerID": peering.ownerID,
+			"region":  peering.region,
+			"cidrs":   peering.cidrs,
+			"accept":  peering.accept,
+		})
+	}
+
+	var ingressRules []map[string]interface{}
+	for _, rule := range values.ingressRules {
+		var cidrs []string
+		for _, cidr := range rule.CIDRs {
+			cidrs = append(cidrs, string(cidr))
+		}
+		ingressRules = append(ingressRules, map[string]interface{}{
+			"protocol":         rule.Protocol,
+			"fromPort":         rule.FromPort,
+			"toPort":           rule.ToPort,
+			"cidrs":            cidrs,
+			"securityGroupIDs": rule.SecurityGroupIDs,
 		})
 	}
 
+	var zones []map[string]interface{}
+	for _, zone := range values.zones {
+		zoneValues := map[string]interface{}{
+			"name":     zone.name,
+			"worker":   zone.worker,
+			"public":   zone.public,
+			"internal": zone.internal,
+		}
+		if values.createSubnets {
+			zoneValues["elasticIPAllocationID"] = zone.elasticIPAllocationID
+		} else {
+			zoneValues["workerSubnetID"] = zone.workerSubnetID
+			zoneValues["publicSubnetID"] = zone.publicSubnetID
+			zoneValues["internalSubnetID"] = zone.internalSubnetID
+		}
+		zones = append(zones, zoneValues)
+	}
+
 	return map[string]interface{}{
 		"aws": map[string]interface{}{
-			"region": infrastructure.Spec.Region,
+			"region":     values.region,
+			"endpoints":  endpoints.TerraformValues(),
+			"assumeRole": terraformAssumeRoleValues(credentials),
 		},
 		"create": map[string]interface{}{
-			"vpc": createVPC,
+			"vpc":     values.createVPC,
+			"subnets": values.createSubnets,
 		},
-		"sshPublicKey": string(infrastructure.Spec.SSHPublicKey),
+		"sshPublicKey": values.sshPublicKey,
 		"vpc": map[string]interface{}{
 			"id":                vpcID,
-			"cidr":              vpcCIDR,
-			"dhcpDomainName":    dhcpDomainName,
+			"cidr":              values.vpcCIDR,
+			"dhcpDomainName":    values.dhcpDomainName,
 			"internetGatewayID": internetGatewayID,
+			"endpoints":         endpointValues,
 		},
-		"clusterName": infrastructure.Namespace,
-		"zones":       zones,
+		"natGateway": map[string]interface{}{
+			"single": values.natGatewayMode == awsapi.NATGatewayModeSingle,
+		},
+		"clusterName": values.clusterName,
+		"iam": map[string]interface{}{
+			"assumeRolePolicy": assumeRolePolicyDocument,
+			"bastionsPolicy":   bastionsPolicyDocument,
+			"nodesPolicy":      nodesPolicyDocument,
+		},
+		"tags":         values.tags,
+		"zones":        zones,
+		"ingressRules": ingressRules,
+		"peerings":     peeringValues,
 		"outputKeys": map[string]interface{}{
-			"vpcIdKey":                   aws.VPCIDKey,
-			"subnetsPublicPrefix":        aws.SubnetPublicPrefix,
-			"subnetsNodesPrefix":         aws.SubnetNodesPrefix,
-			"securityGroupsNodes":        aws.SecurityGroupsNodes,
-			"sshKeyName":                 aws.SSHKeyName,
-			"iamInstanceProfileNodes":    aws.IAMInstanceProfileNodes,
-			"iamInstanceProfileBastions": aws.IAMInstanceProfileBastions,
-			"nodesRole":                  aws.NodesRole,
-			"bastionsRole":               aws.BastionsRole,
+			"vpcIdKey":                        aws.VPCIDKey,
+			"subnetsPublicPrefix":             aws.SubnetPublicPrefix,
+			"subnetsNodesPrefix":              aws.SubnetNodesPrefix,
+			"subnetsPrivatePrefix":            aws.SubnetPrivatePrefix,
+			"internetGatewayID":               aws.InternetGatewayID,
+			"routeTableMain":                  aws.RouteTableMain,
+			"routeTablesPrivatePrefix":        aws.RouteTablePrivatePrefix,
+			"securityGroupsNodes":             aws.SecurityGroupsNodes,
+			"securityGroupsBastions":          aws.SecurityGroupsBastions,
+			"natGatewayIDPrefix":              aws.NATGatewayIDPrefix,
+			"natGatewayPublicIPPrefix":        aws.NATGatewayPublicIPPrefix,
+			"natGatewayEIPAllocationIDPrefix": aws.NATGatewayEIPAllocationIDPrefix,
+			"vpcEndpointPrefix":               aws.VPCEndpointPrefix,
+			"vpcPeeringConnectionPrefix":      aws.VPCPeeringConnectionPrefix,
+			"sshKeyName":                      aws.SSHKeyName,
+			"iamInstanceProfileNodes":         aws.IAMInstanceProfileNodes,
+			"iamInstanceProfileBastions":      aws.IAMInstanceProfileBastions,
+			"nodesRole":                       aws.NodesRole,
+			"bastionsRole":                    aws.BastionsRole,
 		},
 	}, nil
 }
 
-func (a *actuator) updateProviderStatus(ctx context.Context, tf *terraformer.Terraformer, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *awsapi.InfrastructureConfig) error {
+// terraformAssumeRoleValues returns the values for the `assume_role` block of the Terraform AWS provider, or nil if
+// the credentials do not contain a role to assume.
+func terraformAssumeRoleValues(credentials client.Credentials) map[string]interface{} {
+	if credentials.RoleARN == "" {
+		return nil
+	}
+
+	values := map[string]interface{}{
+		"roleARN":     credentials.RoleARN,
+		"sessionName": client.RoleSessionName,
+	}
+	if credentials.ExternalID != "" {
+		values["externalID"] = credentials.ExternalID
+	}
+	return values
+}
+
+func (a *actuator) updateProviderStatus(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *awsapi.InfrastructureConfig, output terraformer.Outputs) error {
+	status, err := computeProviderStatus(infrastructureConfig, output)
+	if err != nil {
+		return err
+	}
+
+	infrastructure.Status.ProviderStatus = &runtime.RawExtension{Object: status}
+	return a.client.Status().Update(ctx, infrastructure)
+}
+
+// providerStatusOutputKeys returns the keys of the Terraform outputs the InfrastructureStatus is computed from.
+func providerStatusOutputKeys(infrastructureConfig *awsapi.InfrastructureConfig) ([]string, error) {
+	subnetMode, err := subnetModeOf(infrastructureConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	outputVarKeys := []string{
 		aws.VPCIDKey,
 		aws.SSHKeyName,
 		aws.IAMInstanceProfileNodes,
+		aws.IAMInstanceProfileBastions,
 		aws.NodesRole,
+		aws.BastionsRole,
 		aws.SecurityGroupsNodes,
+		aws.SecurityGroupsBastions,
 	}
 
 	for zoneIndex := range infrastructureConfig.Networks.Zones {
 		outputVarKeys = append(outputVarKeys, fmt.Sprintf("%s%d", aws.SubnetNodesPrefix, zoneIndex))
 		outputVarKeys = append(outputVarKeys, fmt.Sprintf("%s%d", aws.SubnetPublicPrefix, zoneIndex))
+		outputVarKeys = append(outputVarKeys, fmt.Sprintf("%s%d", aws.SubnetPrivatePrefix, zoneIndex))
+	}
+
+	// The internet gateway, the route tables and the NAT gateways are only managed if the subnets are created.
+	if subnetMode == awsapi.SubnetModeCreate {
+		outputVarKeys = append(outputVarKeys, aws.InternetGatewayID, aws.RouteTableMain)
+		for zoneIndex := range infrastructureConfig.Networks.Zones {
+			outputVarKeys = append(outputVarKeys, fmt.Sprintf("%s%d", aws.RouteTablePrivatePrefix, zoneIndex))
+		}
+
+		natGatewayMode, err := natGatewayModeOf(infrastructureConfig)
+		if err != nil {
+			return nil, err
+		}
+		for _, zoneIndex := range natGatewayZoneIndices(infrastructureConfig, natGatewayMode) {
+			outputVarKeys = append(outputVarKeys, fmt.Sprintf("%s%d", aws.NATGatewayIDPrefix, zoneIndex))
+			outputVarKeys = append(outputVarKeys, fmt.Sprintf("%s%d", aws.NATGatewayPublicIPPrefix, zoneIndex))
+			outputVarKeys = append(outputVarKeys, fmt.Sprintf("%s%d", aws.NATGatewayEIPAllocationIDPrefix, zoneIndex))
+		}
 	}
 
-	output, err := tf.GetStateOutputVariables(outputVarKeys...)
+	vpcEndpoints, err := vpcEndpointsOf(infrastructureConfig)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	for _, endpoint := range vpcEndpoints {
+		outputVarKeys = append(outputVarKeys, aws.VPCEndpointPrefix+endpoint.name)
+	}
+	for _, peering := range infrastructureConfig.Networks.Peerings {
+		outputVarKeys = append(outputVarKeys, aws.VPCPeeringConnectionPrefix+peering.Name)
+	}
+
+	return outputVarKeys, nil
+}
+
+func computeProviderStatus(infrastructureConfig *awsapi.InfrastructureConfig, output terraformer.Outputs) (*awsv1alpha1.InfrastructureStatus, error) {
+	reconciler, err := reconcilerOf(infrastructureConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	subnetMode, err := subnetModeOf(infrastructureConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	natGatewayMode, err := natGatewayModeOf(infrastructureConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	vpcEndpoints, err := vpcEndpointsOf(infrastructureConfig)
+	if err != nil {
+		return nil, err
 	}
 
 	subnets, err := computeProviderStatusSubnets(infrastructureConfig, output)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	infrastructure.Status.ProviderStatus = &runtime.RawExtension{
-		Object: &awsv1alpha1.InfrastructureStatus{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: awsv1alpha1.SchemeGroupVersion.String(),
-				Kind:       "InfrastructureStatus",
-			},
-			VPC: awsv1alpha1.VPCStatus{
-				ID:      output[aws.VPCIDKey],
-				Subnets: subnets,
-				SecurityGroups: []awsv1alpha1.SecurityGroup{
-					{
-						Purpose: awsapi.PurposeNodes,
-						ID:      output[aws.SecurityGroupsNodes],
-					},
+	var (
+		routeTables []awsv1alpha1.RouteTable
+		natGateways []awsv1alpha1.NATGatewayStatus
+	)
+	if subnetMode == awsapi.SubnetModeCreate {
+		routeTables, err = computeProviderStatusRouteTables(infrastructureConfig, output)
+		if err != nil {
+			return nil, err
+		}
+		natGateways = computeProviderStatusNATGateways(infrastructureConfig, natGatewayMode, output)
+	}
+
+	return &awsv1alpha1.InfrastructureStatus{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: awsv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "InfrastructureStatus",
+		},
+		VPC: awsv1alpha1.VPCStatus{
+			ID:                output[aws.VPCIDKey],
+			InternetGatewayID: output[aws.InternetGatewayID],
+			Subnets:           subnets,
+			RouteTables:       routeTables,
+			SecurityGroups: []awsv1alpha1.SecurityGroup{
+				{
+					Purpose: awsapi.PurposeNodes,
+					ID:      output[aws.SecurityGroupsNodes],
+				},
+				{
+					Purpose: awsapi.PurposeBastions,
+					ID:      output[aws.SecurityGroupsBastions],
 				},
 			},
-			EC2: awsv1alpha1.EC2{
-				KeyName: output[aws.SSHKeyName],
+			NATGateways: natGateways,
+			Endpoints:   computeProviderStatusVPCEndpoints(vpcEndpoints, output),
+			Peerings:    computeProviderStatusVPCPeerings(infrastructureConfig, output),
+		},
+		EC2: awsv1alpha1.EC2{
+			KeyName: output[aws.SSHKeyName],
+		},
+		IAM: awsv1alpha1.IAM{
+			InstanceProfiles: []awsv1alpha1.InstanceProfile{
+				{
+					Purpose: awsapi.PurposeNodes,
+					Name:    output[aws.IAMInstanceProfileNodes],
+				},
+				{
+					Purpose: awsapi.PurposeBastions,
+					Name:    output[aws.IAMInstanceProfileBastions],
+				},
 			},
-			IAM: awsv1alpha1.IAM{
-				InstanceProfiles: []awsv1alpha1.InstanceProfile{
-					{
-						Purpose: awsapi.PurposeNodes,
-						Name:    output[aws.IAMInstanceProfileNodes],
-					},
+			Roles: []awsv1alpha1.Role{
+				{
+					Purpose: awsapi.PurposeNodes,
+					ARN:     output[aws.NodesRole],
 				},
-				Roles: []awsv1alpha1.Role{
-					{
-						Purpose: awsapi.PurposeNodes,
-						ARN:     output[aws.NodesRole],
-					},
+				{
+					Purpose: awsapi.PurposeBastions,
+					ARN:     output[aws.BastionsRole],
 				},
 			},
 		},
-	}
-
-	return a.client.Status().Update(ctx, infrastructure)
+		Reconciler: awsv1alpha1.InfrastructureReconciler(reconciler),
+	}, nil
 }
 
-func computeProviderStatusSubnets(infrastructure *awsapi.InfrastructureConfig, values map[string]string) ([]awsv1alpha1.Subnet, error) {
+func computeProviderStatusSubnets(infrastructure *awsapi.InfrastructureConfig, output terraformer.Outputs) ([]awsv1alpha1.Subnet, error) {
 	var subnetsToReturn []awsv1alpha1.Subnet
 
-	for key, value := range values {
-		var prefix, purpose string
-		if strings.HasPrefix(key, aws.SubnetPublicPrefix) {
-			prefix = aws.SubnetPublicPrefix
-			purpose = awsapi.PurposePublic
+	for _, subnetType := range []struct {
+		prefix  string
+		purpose string
+	}{
+		{aws.SubnetNodesPrefix, awsv1alpha1.PurposeNodes},
+		{aws.SubnetPublicPrefix, awsapi.PurposePublic},
+		{aws.SubnetPrivatePrefix, awsapi.PurposeInternal},
+	} {
+		subnets, err := output.Indexed(subnetType.prefix)
+		if err != nil {
+			return nil, err
 		}
-		if strings.HasPrefix(key, aws.SubnetNodesPrefix) {
-			prefix = aws.SubnetNodesPrefix
-			purpose = awsv1alpha1.PurposeNodes
+
+		for zoneIndex, zone := range infrastructure.Networks.Zones {
+			subnetID, ok := subnets[zoneIndex]
+			if !ok {
+				continue
+			}
+			subnetsToReturn = append(subnetsToReturn, awsv1alpha1.Subnet{
+				ID:      subnetID,
+				Purpose: subnetType.purpose,
+				Zone:    zone.Name,
+			})
 		}
+	}
+
+	return subnetsToReturn, nil
+}
 
-		if len(prefix) == 0 {
+func computeProviderStatusRouteTables(infrastructureConfig *awsapi.InfrastructureConfig, output terraformer.Outputs) ([]awsv1alpha1.RouteTable, error) {
+	routeTables := []awsv1alpha1.RouteTable{
+		{
+			Purpose: awsapi.PurposePublic,
+			ID:      output[aws.RouteTableMain],
+		},
+	}
+
+	privateRouteTables, err := output.Indexed(aws.RouteTablePrivatePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for zoneIndex, zone := range infrastructureConfig.Networks.Zones {
+		routeTableID, ok := privateRouteTables[zoneIndex]
+		if !ok {
 			continue
 		}
+		routeTables = append(routeTables, awsv1alpha1.RouteTable{
+			Purpose: awsapi.PurposePrivate,
+			ID:      routeTableID,
+			Zone:    zone.Name,
+		})
+	}
 
-		zoneID, err := strconv.Atoi(strings.TrimPrefix(key, prefix))
-		if err != nil {
-			return nil, err
-		}
-		subnetsToReturn = append(subnetsToReturn, awsv1alpha1.Subnet{
-			ID:      value,
-			Purpose: purpose,
-			Zone:    infrastructure.Networks.Zones[zoneID].Name,
+	return routeTables, nil
+}
+
+func computeProviderStatusNATGateways(infrastructureConfig *awsapi.InfrastructureConfig, mode awsapi.NATGatewayMode, output terraformer.Outputs) []awsv1alpha1.NATGatewayStatus {
+	var natGateways []awsv1alpha1.NATGatewayStatus
+
+	for _, zoneIndex := range natGatewayZoneIndices(infrastructureConfig, mode) {
+		natGateways = append(natGateways, awsv1alpha1.NATGatewayStatus{
+			ID:                    output[fmt.Sprintf("%s%d", aws.NATGatewayIDPrefix, zoneIndex)],
+			Zone:                  infrastructureConfig.Networks.Zones[zoneIndex].Name,
+			PublicIP:              output[fmt.Sprintf("%s%d", aws.NATGatewayPublicIPPrefix, zoneIndex)],
+			ElasticIPAllocationID: output[fmt.Sprintf("%s%d", aws.NATGatewayEIPAllocationIDPrefix, zoneIndex)],
 		})
 	}
 
-	return subnetsToReturn, nil
+	return natGateways
+}
+
+func computeProviderStatusVPCEndpoints(vpcEndpoints []vpcEndpoint, output terraformer.Outputs) []awsv1alpha1.VPCEndpointStatus {
+	var endpoints []awsv1alpha1.VPCEndpointStatus
+
+	for _, endpoint := range vpcEndpoints {
+		endpoints = append(endpoints, awsv1alpha1.VPCEndpointStatus{
+			Service: endpoint.service,
+			Type:    awsv1alpha1.VPCEndpointType(endpoint.endpointType),
+			ID:      output[aws.VPCEndpointPrefix+endpoint.name],
+		})
+	}
+
+	return endpoints
+}
+
+func computeProviderStatusVPCPeerings(infrastructureConfig *awsapi.InfrastructureConfig, output terraformer.Outputs) []awsv1alpha1.VPCPeeringStatus {
+	var peerings []awsv1alpha1.VPCPeeringStatus
+
+	for _, peering := range infrastructureConfig.Networks.Peerings {
+		peerings = append(peerings, awsv1alpha1.VPCPeeringStatus{
+			Name:  peering.Name,
+			VPCID: peering.VPCID,
+			ID:    output[aws.VPCPeeringConnectionPrefix+peering.Name],
+		})
+	}
+
+	return peerings
+}
+
+// subnetModeOf returns the subnet mode of the given infrastructure configuration. It defaults to `Create` if no mode
+// is configured.
+func subnetModeOf(infrastructureConfig *awsapi.InfrastructureConfig) (awsapi.SubnetMode, error) {
+	vpc := infrastructureConfig.Networks.VPC
+	switch vpc.SubnetMode {
+	case "", awsapi.SubnetModeCreate:
+		return awsapi.SubnetModeCreate, nil
+	case awsapi.SubnetModeExisting:
+		if vpc.ID == nil {
+			return "", fmt.Errorf("subnet mode %q requires an existing VPC", awsapi.SubnetModeExisting)
+		}
+		return awsapi.SubnetModeExisting, nil
+	default:
+		return "", fmt.Errorf("unknown subnet mode %q, must be one of %q and %q", vpc.SubnetMode, awsapi.SubnetModeCreate, awsapi.SubnetModeExisting)
+	}
+}
+
+// existingZoneSubnets returns the existing subnets of the given zone by their purpose. Subnets that are referenced by
+// id must belong to the given VPC and zone, all others are discovered by their purpose tag.
+func existingZoneSubnets(ctx context.Context, awsClient client.Interface, vpcID string, zone awsapi.Zone) (map[string]client.Subnet, error) {
+	ids := make(map[string]*string)
+	if zone.Subnets != nil {
+		ids[awsapi.PurposeNodes] = zone.Subnets.Workers
+		ids[awsapi.PurposePublic] = zone.Subnets.Public
+		ids[awsapi.PurposeInternal] = zone.Subnets.Internal
+	}
+
+	var (
+		subnets  = make(map[string]client.Subnet)
+		purposes = make(map[string]string)
+	)
+	for _, purpose := range []string{awsapi.PurposeNodes, awsapi.PurposePublic, awsapi.PurposeInternal} {
+		var candidates []client.Subnet
+		if id := ids[purpose]; id != nil {
+			found, err := awsClient.GetSubnets(ctx, []string{*id})
+			if err != nil {
+				return nil, err
+			}
+			candidates = found
+		} else {
+			found, err := awsClient.FindSubnets(ctx, vpcID, zone.Name, map[string]string{awsapi.SubnetPurposeTagKey: purpose})
+			if err != nil {
+				return nil, err
+			}
+			candidates = found
+		}
+
+		if len(candidates) != 1 {
+			return nil, fmt.Errorf("zone %s: expected exactly one %s subnet in VPC %s but found %d", zone.Name, purpose, vpcID, len(candidates))
+		}
+		subnet := candidates[0]
+
+		if subnet.VPCID != vpcID {
+			return nil, fmt.Errorf("zone %s: %s subnet %s does not belong to VPC %s", zone.Name, purpose, subnet.ID, vpcID)
+		}
+		if subnet.AvailabilityZone != zone.Name {
+			return nil, fmt.Errorf("zone %s: %s subnet %s is in zone %s", zone.Name, purpose, subnet.ID, subnet.AvailabilityZone)
+		}
+		if otherPurpose, ok := purposes[subnet.ID]; ok {
+			return nil, fmt.Errorf("zone %s: subnet %s is used as %s and %s subnet", zone.Name, subnet.ID, otherPurpose, purpose)
+		}
+		purposes[subnet.ID] = purpose
+
+		subnets[purpose] = subnet
+	}
+
+	return subnets, nil
+}
+
+// reconcilerFor returns the reconciler of the given infrastructure. Once the infrastructure exists, the reconciler is
+// recorded in its provider status and the InfrastructureConfig must not select another one, as neither reconciler
+// knows about the resources of the other.
+func (a *actuator) reconcilerFor(infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *awsapi.InfrastructureConfig) (awsapi.InfrastructureReconciler, error) {
+	reconciler, err := reconcilerOf(infrastructureConfig)
+	if err != nil {
+		return "", err
+	}
+
+	status, err := a.infrastructureStatusOf(infrastructure)
+	if err != nil || status == nil {
+		return reconciler, err
+	}
+	if recorded := recordedReconcilerOf(status); recorded != reconciler {
+		return "", fmt.Errorf("the infrastructure reconciler cannot be changed from %q to %q once the infrastructure exists", recorded, reconciler)
+	}
+	return reconciler, nil
+}
+
+// recordedReconcilerOf returns the reconciler recorded in the given InfrastructureStatus, `Terraform` for statuses
+// that were written before the reconciler was recorded.
+func recordedReconcilerOf(status *awsapi.InfrastructureStatus) awsapi.InfrastructureReconciler {
+	if status.Reconciler == "" {
+		return awsapi.InfrastructureReconcilerTerraform
+	}
+	return status.Reconciler
+}
+
+// reconcilerOf returns the reconciler of the given InfrastructureConfig, `Terraform` if it does not configure one.
+func reconcilerOf(infrastructureConfig *awsapi.InfrastructureConfig) (awsapi.InfrastructureReconciler, error) {
+	switch infrastructureConfig.Reconciler {
+	case "":
+		return awsapi.InfrastructureReconcilerTerraform, nil
+	case awsapi.InfrastructureReconcilerTerraform, awsapi.InfrastructureReconcilerNative:
+		return infrastructureConfig.Reconciler, nil
+	default:
+		return "", fmt.Errorf("unknown infrastructure reconciler %q, must be one of %q and %q", infrastructureConfig.Reconciler, awsapi.InfrastructureReconcilerTerraform, awsapi.InfrastructureReconcilerNative)
+	}
+}
+
+// natGatewayModeOf returns the NAT gateway mode of the given infrastructure configuration. It defaults to
+// `PerZone` if no mode is configured.
+func natGatewayModeOf(infrastructureConfig *awsapi.InfrastructureConfig) (awsapi.NATGatewayMode, error) {
+	natGateway := infrastructureConfig.Networks.NATGateway
+	if natGateway == nil || natGateway.Mode == "" {
+		return awsapi.NATGatewayModePerZone, nil
+	}
+
+	switch natGateway.Mode {
+	case awsapi.NATGatewayModePerZone, awsapi.NATGatewayModeSingle:
+		return natGateway.Mode, nil
+	default:
+		return "", fmt.Errorf("unknown NAT gateway mode %q, must be one of %q and %q", natGateway.Mode, awsapi.NATGatewayModePerZone, awsapi.NATGatewayModeSingle)
+	}
+}
+
+// natGatewayZoneIndices returns the indices of the zones that contain a NAT gateway in the given mode.
+func natGatewayZoneIndices(infrastructureConfig *awsapi.InfrastructureConfig, mode awsapi.NATGatewayMode) []int {
+	var indices []int
+	for zoneIndex := range infrastructureConfig.Networks.Zones {
+		if mode == awsapi.NATGatewayModeSingle && zoneIndex > 0 {
+			break
+		}
+		indices = append(indices, zoneIndex)
+	}
+	return indices
+}
+
+var (
+	vpcEndpointServiceRegex = regexp.MustCompile(`^[a-z0-9]+([.-][a-z0-9]+)*$`)
+	gatewayEndpointServices = sets.NewString("s3", "dynamodb")
+)
+
+// vpcEndpoint is a validated VPC endpoint of an infrastructure configuration.
+type vpcEndpoint struct {
+	// name is the name of the endpoint in the Terraform configuration.
+	name         string
+	service      string
+	endpointType awsapi.VPCEndpointType
+}
+
+// vpcEndpointsOf validates and defaults the VPC endpoints of the given infrastructure configuration.
+func vpcEndpointsOf(infrastructureConfig *awsapi.InfrastructureConfig) ([]vpcEndpoint, error) {
+	var (
+		endpoints []vpcEndpoint
+		names     = sets.NewString()
+	)
+
+	for _, endpoint := range infrastructureConfig.Networks.VPC.Endpoints {
+		if !vpcEndpointServiceRegex.MatchString(endpoint.Service) {
+			return nil, fmt.Errorf("invalid VPC endpoint service %q", endpoint.Service)
+		}
+
+		endpointType := endpoint.Type
+		if endpointType == "" {
+			endpointType = awsapi.VPCEndpointTypeInterface
+			if gatewayEndpointServices.Has(endpoint.Service) {
+				endpointType = awsapi.VPCEndpointTypeGateway
+			}
+		}
+
+		switch endpointType {
+		case awsapi.VPCEndpointTypeGateway:
+			if !gatewayEndpointServices.Has(endpoint.Service) {
+				return nil, fmt.Errorf("VPC endpoint service %q does not support endpoints of type %q, only %s do", endpoint.Service, endpointType, strings.Join(gatewayEndpointServices.List(), " and "))
+			}
+		case awsapi.VPCEndpointTypeInterface:
+		default:
+			return nil, fmt.Errorf("unknown VPC endpoint type %q, must be one of %q and %q", endpointType, awsapi.VPCEndpointTypeGateway, awsapi.VPCEndpointTypeInterface)
+		}
+
+		name := strings.NewReplacer(".", "_", "-", "_").Replace(endpoint.Service)
+		if names.Has(name) {
+			return nil, fmt.Errorf("duplicate VPC endpoint for service %q", endpoint.Service)
+		}
+		names.Insert(name)
+
+		endpoints = append(endpoints, vpcEndpoint{name, endpoint.Service, endpointType})
+	}
+
+	return endpoints, nil
 }
@@ -0,0 +1,1188 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	awsapi "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws/install"
+	awsv1alpha1 "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws/v1alpha1"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
+	awsclient "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client/fake"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+	"github.com/gardener/gardener-extensions/pkg/terraformer"
+
+	gardencore "github.com/gardener/gardener/pkg/apis/core"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/chartrenderer"
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/version"
+
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestInfrastructure(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "AWS Infrastructure Suite")
+}
+
+var _ = Describe("Actuator", func() {
+	var (
+		ctx     context.Context
+		backend *fake.Backend
+		a       *actuator
+
+		clusterName = "shoot--foo--bar"
+		clusterTag  = "kubernetes.io/cluster/" + clusterName
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		backend = fake.NewBackend()
+		a = &actuator{}
+	})
+
+	Describe("#delete", func() {
+		var (
+			ctrl        *gomock.Controller
+			c           *mockclient.MockClient
+			tfFactory   *terraformer.FakeFactory
+			infra       *extensionsv1alpha1.Infrastructure
+			vpcID       string
+			foreignTags = map[string]string{"kubernetes.io/cluster/other": "owned"}
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			c = mockclient.NewMockClient(ctrl)
+
+			vpcID = backend.CreateVPC("10.250.0.0/16", nil)
+			tfFactory = &terraformer.FakeFactory{Outputs: map[string]string{aws.VPCIDKey: vpcID}}
+
+			a.logger = log.Log
+			a.recorder = record.NewFakeRecorder(10)
+			a.client = c
+			a.terraformerFactory = tfFactory
+			a.newAWSClient = func(_ awsclient.Credentials, _ string, _ awsclient.Endpoints) (awsclient.Interface, error) {
+				return fake.NewClient(backend), nil
+			}
+
+			infra = &extensionsv1alpha1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Namespace: clusterName, Name: "infra"},
+				Spec: extensionsv1alpha1.InfrastructureSpec{
+					Region:    "eu-west-1",
+					SecretRef: corev1.SecretReference{Namespace: clusterName, Name: "cloudprovider"},
+				},
+			}
+			c.EXPECT().Get(ctx, kutil.Key(clusterName, "cloudprovider"), gomock.AssignableToTypeOf(&corev1.Secret{})).SetArg(2, corev1.Secret{
+				Data: map[string][]byte{aws.AccessKeyID: []byte("id"), aws.SecretAccessKey: []byte("secret")},
+			}).Return(nil).AnyTimes()
+			c.EXPECT().Delete(gomock.Any(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: clusterName, Name: aws.CloudControllerManagerCredentialsSecretName}}).Return(nil).AnyTimes()
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		It("should delete all resources owned by the cluster before destroying the infrastructure", func() {
+			tfFactory.Get(aws.TerrformerPurposeInfra, clusterName, "infra").SetConfigExists(true)
+
+			backend.CreateLoadBalancer("owned", vpcID, map[string]string{clusterTag: "owned"})
+			backend.CreateLoadBalancer("foreign", vpcID, foreignTags)
+			nlbARN := backend.CreateNetworkLoadBalancer("owned", vpcID, map[string]string{clusterTag: "owned"})
+			foreignNLBARN := backend.CreateNetworkLoadBalancer("foreign", vpcID, foreignTags)
+			backend.CreateTargetGroup("owned", vpcID, nlbARN, map[string]string{clusterTag: "owned"})
+			foreignTargetGroupARN := backend.CreateTargetGroup("foreign", vpcID, foreignNLBARN, foreignTags)
+			groupID := backend.CreateSecurityGroup(vpcID, "owned", map[string]string{clusterTag: "owned"})
+			foreignGroupID := backend.CreateSecurityGroup(vpcID, "foreign", foreignTags)
+			backend.CreateNetworkInterface(vpcID, []string{groupID}, map[string]string{"cluster.k8s.amazonaws.com/name": clusterName})
+			foreignNetworkInterfaceID := backend.CreateNetworkInterface(vpcID, []string{foreignGroupID}, foreignTags)
+
+			Expect(a.delete(ctx, infra, nil)).To(Succeed())
+
+			Expect(backend.LoadBalancerNames()).To(ConsistOf("foreign"))
+			Expect(backend.NetworkLoadBalancerARNs()).To(ConsistOf(foreignNLBARN))
+			Expect(backend.TargetGroupARNs()).To(ConsistOf(foreignTargetGroupARN))
+			Expect(backend.NetworkInterfaceIDs()).To(ConsistOf(foreignNetworkInterfaceID))
+			Expect(backend.SecurityGroupIDs()).To(ConsistOf(foreignGroupID))
+			Expect(tfFactory.Get(aws.TerrformerPurposeInfra, clusterName, "infra").Destroys()).To(HaveLen(1))
+		})
+
+		It("should skip the deletion if the Terraform state does not contain the VPC", func() {
+			backend.CreateLoadBalancer("owned", vpcID, map[string]string{clusterTag: "owned"})
+
+			Expect(a.delete(ctx, infra, nil)).To(Succeed())
+
+			Expect(backend.LoadBalancerNames()).To(ConsistOf("owned"))
+			Expect(backend.Calls("DescribeLoadBalancers")).To(BeZero())
+		})
+
+		It("should clean up the EBS volumes even if the Terraform state does not contain the VPC", func() {
+			a.volumeCleanup = awsapi.VolumeCleanup{DeleteVolumes: true}
+			backend.CreateVolume("available", map[string]string{clusterTag: "owned"})
+
+			Expect(a.delete(ctx, infra, nil)).To(Succeed())
+
+			Expect(backend.VolumeIDs()).To(BeEmpty())
+		})
+
+		It("should delete the IAM user of the cloud-controller-manager", func() {
+			client := fake.NewClient(backend)
+			_, err := client.CreateUser(ctx, clusterName+"-cloud-controller-manager")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.CreateAccessKey(ctx, clusterName+"-cloud-controller-manager")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(a.delete(ctx, infra, nil)).To(Succeed())
+
+			Expect(backend.UserNames()).To(BeEmpty())
+		})
+
+		It("should delete the infrastructure natively if the native reconciler is configured", func() {
+			scheme := runtime.NewScheme()
+			install.Install(scheme)
+			Expect(a.InjectScheme(scheme)).To(Succeed())
+			infra.Spec.SSHPublicKey = []byte("ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQ== foo@bar")
+			infra.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureConfig","reconciler":"Native","networks":{"vpc":{"cidr":"10.250.0.0/16"},"zones":[{"name":"eu-west-1a","workers":"10.250.0.0/19","public":"10.250.96.0/22","internal":"10.250.112.0/22"}]}}`)}
+
+			infrastructureConfig, err := a.infrastructureConfigOf(infra)
+			Expect(err).NotTo(HaveOccurred())
+			output, err := a.reconcileNative(ctx, infra, infrastructureConfig, fake.NewClient(backend), nil)
+			Expect(err).NotTo(HaveOccurred())
+			backend.CreateLoadBalancer("owned", output[aws.VPCIDKey], map[string]string{clusterTag: "owned"})
+
+			Expect(a.delete(ctx, infra, nil)).To(Succeed())
+
+			Expect(backend.LoadBalancerNames()).To(BeEmpty())
+			Expect(backend.VPCIDs()).To(ConsistOf(vpcID))
+			Expect(backend.RoleNames()).To(BeEmpty())
+			Expect(tfFactory.Get(aws.TerrformerPurposeInfra, clusterName, "infra").Destroys()).To(BeEmpty())
+		})
+
+		It("should delete the infrastructure with the reconciler recorded in the provider status", func() {
+			scheme := runtime.NewScheme()
+			install.Install(scheme)
+			Expect(a.InjectScheme(scheme)).To(Succeed())
+			infra.Spec.SSHPublicKey = []byte("ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQ== foo@bar")
+			infra.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureConfig","networks":{"vpc":{"cidr":"10.250.0.0/16"},"zones":[{"name":"eu-west-1a","workers":"10.250.0.0/19","public":"10.250.96.0/22","internal":"10.250.112.0/22"}]}}`)}
+			infra.Status.ProviderStatus = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureStatus","reconciler":"Native","ec2":{},"iam":{},"vpc":{}}`)}
+
+			infrastructureConfig, err := a.infrastructureConfigOf(infra)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = a.reconcileNative(ctx, infra, infrastructureConfig, fake.NewClient(backend), nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(a.delete(ctx, infra, nil)).To(Succeed())
+
+			Expect(backend.VPCIDs()).To(ConsistOf(vpcID))
+			Expect(tfFactory.Get(aws.TerrformerPurposeInfra, clusterName, "infra").Destroys()).To(BeEmpty())
+		})
+
+		It("should delete the infrastructure natively even if the InfrastructureConfig has become invalid", func() {
+			scheme := runtime.NewScheme()
+			install.Install(scheme)
+			Expect(a.InjectScheme(scheme)).To(Succeed())
+			infra.Spec.SSHPublicKey = []byte("ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQ== foo@bar")
+			infra.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureConfig","reconciler":"Native","networks":{"vpc":{"cidr":"10.250.0.0/16"},"zones":[{"name":"eu-west-1a","workers":"10.250.0.0/19","public":"10.250.96.0/22","internal":"10.250.112.0/22"}]}}`)}
+
+			infrastructureConfig, err := a.infrastructureConfigOf(infra)
+			Expect(err).NotTo(HaveOccurred())
+			output, err := a.reconcileNative(ctx, infra, infrastructureConfig, fake.NewClient(backend), nil)
+			Expect(err).NotTo(HaveOccurred())
+			infra.Status.ProviderStatus = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureStatus","reconciler":"Native","ec2":{},"iam":{},"vpc":{"id":"` + output[aws.VPCIDKey] + `"}}`)}
+			infra.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureConfig","reconciler":"Native","networks":{"vpc":{"cidr":"10.250.0.0/16"},"zones":[{"name":"eu-west-1b","workers":"10.250.0.0/19","public":"10.250.96.0/22","internal":"10.250.112.0/22","elasticIPAllocationID":"eipalloc-released"}],"peerings":[{"name":"gone","vpcID":"vpc-gone","cidrs":["10.0.0.0/16"]}]}}`)}
+
+			Expect(a.delete(ctx, infra, nil)).To(Succeed())
+
+			Expect(backend.VPCIDs()).To(ConsistOf(vpcID))
+			Expect(backend.SubnetIDs()).To(BeEmpty())
+			Expect(backend.ElasticIPAllocationIDs()).To(BeEmpty())
+			Expect(backend.RoleNames()).To(BeEmpty())
+		})
+	})
+
+	Describe("#reconcilerFor", func() {
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			install.Install(scheme)
+			Expect(a.InjectScheme(scheme)).To(Succeed())
+		})
+
+		DescribeTable("should only allow the reconciler recorded in the provider status",
+			func(status string, reconciler awsapi.InfrastructureReconciler, matcher OmegaMatcher) {
+				infra := &extensionsv1alpha1.Infrastructure{}
+				if status != "" {
+					infra.Status.ProviderStatus = &runtime.RawExtension{Raw: []byte(status)}
+				}
+
+				_, err := a.reconcilerFor(infra, &awsapi.InfrastructureConfig{Reconciler: reconciler})
+				Expect(err).To(matcher)
+			},
+			Entry("new infrastructure", "", awsapi.InfrastructureReconcilerNative, Succeed()),
+			Entry("same reconciler", `{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureStatus","reconciler":"Native"}`, awsapi.InfrastructureReconcilerNative, Succeed()),
+			Entry("status without reconciler", `{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureStatus"}`, awsapi.InfrastructureReconciler(""), Succeed()),
+			Entry("switch from Terraform", `{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureStatus"}`, awsapi.InfrastructureReconcilerNative, MatchError(ContainSubstring(`from "Terraform" to "Native"`))),
+			Entry("switch from Native", `{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureStatus","reconciler":"Native"}`, awsapi.InfrastructureReconcilerTerraform, MatchError(ContainSubstring(`from "Native" to "Terraform"`))),
+		)
+	})
+
+	Describe("#volumeCleanupFor", func() {
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			install.Install(scheme)
+			Expect(a.InjectScheme(scheme)).To(Succeed())
+			a.volumeCleanup = awsapi.VolumeCleanup{DeleteVolumes: true}
+		})
+
+		It("should return the default if the infrastructure has no provider config", func() {
+			Expect(a.volumeCleanupFor(&extensionsv1alpha1.Infrastructure{})).To(Equal(awsapi.VolumeCleanup{DeleteVolumes: true}))
+		})
+
+		It("should return the default if the provider config does not configure a volume cleanup", func() {
+			Expect(a.volumeCleanupFor(&extensionsv1alpha1.Infrastructure{
+				Spec: extensionsv1alpha1.InfrastructureSpec{
+					ProviderConfig: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureConfig"}`)},
+				},
+			})).To(Equal(awsapi.VolumeCleanup{DeleteVolumes: true}))
+		})
+
+		It("should return the volume cleanup of the provider config", func() {
+			Expect(a.volumeCleanupFor(&extensionsv1alpha1.Infrastructure{
+				Spec: extensionsv1alpha1.InfrastructureSpec{
+					ProviderConfig: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureConfig","volumeCleanup":{"deleteVolumes":false,"deleteSnapshots":true,"dryRun":true}}`)},
+				},
+			})).To(Equal(awsapi.VolumeCleanup{DeleteSnapshots: true, DryRun: true}))
+		})
+	})
+
+	Describe("#cleanupKubernetesVolumesAndSnapshots", func() {
+		var (
+			recorder *record.FakeRecorder
+			infra    *extensionsv1alpha1.Infrastructure
+
+			volumeID, inUseVolumeID, foreignVolumeID string
+			snapshotID, foreignSnapshotID            string
+			otherAccountSnapshotID                   string
+		)
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+			a.recorder = recorder
+			infra = &extensionsv1alpha1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Namespace: clusterName, Name: "infra"}}
+
+			volumeID = backend.CreateVolume("available", map[string]string{clusterTag: "owned"})
+			inUseVolumeID = backend.CreateVolume("in-use", map[string]string{clusterTag: "owned"})
+			foreignVolumeID = backend.CreateVolume("available", nil)
+			snapshotID = backend.CreateSnapshot(volumeID, "", map[string]string{clusterTag: "owned"})
+			foreignSnapshotID = backend.CreateSnapshot(foreignVolumeID, "", nil)
+			otherAccountSnapshotID = backend.CreateSnapshot(volumeID, "000000000001", map[string]string{clusterTag: "owned"})
+		})
+
+		It("should delete the available volumes and the snapshots owned by the cluster", func() {
+			Expect(a.cleanupKubernetesVolumesAndSnapshots(ctx, fake.NewClient(backend), infra, awsapi.VolumeCleanup{DeleteVolumes: true, DeleteSnapshots: true})).To(Succeed())
+
+			Expect(backend.VolumeIDs()).To(ConsistOf(inUseVolumeID, foreignVolumeID))
+			Expect(backend.SnapshotIDs()).To(ConsistOf(foreignSnapshotID, otherAccountSnapshotID))
+			Expect(recorder.Events).To(Receive(Equal("Normal VolumeCleanup Deleted 1 EBS volumes: " + volumeID)))
+			Expect(recorder.Events).To(Receive(Equal("Normal VolumeCleanup Deleted 1 EBS snapshots: " + snapshotID)))
+		})
+
+		It("should only delete the volumes if snapshots are not to be deleted", func() {
+			Expect(a.cleanupKubernetesVolumesAndSnapshots(ctx, fake.NewClient(backend), infra, awsapi.VolumeCleanup{DeleteVolumes: true})).To(Succeed())
+
+			Expect(backend.VolumeIDs()).To(ConsistOf(inUseVolumeID, foreignVolumeID))
+			Expect(backend.SnapshotIDs()).To(ConsistOf(snapshotID, foreignSnapshotID, otherAccountSnapshotID))
+			Expect(backend.Calls("DescribeSnapshots")).To(BeZero())
+		})
+
+		It("should only report the volumes and snapshots in dry-run mode", func() {
+			Expect(a.cleanupKubernetesVolumesAndSnapshots(ctx, fake.NewClient(backend), infra, awsapi.VolumeCleanup{DeleteVolumes: true, DeleteSnapshots: true, DryRun: true})).To(Succeed())
+
+			Expect(backend.VolumeIDs()).To(HaveLen(3))
+			Expect(backend.SnapshotIDs()).To(HaveLen(3))
+			Expect(backend.Calls("DeleteVolume")).To(BeZero())
+			Expect(backend.Calls("DeleteSnapshot")).To(BeZero())
+			Expect(recorder.Events).To(Receive(Equal("Normal VolumeCleanup Dry run: 1 EBS volumes would be deleted: " + volumeID)))
+			Expect(recorder.Events).To(Receive(Equal("Normal VolumeCleanup Dry run: 1 EBS snapshots would be deleted: " + snapshotID)))
+		})
+	})
+
+	Describe("#destroyKubernetesLoadBalancers", func() {
+		It("should only delete the load balancers owned by the cluster", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			backend.CreateLoadBalancer("owned", vpcID, map[string]string{clusterTag: "owned"})
+			backend.CreateLoadBalancer("foreign", vpcID, nil)
+
+			Expect(a.destroyKubernetesLoadBalancers(ctx, fake.NewClient(backend), vpcID, clusterName)).To(Succeed())
+
+			Expect(backend.LoadBalancerNames()).To(ConsistOf("foreign"))
+		})
+
+		It("should return the error of the deletion", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			backend.CreateLoadBalancer("owned", vpcID, map[string]string{clusterTag: "owned"})
+			backend.InjectError("DeleteLoadBalancer", errors.New("fake"))
+
+			Expect(a.destroyKubernetesLoadBalancers(ctx, fake.NewClient(backend), vpcID, clusterName)).NotTo(Succeed())
+
+			Expect(backend.LoadBalancerNames()).To(ConsistOf("owned"))
+		})
+	})
+
+	Describe("#destroyKubernetesTargetGroups", func() {
+		It("should fail while an owned target group is still in use", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			nlbARN := backend.CreateNetworkLoadBalancer("owned", vpcID, map[string]string{clusterTag: "owned"})
+			targetGroupARN := backend.CreateTargetGroup("owned", vpcID, nlbARN, map[string]string{clusterTag: "owned"})
+
+			Expect(a.destroyKubernetesTargetGroups(ctx, fake.NewClient(backend), vpcID, clusterName)).NotTo(Succeed())
+			Expect(backend.TargetGroupARNs()).To(ConsistOf(targetGroupARN))
+
+			Expect(a.destroyKubernetesNetworkLoadBalancers(ctx, fake.NewClient(backend), vpcID, clusterName)).To(Succeed())
+			Expect(a.destroyKubernetesTargetGroups(ctx, fake.NewClient(backend), vpcID, clusterName)).To(Succeed())
+			Expect(backend.TargetGroupARNs()).To(BeEmpty())
+		})
+	})
+
+	Describe("#destroyKubernetesSecurityGroups", func() {
+		It("should fail while an owned security group is still used by a network interface", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			groupID := backend.CreateSecurityGroup(vpcID, "owned", map[string]string{clusterTag: "owned"})
+			backend.CreateNetworkInterface(vpcID, []string{groupID}, map[string]string{clusterTag: "owned"})
+
+			Expect(a.destroyKubernetesSecurityGroups(ctx, fake.NewClient(backend), vpcID, clusterName)).NotTo(Succeed())
+
+			Expect(a.destroyKubernetesNetworkInterfaces(ctx, fake.NewClient(backend), vpcID, clusterName)).To(Succeed())
+			Expect(a.destroyKubernetesSecurityGroups(ctx, fake.NewClient(backend), vpcID, clusterName)).To(Succeed())
+			Expect(backend.SecurityGroupIDs()).To(BeEmpty())
+		})
+	})
+
+	Describe("#endpointsFromSecret", func() {
+		It("should override the configured endpoints with the ones of the secret", func() {
+			a.endpoints = awsclient.Endpoints{EC2: "https://ec2.example.com", STS: "https://sts.example.com"}
+
+			Expect(a.endpointsFromSecret(&corev1.Secret{Data: map[string][]byte{
+				aws.EC2Endpoint: []byte("http://localstack:4566"),
+				aws.IAMEndpoint: []byte("http://localstack:4566"),
+			}})).To(Equal(awsclient.Endpoints{
+				EC2: "http://localstack:4566",
+				STS: "https://sts.example.com",
+				IAM: "http://localstack:4566",
+			}))
+		})
+	})
+
+	Describe("#generateTerraformInfraConfig", func() {
+		var (
+			infrastructure       *extensionsv1alpha1.Infrastructure
+			infrastructureConfig *awsapi.InfrastructureConfig
+		)
+
+		BeforeEach(func() {
+			infrastructure = &extensionsv1alpha1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Namespace: clusterName},
+				Spec: extensionsv1alpha1.InfrastructureSpec{
+					Region:       "eu-west-1",
+					SSHPublicKey: []byte("ssh-rsa AAAA"),
+				},
+			}
+			infrastructureConfig = &awsapi.InfrastructureConfig{
+				Networks: awsapi.Networks{
+					Zones: []awsapi.Zone{
+						{Name: "eu-west-1a", Workers: "10.250.0.0/19", Public: "10.250.96.0/22", Internal: "10.250.112.0/22"},
+						{Name: "eu-west-1b", Workers: "10.250.32.0/19", Public: "10.250.100.0/22", Internal: "10.250.116.0/22"},
+					},
+				},
+			}
+		})
+
+		It("should use the internet gateway of an existing VPC", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			igwID := backend.CreateInternetGateway(vpcID, nil)
+			infrastructureConfig.Networks.VPC.ID = &vpcID
+
+			config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(config["create"]).To(Equal(map[string]interface{}{"vpc": false, "subnets": true}))
+			Expect(config["vpc"]).To(HaveKeyWithValue("id", vpcID))
+			Expect(config["vpc"]).To(HaveKeyWithValue("internetGatewayID", igwID))
+		})
+
+		It("should fail if the existing VPC has no internet gateway", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			infrastructureConfig.Networks.VPC.ID = &vpcID
+
+			_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should create a new VPC without any AWS API call", func() {
+			cidr := gardencore.CIDR("10.250.0.0/16")
+			infrastructureConfig.Networks.VPC.CIDR = &cidr
+
+			config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(config["create"]).To(Equal(map[string]interface{}{"vpc": true, "subnets": true}))
+			Expect(config["vpc"]).To(HaveKeyWithValue("cidr", "10.250.0.0/16"))
+			Expect(backend.Calls("DescribeInternetGateways")).To(BeZero())
+		})
+
+		It("should pass the endpoint overrides to the Terraform provider", func() {
+			cidr := gardencore.CIDR("10.250.0.0/16")
+			infrastructureConfig.Networks.VPC.CIDR = &cidr
+
+			config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{EC2: "http://localstack:4566"}, awsclient.Credentials{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(config["aws"]).To(HaveKeyWithValue("endpoints", map[string]interface{}{"ec2": "http://localstack:4566"}))
+		})
+
+		It("should pass the role to assume to the Terraform provider", func() {
+			cidr := gardencore.CIDR("10.250.0.0/16")
+			infrastructureConfig.Networks.VPC.CIDR = &cidr
+
+			config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{RoleARN: "arn:aws:iam::123456789012:role/gardener", ExternalID: "external"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(config["aws"]).To(HaveKeyWithValue("assumeRole", map[string]interface{}{
+				"roleARN":     "arn:aws:iam::123456789012:role/gardener",
+				"sessionName": awsclient.RoleSessionName,
+				"externalID":  "external",
+			}))
+
+			renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{TillerVersion: version.GetVersionProto()})
+			files, err := terraformer.RenderChart(renderer, filepath.Join("..", "..", "..", "charts", "internal", "aws-infra"), "aws-infra", clusterName, config)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(files.Main).To(ContainSubstring(`
+  assume_role {
+    role_arn     = "arn:aws:iam::123456789012:role/gardener"
+    session_name = "gardener-extension-provider-aws"
+    external_id  = "external"
+  }
+}`))
+		})
+
+		It("should render the IAM policy documents of the native reconciler", func() {
+			cidr := gardencore.CIDR("10.250.0.0/16")
+			infrastructureConfig.Networks.VPC.CIDR = &cidr
+
+			config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{TillerVersion: version.GetVersionProto()})
+			files, err := terraformer.RenderChart(renderer, filepath.Join("..", "..", "..", "charts", "internal", "aws-infra"), "aws-infra", clusterName, config)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(files.Main).To(ContainSubstring("assume_role_policy = <<EOF\n" + assumeRolePolicyDocument + "EOF\n"))
+			Expect(files.Main).To(ContainSubstring("policy = <<EOF\n" + bastionsPolicyDocument + "EOF\n"))
+			Expect(files.Main).To(ContainSubstring("policy = <<EOF\n" + nodesPolicyDocument + "EOF\n"))
+		})
+
+		Context("NAT gateways", func() {
+			var allocationID = "eipalloc-1"
+
+			BeforeEach(func() {
+				cidr := gardencore.CIDR("10.250.0.0/16")
+				infrastructureConfig.Networks.VPC.CIDR = &cidr
+			})
+
+			It("should create one NAT gateway per zone by default", func() {
+				infrastructureConfig.Networks.Zones[1].ElasticIPAllocationID = &allocationID
+
+				config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(config["natGateway"]).To(Equal(map[string]interface{}{"single": false}))
+				zones := config["zones"].([]map[string]interface{})
+				Expect(zones[0]).To(HaveKeyWithValue("elasticIPAllocationID", ""))
+				Expect(zones[1]).To(HaveKeyWithValue("elasticIPAllocationID", allocationID))
+			})
+
+			It("should use a single NAT gateway", func() {
+				infrastructureConfig.Networks.NATGateway = &awsapi.NATGateway{Mode: awsapi.NATGatewayModeSingle}
+				infrastructureConfig.Networks.Zones[0].ElasticIPAllocationID = &allocationID
+
+				config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(config["natGateway"]).To(Equal(map[string]interface{}{"single": true}))
+			})
+
+			It("should fail for an unknown NAT gateway mode", func() {
+				infrastructureConfig.Networks.NATGateway = &awsapi.NATGateway{Mode: "Foo"}
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail if a single NAT gateway is used and another zone than the first has an Elastic IP", func() {
+				infrastructureConfig.Networks.NATGateway = &awsapi.NATGateway{Mode: awsapi.NATGatewayModeSingle}
+				infrastructureConfig.Networks.Zones[1].ElasticIPAllocationID = &allocationID
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail for an invalid Elastic IP allocation id", func() {
+				invalidAllocationID := "eipalloc-1\"\nresource \"foo\" \"bar\" {}"
+				infrastructureConfig.Networks.Zones[0].ElasticIPAllocationID = &invalidAllocationID
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail if two zones use the same Elastic IP", func() {
+				infrastructureConfig.Networks.Zones[0].ElasticIPAllocationID = &allocationID
+				infrastructureConfig.Networks.Zones[1].ElasticIPAllocationID = &allocationID
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should render a single NAT gateway with the given Elastic IP that is shared by all zones", func() {
+				infrastructureConfig.Networks.NATGateway = &awsapi.NATGateway{Mode: awsapi.NATGatewayModeSingle}
+				infrastructureConfig.Networks.Zones[0].ElasticIPAllocationID = &allocationID
+
+				config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{TillerVersion: version.GetVersionProto()})
+				files, err := terraformer.RenderChart(renderer, filepath.Join("..", "..", "..", "charts", "internal", "aws-infra"), "aws-infra", clusterName, config)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(files.Main).NotTo(ContainSubstring(`resource "aws_eip"`))
+				Expect(files.Main).To(ContainSubstring(`allocation_id = "eipalloc-1"`))
+				Expect(strings.Count(files.Main, `resource "aws_nat_gateway"`)).To(Equal(1))
+				Expect(strings.Count(files.Main, `nat_gateway_id         = "${aws_nat_gateway.natgw_z0.id}"`)).To(Equal(2))
+				Expect(files.Main).To(ContainSubstring(`output "nat_gateway_public_ip_z0"`))
+				Expect(files.Main).NotTo(ContainSubstring(`output "nat_gateway_public_ip_z1"`))
+			})
+
+			It("should render one NAT gateway and Elastic IP per zone", func() {
+
+				config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{TillerVersion: version.GetVersionProto()})
+				files, err := terraformer.RenderChart(renderer, filepath.Join("..", "..", "..", "charts", "internal", "aws-infra"), "aws-infra", clusterName, config)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strings.Count(files.Main, `resource "aws_eip"`)).To(Equal(2))
+				Expect(files.Main).To(ContainSubstring(`allocation_id = "${aws_eip.eip_natgw_z1.id}"`))
+				Expect(files.Main).To(ContainSubstring(`nat_gateway_id         = "${aws_nat_gateway.natgw_z1.id}"`))
+				Expect(files.Main).To(ContainSubstring(`output "nat_gateway_public_ip_z1"`))
+			})
+		})
+
+		Context("VPC endpoints", func() {
+			BeforeEach(func() {
+				cidr := gardencore.CIDR("10.250.0.0/16")
+				infrastructureConfig.Networks.VPC.CIDR = &cidr
+			})
+
+			It("should render gateway endpoints into the route tables and interface endpoints into the worker subnets", func() {
+				infrastructureConfig.Networks.VPC.Endpoints = []awsapi.VPCEndpoint{{Service: "s3"}, {Service: "ecr.dkr"}}
+
+				config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{TillerVersion: version.GetVersionProto()})
+				files, err := terraformer.RenderChart(renderer, filepath.Join("..", "..", "..", "charts", "internal", "aws-infra"), "aws-infra", clusterName, config)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(files.Main).To(ContainSubstring(`resource "aws_vpc_endpoint" "s3" {
+  vpc_id            = "${aws_vpc.vpc.id}"
+  service_name      = "com.amazonaws.eu-west-1.s3"
+  vpc_endpoint_type = "Gateway"
+  route_table_ids   = ["${aws_route_table.routetable_private_utility_z0.id}", "${aws_route_table.routetable_private_utility_z1.id}"]
+`))
+				Expect(files.Main).To(ContainSubstring(`resource "aws_vpc_endpoint" "ecr_dkr" {
+  vpc_id            = "${aws_vpc.vpc.id}"
+  service_name      = "com.amazonaws.eu-west-1.ecr.dkr"
+  vpc_endpoint_type = "Interface"
+  subnet_ids          = ["${aws_subnet.nodes_z0.id}", "${aws_subnet.nodes_z1.id}"]
+  security_group_ids  = ["${aws_security_group.nodes.id}"]
+  private_dns_enabled = true
+`))
+				Expect(files.Main).To(ContainSubstring(`output "vpc_endpoint_ecr_dkr" {`))
+			})
+
+			It("should use the given endpoint type", func() {
+				infrastructureConfig.Networks.VPC.Endpoints = []awsapi.VPCEndpoint{{Service: "s3", Type: awsapi.VPCEndpointTypeInterface}}
+
+				config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(config["vpc"]).To(HaveKeyWithValue("endpoints", []map[string]interface{}{
+					{"name": "s3", "service": "s3", "serviceName": "com.amazonaws.eu-west-1.s3", "type": "Interface"},
+				}))
+			})
+
+			DescribeTable("should reject invalid endpoints",
+				func(endpoints ...awsapi.VPCEndpoint) {
+					infrastructureConfig.Networks.VPC.Endpoints = endpoints
+
+					_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+					Expect(err).To(HaveOccurred())
+				},
+				Entry("invalid service", awsapi.VPCEndpoint{Service: "S3"}),
+				Entry("gateway endpoint for an interface service", awsapi.VPCEndpoint{Service: "sts", Type: awsapi.VPCEndpointTypeGateway}),
+				Entry("unknown type", awsapi.VPCEndpoint{Service: "sts", Type: "Foo"}),
+				Entry("duplicate service", awsapi.VPCEndpoint{Service: "sts"}, awsapi.VPCEndpoint{Service: "sts", Type: awsapi.VPCEndpointTypeInterface}),
+			)
+		})
+
+		Context("tags", func() {
+			BeforeEach(func() {
+				cidr := gardencore.CIDR("10.250.0.0/16")
+				infrastructureConfig.Networks.VPC.CIDR = &cidr
+			})
+
+			It("should render the tags into the tagged resources", func() {
+				infrastructureConfig.Tags = map[string]string{"owner": "shoot"}
+
+				config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, map[string]string{"cost-center": "1234", "owner": "seed"})
+				Expect(err).NotTo(HaveOccurred())
+
+				renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{TillerVersion: version.GetVersionProto()})
+				files, err := terraformer.RenderChart(renderer, filepath.Join("..", "..", "..", "charts", "internal", "aws-infra"), "aws-infra", clusterName, config)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strings.Count(files.Main, "tags {")).To(Equal(strings.Count(files.Main, `"cost-center" = "1234"`)))
+				Expect(strings.Count(files.Main, "tags {")).To(Equal(strings.Count(files.Main, `"owner" = "shoot"`)))
+				Expect(files.Main).To(ContainSubstring(`resource "aws_subnet" "public_utility_z1" {
+  vpc_id            = "${aws_vpc.vpc.id}"
+  cidr_block        = "10.250.100.0/22"
+  availability_zone = "eu-west-1b"
+
+  tags {
+    Name = "shoot--foo--bar-public-utility-z1"
+    "kubernetes.io/cluster/shoot--foo--bar"  = "1"
+    "kubernetes.io/role/elb" = "use"
+    "cost-center" = "1234"
+    "owner" = "shoot"
+  }
+}`))
+			})
+
+			It("should fail for invalid tags", func() {
+				infrastructureConfig.Tags = map[string]string{"kubernetes.io/cluster/foo": "owned"}
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("ingress rules", func() {
+			It("should render one rule for the CIDRs and one per security group", func() {
+				vpcCIDR := gardencore.CIDR("10.250.0.0/16")
+				infrastructureConfig.Networks.VPC.CIDR = &vpcCIDR
+				infrastructureConfig.Networks.IngressRules = []awsapi.IngressRule{{
+					Protocol:         "tcp",
+					FromPort:         30000,
+					ToPort:           32767,
+					CIDRs:            []gardencore.CIDR{"10.0.0.0/8", "192.168.0.0/16"},
+					SecurityGroupIDs: []string{"sg-12345"},
+				}}
+
+				config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{TillerVersion: version.GetVersionProto()})
+				files, err := terraformer.RenderChart(renderer, filepath.Join("..", "..", "..", "charts", "internal", "aws-infra"), "aws-infra", clusterName, config)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(files.Main).To(ContainSubstring(`resource "aws_security_group_rule" "nodes_custom_0" {
+  type              = "ingress"
+  from_port         = 30000
+  to_port           = 32767
+  protocol          = "tcp"
+  cidr_blocks       = ["10.0.0.0/8", "192.168.0.0/16"]
+  security_group_id = "${aws_security_group.nodes.id}"
+}`))
+				Expect(files.Main).To(ContainSubstring(`resource "aws_security_group_rule" "nodes_custom_0_sg0" {
+  type                     = "ingress"
+  from_port                = 30000
+  to_port                  = 32767
+  protocol                 = "tcp"
+  security_group_id        = "${aws_security_group.nodes.id}"
+  source_security_group_id = "sg-12345"
+}`))
+			})
+
+			It("should fail for a rule opening SSH to the internet", func() {
+				infrastructureConfig.Networks.IngressRules = []awsapi.IngressRule{{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRs: []gardencore.CIDR{"0.0.0.0/0"}}}
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(MatchError(ContainSubstring("public access must be allowed explicitly")))
+			})
+		})
+
+		Context("VPC peerings", func() {
+			BeforeEach(func() {
+				cidr := gardencore.CIDR("10.250.0.0/16")
+				infrastructureConfig.Networks.VPC.CIDR = &cidr
+			})
+
+			It("should render the peering connections and the routes to their CIDRs in the private route tables", func() {
+				ownerID := "210987654321"
+				region := "eu-central-1"
+				infrastructureConfig.Networks.Peerings = []awsapi.VPCPeering{
+					{Name: "shared", VPCID: "vpc-1", CIDRs: []gardencore.CIDR{"10.0.0.0/16", "10.1.0.0/16"}},
+					{Name: "partner", VPCID: "vpc-2", OwnerID: &ownerID, CIDRs: []gardencore.CIDR{"172.16.0.0/16"}},
+					{Name: "remote", VPCID: "vpc-3", Region: &region, CIDRs: []gardencore.CIDR{"192.168.0.0/16"}},
+				}
+
+				config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{TillerVersion: version.GetVersionProto()})
+				files, err := terraformer.RenderChart(renderer, filepath.Join("..", "..", "..", "charts", "internal", "aws-infra"), "aws-infra", clusterName, config)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(files.Main).To(ContainSubstring(`resource "aws_vpc_peering_connection" "shared" {
+  vpc_id        = "${aws_vpc.vpc.id}"
+  peer_vpc_id   = "vpc-1"
+  auto_accept   = true
+`))
+				Expect(files.Main).To(ContainSubstring(`resource "aws_vpc_peering_connection" "partner" {
+  vpc_id        = "${aws_vpc.vpc.id}"
+  peer_vpc_id   = "vpc-2"
+  peer_owner_id = "210987654321"
+
+`))
+				Expect(files.Main).To(ContainSubstring(`resource "aws_vpc_peering_connection" "remote" {
+  vpc_id        = "${aws_vpc.vpc.id}"
+  peer_vpc_id   = "vpc-3"
+  peer_region   = "eu-central-1"
+`))
+				Expect(files.Main).To(ContainSubstring(`resource "aws_vpc_peering_connection_accepter" "remote" {
+  provider                  = "aws.peering_remote"
+`))
+				Expect(files.Main).NotTo(ContainSubstring(`resource "aws_vpc_peering_connection_accepter" "partner"`))
+				Expect(files.Main).To(ContainSubstring(`resource "aws_route" "private_utility_z1_peering_shared_1" {
+  route_table_id            = "${aws_route_table.routetable_private_utility_z1.id}"
+  destination_cidr_block    = "10.1.0.0/16"
+  vpc_peering_connection_id = "${aws_vpc_peering_connection.shared.id}"
+}`))
+				Expect(files.Main).To(ContainSubstring(`output "vpc_peering_connection_partner" {`))
+				Expect(backend.Calls("GetCallerIdentity")).To(Equal(1))
+			})
+
+			It("should accept peering connections to other regions with the credentials of the main provider", func() {
+				region := "eu-central-1"
+				infrastructureConfig.Networks.Peerings = []awsapi.VPCPeering{
+					{Name: "remote", VPCID: "vpc-3", Region: &region, CIDRs: []gardencore.CIDR{"192.168.0.0/16"}},
+				}
+
+				config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{EC2: "http://localstack:4566"}, awsclient.Credentials{RoleARN: "arn:aws:iam::123456789012:role/gardener"}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{TillerVersion: version.GetVersionProto()})
+				files, err := terraformer.RenderChart(renderer, filepath.Join("..", "..", "..", "charts", "internal", "aws-infra"), "aws-infra", clusterName, config)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(files.Main).To(ContainSubstring(`provider "aws" {
+  alias      = "peering_remote"
+  access_key = "${var.ACCESS_KEY_ID}"
+  secret_key = "${var.SECRET_ACCESS_KEY}"
+  token      = "${var.SESSION_TOKEN}"
+  region     = "eu-central-1"
+
+  assume_role {
+    role_arn     = "arn:aws:iam::123456789012:role/gardener"
+    session_name = "gardener-extension-provider-aws"
+  }
+
+  endpoints {
+    ec2 = "http://localstack:4566"
+  }
+}`))
+			})
+
+			It("should fail for a peering CIDR overlapping with the VPC", func() {
+				infrastructureConfig.Networks.Peerings = []awsapi.VPCPeering{{Name: "shared", VPCID: "vpc-1", CIDRs: []gardencore.CIDR{"10.250.128.0/17"}}}
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(MatchError(ContainSubstring("overlaps with 10.250.0.0/16 of the VPC")))
+			})
+		})
+
+		Context("existing subnets", func() {
+			var (
+				vpcID      string
+				subnetTags = func(purpose string) map[string]string {
+					return map[string]string{awsapi.SubnetPurposeTagKey: purpose}
+				}
+			)
+
+			BeforeEach(func() {
+				vpcID = backend.CreateVPC("10.250.0.0/16", nil)
+				infrastructureConfig.Networks.VPC.ID = &vpcID
+				infrastructureConfig.Networks.VPC.SubnetMode = awsapi.SubnetModeExisting
+				infrastructureConfig.Networks.Zones = []awsapi.Zone{{Name: "eu-west-1a"}}
+			})
+
+			It("should use the referenced subnets and only create the security groups and IAM resources", func() {
+				nodesID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.0.0/19", nil)
+				publicID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.96.0/22", nil)
+				internalID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.112.0/22", nil)
+				infrastructureConfig.Networks.Zones[0].Subnets = &awsapi.ZoneSubnets{Workers: &nodesID, Public: &publicID, Internal: &internalID}
+
+				config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(config["create"]).To(Equal(map[string]interface{}{"vpc": false, "subnets": false}))
+				Expect(config["zones"]).To(Equal([]map[string]interface{}{{
+					"name":             "eu-west-1a",
+					"worker":           "10.250.0.0/19",
+					"public":           "10.250.96.0/22",
+					"internal":         "10.250.112.0/22",
+					"workerSubnetID":   nodesID,
+					"publicSubnetID":   publicID,
+					"internalSubnetID": internalID,
+				}}))
+				Expect(backend.Calls("DescribeInternetGateways")).To(BeZero())
+
+				renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{TillerVersion: version.GetVersionProto()})
+				files, err := terraformer.RenderChart(renderer, filepath.Join("..", "..", "..", "charts", "internal", "aws-infra"), "aws-infra", clusterName, config)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(files.Main).NotTo(ContainSubstring(`resource "aws_subnet"`))
+				Expect(files.Main).NotTo(ContainSubstring(`resource "aws_route_table"`))
+				Expect(files.Main).NotTo(ContainSubstring(`resource "aws_nat_gateway"`))
+				Expect(files.Main).To(ContainSubstring(`resource "aws_security_group" "nodes"`))
+				Expect(files.Main).To(ContainSubstring(`resource "aws_iam_instance_profile" "nodes"`))
+				Expect(files.Main).To(ContainSubstring(`output "subnet_nodes_z0" {
+  value = "` + nodesID + `"
+}`))
+				Expect(files.Main).To(ContainSubstring(`output "subnet_public_utility_z0" {
+  value = "` + publicID + `"
+}`))
+				Expect(files.Main).To(ContainSubstring(`cidr_blocks       = ["10.250.112.0/22"]`))
+			})
+
+			It("should discover the subnets by their purpose tag", func() {
+				nodesID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.0.0/19", subnetTags(awsapi.PurposeNodes))
+				publicID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.96.0/22", subnetTags(awsapi.PurposePublic))
+				internalID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.112.0/22", subnetTags(awsapi.PurposeInternal))
+				backend.CreateSubnet(vpcID, "eu-west-1b", "10.250.32.0/19", subnetTags(awsapi.PurposeNodes))
+
+				config, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(config["zones"]).To(ConsistOf(SatisfyAll(
+					HaveKeyWithValue("workerSubnetID", nodesID),
+					HaveKeyWithValue("publicSubnetID", publicID),
+					HaveKeyWithValue("internalSubnetID", internalID),
+				)))
+			})
+
+			It("should fail if a referenced subnet belongs to another VPC", func() {
+				otherVPCID := backend.CreateVPC("10.251.0.0/16", nil)
+				nodesID := backend.CreateSubnet(otherVPCID, "eu-west-1a", "10.251.0.0/19", nil)
+				backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.96.0/22", subnetTags(awsapi.PurposePublic))
+				backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.112.0/22", subnetTags(awsapi.PurposeInternal))
+				infrastructureConfig.Networks.Zones[0].Subnets = &awsapi.ZoneSubnets{Workers: &nodesID}
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(MatchError(ContainSubstring("does not belong to VPC")))
+			})
+
+			It("should fail if a referenced subnet is in another zone", func() {
+				nodesID := backend.CreateSubnet(vpcID, "eu-west-1b", "10.250.32.0/19", nil)
+				infrastructureConfig.Networks.Zones[0].Subnets = &awsapi.ZoneSubnets{Workers: &nodesID}
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(MatchError(ContainSubstring("is in zone eu-west-1b")))
+			})
+
+			It("should fail if no or several subnets are tagged with a purpose", func() {
+				backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.0.0/19", subnetTags(awsapi.PurposeNodes))
+				backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.32.0/19", subnetTags(awsapi.PurposeNodes))
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(MatchError(ContainSubstring("expected exactly one nodes subnet")))
+			})
+
+			It("should fail if a subnet is used for several purposes", func() {
+				subnetID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.0.0/19", nil)
+				infrastructureConfig.Networks.Zones[0].Subnets = &awsapi.ZoneSubnets{Workers: &subnetID, Public: &subnetID, Internal: &subnetID}
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(MatchError(ContainSubstring("is used as nodes and public subnet")))
+			})
+
+			It("should fail without an existing VPC", func() {
+				cidr := gardencore.CIDR("10.250.0.0/16")
+				infrastructureConfig.Networks.VPC.ID = nil
+				infrastructureConfig.Networks.VPC.CIDR = &cidr
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail if NAT gateways are configured", func() {
+				infrastructureConfig.Networks.NATGateway = &awsapi.NATGateway{Mode: awsapi.NATGatewayModeSingle}
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail for gateway VPC endpoints", func() {
+				infrastructureConfig.Networks.VPC.Endpoints = []awsapi.VPCEndpoint{{Service: "s3"}}
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail if VPC peerings are configured", func() {
+				infrastructureConfig.Networks.Peerings = []awsapi.VPCPeering{{Name: "shared", VPCID: "vpc-1", CIDRs: []gardencore.CIDR{"10.0.0.0/16"}}}
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(MatchError(ContainSubstring("VPC peerings cannot be configured")))
+			})
+
+			It("should fail if subnets are referenced but created", func() {
+				subnetID := "subnet-1"
+				infrastructureConfig.Networks.VPC.SubnetMode = awsapi.SubnetModeCreate
+				infrastructureConfig.Networks.Zones[0].Subnets = &awsapi.ZoneSubnets{Workers: &subnetID}
+				backend.CreateInternetGateway(vpcID, nil)
+
+				_, err := generateTerraformInfraConfig(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend), awsclient.Endpoints{}, awsclient.Credentials{}, nil)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
+
+var _ = Describe("#computeProviderStatusNATGateways", func() {
+	var (
+		infrastructureConfig = &awsapi.InfrastructureConfig{
+			Networks: awsapi.Networks{
+				Zones: []awsapi.Zone{{Name: "eu-west-1a"}, {Name: "eu-west-1b"}},
+			},
+		}
+		output = terraformer.Outputs{
+			"nat_gateway_id_z0":                "nat-0",
+			"nat_gateway_public_ip_z0":         "1.2.3.4",
+			"nat_gateway_eip_allocation_id_z0": "eipalloc-0",
+			"nat_gateway_id_z1":                "nat-1",
+			"nat_gateway_public_ip_z1":         "5.6.7.8",
+			"nat_gateway_eip_allocation_id_z1": "eipalloc-1",
+		}
+	)
+
+	It("should report the NAT gateway of every zone", func() {
+		Expect(computeProviderStatusNATGateways(infrastructureConfig, awsapi.NATGatewayModePerZone, output)).To(Equal([]awsv1alpha1.NATGatewayStatus{
+			{ID: "nat-0", Zone: "eu-west-1a", PublicIP: "1.2.3.4", ElasticIPAllocationID: "eipalloc-0"},
+			{ID: "nat-1", Zone: "eu-west-1b", PublicIP: "5.6.7.8", ElasticIPAllocationID: "eipalloc-1"},
+		}))
+	})
+
+	It("should only report the NAT gateway of the first zone for a single NAT gateway", func() {
+		Expect(computeProviderStatusNATGateways(infrastructureConfig, awsapi.NATGatewayModeSingle, output)).To(Equal([]awsv1alpha1.NATGatewayStatus{
+			{ID: "nat-0", Zone: "eu-west-1a", PublicIP: "1.2.3.4", ElasticIPAllocationID: "eipalloc-0"},
+		}))
+	})
+})
+
+var _ = Describe("#computeProviderStatusVPCEndpoints", func() {
+	It("should report the id of every endpoint", func() {
+		output := terraformer.Outputs{
+			"vpc_endpoint_s3":      "vpce-1",
+			"vpc_endpoint_ecr_api": "vpce-2",
+		}
+
+		Expect(computeProviderStatusVPCEndpoints([]vpcEndpoint{
+			{"s3", "s3", awsapi.VPCEndpointTypeGateway},
+			{"ecr_api", "ecr.api", awsapi.VPCEndpointTypeInterface},
+		}, output)).To(Equal([]awsv1alpha1.VPCEndpointStatus{
+			{Service: "s3", Type: awsv1alpha1.VPCEndpointTypeGateway, ID: "vpce-1"},
+			{Service: "ecr.api", Type: awsv1alpha1.VPCEndpointTypeInterface, ID: "vpce-2"},
+		}))
+	})
+})
+
+var _ = Describe("#computeProviderStatusVPCPeerings", func() {
+	It("should report the id of every peering connection", func() {
+		infrastructureConfig := &awsapi.InfrastructureConfig{
+			Networks: awsapi.Networks{
+				Peerings: []awsapi.VPCPeering{{Name: "shared", VPCID: "vpc-1"}, {Name: "partner", VPCID: "vpc-2"}},
+			},
+		}
+		output := terraformer.Outputs{
+			"vpc_peering_connection_shared":  "pcx-1",
+			"vpc_peering_connection_partner": "pcx-2",
+		}
+
+		Expect(computeProviderStatusVPCPeerings(infrastructureConfig, output)).To(Equal([]awsv1alpha1.VPCPeeringStatus{
+			{Name: "shared", VPCID: "vpc-1", ID: "pcx-1"},
+			{Name: "partner", VPCID: "vpc-2", ID: "pcx-2"},
+		}))
+	})
+})
+
+var _ = Describe("#computeProviderStatus", func() {
+	It("should report all created resources", func() {
+		infrastructureConfig := &awsapi.InfrastructureConfig{
+			Networks: awsapi.Networks{
+				NATGateway: &awsapi.NATGateway{Mode: awsapi.NATGatewayModeSingle},
+				Zones:      []awsapi.Zone{{Name: "eu-west-1a"}, {Name: "eu-west-1b"}},
+			},
+		}
+		output := terraformer.Outputs{
+			"vpc_id":                           "vpc-1",
+			"internet_gateway_id":              "igw-1",
+			"route_table_main":                 "rtb-main",
+			"keyName":                          "key",
+			"iamInstanceProfileNodes":          "nodes",
+			"iamInstanceProfileBastions":       "bastions",
+			"nodes_role_arn":                   "arn:nodes",
+			"bastions_role_arn":                "arn:bastions",
+			"security_group_nodes":             "sg-nodes",
+			"security_group_bastions":          "sg-bastions",
+			"subnet_nodes_z0":                  "subnet-nodes-0",
+			"subnet_public_utility_z0":         "subnet-public-0",
+			"subnet_private_utility_z0":        "subnet-private-0",
+			"route_table_private_utility_z0":   "rtb-private-0",
+			"subnet_nodes_z1":                  "subnet-nodes-1",
+			"subnet_public_utility_z1":         "subnet-public-1",
+			"subnet_private_utility_z1":        "subnet-private-1",
+			"route_table_private_utility_z1":   "rtb-private-1",
+			"nat_gateway_id_z0":                "nat-0",
+			"nat_gateway_public_ip_z0":         "1.2.3.4",
+			"nat_gateway_eip_allocation_id_z0": "eipalloc-0",
+		}
+
+		keys, err := providerStatusOutputKeys(infrastructureConfig)
+		Expect(err).NotTo(HaveOccurred())
+		var outputKeys []string
+		for key := range output {
+			outputKeys = append(outputKeys, key)
+		}
+		Expect(keys).To(ConsistOf(outputKeys))
+
+		status, err := computeProviderStatus(infrastructureConfig, output)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(&awsv1alpha1.InfrastructureStatus{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "aws.provider.extensions.gardener.cloud/v1alpha1",
+				Kind:       "InfrastructureStatus",
+			},
+			VPC: awsv1alpha1.VPCStatus{
+				ID:                "vpc-1",
+				InternetGatewayID: "igw-1",
+				Subnets: []awsv1alpha1.Subnet{
+					{Purpose: awsv1alpha1.PurposeNodes, ID: "subnet-nodes-0", Zone: "eu-west-1a"},
+					{Purpose: awsv1alpha1.PurposeNodes, ID: "subnet-nodes-1", Zone: "eu-west-1b"},
+					{Purpose: awsv1alpha1.PurposePublic, ID: "subnet-public-0", Zone: "eu-west-1a"},
+					{Purpose: awsv1alpha1.PurposePublic, ID: "subnet-public-1", Zone: "eu-west-1b"},
+					{Purpose: awsv1alpha1.PurposeInternal, ID: "subnet-private-0", Zone: "eu-west-1a"},
+					{Purpose: awsv1alpha1.PurposeInternal, ID: "subnet-private-1", Zone: "eu-west-1b"},
+				},
+				RouteTables: []awsv1alpha1.RouteTable{
+					{Purpose: awsv1alpha1.PurposePublic, ID: "rtb-main"},
+					{Purpose: awsv1alpha1.PurposePrivate, ID: "rtb-private-0", Zone: "eu-west-1a"},
+					{Purpose: awsv1alpha1.PurposePrivate, ID: "rtb-private-1", Zone: "eu-west-1b"},
+				},
+				SecurityGroups: []awsv1alpha1.SecurityGroup{
+					{Purpose: awsv1alpha1.PurposeNodes, ID: "sg-nodes"},
+					{Purpose: awsv1alpha1.PurposeBastions, ID: "sg-bastions"},
+				},
+				NATGateways: []awsv1alpha1.NATGatewayStatus{
+					{ID: "nat-0", Zone: "eu-west-1a", PublicIP: "1.2.3.4", ElasticIPAllocationID: "eipalloc-0"},
+				},
+			},
+			EC2: awsv1alpha1.EC2{
+				KeyName: "key",
+			},
+			IAM: awsv1alpha1.IAM{
+				InstanceProfiles: []awsv1alpha1.InstanceProfile{
+					{Purpose: awsv1alpha1.PurposeNodes, Name: "nodes"},
+					{Purpose: awsv1alpha1.PurposeBastions, Name: "bastions"},
+				},
+				Roles: []awsv1alpha1.Role{
+					{Purpose: awsv1alpha1.PurposeNodes, ARN: "arn:nodes"},
+					{Purpose: awsv1alpha1.PurposeBastions, ARN: "arn:bastions"},
+				},
+			},
+			Reconciler: awsv1alpha1.InfrastructureReconcilerTerraform,
+		}))
+	})
+
+	It("should not report route tables, an internet gateway or NAT gateways for existing subnets", func() {
+		vpcID := "vpc-1"
+		infrastructureConfig := &awsapi.InfrastructureConfig{
+			Networks: awsapi.Networks{
+				VPC:   awsapi.VPC{ID: &vpcID, SubnetMode: awsapi.SubnetModeExisting},
+				Zones: []awsapi.Zone{{Name: "eu-west-1a"}},
+			},
+		}
+		output := terraformer.Outputs{
+			"vpc_id":                     "vpc-1",
+			"keyName":                    "key",
+			"iamInstanceProfileNodes":    "nodes",
+			"iamInstanceProfileBastions": "bastions",
+			"nodes_role_arn":             "arn:nodes",
+			"bastions_role_arn":          "arn:bastions",
+			"security_group_nodes":       "sg-nodes",
+			"security_group_bastions":    "sg-bastions",
+			"subnet_nodes_z0":            "subnet-nodes-0",
+			"subnet_public_utility_z0":   "subnet-public-0",
+			"subnet_private_utility_z0":  "subnet-private-0",
+		}
+
+		keys, err := providerStatusOutputKeys(infrastructureConfig)
+		Expect(err).NotTo(HaveOccurred())
+		var outputKeys []string
+		for key := range output {
+			outputKeys = append(outputKeys, key)
+		}
+		Expect(keys).To(ConsistOf(outputKeys))
+
+		status, err := computeProviderStatus(infrastructureConfig, output)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.VPC.InternetGatewayID).To(BeEmpty())
+		Expect(status.VPC.RouteTables).To(BeEmpty())
+		Expect(status.VPC.NATGateways).To(BeEmpty())
+		Expect(status.VPC.Subnets).To(ConsistOf(
+			awsv1alpha1.Subnet{Purpose: awsv1alpha1.PurposeNodes, ID: "subnet-nodes-0", Zone: "eu-west-1a"},
+			awsv1alpha1.Subnet{Purpose: awsv1alpha1.PurposePublic, ID: "subnet-public-0", Zone: "eu-west-1a"},
+			awsv1alpha1.Subnet{Purpose: awsv1alpha1.PurposeInternal, ID: "subnet-private-0", Zone: "eu-west-1a"},
+		))
+	})
+})
@@ -15,7 +15,9 @@
 package infrastructure
 
 import (
+	awsapi "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
 	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
+	awsclient "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
 	"github.com/gardener/gardener-extensions/pkg/controller/infrastructure"
 
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -33,13 +35,23 @@ type AddOptions struct {
 	Controller controller.Options
 	// IgnoreOperationAnnotation specifies whether to ignore the operation annotation or not.
 	IgnoreOperationAnnotation bool
+	// Endpoints are the AWS endpoint overrides used unless the cloud provider secret specifies other ones.
+	Endpoints awsclient.Endpoints
+	// WebIdentity is the web identity of the extension used for cloud provider secrets without an access key.
+	WebIdentity awsclient.WebIdentity
+	// VolumeCleanup is the cleanup of EBS volumes and snapshots used unless the shoot configures another one.
+	VolumeCleanup awsapi.VolumeCleanup
+	// DefaultTags are the tags added to the AWS resources of every shoot.
+	DefaultTags map[string]string
+	// VPCQuota is the maximum number of VPCs per region, 0 disables the pre-flight check of the VPC quota.
+	VPCQuota int
 }
 
 // AddToManagerWithOptions adds a controller with the given Options to the given manager.
 // The opts.Reconciler is being set with a newly instantiated actuator.
 func AddToManagerWithOptions(mgr manager.Manager, opts AddOptions) error {
 	return infrastructure.Add(mgr, infrastructure.AddArgs{
-		Actuator:          infrastructure.OperationAnnotationWrapper(NewActuator()),
+		Actuator:          infrastructure.OperationAnnotationWrapper(NewActuator(mgr.GetRecorder(ActuatorName), opts.Endpoints, opts.WebIdentity, opts.VolumeCleanup, opts.DefaultTags, opts.VPCQuota)),
 		ControllerOptions: opts.Controller,
 		Predicates:        infrastructure.DefaultPredicates(mgr.GetClient(), aws.Type, opts.IgnoreOperationAnnotation),
 	})
@@ -0,0 +1,199 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
+	awsclient "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// cloudControllerManagerPolicyDocument is the inline policy of the IAM user of the cloud-controller-manager. It only
+// allows what the AWS cloud provider needs to look up the nodes and to manage the load balancers of services and their
+// security groups, and what the EBS CSI driver controller that shares the user needs to manage volumes and snapshots.
+const cloudControllerManagerPolicyDocument = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "ec2:DescribeInstances",
+        "ec2:DescribeRegions",
+        "ec2:DescribeRouteTables",
+        "ec2:DescribeSecurityGroups",
+        "ec2:DescribeSubnets",
+        "ec2:DescribeVpcs",
+        "ec2:CreateSecurityGroup",
+        "ec2:CreateTags",
+        "ec2:DeleteSecurityGroup",
+        "ec2:AuthorizeSecurityGroupIngress",
+        "ec2:RevokeSecurityGroupIngress",
+        "ec2:DescribeAvailabilityZones",
+        "ec2:DescribeSnapshots",
+        "ec2:DescribeVolumes",
+        "ec2:DescribeVolumesModifications",
+        "ec2:CreateVolume",
+        "ec2:DeleteVolume",
+        "ec2:ModifyVolume",
+        "ec2:AttachVolume",
+        "ec2:DetachVolume",
+        "ec2:CreateSnapshot",
+        "ec2:DeleteSnapshot",
+        "ec2:DeleteTags"
+      ],
+      "Resource": [
+        "*"
+      ]
+    },
+    {
+      "Effect": "Allow",
+      "Action": [
+        "elasticloadbalancing:AddTags",
+        "elasticloadbalancing:ApplySecurityGroupsToLoadBalancer",
+        "elasticloadbalancing:AttachLoadBalancerToSubnets",
+        "elasticloadbalancing:ConfigureHealthCheck",
+        "elasticloadbalancing:CreateListener",
+        "elasticloadbalancing:CreateLoadBalancer",
+        "elasticloadbalancing:CreateLoadBalancerListeners",
+        "elasticloadbalancing:CreateLoadBalancerPolicy",
+        "elasticloadbalancing:CreateTargetGroup",
+        "elasticloadbalancing:DeleteListener",
+        "elasticloadbalancing:DeleteLoadBalancer",
+        "elasticloadbalancing:DeleteLoadBalancerListeners",
+        "elasticloadbalancing:DeleteTargetGroup",
+        "elasticloadbalancing:DeregisterInstancesFromLoadBalancer",
+        "elasticloadbalancing:DeregisterTargets",
+        "elasticloadbalancing:Describe*",
+        "elasticloadbalancing:DetachLoadBalancerFromSubnets",
+        "elasticloadbalancing:ModifyListener",
+        "elasticloadbalancing:ModifyLoadBalancerAttributes",
+        "elasticloadbalancing:ModifyTargetGroup",
+        "elasticloadbalancing:ModifyTargetGroupAttributes",
+        "elasticloadbalancing:RegisterInstancesWithLoadBalancer",
+        "elasticloadbalancing:RegisterTargets",
+        "elasticloadbalancing:SetLoadBalancerPoliciesForBackendServer",
+        "elasticloadbalancing:SetLoadBalancerPoliciesOfListener"
+      ],
+      "Resource": [
+        "*"
+      ]
+    },
+    {
+      "Effect": "Allow",
+      "Action": [
+        "iam:CreateServiceLinkedRole"
+      ],
+      "Resource": [
+        "*"
+      ],
+      "Condition": {
+        "StringEquals": {
+          "iam:AWSServiceName": "elasticloadbalancing.amazonaws.com"
+        }
+      }
+    }
+  ]
+}
+`
+
+// cloudControllerManagerUserName returns the name of the IAM user of the cloud-controller-manager of the given cluster.
+func cloudControllerManagerUserName(clusterName string) string {
+	return clusterName + "-cloud-controller-manager"
+}
+
+// reconcileCloudControllerManagerCredentials ensures the IAM user of the cloud-controller-manager with its inline
+// policy and stores an access key of the user in the secret aws.CloudControllerManagerCredentialsSecretName in the
+// shoot <namespace>. The secret of an access key is only known after its creation, so a new access key is created if
+// the secret does not hold one of the user. Access keys of the user that are not in the secret are deleted.
+func (a *actuator) reconcileCloudControllerManagerCredentials(ctx context.Context, awsClient awsclient.Interface, namespace string) error {
+	name := cloudControllerManagerUserName(namespace)
+
+	user, err := awsClient.GetUser(ctx, name)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		if _, err := awsClient.CreateUser(ctx, name); err != nil {
+			return err
+		}
+	}
+	if err := awsClient.PutUserPolicy(ctx, name, name, cloudControllerManagerPolicyDocument); err != nil {
+		return err
+	}
+
+	accessKeyIDs, err := awsClient.ListAccessKeys(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{}
+	if err := a.client.Get(ctx, kutil.Key(namespace, aws.CloudControllerManagerCredentialsSecretName), secret); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	currentID := string(secret.Data[aws.AccessKeyID])
+	if !sets.NewString(accessKeyIDs...).Has(currentID) {
+		currentID = ""
+	}
+
+	for _, id := range accessKeyIDs {
+		if id == currentID {
+			continue
+		}
+		if err := awsClient.DeleteAccessKey(ctx, name, id); err != nil {
+			return err
+		}
+	}
+	if currentID != "" {
+		return nil
+	}
+
+	accessKey, err := awsClient.CreateAccessKey(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: aws.CloudControllerManagerCredentialsSecretName}}
+	return extensionscontroller.CreateOrUpdate(ctx, a.client, secret, func() error {
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{
+			aws.AccessKeyID:     []byte(accessKey.ID),
+			aws.SecretAccessKey: []byte(accessKey.Secret),
+		}
+		return nil
+	})
+}
+
+// deleteCloudControllerManagerCredentials deletes the IAM user of the cloud-controller-manager together with its
+// access keys and the secret holding its credentials in the shoot <namespace>.
+func (a *actuator) deleteCloudControllerManagerCredentials(ctx context.Context, awsClient awsclient.Interface, namespace string) error {
+	if err := awsClient.DeleteUser(ctx, cloudControllerManagerUserName(namespace)); err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: aws.CloudControllerManagerCredentialsSecretName}}
+	if err := a.client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
+	awsclient "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client/fake"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("#reconcileCloudControllerManagerCredentials", func() {
+	var (
+		ctx       context.Context
+		ctrl      *gomock.Controller
+		c         *mockclient.MockClient
+		backend   *fake.Backend
+		awsClient awsclient.Interface
+		a         *actuator
+
+		namespace = "shoot--foo--bar"
+		userName  = namespace + "-cloud-controller-manager"
+		secretKey = kutil.Key(namespace, aws.CloudControllerManagerCredentialsSecretName)
+		notFound  = apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, aws.CloudControllerManagerCredentialsSecretName)
+
+		returnSecret = func(data map[string][]byte) func(context.Context, interface{}, runtime.Object) error {
+			return func(_ context.Context, _ interface{}, obj runtime.Object) error {
+				obj.(*corev1.Secret).Data = data
+				return nil
+			}
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		ctrl = gomock.NewController(GinkgoT())
+		c = mockclient.NewMockClient(ctrl)
+		backend = fake.NewBackend()
+		awsClient = fake.NewClient(backend)
+		a = &actuator{client: c}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should create the user with its policy and store a new access key in the secret", func() {
+		var created *corev1.Secret
+		c.EXPECT().Get(ctx, secretKey, gomock.AssignableToTypeOf(&corev1.Secret{})).Return(notFound).Times(2)
+		c.EXPECT().Create(ctx, gomock.AssignableToTypeOf(&corev1.Secret{})).DoAndReturn(func(_ context.Context, obj runtime.Object) error {
+			created = obj.(*corev1.Secret)
+			return nil
+		})
+
+		Expect(a.reconcileCloudControllerManagerCredentials(ctx, awsClient, namespace)).To(Succeed())
+
+		Expect(backend.UserNames()).To(ConsistOf(userName))
+		Expect(backend.UserPolicies(userName)).To(HaveKeyWithValue(userName, cloudControllerManagerPolicyDocument))
+		accessKeys := backend.AccessKeys(userName)
+		Expect(accessKeys).To(HaveLen(1))
+		for id, secret := range accessKeys {
+			Expect(created.Data).To(Equal(map[string][]byte{aws.AccessKeyID: []byte(id), aws.SecretAccessKey: []byte(secret)}))
+		}
+	})
+
+	It("should keep the access key of the secret and delete the other ones", func() {
+		_, err := awsClient.CreateUser(ctx, userName)
+		Expect(err).NotTo(HaveOccurred())
+		current, err := awsClient.CreateAccessKey(ctx, userName)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = awsClient.CreateAccessKey(ctx, userName)
+		Expect(err).NotTo(HaveOccurred())
+		c.EXPECT().Get(ctx, secretKey, gomock.AssignableToTypeOf(&corev1.Secret{})).DoAndReturn(returnSecret(map[string][]byte{
+			aws.AccessKeyID:     []byte(current.ID),
+			aws.SecretAccessKey: []byte(current.Secret),
+		}))
+
+		Expect(a.reconcileCloudControllerManagerCredentials(ctx, awsClient, namespace)).To(Succeed())
+
+		Expect(backend.AccessKeys(userName)).To(Equal(map[string]string{current.ID: current.Secret}))
+	})
+
+	It("should replace the access key of the secret if it no longer exists", func() {
+		_, err := awsClient.CreateUser(ctx, userName)
+		Expect(err).NotTo(HaveOccurred())
+		outdated := map[string][]byte{aws.AccessKeyID: []byte("AKIA-deleted"), aws.SecretAccessKey: []byte("secret")}
+		c.EXPECT().Get(ctx, secretKey, gomock.AssignableToTypeOf(&corev1.Secret{})).DoAndReturn(returnSecret(outdated)).Times(2)
+		c.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&corev1.Secret{})).DoAndReturn(func(_ context.Context, obj runtime.Object) error {
+			Expect(obj.(*corev1.Secret).Data[aws.AccessKeyID]).NotTo(Equal(outdated[aws.AccessKeyID]))
+			return nil
+		})
+
+		Expect(a.reconcileCloudControllerManagerCredentials(ctx, awsClient, namespace)).To(Succeed())
+
+		Expect(backend.AccessKeys(userName)).To(HaveLen(1))
+	})
+})
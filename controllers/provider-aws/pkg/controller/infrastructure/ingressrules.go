@@ -0,0 +1,171 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	awsapi "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+)
+
+const (
+	ingressProtocolTCP  = "tcp"
+	ingressProtocolUDP  = "udp"
+	ingressProtocolICMP = "icmp"
+	ingressProtocolAll  = "-1"
+)
+
+// securityGroupIDRegex matches the ids of security groups, e.g. `sg-0123456789abcdef0`. Each source security group of
+// a custom ingress rule becomes the quoted `source_security_group_id` of a Terraform rule and is passed as group id to
+// the EC2 API by the native reconciler.
+var securityGroupIDRegex = regexp.MustCompile(`^sg-[0-9a-f]+$`)
+
+// portRange is an inclusive range of ports.
+type portRange struct {
+	name string
+	from int
+	to   int
+}
+
+// sensitivePortRanges are the port ranges that must not be reachable from more than maxPublicSourceAddresses public
+// IPv4 addresses unless public access is explicitly allowed.
+var sensitivePortRanges = []portRange{
+	{"SSH", 22, 22},
+	{"etcd", 2379, 2380},
+	{"RDP", 3389, 3389},
+	{"kubelet", 10250, 10250},
+	{"kubelet read-only", 10255, 10255},
+}
+
+// maxPublicSourceAddresses is the number of public IPv4 addresses, those of a /16, that the sources of all ingress
+// rules together may open a sensitive port range to. Counting the addresses instead of checking each CIDR for
+// 0.0.0.0/0 also catches the internet split into several CIDRs, e.g. 0.0.0.0/1 and 128.0.0.0/1.
+const maxPublicSourceAddresses = 1 << 16
+
+// privateNetworks are the IPv4 networks for private use (RFC 1918), whose addresses are not counted as public.
+var privateNetworks = []*net.IPNet{
+	mustParseCIDR("10.0.0.0/8"),
+	mustParseCIDR("172.16.0.0/12"),
+	mustParseCIDR("192.168.0.0/16"),
+}
+
+// validateIngressRules checks that the given additional ingress rules of the nodes are valid AWS security group
+// rules and that together they do not open sensitive ports to more than maxPublicSourceAddresses public addresses
+// without allowing public access.
+func validateIngressRules(rules []awsapi.IngressRule) error {
+	publicAddresses := make(map[string]uint64, len(sensitivePortRanges))
+	for i, rule := range rules {
+		if err := validateIngressRule(rule); err != nil {
+			return fmt.Errorf("ingress rule %d: %v", i, err)
+		}
+		if rule.AllowPublicAccess {
+			continue
+		}
+
+		var exceeded []string
+		for _, name := range sensitivePortRangesOf(rule) {
+			for _, cidr := range rule.CIDRs {
+				publicAddresses[name] += publicAddressesOf(string(cidr))
+			}
+			if publicAddresses[name] > maxPublicSourceAddresses {
+				exceeded = append(exceeded, name)
+			}
+		}
+		if len(exceeded) > 0 {
+			return fmt.Errorf("ingress rule %d: the sources open the sensitive ports of %s to more than %d public addresses, public access must be allowed explicitly", i, strings.Join(exceeded, ", "), maxPublicSourceAddresses)
+		}
+	}
+	return nil
+}
+
+func validateIngressRule(rule awsapi.IngressRule) error {
+	switch rule.Protocol {
+	case ingressProtocolTCP, ingressProtocolUDP:
+		if rule.FromPort < 0 || rule.ToPort > 65535 || rule.FromPort > rule.ToPort {
+			return fmt.Errorf("invalid port range %d-%d, ports must be between 0 and 65535", rule.FromPort, rule.ToPort)
+		}
+	case ingressProtocolICMP:
+		if rule.FromPort < -1 || rule.FromPort > 255 || rule.ToPort < -1 || rule.ToPort > 255 {
+			return fmt.Errorf("invalid ICMP type %d or code %d, they must be between -1 and 255", rule.FromPort, rule.ToPort)
+		}
+	case ingressProtocolAll:
+		if rule.FromPort != 0 || rule.ToPort != 0 {
+			return fmt.Errorf("ports must be 0 for protocol %q", ingressProtocolAll)
+		}
+	default:
+		return fmt.Errorf("unknown protocol %q, must be one of %q, %q, %q and %q", rule.Protocol, ingressProtocolTCP, ingressProtocolUDP, ingressProtocolICMP, ingressProtocolAll)
+	}
+
+	if len(rule.CIDRs) == 0 && len(rule.SecurityGroupIDs) == 0 {
+		return fmt.Errorf("at least one source CIDR or security group id is required")
+	}
+	for _, id := range rule.SecurityGroupIDs {
+		if !securityGroupIDRegex.MatchString(id) {
+			return fmt.Errorf("invalid security group id %q", id)
+		}
+	}
+
+	for _, cidr := range rule.CIDRs {
+		if ip, _, err := net.ParseCIDR(string(cidr)); err != nil || ip.To4() == nil {
+			return fmt.Errorf("invalid IPv4 CIDR %q", cidr)
+		}
+	}
+
+	return nil
+}
+
+// publicAddressesOf returns the number of addresses of the given valid IPv4 CIDR that are not in a private network.
+func publicAddressesOf(cidr string) uint64 {
+	_, ipNet, _ := net.ParseCIDR(cidr)
+	ones, bits := ipNet.Mask.Size()
+	addresses := uint64(1) << uint(bits-ones)
+
+	for _, private := range privateNetworks {
+		privateOnes, _ := private.Mask.Size()
+		switch {
+		case ones >= privateOnes && private.Contains(ipNet.IP):
+			return 0
+		case ones < privateOnes && ipNet.Contains(private.IP):
+			addresses -= uint64(1) << uint(bits-privateOnes)
+		}
+	}
+	return addresses
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return ipNet
+}
+
+// sensitivePortRangesOf returns the names of the sensitive port ranges the given rule overlaps with.
+func sensitivePortRangesOf(rule awsapi.IngressRule) []string {
+	if rule.Protocol == ingressProtocolICMP {
+		return nil
+	}
+
+	var names []string
+	for _, sensitive := range sensitivePortRanges {
+		if rule.Protocol == ingressProtocolAll || (rule.FromPort <= sensitive.to && rule.ToPort >= sensitive.from) {
+			names = append(names, sensitive.name)
+		}
+	}
+	return names
+}
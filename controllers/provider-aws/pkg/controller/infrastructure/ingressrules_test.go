@@ -0,0 +1,78 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	awsapi "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+
+	gardencore "github.com/gardener/gardener/pkg/apis/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ingress rules", func() {
+	Describe("#validateIngressRules", func() {
+		DescribeTable("should allow valid rules",
+			func(rule awsapi.IngressRule) {
+				Expect(validateIngressRules([]awsapi.IngressRule{rule})).To(Succeed())
+			},
+			Entry("node ports from an on-prem network", awsapi.IngressRule{Protocol: "tcp", FromPort: 30000, ToPort: 32767, CIDRs: []gardencore.CIDR{"10.0.0.0/8"}}),
+			Entry("SSH from a security group", awsapi.IngressRule{Protocol: "tcp", FromPort: 22, ToPort: 22, SecurityGroupIDs: []string{"sg-12345"}}),
+			Entry("non-sensitive port from the internet", awsapi.IngressRule{Protocol: "udp", FromPort: 4789, ToPort: 4789, CIDRs: []gardencore.CIDR{"0.0.0.0/0"}}),
+			Entry("ICMP from the internet", awsapi.IngressRule{Protocol: "icmp", FromPort: -1, ToPort: -1, CIDRs: []gardencore.CIDR{"0.0.0.0/0"}}),
+			Entry("SSH from the internet if public access is allowed", awsapi.IngressRule{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRs: []gardencore.CIDR{"0.0.0.0/0"}, AllowPublicAccess: true}),
+			Entry("all protocols from a network", awsapi.IngressRule{Protocol: "-1", CIDRs: []gardencore.CIDR{"192.168.0.0/16"}}),
+			Entry("SSH from a public /16", awsapi.IngressRule{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRs: []gardencore.CIDR{"203.0.0.0/16"}}),
+			Entry("SSH from a /12 that is private except for a /16", awsapi.IngressRule{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRs: []gardencore.CIDR{"10.240.0.0/12", "172.16.0.0/12"}}),
+		)
+
+		DescribeTable("should reject invalid rules",
+			func(rule awsapi.IngressRule, message string) {
+				Expect(validateIngressRules([]awsapi.IngressRule{rule})).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("unknown protocol", awsapi.IngressRule{Protocol: "sctp", CIDRs: []gardencore.CIDR{"10.0.0.0/8"}}, "unknown protocol"),
+			Entry("inverted port range", awsapi.IngressRule{Protocol: "tcp", FromPort: 443, ToPort: 80, CIDRs: []gardencore.CIDR{"10.0.0.0/8"}}, "invalid port range"),
+			Entry("port out of range", awsapi.IngressRule{Protocol: "udp", FromPort: 1, ToPort: 65536, CIDRs: []gardencore.CIDR{"10.0.0.0/8"}}, "invalid port range"),
+			Entry("ports for all protocols", awsapi.IngressRule{Protocol: "-1", FromPort: 1, ToPort: 2, CIDRs: []gardencore.CIDR{"10.0.0.0/8"}}, "ports must be 0"),
+			Entry("no source", awsapi.IngressRule{Protocol: "tcp", FromPort: 443, ToPort: 443}, "at least one source"),
+			Entry("invalid CIDR", awsapi.IngressRule{Protocol: "tcp", FromPort: 443, ToPort: 443, CIDRs: []gardencore.CIDR{"10.0.0.0"}}, "invalid IPv4 CIDR"),
+			Entry("IPv6 CIDR", awsapi.IngressRule{Protocol: "tcp", FromPort: 443, ToPort: 443, CIDRs: []gardencore.CIDR{"::/0"}}, "invalid IPv4 CIDR"),
+			Entry("invalid security group id", awsapi.IngressRule{Protocol: "tcp", FromPort: 443, ToPort: 443, SecurityGroupIDs: []string{"nodes"}}, "invalid security group id"),
+			Entry("security group id with Terraform", awsapi.IngressRule{Protocol: "tcp", FromPort: 443, ToPort: 443, SecurityGroupIDs: []string{"sg-1\"\nresource \"foo\" \"bar\" {}"}}, "invalid security group id"),
+			Entry("SSH from the internet", awsapi.IngressRule{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRs: []gardencore.CIDR{"0.0.0.0/0"}}, "sensitive ports of SSH"),
+			Entry("port range containing the kubelet from the internet", awsapi.IngressRule{Protocol: "tcp", FromPort: 10000, ToPort: 11000, CIDRs: []gardencore.CIDR{"10.0.0.0/8", "0.0.0.0/0"}}, "sensitive ports of kubelet, kubelet read-only"),
+			Entry("all protocols from the internet", awsapi.IngressRule{Protocol: "-1", CIDRs: []gardencore.CIDR{"0.0.0.0/0"}}, "sensitive ports of SSH, etcd, RDP"),
+			Entry("SSH from the internet split into halves", awsapi.IngressRule{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRs: []gardencore.CIDR{"0.0.0.0/1", "128.0.0.0/1"}}, "sensitive ports of SSH"),
+			Entry("SSH from a public /15", awsapi.IngressRule{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRs: []gardencore.CIDR{"203.0.0.0/15"}}, "sensitive ports of SSH"),
+			Entry("SSH from a /8 containing private networks", awsapi.IngressRule{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRs: []gardencore.CIDR{"172.0.0.0/8"}}, "sensitive ports of SSH"),
+		)
+
+		It("should count the public addresses of all rules", func() {
+			Expect(validateIngressRules([]awsapi.IngressRule{
+				{Protocol: "tcp", FromPort: 10250, ToPort: 10250, CIDRs: []gardencore.CIDR{"203.0.0.0/16"}},
+				{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRs: []gardencore.CIDR{"203.0.0.0/17"}},
+				{Protocol: "-1", CIDRs: []gardencore.CIDR{"198.51.0.0/17"}},
+			})).To(MatchError(And(HavePrefix("ingress rule 2:"), ContainSubstring("sensitive ports of kubelet"), Not(ContainSubstring("SSH")))))
+		})
+
+		It("should report the index of the invalid rule", func() {
+			Expect(validateIngressRules([]awsapi.IngressRule{
+				{Protocol: "tcp", FromPort: 443, ToPort: 443, CIDRs: []gardencore.CIDR{"10.0.0.0/8"}},
+				{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRs: []gardencore.CIDR{"0.0.0.0/0"}},
+			})).To(MatchError(HavePrefix("ingress rule 1:")))
+		})
+	})
+})
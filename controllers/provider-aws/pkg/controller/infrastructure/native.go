@@ -0,0 +1,1279 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	awsapi "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+	"github.com/gardener/gardener-extensions/pkg/terraformer"
+
+	"github.com/go-logr/logr"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// assumeRolePolicyDocument allows EC2 instances to assume the roles of the instance profiles. The policy documents
+	// are also rendered into the Terraform configuration, so that both reconcilers grant the same permissions.
+	assumeRolePolicyDocument = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Principal": {
+        "Service": "ec2.amazonaws.com"
+      },
+      "Action": "sts:AssumeRole"
+    }
+  ]
+}
+`
+	// bastionsPolicyDocument is the inline policy of the role of the bastions.
+	bastionsPolicyDocument = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "ec2:DescribeRegions"
+      ],
+      "Resource": [
+        "*"
+      ]
+    }
+  ]
+}
+`
+	// nodesPolicyDocument is the inline policy of the role of the nodes.
+	nodesPolicyDocument = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "ec2:DescribeInstances"
+      ],
+      "Resource": [
+        "*"
+      ]
+    },
+    {
+      "Effect": "Allow",
+      "Action": [
+        "ecr:GetAuthorizationToken",
+        "ecr:BatchCheckLayerAvailability",
+        "ecr:GetDownloadUrlForLayer",
+        "ecr:GetRepositoryPolicy",
+        "ecr:DescribeRepositories",
+        "ecr:ListImages",
+        "ecr:BatchGetImage"
+      ],
+      "Resource": [
+        "*"
+      ]
+    }
+  ]
+}
+`
+
+	allIPv4CIDR       = "0.0.0.0/0"
+	nodePortRangeFrom = 30000
+	nodePortRangeTo   = 32767
+
+	// managedSecurityGroupRuleDescription is the description of the security group rules added by the native
+	// reconciler. It distinguishes them from the rules of others, e.g. the ones the cloud-controller-manager adds for
+	// load balancers.
+	managedSecurityGroupRuleDescription = "Managed by Gardener"
+
+	natGatewayStateDeleting  = "deleting"
+	natGatewayStateFailed    = "failed"
+	vpcEndpointStateDeleting = "deleting"
+
+	vpcPeeringStatePendingAcceptance = "pending-acceptance"
+)
+
+// nativeReconciler manages the infrastructure resources of a shoot directly with the AWS API instead of Terraform.
+// It manages the same resources with the same names and tags as the aws-infra chart, so that it adopts resources
+// that were created by Terraform. Resources are looked up by their `Name` tag and the cluster tag, which makes every
+// step idempotent.
+type nativeReconciler struct {
+	logger            logr.Logger
+	awsClient         client.Interface
+	newRegionalClient regionalClientFunc
+	values            *infrastructureValues
+}
+
+// regionalClientFunc returns an AWS client for the given <region> with the credentials of the infrastructure.
+type regionalClientFunc func(region string) (client.Interface, error)
+
+// zoneSubnetIDs are the ids of the subnets of a zone.
+type zoneSubnetIDs struct {
+	nodes    string
+	public   string
+	internal string
+}
+
+func newNativeReconciler(logger logr.Logger, awsClient client.Interface, newRegionalClient regionalClientFunc, values *infrastructureValues) *nativeReconciler {
+	return &nativeReconciler{
+		logger:            logger,
+		awsClient:         awsClient,
+		newRegionalClient: newRegionalClient,
+		values:            values,
+	}
+}
+
+// name returns the name of the resource with the given <suffix>, or the cluster name if the suffix is empty.
+func (r *nativeReconciler) name(suffix string) string {
+	if suffix == "" {
+		return r.values.clusterName
+	}
+	return fmt.Sprintf("%s-%s", r.values.clusterName, suffix)
+}
+
+func (r *nativeReconciler) clusterTag() string {
+	return fmt.Sprintf("kubernetes.io/cluster/%s", r.values.clusterName)
+}
+
+// lookupTags returns the tags the resource with the given <name> is looked up by.
+func (r *nativeReconciler) lookupTags(name string) map[string]string {
+	return map[string]string{
+		"Name":         name,
+		r.clusterTag(): "1",
+	}
+}
+
+// tagsFor returns all tags of the resource with the given <name>, i.e. the custom tags, the given <extraTags> and
+// the lookup tags.
+func (r *nativeReconciler) tagsFor(name string, extraTags map[string]string) map[string]string {
+	tags := make(map[string]string, len(r.values.tags)+len(extraTags)+2)
+	for key, value := range r.values.tags {
+		tags[key] = value
+	}
+	for key, value := range extraTags {
+		tags[key] = value
+	}
+	for key, value := range r.lookupTags(name) {
+		tags[key] = value
+	}
+	return tags
+}
+
+// ensureTags adds the <desired> tags that are missing in or differ from the <current> tags of the resource <id>.
+func (r *nativeReconciler) ensureTags(ctx context.Context, id string, current, desired map[string]string) error {
+	missing := make(map[string]string)
+	for key, value := range desired {
+		if currentValue, ok := current[key]; !ok || currentValue != value {
+			missing[key] = value
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return r.awsClient.CreateTags(ctx, []string{id}, missing)
+}
+
+// reconcile creates or updates all resources of the infrastructure and returns the same outputs as the Terraform
+// configuration of the aws-infra chart.
+func (r *nativeReconciler) reconcile(ctx context.Context) (terraformer.Outputs, error) {
+	output := terraformer.Outputs{}
+
+	vpcID, internetGatewayID, err := r.reconcileVPC(ctx)
+	if err != nil {
+		return nil, err
+	}
+	output[aws.VPCIDKey] = vpcID
+
+	subnets, err := r.reconcileSubnets(ctx, vpcID)
+	if err != nil {
+		return nil, err
+	}
+	for zoneIndex, zoneSubnets := range subnets {
+		output[fmt.Sprintf("%s%d", aws.SubnetNodesPrefix, zoneIndex)] = zoneSubnets.nodes
+		output[fmt.Sprintf("%s%d", aws.SubnetPublicPrefix, zoneIndex)] = zoneSubnets.public
+		output[fmt.Sprintf("%s%d", aws.SubnetPrivatePrefix, zoneIndex)] = zoneSubnets.internal
+	}
+
+	nodesSecurityGroupID, bastionsSecurityGroupID, err := r.reconcileSecurityGroups(ctx, vpcID)
+	if err != nil {
+		return nil, err
+	}
+	output[aws.SecurityGroupsNodes] = nodesSecurityGroupID
+	output[aws.SecurityGroupsBastions] = bastionsSecurityGroupID
+
+	var privateRouteTableIDs []string
+	if r.values.createSubnets {
+		output[aws.InternetGatewayID] = internetGatewayID
+
+		privateRouteTableIDs, err = r.reconcileRouting(ctx, vpcID, internetGatewayID, subnets, output)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.reconcileVPCPeerings(ctx, vpcID, privateRouteTableIDs, output); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.reconcileVPCEndpoints(ctx, vpcID, nodesSecurityGroupID, subnets, privateRouteTableIDs, output); err != nil {
+		return nil, err
+	}
+
+	for _, purpose := range []struct {
+		name               string
+		policy             string
+		instanceProfileKey string
+		roleKey            string
+	}{
+		{awsapi.PurposeNodes, nodesPolicyDocument, aws.IAMInstanceProfileNodes, aws.NodesRole},
+		{awsapi.PurposeBastions, bastionsPolicyDocument, aws.IAMInstanceProfileBastions, aws.BastionsRole},
+	} {
+		instanceProfileName, roleARN, err := r.reconcileIAM(ctx, purpose.name, purpose.policy)
+		if err != nil {
+			return nil, err
+		}
+		output[purpose.instanceProfileKey] = instanceProfileName
+		output[purpose.roleKey] = roleARN
+	}
+
+	keyName, err := r.reconcileKeyPair(ctx)
+	if err != nil {
+		return nil, err
+	}
+	output[aws.SSHKeyName] = keyName
+
+	return output, nil
+}
+
+// reconcileVPC reconciles the VPC, its DHCP options and its internet gateway if the VPC is created, and returns the
+// ids of the VPC and the internet gateway.
+func (r *nativeReconciler) reconcileVPC(ctx context.Context) (string, string, error) {
+	if !r.values.createVPC {
+		return r.values.vpcID, r.values.internetGatewayID, nil
+	}
+
+	name := r.name("")
+	tags := r.tagsFor(name, nil)
+
+	vpcs, err := r.awsClient.FindVPCs(ctx, r.lookupTags(name))
+	if err != nil {
+		return "", "", err
+	}
+	var vpc client.VPC
+	switch len(vpcs) {
+	case 0:
+		id, err := r.awsClient.CreateVPC(ctx, r.values.vpcCIDR, tags)
+		if err != nil {
+			return "", "", err
+		}
+		r.logger.Info("Created VPC", "id", id)
+		vpc = client.VPC{ID: id, CIDR: r.values.vpcCIDR}
+	case 1:
+		vpc = vpcs[0]
+		if vpc.CIDR != r.values.vpcCIDR {
+			return "", "", fmt.Errorf("VPC %s has the CIDR %s instead of %s, the CIDR of a VPC cannot be changed", vpc.ID, vpc.CIDR, r.values.vpcCIDR)
+		}
+		if err := r.ensureTags(ctx, vpc.ID, vpc.Tags, tags); err != nil {
+			return "", "", err
+		}
+	default:
+		return "", "", fmt.Errorf("found %d VPCs named %s", len(vpcs), name)
+	}
+
+	dhcpOptions, err := r.awsClient.FindDHCPOptions(ctx, r.lookupTags(name))
+	if err != nil {
+		return "", "", err
+	}
+	var dhcpOptionsID string
+	switch len(dhcpOptions) {
+	case 0:
+		dhcpOptionsID, err = r.awsClient.CreateDHCPOptions(ctx, r.values.dhcpDomainName, tags)
+		if err != nil {
+			return "", "", err
+		}
+	case 1:
+		dhcpOptionsID = dhcpOptions[0].ID
+		if err := r.ensureTags(ctx, dhcpOptionsID, dhcpOptions[0].Tags, tags); err != nil {
+			return "", "", err
+		}
+	default:
+		return "", "", fmt.Errorf("found %d DHCP options named %s", len(dhcpOptions), name)
+	}
+	if vpc.DHCPOptionsID != dhcpOptionsID {
+		if err := r.awsClient.AssociateDHCPOptions(ctx, vpc.ID, dhcpOptionsID); err != nil {
+			return "", "", err
+		}
+	}
+
+	internetGateways, err := r.awsClient.FindInternetGateways(ctx, r.lookupTags(name))
+	if err != nil {
+		return "", "", err
+	}
+	var internetGateway client.InternetGateway
+	switch len(internetGateways) {
+	case 0:
+		id, err := r.awsClient.CreateInternetGateway(ctx, tags)
+		if err != nil {
+			return "", "", err
+		}
+		internetGateway = client.InternetGateway{ID: id}
+	case 1:
+		internetGateway = internetGateways[0]
+		if err := r.ensureTags(ctx, internetGateway.ID, internetGateway.Tags, tags); err != nil {
+			return "", "", err
+		}
+	default:
+		return "", "", fmt.Errorf("found %d internet gateways named %s", len(internetGateways), name)
+	}
+	if !sets.NewString(internetGateway.VPCIDs...).Has(vpc.ID) {
+		if err := r.awsClient.AttachInternetGateway(ctx, internetGateway.ID, vpc.ID); err != nil {
+			return "", "", err
+		}
+	}
+
+	return vpc.ID, internetGateway.ID, nil
+}
+
+// reconcileSubnets reconciles the subnets of all zones if they are created, and returns their ids.
+func (r *nativeReconciler) reconcileSubnets(ctx context.Context, vpcID string) ([]zoneSubnetIDs, error) {
+	var subnets []zoneSubnetIDs
+	for zoneIndex, zone := range r.values.zones {
+		if !r.values.createSubnets {
+			subnets = append(subnets, zoneSubnetIDs{
+				nodes:    zone.workerSubnetID,
+				public:   zone.publicSubnetID,
+				internal: zone.internalSubnetID,
+			})
+			continue
+		}
+
+		nodes, err := r.reconcileSubnet(ctx, vpcID, zone.name, zone.worker, fmt.Sprintf("nodes-z%d", zoneIndex), nil)
+		if err != nil {
+			return nil, err
+		}
+		internal, err := r.reconcileSubnet(ctx, vpcID, zone.name, zone.internal, fmt.Sprintf("private-utility-z%d", zoneIndex), map[string]string{"kubernetes.io/role/internal-elb": "use"})
+		if err != nil {
+			return nil, err
+		}
+		public, err := r.reconcileSubnet(ctx, vpcID, zone.name, zone.public, fmt.Sprintf("public-utility-z%d", zoneIndex), map[string]string{"kubernetes.io/role/elb": "use"})
+		if err != nil {
+			return nil, err
+		}
+		subnets = append(subnets, zoneSubnetIDs{nodes: nodes, public: public, internal: internal})
+	}
+	return subnets, nil
+}
+
+func (r *nativeReconciler) reconcileSubnet(ctx context.Context, vpcID, zone, cidr, suffix string, extraTags map[string]string) (string, error) {
+	name := r.name(suffix)
+	tags := r.tagsFor(name, extraTags)
+
+	subnets, err := r.awsClient.FindSubnets(ctx, vpcID, zone, r.lookupTags(name))
+	if err != nil {
+		return "", err
+	}
+	switch len(subnets) {
+	case 0:
+		return r.awsClient.CreateSubnet(ctx, vpcID, zone, cidr, tags)
+	case 1:
+		if subnets[0].CIDR != cidr {
+			return "", fmt.Errorf("subnet %s has the CIDR %s instead of %s, the CIDR of a subnet cannot be changed", subnets[0].ID, subnets[0].CIDR, cidr)
+		}
+		return subnets[0].ID, r.ensureTags(ctx, subnets[0].ID, subnets[0].Tags, tags)
+	default:
+		return "", fmt.Errorf("found %d subnets named %s", len(subnets), name)
+	}
+}
+
+// reconcileSecurityGroups reconciles the security groups of the nodes and the bastions and returns their ids. Missing
+// rules are added, rules that were added by the reconciler but are no longer desired are revoked, and the rules of
+// others are kept.
+func (r *nativeReconciler) reconcileSecurityGroups(ctx context.Context, vpcID string) (string, string, error) {
+	bastions, err := r.reconcileSecurityGroup(ctx, vpcID, awsapi.PurposeBastions, "Security group for bastions")
+	if err != nil {
+		return "", "", err
+	}
+	if err := r.reconcileSecurityGroupRules(ctx, bastions, []client.SecurityGroupRule{
+		{Type: client.SecurityGroupRuleTypeIngress, Protocol: "tcp", FromPort: 22, ToPort: 22, CIDR: allIPv4CIDR},
+		{Type: client.SecurityGroupRuleTypeEgress, Protocol: "-1", CIDR: allIPv4CIDR},
+	}); err != nil {
+		return "", "", err
+	}
+
+	nodes, err := r.reconcileSecurityGroup(ctx, vpcID, awsapi.PurposeNodes, "Security group for nodes")
+	if err != nil {
+		return "", "", err
+	}
+	rules := []client.SecurityGroupRule{
+		{Type: client.SecurityGroupRuleTypeIngress, Protocol: "-1", SecurityGroupID: nodes.ID},
+		{Type: client.SecurityGroupRuleTypeIngress, Protocol: "tcp", FromPort: nodePortRangeFrom, ToPort: nodePortRangeTo, CIDR: allIPv4CIDR},
+		{Type: client.SecurityGroupRuleTypeIngress, Protocol: "udp", FromPort: nodePortRangeFrom, ToPort: nodePortRangeTo, CIDR: allIPv4CIDR},
+		{Type: client.SecurityGroupRuleTypeIngress, Protocol: "tcp", FromPort: 22, ToPort: 22, SecurityGroupID: bastions.ID},
+		{Type: client.SecurityGroupRuleTypeEgress, Protocol: "-1", CIDR: allIPv4CIDR},
+	}
+	for _, zone := range r.values.zones {
+		for _, cidr := range []string{zone.internal, zone.public} {
+			for _, protocol := range []string{"tcp", "udp"} {
+				rules = append(rules, client.SecurityGroupRule{Type: client.SecurityGroupRuleTypeIngress, Protocol: protocol, FromPort: nodePortRangeFrom, ToPort: nodePortRangeTo, CIDR: cidr})
+			}
+		}
+	}
+	for _, rule := range r.values.ingressRules {
+		ingressRule := client.SecurityGroupRule{
+			Type:     client.SecurityGroupRuleTypeIngress,
+			Protocol: rule.Protocol,
+			FromPort: int64(rule.FromPort),
+			ToPort:   int64(rule.ToPort),
+		}
+		for _, cidr := range rule.CIDRs {
+			ingressRule.CIDR = string(cidr)
+			rules = append(rules, ingressRule)
+		}
+		ingressRule.CIDR = ""
+		for _, securityGroupID := range rule.SecurityGroupIDs {
+			ingressRule.SecurityGroupID = securityGroupID
+			rules = append(rules, ingressRule)
+		}
+	}
+	if err := r.reconcileSecurityGroupRules(ctx, nodes, rules); err != nil {
+		return "", "", err
+	}
+
+	return nodes.ID, bastions.ID, nil
+}
+
+func (r *nativeReconciler) reconcileSecurityGroup(ctx context.Context, vpcID, purpose, description string) (*client.SecurityGroup, error) {
+	name := r.name(purpose)
+	tags := r.tagsFor(name, nil)
+
+	groups, err := r.awsClient.FindSecurityGroups(ctx, vpcID, r.lookupTags(name))
+	if err != nil {
+		return nil, err
+	}
+	switch len(groups) {
+	case 0:
+		id, err := r.awsClient.CreateSecurityGroup(ctx, vpcID, name, description, tags)
+		if err != nil {
+			return nil, err
+		}
+		return &client.SecurityGroup{ID: id, Name: name}, nil
+	case 1:
+		return &groups[0], r.ensureTags(ctx, groups[0].ID, groups[0].Tags, tags)
+	default:
+		return nil, fmt.Errorf("found %d security groups named %s", len(groups), name)
+	}
+}
+
+func (r *nativeReconciler) reconcileSecurityGroupRules(ctx context.Context, group *client.SecurityGroup, rules []client.SecurityGroupRule) error {
+	var missing []client.SecurityGroupRule
+	for _, rule := range rules {
+		rule.Description = managedSecurityGroupRuleDescription
+		if !containsSecurityGroupRule(group.Rules, rule) && !containsSecurityGroupRule(missing, rule) {
+			missing = append(missing, rule)
+		}
+	}
+
+	var obsolete []client.SecurityGroupRule
+	for _, rule := range group.Rules {
+		if rule.Description == managedSecurityGroupRuleDescription && !containsSecurityGroupRule(rules, rule) {
+			obsolete = append(obsolete, rule)
+		}
+	}
+
+	if len(missing) > 0 {
+		if err := r.awsClient.AuthorizeSecurityGroupRules(ctx, group.ID, missing); err != nil {
+			return err
+		}
+	}
+	if len(obsolete) == 0 {
+		return nil
+	}
+	r.logger.Info("Revoking security group rules", "id", group.ID, "rules", obsolete)
+	return r.awsClient.RevokeSecurityGroupRules(ctx, group.ID, obsolete)
+}
+
+// containsSecurityGroupRule returns true if the given rules contain the given rule, regardless of its description.
+func containsSecurityGroupRule(rules []client.SecurityGroupRule, rule client.SecurityGroupRule) bool {
+	rule.Description = ""
+	for _, r := range rules {
+		r.Description = ""
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileRouting reconciles the route tables, the NAT gateways and their Elastic IPs, and returns the ids of the
+// private route tables.
+func (r *nativeReconciler) reconcileRouting(ctx context.Context, vpcID, internetGatewayID string, subnets []zoneSubnetIDs, output terraformer.Outputs) ([]string, error) {
+	mainRouteTableID, err := r.reconcileRouteTable(ctx, vpcID, r.name(""), client.Route{DestinationCIDR: allIPv4CIDR, GatewayID: internetGatewayID})
+	if err != nil {
+		return nil, err
+	}
+	output[aws.RouteTableMain] = mainRouteTableID
+
+	var (
+		natGatewayIDs   = make(map[int]string)
+		natGatewayNames = sets.NewString()
+		elasticIPNames  = sets.NewString()
+	)
+	for _, zoneIndex := range r.values.natGatewayZoneIndices {
+		zone := r.values.zones[zoneIndex]
+
+		elasticIPName := r.name(fmt.Sprintf("eip-natgw-z%d", zoneIndex))
+		elasticIP, err := r.reconcileElasticIP(ctx, elasticIPName, zone)
+		if err != nil {
+			return nil, err
+		}
+		if zone.elasticIPAllocationID == "" {
+			elasticIPNames.Insert(elasticIPName)
+		}
+
+		natGatewayName := r.name(fmt.Sprintf("natgw-z%d", zoneIndex))
+		natGatewayID, err := r.reconcileNATGateway(ctx, vpcID, natGatewayName, subnets[zoneIndex].public, elasticIP.AllocationID)
+		if err != nil {
+			return nil, err
+		}
+		natGatewayNames.Insert(natGatewayName)
+
+		natGatewayIDs[zoneIndex] = natGatewayID
+		output[fmt.Sprintf("%s%d", aws.NATGatewayIDPrefix, zoneIndex)] = natGatewayID
+		output[fmt.Sprintf("%s%d", aws.NATGatewayPublicIPPrefix, zoneIndex)] = elasticIP.PublicIP
+		output[fmt.Sprintf("%s%d", aws.NATGatewayEIPAllocationIDPrefix, zoneIndex)] = elasticIP.AllocationID
+	}
+
+	var (
+		privateRouteTableIDs []string
+		routeTableOfSubnet   = make(map[string]string)
+	)
+	for zoneIndex, zone := range r.values.zones {
+		natGatewayIndex := zoneIndex
+		if r.values.natGatewayMode == awsapi.NATGatewayModeSingle {
+			natGatewayIndex = 0
+		}
+
+		routeTableID, err := r.reconcileRouteTable(ctx, vpcID, r.name("private-"+zone.name), client.Route{DestinationCIDR: allIPv4CIDR, NATGatewayID: natGatewayIDs[natGatewayIndex]})
+		if err != nil {
+			return nil, err
+		}
+		privateRouteTableIDs = append(privateRouteTableIDs, routeTableID)
+		output[fmt.Sprintf("%s%d", aws.RouteTablePrivatePrefix, zoneIndex)] = routeTableID
+
+		routeTableOfSubnet[subnets[zoneIndex].internal] = routeTableID
+		routeTableOfSubnet[subnets[zoneIndex].nodes] = routeTableID
+		routeTableOfSubnet[subnets[zoneIndex].public] = mainRouteTableID
+	}
+
+	if err := r.reconcileRouteTableAssociations(ctx, vpcID, routeTableOfSubnet); err != nil {
+		return nil, err
+	}
+
+	// NAT gateways and Elastic IPs of zones that no longer contain a NAT gateway are only deleted once no route
+	// targets them anymore.
+	if err := r.deleteNATGateways(ctx, vpcID, natGatewayNames); err != nil {
+		return nil, err
+	}
+	if err := r.releaseElasticIPs(ctx, elasticIPNames); err != nil {
+		return nil, err
+	}
+
+	return privateRouteTableIDs, nil
+}
+
+// reconcileRouteTable reconciles the route table with the given <name> and its default <route>, and returns its id.
+func (r *nativeReconciler) reconcileRouteTable(ctx context.Context, vpcID, name string, route client.Route) (string, error) {
+	tags := r.tagsFor(name, nil)
+
+	routeTables, err := r.awsClient.FindRouteTables(ctx, vpcID, r.lookupTags(name))
+	if err != nil {
+		return "", err
+	}
+	var routeTable client.RouteTable
+	switch len(routeTables) {
+	case 0:
+		id, err := r.awsClient.CreateRouteTable(ctx, vpcID, tags)
+		if err != nil {
+			return "", err
+		}
+		routeTable = client.RouteTable{ID: id}
+	case 1:
+		routeTable = routeTables[0]
+		if err := r.ensureTags(ctx, routeTable.ID, routeTable.Tags, tags); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("found %d route tables named %s", len(routeTables), name)
+	}
+
+	for _, existing := range routeTable.Routes {
+		if existing.DestinationCIDR != route.DestinationCIDR {
+			continue
+		}
+		if existing == route {
+			return routeTable.ID, nil
+		}
+		return routeTable.ID, r.awsClient.ReplaceRoute(ctx, routeTable.ID, route)
+	}
+	return routeTable.ID, r.awsClient.CreateRoute(ctx, routeTable.ID, route)
+}
+
+// reconcileRouteTableAssociations associates the subnets with the route tables of the given <routeTableOfSubnet>
+// map, replacing their associations with other route tables.
+func (r *nativeReconciler) reconcileRouteTableAssociations(ctx context.Context, vpcID string, routeTableOfSubnet map[string]string) error {
+	routeTables, err := r.awsClient.FindRouteTables(ctx, vpcID, nil)
+	if err != nil {
+		return err
+	}
+
+	type association struct {
+		id           string
+		routeTableID string
+	}
+	associations := make(map[string]association)
+	for _, routeTable := range routeTables {
+		for _, a := range routeTable.Associations {
+			if a.SubnetID != "" {
+				associations[a.SubnetID] = association{a.ID, routeTable.ID}
+			}
+		}
+	}
+
+	for subnetID, routeTableID := range routeTableOfSubnet {
+		existing, ok := associations[subnetID]
+		switch {
+		case !ok:
+			if err := r.awsClient.AssociateRouteTable(ctx, routeTableID, subnetID); err != nil {
+				return err
+			}
+		case existing.routeTableID != routeTableID:
+			if err := r.awsClient.ReplaceRouteTableAssociation(ctx, existing.id, routeTableID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// reconcileElasticIP returns the Elastic IP of the NAT gateway of the given <zone>. Unless the zone references an
+// Elastic IP, the Elastic IP with the given <name> is allocated.
+func (r *nativeReconciler) reconcileElasticIP(ctx context.Context, name string, zone zoneValues) (*client.ElasticIP, error) {
+	if zone.elasticIPAllocationID != "" {
+		elasticIP, err := r.awsClient.GetElasticIP(ctx, zone.elasticIPAllocationID)
+		if err != nil {
+			return nil, err
+		}
+		if elasticIP == nil {
+			return nil, fmt.Errorf("zone %s: Elastic IP with allocation id %s does not exist", zone.name, zone.elasticIPAllocationID)
+		}
+		return elasticIP, nil
+	}
+
+	tags := r.tagsFor(name, nil)
+	elasticIPs, err := r.awsClient.FindElasticIPs(ctx, r.lookupTags(name))
+	if err != nil {
+		return nil, err
+	}
+	switch len(elasticIPs) {
+	case 0:
+		return r.awsClient.AllocateElasticIP(ctx, tags)
+	case 1:
+		return &elasticIPs[0], r.ensureTags(ctx, elasticIPs[0].AllocationID, elasticIPs[0].Tags, tags)
+	default:
+		return nil, fmt.Errorf("found %d Elastic IPs named %s", len(elasticIPs), name)
+	}
+}
+
+// reconcileNATGateway reconciles the NAT gateway with the given <name> and returns its id. A NAT gateway in another
+// subnet or with another Elastic IP is replaced.
+func (r *nativeReconciler) reconcileNATGateway(ctx context.Context, vpcID, name, subnetID, allocationID string) (string, error) {
+	tags := r.tagsFor(name, nil)
+
+	natGateways, err := r.awsClient.FindNATGateways(ctx, vpcID, r.lookupTags(name))
+	if err != nil {
+		return "", err
+	}
+	for _, natGateway := range natGateways {
+		switch {
+		case natGateway.State == natGatewayStateDeleting:
+			continue
+		case natGateway.State != natGatewayStateFailed && natGateway.SubnetID == subnetID && natGateway.AllocationID == allocationID:
+			return natGateway.ID, r.ensureTags(ctx, natGateway.ID, natGateway.Tags, tags)
+		}
+
+		r.logger.Info("Deleting outdated NAT gateway", "id", natGateway.ID, "state", natGateway.State)
+		if err := r.awsClient.DeleteNATGateway(ctx, natGateway.ID); err != nil {
+			return "", err
+		}
+	}
+
+	return r.awsClient.CreateNATGateway(ctx, subnetID, allocationID, tags)
+}
+
+// deleteNATGateways deletes the NAT gateways of the cluster except the ones with the given <keepNames>.
+func (r *nativeReconciler) deleteNATGateways(ctx context.Context, vpcID string, keepNames sets.String) error {
+	natGateways, err := r.awsClient.FindNATGateways(ctx, vpcID, map[string]string{r.clusterTag(): "1"})
+	if err != nil {
+		return err
+	}
+	for _, natGateway := range natGateways {
+		name := natGateway.Tags["Name"]
+		if !strings.HasPrefix(name, r.name("natgw-z")) || keepNames.Has(name) || natGateway.State == natGatewayStateDeleting {
+			continue
+		}
+		r.logger.Info("Deleting NAT gateway", "id", natGateway.ID)
+		if err := r.awsClient.DeleteNATGateway(ctx, natGateway.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseElasticIPs releases the Elastic IPs allocated for the NAT gateways of the cluster except the ones with the
+// given <keepNames>. Elastic IPs that are still associated are released by a later reconciliation.
+func (r *nativeReconciler) releaseElasticIPs(ctx context.Context, keepNames sets.String) error {
+	elasticIPs, err := r.awsClient.FindElasticIPs(ctx, map[string]string{r.clusterTag(): "1"})
+	if err != nil {
+		return err
+	}
+	for _, elasticIP := range elasticIPs {
+		name := elasticIP.Tags["Name"]
+		if !strings.HasPrefix(name, r.name("eip-natgw-z")) || keepNames.Has(name) {
+			continue
+		}
+		if elasticIP.AssociationID != "" {
+			r.logger.Info("Skipping the release of the Elastic IP because it is still associated", "allocationID", elasticIP.AllocationID)
+			continue
+		}
+		r.logger.Info("Releasing Elastic IP", "allocationID", elasticIP.AllocationID)
+		if err := r.awsClient.ReleaseElasticIP(ctx, elasticIP.AllocationID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileVPCEndpoints reconciles the configured VPC endpoints. Endpoints are identified by their service because
+// they cannot be tagged. Endpoints of other services are only deleted if the VPC is created.
+func (r *nativeReconciler) reconcileVPCEndpoints(ctx context.Context, vpcID, nodesSecurityGroupID string, subnets []zoneSubnetIDs, privateRouteTableIDs []string, output terraformer.Outputs) error {
+	endpoints, err := r.awsClient.FindVPCEndpoints(ctx, vpcID)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]client.VPCEndpoint)
+	for _, endpoint := range endpoints {
+		if endpoint.State != vpcEndpointStateDeleting {
+			existing[endpoint.ServiceName] = endpoint
+		}
+	}
+
+	var nodesSubnetIDs []string
+	for _, zoneSubnets := range subnets {
+		nodesSubnetIDs = append(nodesSubnetIDs, zoneSubnets.nodes)
+	}
+
+	serviceNames := sets.NewString()
+	for _, endpoint := range r.values.vpcEndpoints {
+		desired := client.VPCEndpoint{
+			VPCID:       vpcID,
+			ServiceName: r.values.serviceName(endpoint),
+			Type:        string(endpoint.endpointType),
+		}
+		if endpoint.endpointType == awsapi.VPCEndpointTypeGateway {
+			desired.RouteTableIDs = privateRouteTableIDs
+		} else {
+			desired.SubnetIDs = nodesSubnetIDs
+			desired.SecurityGroupIDs = []string{nodesSecurityGroupID}
+			desired.PrivateDNSEnabled = true
+		}
+		serviceNames.Insert(desired.ServiceName)
+
+		id, err := r.reconcileVPCEndpoint(ctx, existing, desired)
+		if err != nil {
+			return err
+		}
+		output[aws.VPCEndpointPrefix+endpoint.name] = id
+	}
+
+	if !r.values.createVPC {
+		return nil
+	}
+	for _, endpoint := range endpoints {
+		if serviceNames.Has(endpoint.ServiceName) || endpoint.State == vpcEndpointStateDeleting {
+			continue
+		}
+		r.logger.Info("Deleting VPC endpoint", "id", endpoint.ID, "service", endpoint.ServiceName)
+		if err := r.awsClient.DeleteVPCEndpoint(ctx, endpoint.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *nativeReconciler) reconcileVPCEndpoint(ctx context.Context, existing map[string]client.VPCEndpoint, desired client.VPCEndpoint) (string, error) {
+	current, ok := existing[desired.ServiceName]
+	if ok && current.Type != desired.Type {
+		r.logger.Info("Replacing VPC endpoint with another type", "id", current.ID, "type", current.Type)
+		if err := r.awsClient.DeleteVPCEndpoint(ctx, current.ID); err != nil {
+			return "", err
+		}
+		ok = false
+	}
+	if !ok {
+		return r.awsClient.CreateVPCEndpoint(ctx, desired)
+	}
+
+	var (
+		currentRouteTableIDs = sets.NewString(current.RouteTableIDs...)
+		desiredRouteTableIDs = sets.NewString(desired.RouteTableIDs...)
+		currentSubnetIDs     = sets.NewString(current.SubnetIDs...)
+		desiredSubnetIDs     = sets.NewString(desired.SubnetIDs...)
+		modification         = client.VPCEndpointModification{
+			AddRouteTableIDs:    desiredRouteTableIDs.Difference(currentRouteTableIDs).List(),
+			RemoveRouteTableIDs: currentRouteTableIDs.Difference(desiredRouteTableIDs).List(),
+			AddSubnetIDs:        desiredSubnetIDs.Difference(currentSubnetIDs).List(),
+			RemoveSubnetIDs:     currentSubnetIDs.Difference(desiredSubnetIDs).List(),
+		}
+	)
+	if len(modification.AddRouteTableIDs)+len(modification.RemoveRouteTableIDs)+len(modification.AddSubnetIDs)+len(modification.RemoveSubnetIDs) > 0 {
+		if err := r.awsClient.ModifyVPCEndpoint(ctx, current.ID, modification); err != nil {
+			return "", err
+		}
+	}
+	return current.ID, nil
+}
+
+// reconcileVPCPeerings reconciles the configured VPC peering connections and the routes to their CIDRs in the private
+// route tables. Peering connections that are no longer configured are deleted together with their routes.
+func (r *nativeReconciler) reconcileVPCPeerings(ctx context.Context, vpcID string, privateRouteTableIDs []string, output terraformer.Outputs) error {
+	existing, err := r.findVPCPeerings(ctx, vpcID)
+	if err != nil {
+		return err
+	}
+
+	// Routes to all peering connections of the cluster are managed, including the ones that are replaced or deleted.
+	managedIDs := sets.NewString()
+	for _, connections := range existing {
+		for _, connection := range connections {
+			managedIDs.Insert(connection.ID)
+		}
+	}
+
+	routes := make(map[string]string)
+	for _, peering := range r.values.vpcPeerings {
+		name := r.name("peering-" + peering.name)
+		id, err := r.reconcileVPCPeering(ctx, vpcID, name, peering, existing[name])
+		if err != nil {
+			return err
+		}
+		delete(existing, name)
+
+		managedIDs.Insert(id)
+		for _, cidr := range peering.cidrs {
+			routes[cidr] = id
+		}
+		output[aws.VPCPeeringConnectionPrefix+peering.name] = id
+	}
+
+	if err := r.reconcileVPCPeeringRoutes(ctx, vpcID, privateRouteTableIDs, routes, managedIDs); err != nil {
+		return err
+	}
+
+	for _, connections := range existing {
+		for _, connection := range connections {
+			r.logger.Info("Deleting VPC peering connection", "id", connection.ID)
+			if err := r.awsClient.DeleteVPCPeeringConnection(ctx, connection.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// reconcileVPCPeering reconciles the VPC peering connection with the given <name> and returns its id. Connections to
+// another VPC, account or region are replaced. The connection is accepted if it is requested in the same account.
+func (r *nativeReconciler) reconcileVPCPeering(ctx context.Context, vpcID, name string, peering vpcPeering, existing []client.VPCPeeringConnection) (string, error) {
+	tags := r.tagsFor(name, nil)
+
+	var current *client.VPCPeeringConnection
+	for i, connection := range existing {
+		if current == nil && connection.PeerVPCID == peering.vpcID && connection.PeerOwnerID == peering.ownerID && connection.PeerRegion == peering.region {
+			current = &existing[i]
+			continue
+		}
+		r.logger.Info("Deleting outdated VPC peering connection", "id", connection.ID, "peerVPCID", connection.PeerVPCID)
+		if err := r.awsClient.DeleteVPCPeeringConnection(ctx, connection.ID); err != nil {
+			return "", err
+		}
+	}
+
+	if current == nil {
+		id, err := r.awsClient.CreateVPCPeeringConnection(ctx, client.VPCPeeringConnection{
+			VPCID:       vpcID,
+			PeerVPCID:   peering.vpcID,
+			PeerOwnerID: peering.ownerID,
+			PeerRegion:  peering.region,
+			Tags:        tags,
+		})
+		if err != nil {
+			return "", err
+		}
+		// A new connection is accepted right away. If it is not yet pending acceptance, the acceptance fails and is
+		// retried by the next reconciliation.
+		current = &client.VPCPeeringConnection{ID: id, State: vpcPeeringStatePendingAcceptance}
+	} else if err := r.ensureTags(ctx, current.ID, current.Tags, tags); err != nil {
+		return "", err
+	}
+
+	if !peering.accept || current.State != vpcPeeringStatePendingAcceptance {
+		return current.ID, nil
+	}
+
+	accepterClient := r.awsClient
+	if peering.region != "" {
+		var err error
+		if accepterClient, err = r.newRegionalClient(peering.region); err != nil {
+			return "", err
+		}
+	}
+	return current.ID, accepterClient.AcceptVPCPeeringConnection(ctx, current.ID)
+}
+
+// reconcileVPCPeeringRoutes ensures the given <routes> from destination CIDRs to VPC peering connections in the
+// private route tables, and deletes the other routes to the given <managedIDs> of VPC peering connections.
+func (r *nativeReconciler) reconcileVPCPeeringRoutes(ctx context.Context, vpcID string, privateRouteTableIDs []string, routes map[string]string, managedIDs sets.String) error {
+	routeTables, err := r.awsClient.FindRouteTables(ctx, vpcID, map[string]string{r.clusterTag(): "1"})
+	if err != nil {
+		return err
+	}
+
+	private := sets.NewString(privateRouteTableIDs...)
+	for _, routeTable := range routeTables {
+		if !private.Has(routeTable.ID) {
+			continue
+		}
+
+		current := make(map[string]client.Route, len(routeTable.Routes))
+		for _, route := range routeTable.Routes {
+			current[route.DestinationCIDR] = route
+			if _, ok := routes[route.DestinationCIDR]; !ok && managedIDs.Has(route.VPCPeeringConnectionID) {
+				if err := r.awsClient.DeleteRoute(ctx, routeTable.ID, route.DestinationCIDR); err != nil {
+					return err
+				}
+			}
+		}
+
+		for cidr, id := range routes {
+			route := client.Route{DestinationCIDR: cidr, VPCPeeringConnectionID: id}
+			existing, ok := current[cidr]
+			switch {
+			case !ok:
+				err = r.awsClient.CreateRoute(ctx, routeTable.ID, route)
+			case existing != route:
+				err = r.awsClient.ReplaceRoute(ctx, routeTable.ID, route)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// findVPCPeerings returns the VPC peering connections of the cluster requested by the VPC <vpcID>, grouped by name.
+func (r *nativeReconciler) findVPCPeerings(ctx context.Context, vpcID string) (map[string][]client.VPCPeeringConnection, error) {
+	connections, err := r.awsClient.FindVPCPeeringConnections(ctx, vpcID, map[string]string{r.clusterTag(): "1"})
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string][]client.VPCPeeringConnection)
+	for _, connection := range connections {
+		if name := connection.Tags["Name"]; strings.HasPrefix(name, r.name("peering-")) {
+			byName[name] = append(byName[name], connection)
+		}
+	}
+	return byName, nil
+}
+
+// reconcileIAM reconciles the role, its inline policy and the instance profile of the given <purpose>, and returns
+// the name of the instance profile and the ARN of the role.
+func (r *nativeReconciler) reconcileIAM(ctx context.Context, purpose, policy string) (string, string, error) {
+	name := r.name(purpose)
+
+	role, err := r.awsClient.GetRole(ctx, name)
+	if err != nil {
+		return "", "", err
+	}
+	if role == nil {
+		if role, err = r.awsClient.CreateRole(ctx, name, assumeRolePolicyDocument); err != nil {
+			return "", "", err
+		}
+	}
+	if err := r.awsClient.PutRolePolicy(ctx, name, name, policy); err != nil {
+		return "", "", err
+	}
+
+	instanceProfile, err := r.awsClient.GetInstanceProfile(ctx, name)
+	if err != nil {
+		return "", "", err
+	}
+	if instanceProfile == nil {
+		if err := r.awsClient.CreateInstanceProfile(ctx, name); err != nil {
+			return "", "", err
+		}
+		instanceProfile = &client.InstanceProfile{Name: name}
+	}
+	if !sets.NewString(instanceProfile.RoleNames...).Has(name) {
+		if err := r.awsClient.AddRoleToInstanceProfile(ctx, name, name); err != nil {
+			return "", "", err
+		}
+	}
+
+	return instanceProfile.Name, role.ARN, nil
+}
+
+// reconcileKeyPair imports the SSH public key of the shoot and returns the name of the key pair. A key pair with
+// another public key is replaced.
+func (r *nativeReconciler) reconcileKeyPair(ctx context.Context) (string, error) {
+	name := r.name("ssh-publickey")
+
+	fingerprint, err := client.PublicKeyFingerprint(r.values.sshPublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	keyPair, err := r.awsClient.GetKeyPair(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if keyPair != nil {
+		if keyPair.Fingerprint == fingerprint {
+			return name, nil
+		}
+		if err := r.awsClient.DeleteKeyPair(ctx, name); err != nil {
+			return "", err
+		}
+	}
+
+	return name, r.awsClient.ImportKeyPair(ctx, name, r.values.sshPublicKey)
+}
+
+// findVPC returns the id of the VPC of the infrastructure, or an empty string if the VPC is created and does not
+// exist (anymore).
+func (r *nativeReconciler) findVPC(ctx context.Context) (string, error) {
+	if !r.values.createVPC {
+		return r.values.vpcID, nil
+	}
+
+	name := r.name("")
+	vpcs, err := r.awsClient.FindVPCs(ctx, r.lookupTags(name))
+	if err != nil {
+		return "", err
+	}
+	switch len(vpcs) {
+	case 0:
+		return "", nil
+	case 1:
+		return vpcs[0].ID, nil
+	default:
+		return "", fmt.Errorf("found %d VPCs named %s", len(vpcs), name)
+	}
+}
+
+// delete deletes all resources of the infrastructure in reverse order of their creation. The resources in the VPC
+// <vpcID> are skipped if it is empty. Only resources that are named and tagged like the ones created by reconcile are
+// deleted, every step tolerates resources that are already gone. The VPC is deleted if it was created, otherwise only
+// the VPC endpoints with the given <endpointIDs> are deleted from it. Deletion only relies on the cluster name of the
+// values, so that it does not depend on an InfrastructureConfig that might have become invalid in the meantime.
+func (r *nativeReconciler) delete(ctx context.Context, vpcID string, endpointIDs []string) error {
+	if vpcID != "" {
+		if err := r.deleteVPCEndpoints(ctx, vpcID, sets.NewString(endpointIDs...)); err != nil {
+			return err
+		}
+
+		peerings, err := r.findVPCPeerings(ctx, vpcID)
+		if err != nil {
+			return err
+		}
+		for _, connections := range peerings {
+			for _, connection := range connections {
+				if err := r.awsClient.DeleteVPCPeeringConnection(ctx, connection.ID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := r.deleteNATGateways(ctx, vpcID, sets.NewString()); err != nil {
+			return err
+		}
+		natGateways, err := r.awsClient.FindNATGateways(ctx, vpcID, map[string]string{r.clusterTag(): "1"})
+		if err != nil {
+			return err
+		}
+		for _, natGateway := range natGateways {
+			if strings.HasPrefix(natGateway.Tags["Name"], r.name("natgw-z")) {
+				return fmt.Errorf("waiting until NAT gateway %s is deleted", natGateway.ID)
+			}
+		}
+	}
+
+	if err := r.releaseElasticIPs(ctx, sets.NewString()); err != nil {
+		return err
+	}
+
+	if vpcID != "" {
+		if err := r.deleteRouteTables(ctx, vpcID); err != nil {
+			return err
+		}
+		if err := r.deleteSubnets(ctx, vpcID); err != nil {
+			return err
+		}
+	}
+
+	if vpcID != "" {
+		// The security group of the nodes references the one of the bastions.
+		for _, purpose := range []string{awsapi.PurposeNodes, awsapi.PurposeBastions} {
+			groups, err := r.awsClient.FindSecurityGroups(ctx, vpcID, r.lookupTags(r.name(purpose)))
+			if err != nil {
+				return err
+			}
+			for _, group := range groups {
+				if err := r.awsClient.DeleteSecurityGroup(ctx, group.ID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	ownedVPCID := ""
+	if r.values.createVPC {
+		ownedVPCID = vpcID
+	}
+	if err := r.deleteVPC(ctx, ownedVPCID); err != nil {
+		return err
+	}
+
+	for _, purpose := range []string{awsapi.PurposeNodes, awsapi.PurposeBastions} {
+		if err := r.awsClient.DeleteInstanceProfile(ctx, r.name(purpose)); err != nil {
+			return err
+		}
+		if err := r.awsClient.DeleteRole(ctx, r.name(purpose)); err != nil {
+			return err
+		}
+	}
+
+	return r.awsClient.DeleteKeyPair(ctx, r.name("ssh-publickey"))
+}
+
+// deleteVPCEndpoints deletes the VPC endpoints with the given <ids>, or all VPC endpoints if the VPC is created.
+func (r *nativeReconciler) deleteVPCEndpoints(ctx context.Context, vpcID string, ids sets.String) error {
+	endpoints, err := r.awsClient.FindVPCEndpoints(ctx, vpcID)
+	if err != nil {
+		return err
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.State == vpcEndpointStateDeleting || (!r.values.createVPC && !ids.Has(endpoint.ID)) {
+			continue
+		}
+		if err := r.awsClient.DeleteVPCEndpoint(ctx, endpoint.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteRouteTables deletes the main and the private route tables after removing their associations.
+func (r *nativeReconciler) deleteRouteTables(ctx context.Context, vpcID string) error {
+	routeTables, err := r.awsClient.FindRouteTables(ctx, vpcID, map[string]string{r.clusterTag(): "1"})
+	if err != nil {
+		return err
+	}
+	for _, routeTable := range routeTables {
+		if name := routeTable.Tags["Name"]; name != r.name("") && !strings.HasPrefix(name, r.name("private-")) {
+			continue
+		}
+		for _, association := range routeTable.Associations {
+			if association.Main {
+				continue
+			}
+			if err := r.awsClient.DisassociateRouteTable(ctx, association.ID); err != nil {
+				return err
+			}
+		}
+		if err := r.awsClient.DeleteRouteTable(ctx, routeTable.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteSubnets deletes the subnets of all zones that were created, regardless of the zones that are configured.
+func (r *nativeReconciler) deleteSubnets(ctx context.Context, vpcID string) error {
+	subnets, err := r.awsClient.FindSubnets(ctx, vpcID, "", map[string]string{r.clusterTag(): "1"})
+	if err != nil {
+		return err
+	}
+	for _, subnet := range subnets {
+		name := subnet.Tags["Name"]
+		if !strings.HasPrefix(name, r.name("nodes-z")) && !strings.HasPrefix(name, r.name("private-utility-z")) && !strings.HasPrefix(name, r.name("public-utility-z")) {
+			continue
+		}
+		if err := r.awsClient.DeleteSubnet(ctx, subnet.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteVPC deletes the internet gateway, the VPC <vpcID> if it is not empty, and the DHCP options.
+func (r *nativeReconciler) deleteVPC(ctx context.Context, vpcID string) error {
+	name := r.name("")
+
+	internetGateways, err := r.awsClient.FindInternetGateways(ctx, r.lookupTags(name))
+	if err != nil {
+		return err
+	}
+	for _, internetGateway := range internetGateways {
+		for _, attachedVPCID := range internetGateway.VPCIDs {
+			if err := r.awsClient.DetachInternetGateway(ctx, internetGateway.ID, attachedVPCID); err != nil {
+				return err
+			}
+		}
+		if err := r.awsClient.DeleteInternetGateway(ctx, internetGateway.ID); err != nil {
+			return err
+		}
+	}
+
+	if vpcID != "" {
+		if err := r.awsClient.DeleteVPC(ctx, vpcID); err != nil {
+			return err
+		}
+	}
+
+	dhcpOptions, err := r.awsClient.FindDHCPOptions(ctx, r.lookupTags(name))
+	if err != nil {
+		return err
+	}
+	for _, options := range dhcpOptions {
+		if err := r.awsClient.DeleteDHCPOptions(ctx, options.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,427 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+
+	awsapi "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
+	awsclient "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client/fake"
+
+	gardencore "github.com/gardener/gardener/pkg/apis/core"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var _ = Describe("Native reconciler", func() {
+	var (
+		ctx       context.Context
+		backend   *fake.Backend
+		awsClient awsclient.Interface
+
+		infrastructure       *extensionsv1alpha1.Infrastructure
+		infrastructureConfig *awsapi.InfrastructureConfig
+
+		regionalClients []string
+
+		clusterName = "shoot--foo--bar"
+		clusterTag  = "kubernetes.io/cluster/" + clusterName
+		sshKey      = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQ== foo@bar"
+
+		newReconciler = func() *nativeReconciler {
+			values, err := computeInfrastructureValues(ctx, infrastructure, infrastructureConfig, awsClient, nil)
+			Expect(err).NotTo(HaveOccurred())
+			return newNativeReconciler(log.Log, awsClient, func(region string) (awsclient.Interface, error) {
+				regionalClients = append(regionalClients, region)
+				return awsClient, nil
+			}, values)
+		}
+		newDeletingReconciler = func(createVPC bool) *nativeReconciler {
+			return newNativeReconciler(log.Log, awsClient, nil, &infrastructureValues{clusterName: clusterName, createVPC: createVPC})
+		}
+		reconcile = func() map[string]string {
+			output, err := newReconciler().reconcile(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			return output
+		}
+		creations = func() map[string]int {
+			creations := make(map[string]int)
+			for _, operation := range []string{"CreateVpc", "CreateDhcpOptions", "CreateInternetGateway", "CreateSubnet", "CreateSecurityGroup",
+				"AuthorizeSecurityGroupIngress", "AuthorizeSecurityGroupEgress", "CreateRouteTable", "CreateRoute", "ReplaceRoute",
+				"AssociateRouteTable", "AllocateAddress", "CreateNatGateway", "CreateVpcEndpoint", "ModifyVpcEndpoint", "CreateRole",
+				"CreateInstanceProfile", "AddRoleToInstanceProfile", "ImportKeyPair", "CreateTags", "CreateVpcPeeringConnection", "AcceptVpcPeeringConnection"} {
+				creations[operation] = backend.Calls(operation)
+			}
+			return creations
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		backend = fake.NewBackend()
+		awsClient = fake.NewClient(backend)
+		regionalClients = nil
+
+		vpcCIDR := gardencore.CIDR("10.250.0.0/16")
+		infrastructure = &extensionsv1alpha1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Namespace: clusterName},
+			Spec: extensionsv1alpha1.InfrastructureSpec{
+				Region:       "eu-west-1",
+				SSHPublicKey: []byte(sshKey),
+			},
+		}
+		infrastructureConfig = &awsapi.InfrastructureConfig{
+			Reconciler: awsapi.InfrastructureReconcilerNative,
+			Tags:       map[string]string{"cost-center": "1234"},
+			Networks: awsapi.Networks{
+				VPC: awsapi.VPC{
+					CIDR: &vpcCIDR,
+					Endpoints: []awsapi.VPCEndpoint{
+						{Service: "s3"},
+						{Service: "ec2", Type: awsapi.VPCEndpointTypeInterface},
+					},
+				},
+				Zones: []awsapi.Zone{
+					{Name: "eu-west-1a", Workers: "10.250.0.0/19", Public: "10.250.96.0/22", Internal: "10.250.112.0/22"},
+					{Name: "eu-west-1b", Workers: "10.250.32.0/19", Public: "10.250.100.0/22", Internal: "10.250.116.0/22"},
+				},
+			},
+		}
+	})
+
+	Describe("#reconcile", func() {
+		It("should create all resources and return the outputs of the InfrastructureStatus", func() {
+			output := reconcile()
+
+			keys, err := providerStatusOutputKeys(infrastructureConfig)
+			Expect(err).NotTo(HaveOccurred())
+			for _, key := range keys {
+				Expect(output).To(HaveKey(key))
+				Expect(output[key]).NotTo(BeEmpty(), key)
+			}
+			_, err = computeProviderStatus(infrastructureConfig, output)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(backend.VPCIDs()).To(ConsistOf(output[aws.VPCIDKey]))
+			Expect(backend.InternetGatewayIDs()).To(ConsistOf(output[aws.InternetGatewayID]))
+			Expect(backend.SubnetIDs()).To(HaveLen(6))
+			Expect(backend.NATGatewayIDs()).To(ConsistOf(output["nat_gateway_id_z0"], output["nat_gateway_id_z1"]))
+			Expect(backend.ElasticIPAllocationIDs()).To(ConsistOf(output["nat_gateway_eip_allocation_id_z0"], output["nat_gateway_eip_allocation_id_z1"]))
+			Expect(backend.VPCEndpointIDs()).To(ConsistOf(output["vpc_endpoint_s3"], output["vpc_endpoint_ec2"]))
+			Expect(backend.RoleNames()).To(ConsistOf(clusterName+"-nodes", clusterName+"-bastions"))
+			Expect(backend.RolePolicies(clusterName + "-nodes")).To(HaveKeyWithValue(clusterName+"-nodes", nodesPolicyDocument))
+			Expect(backend.InstanceProfileNames()).To(ConsistOf(output[aws.IAMInstanceProfileNodes], output[aws.IAMInstanceProfileBastions]))
+			Expect(backend.KeyPairNames()).To(ConsistOf(clusterName + "-ssh-publickey"))
+
+			Expect(backend.Tags(output[aws.VPCIDKey])).To(Equal(map[string]string{"Name": clusterName, clusterTag: "1", "cost-center": "1234"}))
+			Expect(backend.Tags(output["subnet_public_utility_z1"])).To(Equal(map[string]string{
+				"Name":                   clusterName + "-public-utility-z1",
+				clusterTag:               "1",
+				"cost-center":            "1234",
+				"kubernetes.io/role/elb": "use",
+			}))
+			Expect(backend.Tags(output["route_table_private_utility_z0"])).To(HaveKeyWithValue("Name", clusterName+"-private-eu-west-1a"))
+		})
+
+		It("should not create or change anything if the infrastructure is up to date", func() {
+			output := reconcile()
+			before := creations()
+
+			Expect(reconcile()).To(Equal(output))
+			Expect(creations()).To(Equal(before))
+		})
+
+		It("should adopt the resources created by Terraform", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", map[string]string{"Name": clusterName, clusterTag: "1"})
+			igwID := backend.CreateInternetGateway(vpcID, map[string]string{"Name": clusterName, clusterTag: "1"})
+			subnetID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.0.0/19", map[string]string{"Name": clusterName + "-nodes-z0", clusterTag: "1"})
+
+			output := reconcile()
+
+			Expect(output).To(HaveKeyWithValue(aws.VPCIDKey, vpcID))
+			Expect(output).To(HaveKeyWithValue(aws.InternetGatewayID, igwID))
+			Expect(output).To(HaveKeyWithValue("subnet_nodes_z0", subnetID))
+			Expect(backend.Calls("CreateVpc")).To(BeZero())
+			Expect(backend.Calls("CreateInternetGateway")).To(BeZero())
+			Expect(backend.Tags(vpcID)).To(HaveKeyWithValue("cost-center", "1234"))
+		})
+
+		It("should fail if the CIDR of an existing subnet differs", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", map[string]string{"Name": clusterName, clusterTag: "1"})
+			backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.64.0/19", map[string]string{"Name": clusterName + "-nodes-z0", clusterTag: "1"})
+
+			_, err := newReconciler().reconcile(ctx)
+			Expect(err).To(MatchError(ContainSubstring("the CIDR of a subnet cannot be changed")))
+		})
+
+		It("should delete the NAT gateways and Elastic IPs that are no longer used", func() {
+			output := reconcile()
+
+			infrastructureConfig.Networks.NATGateway = &awsapi.NATGateway{Mode: awsapi.NATGatewayModeSingle}
+			singleOutput := reconcile()
+
+			Expect(singleOutput).NotTo(HaveKey("nat_gateway_id_z1"))
+			Expect(backend.NATGatewayIDs()).To(ConsistOf(output["nat_gateway_id_z0"]))
+			Expect(backend.ElasticIPAllocationIDs()).To(ConsistOf(output["nat_gateway_eip_allocation_id_z0"]))
+			Expect(backend.Calls("ReplaceRoute")).To(Equal(1))
+		})
+
+		It("should use the referenced Elastic IP", func() {
+			allocationID := backend.CreateElasticIP(nil)
+			infrastructureConfig.Networks.Zones[1].ElasticIPAllocationID = &allocationID
+
+			output := reconcile()
+
+			Expect(output).To(HaveKeyWithValue("nat_gateway_eip_allocation_id_z1", allocationID))
+			Expect(backend.ElasticIPAllocationIDs()).To(ConsistOf(output["nat_gateway_eip_allocation_id_z0"], allocationID))
+		})
+
+		It("should add the custom ingress rules to the security group of the nodes", func() {
+			sourceGroupID := backend.CreateSecurityGroup(backend.CreateVPC("10.251.0.0/16", nil), "source", nil)
+			infrastructureConfig.Networks.IngressRules = []awsapi.IngressRule{{
+				Protocol:         "tcp",
+				FromPort:         30000,
+				ToPort:           32767,
+				CIDRs:            []gardencore.CIDR{"10.0.0.0/8"},
+				SecurityGroupIDs: []string{sourceGroupID},
+			}}
+
+			output := reconcile()
+
+			groups, err := awsClient.FindSecurityGroups(ctx, output[aws.VPCIDKey], map[string]string{"Name": clusterName + "-nodes"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0].Rules).To(ContainElement(awsclient.SecurityGroupRule{Type: awsclient.SecurityGroupRuleTypeIngress, Protocol: "tcp", FromPort: 30000, ToPort: 32767, CIDR: "10.0.0.0/8", Description: managedSecurityGroupRuleDescription}))
+			Expect(groups[0].Rules).To(ContainElement(awsclient.SecurityGroupRule{Type: awsclient.SecurityGroupRuleTypeIngress, Protocol: "tcp", FromPort: 30000, ToPort: 32767, SecurityGroupID: sourceGroupID, Description: managedSecurityGroupRuleDescription}))
+		})
+
+		It("should revoke the custom ingress rules that are no longer desired and keep the rules of others", func() {
+			infrastructureConfig.Networks.IngressRules = []awsapi.IngressRule{
+				{Protocol: "tcp", FromPort: 8080, ToPort: 8080, CIDRs: []gardencore.CIDR{"10.0.0.0/8"}},
+				{Protocol: "tcp", FromPort: 9090, ToPort: 9090, CIDRs: []gardencore.CIDR{"10.0.0.0/8"}},
+			}
+			output := reconcile()
+
+			groups, err := awsClient.FindSecurityGroups(ctx, output[aws.VPCIDKey], map[string]string{"Name": clusterName + "-nodes"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			loadBalancerRule := awsclient.SecurityGroupRule{Type: awsclient.SecurityGroupRuleTypeIngress, Protocol: "tcp", FromPort: 31000, ToPort: 31000, CIDR: "192.168.0.0/24"}
+			Expect(awsClient.AuthorizeSecurityGroupRules(ctx, groups[0].ID, []awsclient.SecurityGroupRule{loadBalancerRule})).To(Succeed())
+
+			infrastructureConfig.Networks.IngressRules = infrastructureConfig.Networks.IngressRules[:1]
+			reconcile()
+
+			groups, err = awsClient.FindSecurityGroups(ctx, output[aws.VPCIDKey], map[string]string{"Name": clusterName + "-nodes"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0].Rules).To(ContainElement(awsclient.SecurityGroupRule{Type: awsclient.SecurityGroupRuleTypeIngress, Protocol: "tcp", FromPort: 8080, ToPort: 8080, CIDR: "10.0.0.0/8", Description: managedSecurityGroupRuleDescription}))
+			Expect(groups[0].Rules).NotTo(ContainElement(awsclient.SecurityGroupRule{Type: awsclient.SecurityGroupRuleTypeIngress, Protocol: "tcp", FromPort: 9090, ToPort: 9090, CIDR: "10.0.0.0/8", Description: managedSecurityGroupRuleDescription}))
+			Expect(groups[0].Rules).To(ContainElement(loadBalancerRule))
+			Expect(backend.Calls("RevokeSecurityGroupIngress")).To(Equal(1))
+		})
+
+		Context("VPC peerings", func() {
+			var (
+				peerVPCID string
+
+				privateRoutes = func(vpcID string) []awsclient.Route {
+					routeTables, err := awsClient.FindRouteTables(ctx, vpcID, map[string]string{"Name": clusterName + "-private-eu-west-1a"})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(routeTables).To(HaveLen(1))
+					return routeTables[0].Routes
+				}
+			)
+
+			BeforeEach(func() {
+				peerVPCID = backend.CreateVPC("10.0.0.0/16", nil)
+				infrastructureConfig.Networks.Peerings = []awsapi.VPCPeering{{Name: "shared", VPCID: peerVPCID, CIDRs: []gardencore.CIDR{"10.0.0.0/16"}}}
+			})
+
+			It("should request, accept and route a VPC peering connection to the same account", func() {
+				output := reconcile()
+
+				id := output["vpc_peering_connection_shared"]
+				Expect(backend.VPCPeeringConnectionIDs()).To(ConsistOf(id))
+				connections, err := awsClient.FindVPCPeeringConnections(ctx, output[aws.VPCIDKey], nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(connections).To(HaveLen(1))
+				Expect(connections[0].State).To(Equal("active"))
+				Expect(connections[0].Tags).To(HaveKeyWithValue("Name", clusterName+"-peering-shared"))
+				Expect(privateRoutes(output[aws.VPCIDKey])).To(ContainElement(awsclient.Route{DestinationCIDR: "10.0.0.0/16", VPCPeeringConnectionID: id}))
+				Expect(regionalClients).To(BeEmpty())
+
+				before := creations()
+				Expect(reconcile()).To(Equal(output))
+				Expect(creations()).To(Equal(before))
+			})
+
+			It("should only request a VPC peering connection to another account", func() {
+				ownerID := "210987654321"
+				infrastructureConfig.Networks.Peerings[0].OwnerID = &ownerID
+
+				output := reconcile()
+
+				connections, err := awsClient.FindVPCPeeringConnections(ctx, output[aws.VPCIDKey], nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(connections).To(HaveLen(1))
+				Expect(connections[0].PeerOwnerID).To(Equal(ownerID))
+				Expect(connections[0].State).To(Equal("pending-acceptance"))
+				Expect(backend.Calls("AcceptVpcPeeringConnection")).To(BeZero())
+			})
+
+			It("should accept a VPC peering connection to another region with a client of that region", func() {
+				region := "eu-central-1"
+				infrastructureConfig.Networks.Peerings[0].Region = &region
+
+				reconcile()
+
+				Expect(regionalClients).To(Equal([]string{region}))
+				Expect(backend.Calls("AcceptVpcPeeringConnection")).To(Equal(1))
+			})
+
+			It("should replace a VPC peering connection to another VPC and delete its routes", func() {
+				output := reconcile()
+
+				otherVPCID := backend.CreateVPC("10.1.0.0/16", nil)
+				infrastructureConfig.Networks.Peerings[0].VPCID = otherVPCID
+				infrastructureConfig.Networks.Peerings[0].CIDRs = []gardencore.CIDR{"10.1.0.0/16"}
+				newOutput := reconcile()
+
+				id := newOutput["vpc_peering_connection_shared"]
+				Expect(id).NotTo(Equal(output["vpc_peering_connection_shared"]))
+				Expect(backend.VPCPeeringConnectionIDs()).To(ConsistOf(id))
+				routes := privateRoutes(output[aws.VPCIDKey])
+				Expect(routes).To(ContainElement(awsclient.Route{DestinationCIDR: "10.1.0.0/16", VPCPeeringConnectionID: id}))
+				for _, route := range routes {
+					Expect(route.DestinationCIDR).NotTo(Equal("10.0.0.0/16"))
+				}
+			})
+
+			It("should delete the VPC peering connections that are no longer configured together with their routes", func() {
+				output := reconcile()
+
+				infrastructureConfig.Networks.Peerings = nil
+				Expect(reconcile()).NotTo(HaveKey("vpc_peering_connection_shared"))
+
+				Expect(backend.VPCPeeringConnectionIDs()).To(BeEmpty())
+				for _, route := range privateRoutes(output[aws.VPCIDKey]) {
+					Expect(route.VPCPeeringConnectionID).To(BeEmpty())
+				}
+			})
+		})
+
+		It("should replace the key pair if the public key changed", func() {
+			reconcile()
+
+			infrastructure.Spec.SSHPublicKey = []byte("ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAg== foo@bar")
+			reconcile()
+
+			Expect(backend.Calls("DeleteKeyPair")).To(Equal(1))
+			Expect(backend.Calls("ImportKeyPair")).To(Equal(2))
+		})
+
+		It("should only create the security groups, IAM resources and key pair for existing subnets", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			nodesID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.0.0/19", nil)
+			publicID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.96.0/22", nil)
+			internalID := backend.CreateSubnet(vpcID, "eu-west-1a", "10.250.112.0/22", nil)
+			infrastructureConfig.Networks.VPC = awsapi.VPC{ID: &vpcID, SubnetMode: awsapi.SubnetModeExisting}
+			infrastructureConfig.Networks.Zones = []awsapi.Zone{{Name: "eu-west-1a", Subnets: &awsapi.ZoneSubnets{Workers: &nodesID, Public: &publicID, Internal: &internalID}}}
+
+			output := reconcile()
+
+			keys, err := providerStatusOutputKeys(infrastructureConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HaveLen(len(keys)))
+			Expect(output).To(HaveKeyWithValue("subnet_nodes_z0", nodesID))
+			Expect(backend.SubnetIDs()).To(ConsistOf(nodesID, publicID, internalID))
+			Expect(backend.Calls("CreateRouteTable")).To(BeZero())
+			Expect(backend.Calls("CreateNatGateway")).To(BeZero())
+			Expect(backend.SecurityGroupIDs()).To(ConsistOf(output[aws.SecurityGroupsNodes], output[aws.SecurityGroupsBastions]))
+		})
+	})
+
+	Describe("#delete", func() {
+		It("should delete all resources of the infrastructure", func() {
+			output := reconcile()
+			r := newDeletingReconciler(true)
+
+			vpcID, err := r.findVPC(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vpcID).To(Equal(output[aws.VPCIDKey]))
+
+			Expect(r.delete(ctx, vpcID, nil)).To(Succeed())
+
+			Expect(backend.VPCIDs()).To(BeEmpty())
+			Expect(backend.DHCPOptionsIDs()).To(BeEmpty())
+			Expect(backend.InternetGatewayIDs()).To(BeEmpty())
+			Expect(backend.SubnetIDs()).To(BeEmpty())
+			Expect(backend.RouteTableIDs()).To(BeEmpty())
+			Expect(backend.SecurityGroupIDs()).To(BeEmpty())
+			Expect(backend.NATGatewayIDs()).To(BeEmpty())
+			Expect(backend.ElasticIPAllocationIDs()).To(BeEmpty())
+			Expect(backend.VPCEndpointIDs()).To(BeEmpty())
+			Expect(backend.RoleNames()).To(BeEmpty())
+			Expect(backend.InstanceProfileNames()).To(BeEmpty())
+			Expect(backend.KeyPairNames()).To(BeEmpty())
+
+			vpcID, err = r.findVPC(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vpcID).To(BeEmpty())
+			Expect(r.delete(ctx, vpcID, nil)).To(Succeed())
+		})
+
+		It("should delete the VPC peering connections", func() {
+			infrastructureConfig.Networks.Peerings = []awsapi.VPCPeering{{Name: "shared", VPCID: backend.CreateVPC("10.0.0.0/16", nil), CIDRs: []gardencore.CIDR{"10.0.0.0/16"}}}
+			output := reconcile()
+
+			Expect(newDeletingReconciler(true).delete(ctx, output[aws.VPCIDKey], nil)).To(Succeed())
+
+			Expect(backend.VPCPeeringConnectionIDs()).To(BeEmpty())
+			Expect(backend.VPCIDs()).NotTo(ContainElement(output[aws.VPCIDKey]))
+		})
+
+		It("should keep an existing VPC and its foreign resources", func() {
+			vpcID := backend.CreateVPC("10.250.0.0/16", nil)
+			igwID := backend.CreateInternetGateway(vpcID, nil)
+			foreignSubnetID := backend.CreateSubnet(vpcID, "eu-west-1c", "10.250.200.0/24", nil)
+			foreignAllocationID := backend.CreateElasticIP(nil)
+			infrastructureConfig.Networks.VPC = awsapi.VPC{ID: &vpcID, Endpoints: infrastructureConfig.Networks.VPC.Endpoints}
+			infrastructureConfig.Networks.Zones[0].ElasticIPAllocationID = &foreignAllocationID
+			routeTables := len(backend.RouteTableIDs())
+
+			foreignEndpointID, err := awsClient.CreateVPCEndpoint(ctx, awsclient.VPCEndpoint{VPCID: vpcID, ServiceName: "com.amazonaws.eu-west-1.dynamodb", Type: "Gateway"})
+			Expect(err).NotTo(HaveOccurred())
+
+			output := reconcile()
+			Expect(newDeletingReconciler(false).delete(ctx, vpcID, []string{output[aws.VPCEndpointPrefix+"s3"], output[aws.VPCEndpointPrefix+"ec2"]})).To(Succeed())
+
+			Expect(backend.VPCIDs()).To(ConsistOf(vpcID))
+			Expect(backend.VPCEndpointIDs()).To(ConsistOf(foreignEndpointID))
+			Expect(backend.InternetGatewayIDs()).To(ConsistOf(igwID))
+			Expect(backend.SubnetIDs()).To(ConsistOf(foreignSubnetID))
+			Expect(backend.ElasticIPAllocationIDs()).To(ConsistOf(foreignAllocationID))
+			Expect(backend.RouteTableIDs()).To(HaveLen(routeTables))
+			Expect(backend.SecurityGroupIDs()).To(BeEmpty())
+		})
+	})
+})
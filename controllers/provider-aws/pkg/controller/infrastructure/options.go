@@ -0,0 +1,166 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"fmt"
+
+	awsapi "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// FlagPrefix is the prefix of the command line flags of the infrastructure controller.
+	FlagPrefix = "infrastructure-"
+	// DeleteVolumesFlag is the name of the command line flag to specify whether orphaned EBS volumes are deleted.
+	DeleteVolumesFlag = "volume-cleanup-delete-volumes"
+	// DeleteSnapshotsFlag is the name of the command line flag to specify whether orphaned EBS snapshots are deleted.
+	DeleteSnapshotsFlag = "volume-cleanup-delete-snapshots"
+	// VolumeCleanupDryRunFlag is the name of the command line flag to specify whether orphaned EBS volumes and
+	// snapshots are only reported instead of being deleted.
+	VolumeCleanupDryRunFlag = "volume-cleanup-dry-run"
+	// DefaultTagsFlag is the name of the command line flag to specify the tags added to the AWS resources of every
+	// shoot.
+	DefaultTagsFlag = "default-tags"
+	// VPCQuotaFlag is the name of the command line flag to specify the maximum number of VPCs per region.
+	VPCQuotaFlag = "vpc-quota"
+
+	// DefaultVPCQuota is the default maximum number of VPCs per region of AWS accounts.
+	DefaultVPCQuota = 5
+)
+
+// VolumeCleanupOptions are command line options for the default cleanup of EBS volumes and snapshots.
+type VolumeCleanupOptions struct {
+	// DeleteVolumes specifies whether available EBS volumes owned by a shoot are deleted.
+	DeleteVolumes bool
+	// DeleteSnapshots specifies whether EBS snapshots owned by a shoot are deleted.
+	DeleteSnapshots bool
+	// DryRun specifies that volumes and snapshots are only reported via events instead of being deleted.
+	DryRun bool
+
+	config *VolumeCleanupConfig
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (o *VolumeCleanupOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.DeleteVolumes, DeleteVolumesFlag, o.DeleteVolumes, "Delete the available EBS volumes of a shoot when its infrastructure is deleted, unless the shoot configures otherwise.")
+	fs.BoolVar(&o.DeleteSnapshots, DeleteSnapshotsFlag, o.DeleteSnapshots, "Delete the EBS snapshots of a shoot when its infrastructure is deleted, unless the shoot configures otherwise.")
+	fs.BoolVar(&o.DryRun, VolumeCleanupDryRunFlag, o.DryRun, "Only report the EBS volumes and snapshots that would be deleted via events.")
+}
+
+// Complete implements Completer.Complete.
+func (o *VolumeCleanupOptions) Complete() error {
+	o.config = &VolumeCleanupConfig{awsapi.VolumeCleanup{
+		DeleteVolumes:   o.DeleteVolumes,
+		DeleteSnapshots: o.DeleteSnapshots,
+		DryRun:          o.DryRun,
+	}}
+	return nil
+}
+
+// Completed returns the completed VolumeCleanupConfig. Only call this if `Complete` was successful.
+func (o *VolumeCleanupOptions) Completed() *VolumeCleanupConfig {
+	return o.config
+}
+
+// VolumeCleanupConfig is a completed volume cleanup configuration.
+type VolumeCleanupConfig struct {
+	// VolumeCleanup is the default volume cleanup.
+	VolumeCleanup awsapi.VolumeCleanup
+}
+
+// Apply sets the volume cleanup of this VolumeCleanupConfig in the given VolumeCleanup.
+func (c *VolumeCleanupConfig) Apply(volumeCleanup *awsapi.VolumeCleanup) {
+	*volumeCleanup = c.VolumeCleanup
+}
+
+// TagOptions are command line options for the default tags of AWS resources.
+type TagOptions struct {
+	// DefaultTags are the tags added to the AWS resources of every shoot.
+	DefaultTags map[string]string
+
+	config *TagConfig
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (o *TagOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringToStringVar(&o.DefaultTags, DefaultTagsFlag, o.DefaultTags, "Tags added to the AWS resources of every shoot, e.g. cost-center=1234,owner=team. Tags of the shoot take precedence.")
+}
+
+// Complete implements Completer.Complete.
+func (o *TagOptions) Complete() error {
+	if err := validateTags(o.DefaultTags); err != nil {
+		return fmt.Errorf("invalid --%s%s: %v", FlagPrefix, DefaultTagsFlag, err)
+	}
+
+	o.config = &TagConfig{o.DefaultTags}
+	return nil
+}
+
+// Completed returns the completed TagConfig. Only call this if `Complete` was successful.
+func (o *TagOptions) Completed() *TagConfig {
+	return o.config
+}
+
+// TagConfig is a completed tag configuration.
+type TagConfig struct {
+	// DefaultTags are the tags added to the AWS resources of every shoot.
+	DefaultTags map[string]string
+}
+
+// Apply sets the default tags of this TagConfig in the given map.
+func (c *TagConfig) Apply(defaultTags *map[string]string) {
+	*defaultTags = c.DefaultTags
+}
+
+// QuotaOptions are command line options for the quotas of the AWS accounts that cannot be read via the EC2 API.
+type QuotaOptions struct {
+	// VPCQuota is the maximum number of VPCs per region.
+	VPCQuota int
+
+	config *QuotaConfig
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (o *QuotaOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&o.VPCQuota, VPCQuotaFlag, o.VPCQuota, "The maximum number of VPCs per region of the AWS accounts, checked before the VPC of a shoot is created. 0 disables the check.")
+}
+
+// Complete implements Completer.Complete.
+func (o *QuotaOptions) Complete() error {
+	if o.VPCQuota < 0 {
+		return fmt.Errorf("--%s%s must not be negative", FlagPrefix, VPCQuotaFlag)
+	}
+
+	o.config = &QuotaConfig{o.VPCQuota}
+	return nil
+}
+
+// Completed returns the completed QuotaConfig. Only call this if `Complete` was successful.
+func (o *QuotaOptions) Completed() *QuotaConfig {
+	return o.config
+}
+
+// QuotaConfig is a completed quota configuration.
+type QuotaConfig struct {
+	// VPCQuota is the maximum number of VPCs per region.
+	VPCQuota int
+}
+
+// Apply sets the VPC quota of this QuotaConfig in the given int.
+func (c *QuotaConfig) Apply(vpcQuota *int) {
+	*vpcQuota = c.VPCQuota
+}
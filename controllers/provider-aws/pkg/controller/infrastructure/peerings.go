@@ -0,0 +1,147 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+
+	awsapi "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var (
+	vpcPeeringNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	awsAccountIDRegex   = regexp.MustCompile(`^[0-9]{12}$`)
+	awsRegionRegex      = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]+$`)
+	vpcIDRegex          = regexp.MustCompile(`^vpc-[0-9a-f]+$`)
+)
+
+// vpcPeering is a validated VPC peering of an infrastructure configuration.
+type vpcPeering struct {
+	name  string
+	vpcID string
+	// ownerID is empty if the other VPC belongs to the account of the shoot, region is empty if the other VPC is in
+	// the region of the shoot.
+	ownerID string
+	region  string
+	cidrs   []string
+	// accept reports whether the peering connection is accepted, i.e. whether both VPCs belong to the same account.
+	accept bool
+}
+
+// validateVPCPeerings checks that the given VPC peerings have unique names, reference a VPC and route CIDRs that
+// neither overlap with each other nor with the given <vpcCIDR>, if it is not empty.
+func validateVPCPeerings(peerings []awsapi.VPCPeering, vpcCIDR string) error {
+	type network struct {
+		owner string
+		ipNet *net.IPNet
+	}
+	var (
+		names    = sets.NewString()
+		networks []network
+	)
+	if vpcCIDR != "" {
+		_, ipNet, err := net.ParseCIDR(vpcCIDR)
+		if err != nil {
+			return fmt.Errorf("invalid VPC CIDR %q", vpcCIDR)
+		}
+		networks = append(networks, network{"the VPC", ipNet})
+	}
+
+	for i, peering := range peerings {
+		if !vpcPeeringNameRegex.MatchString(peering.Name) {
+			return fmt.Errorf("VPC peering %d: invalid name %q, it must be a DNS label", i, peering.Name)
+		}
+		if names.Has(peering.Name) {
+			return fmt.Errorf("VPC peering %d: duplicate name %q", i, peering.Name)
+		}
+		names.Insert(peering.Name)
+
+		if !vpcIDRegex.MatchString(peering.VPCID) {
+			return fmt.Errorf("VPC peering %s: invalid VPC id %q", peering.Name, peering.VPCID)
+		}
+		if peering.OwnerID != nil && !awsAccountIDRegex.MatchString(*peering.OwnerID) {
+			return fmt.Errorf("VPC peering %s: invalid owner id %q, it must be an AWS account id", peering.Name, *peering.OwnerID)
+		}
+		if peering.Region != nil && !awsRegionRegex.MatchString(*peering.Region) {
+			return fmt.Errorf("VPC peering %s: invalid region %q", peering.Name, *peering.Region)
+		}
+
+		if len(peering.CIDRs) == 0 {
+			return fmt.Errorf("VPC peering %s: at least one CIDR is required", peering.Name)
+		}
+		for _, cidr := range peering.CIDRs {
+			ip, ipNet, err := net.ParseCIDR(string(cidr))
+			if err != nil || ip.To4() == nil {
+				return fmt.Errorf("VPC peering %s: invalid IPv4 CIDR %q", peering.Name, cidr)
+			}
+			if ones, _ := ipNet.Mask.Size(); ones == 0 {
+				return fmt.Errorf("VPC peering %s: CIDR %s would replace the default route", peering.Name, cidr)
+			}
+			for _, other := range networks {
+				if other.ipNet.Contains(ipNet.IP) || ipNet.Contains(other.ipNet.IP) {
+					return fmt.Errorf("VPC peering %s: CIDR %s overlaps with %s of %s", peering.Name, cidr, other.ipNet, other.owner)
+				}
+			}
+			networks = append(networks, network{"VPC peering " + peering.Name, ipNet})
+		}
+	}
+
+	return nil
+}
+
+// vpcPeeringsOf returns the given validated VPC <peerings> of a shoot in the given <region>. The account of the shoot
+// is only looked up if the owner of a peered VPC is configured.
+func vpcPeeringsOf(ctx context.Context, awsClient client.Interface, peerings []awsapi.VPCPeering, region string) ([]vpcPeering, error) {
+	var (
+		result    []vpcPeering
+		accountID string
+	)
+	for _, peering := range peerings {
+		p := vpcPeering{
+			name:   peering.Name,
+			vpcID:  peering.VPCID,
+			accept: true,
+		}
+
+		if peering.OwnerID != nil {
+			if accountID == "" {
+				id, err := awsClient.GetAccountID(ctx)
+				if err != nil {
+					return nil, err
+				}
+				accountID = id
+			}
+			if *peering.OwnerID != accountID {
+				p.ownerID = *peering.OwnerID
+				p.accept = false
+			}
+		}
+		if peering.Region != nil && *peering.Region != region {
+			p.region = *peering.Region
+		}
+		for _, cidr := range peering.CIDRs {
+			p.cidrs = append(p.cidrs, string(cidr))
+		}
+
+		result = append(result, p)
+	}
+	return result, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+
+	awsapi "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client/fake"
+
+	gardencore "github.com/gardener/gardener/pkg/apis/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("VPC peerings", func() {
+	var (
+		ownerID = "210987654321"
+		region  = "eu-central-1"
+	)
+
+	Describe("#validateVPCPeerings", func() {
+		DescribeTable("should allow valid peerings",
+			func(peering awsapi.VPCPeering) {
+				Expect(validateVPCPeerings([]awsapi.VPCPeering{peering}, "10.250.0.0/16")).To(Succeed())
+			},
+			Entry("peering in the same account and region", awsapi.VPCPeering{Name: "shared-services", VPCID: "vpc-12345", CIDRs: []gardencore.CIDR{"10.0.0.0/16"}}),
+			Entry("peering to another account and region", awsapi.VPCPeering{Name: "on-prem", VPCID: "vpc-12345", OwnerID: &ownerID, Region: &region, CIDRs: []gardencore.CIDR{"10.0.0.0/16", "192.168.0.0/24"}}),
+		)
+
+		DescribeTable("should reject invalid peerings",
+			func(peering awsapi.VPCPeering, message string) {
+				Expect(validateVPCPeerings([]awsapi.VPCPeering{peering}, "10.250.0.0/16")).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("invalid name", awsapi.VPCPeering{Name: "Shared_Services", VPCID: "vpc-12345", CIDRs: []gardencore.CIDR{"10.0.0.0/16"}}, "must be a DNS label"),
+			Entry("invalid VPC id", awsapi.VPCPeering{Name: "shared", VPCID: "12345", CIDRs: []gardencore.CIDR{"10.0.0.0/16"}}, "invalid VPC id"),
+			Entry("VPC id with Terraform", awsapi.VPCPeering{Name: "shared", VPCID: "vpc-1\"\nresource \"foo\" \"bar\" {}", CIDRs: []gardencore.CIDR{"10.0.0.0/16"}}, "invalid VPC id"),
+			Entry("invalid owner id", awsapi.VPCPeering{Name: "shared", VPCID: "vpc-12345", OwnerID: &region, CIDRs: []gardencore.CIDR{"10.0.0.0/16"}}, "it must be an AWS account id"),
+			Entry("invalid region", awsapi.VPCPeering{Name: "shared", VPCID: "vpc-12345", Region: &ownerID, CIDRs: []gardencore.CIDR{"10.0.0.0/16"}}, "invalid region"),
+			Entry("no CIDR", awsapi.VPCPeering{Name: "shared", VPCID: "vpc-12345"}, "at least one CIDR"),
+			Entry("IPv6 CIDR", awsapi.VPCPeering{Name: "shared", VPCID: "vpc-12345", CIDRs: []gardencore.CIDR{"fd00::/8"}}, "invalid IPv4 CIDR"),
+			Entry("default route", awsapi.VPCPeering{Name: "shared", VPCID: "vpc-12345", CIDRs: []gardencore.CIDR{"0.0.0.0/0"}}, "would replace the default route"),
+			Entry("CIDR overlapping with the VPC", awsapi.VPCPeering{Name: "shared", VPCID: "vpc-12345", CIDRs: []gardencore.CIDR{"10.0.0.0/8"}}, "overlaps with 10.250.0.0/16 of the VPC"),
+		)
+
+		It("should reject duplicate names and overlapping CIDRs of different peerings", func() {
+			Expect(validateVPCPeerings([]awsapi.VPCPeering{
+				{Name: "shared", VPCID: "vpc-12345", CIDRs: []gardencore.CIDR{"10.0.0.0/16"}},
+				{Name: "shared", VPCID: "vpc-67890", CIDRs: []gardencore.CIDR{"10.1.0.0/16"}},
+			}, "")).To(MatchError(`VPC peering 1: duplicate name "shared"`))
+			Expect(validateVPCPeerings([]awsapi.VPCPeering{
+				{Name: "shared", VPCID: "vpc-12345", CIDRs: []gardencore.CIDR{"10.0.0.0/16"}},
+				{Name: "other", VPCID: "vpc-67890", CIDRs: []gardencore.CIDR{"10.0.128.0/17"}},
+			}, "")).To(MatchError("VPC peering other: CIDR 10.0.128.0/17 overlaps with 10.0.0.0/16 of VPC peering shared"))
+		})
+	})
+
+	Describe("#vpcPeeringsOf", func() {
+		var backend *fake.Backend
+
+		BeforeEach(func() {
+			backend = fake.NewBackend()
+		})
+
+		It("should accept peerings in the account of the shoot and only report another owner and region", func() {
+			sameOwnerID := fake.DefaultAccountID
+			sameRegion := "eu-west-1"
+
+			peerings, err := vpcPeeringsOf(context.TODO(), fake.NewClient(backend), []awsapi.VPCPeering{
+				{Name: "default", VPCID: "vpc-1", CIDRs: []gardencore.CIDR{"10.0.0.0/16"}},
+				{Name: "same", VPCID: "vpc-2", OwnerID: &sameOwnerID, Region: &sameRegion, CIDRs: []gardencore.CIDR{"10.1.0.0/16"}},
+				{Name: "other", VPCID: "vpc-3", OwnerID: &ownerID, Region: &region, CIDRs: []gardencore.CIDR{"10.2.0.0/16"}},
+			}, "eu-west-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(peerings).To(Equal([]vpcPeering{
+				{name: "default", vpcID: "vpc-1", cidrs: []string{"10.0.0.0/16"}, accept: true},
+				{name: "same", vpcID: "vpc-2", cidrs: []string{"10.1.0.0/16"}, accept: true},
+				{name: "other", vpcID: "vpc-3", ownerID: ownerID, region: region, cidrs: []string{"10.2.0.0/16"}},
+			}))
+			Expect(backend.Calls("GetCallerIdentity")).To(Equal(1))
+		})
+
+		It("should not look up the account without configured owners", func() {
+			_, err := vpcPeeringsOf(context.TODO(), fake.NewClient(backend), []awsapi.VPCPeering{{Name: "default", VPCID: "vpc-1"}}, "eu-west-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backend.Calls("GetCallerIdentity")).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,329 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	awsapi "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	gardencorev1alpha1helper "github.com/gardener/gardener/pkg/apis/core/v1alpha1/helper"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/hashicorp/go-multierror"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// maxElasticIPsAttribute is the EC2 account attribute containing the maximum number of Elastic IPs in the region.
+	maxElasticIPsAttribute = "vpc-max-elastic-ips"
+	// maxSecurityGroupsPerInterfaceAttribute is the EC2 account attribute containing the maximum number of security
+	// groups per network interface.
+	maxSecurityGroupsPerInterfaceAttribute = "vpc-max-security-groups-per-interface"
+	// maxSecurityGroupRulesPerInterface is the maximum product of the rules per security group and the security groups
+	// per network interface that AWS allows.
+	maxSecurityGroupRulesPerInterface = 1000
+
+	// nodesIngressRules is the number of ingress rules of the nodes security group independent of the zones.
+	nodesIngressRules = 4
+	// nodesIngressRulesPerZone is the number of ingress rules of the nodes security group per zone.
+	nodesIngressRulesPerZone = 4
+)
+
+// unauthorizedErrorCodes are the codes of AWS errors that mean that the credentials are invalid or not allowed to
+// identify themselves.
+var unauthorizedErrorCodes = sets.NewString(
+	"AccessDenied",
+	"AccessDeniedException",
+	"AuthFailure",
+	"ExpiredToken",
+	"ExpiredTokenException",
+	"IncompleteSignature",
+	"InvalidClientTokenId",
+	"InvalidIdentityToken",
+	"SignatureDoesNotMatch",
+	"UnrecognizedClientException",
+)
+
+// preflightCheck checks the credentials of the cloud provider secret, the permissions of their identity and the
+// VPC, Elastic IP and security group quotas before any resource is created. Like this, these problems fail the
+// reconciliation with a classified error code right away instead of after a failed Terraform run.
+func (a *actuator) preflightCheck(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *awsapi.InfrastructureConfig, awsClient client.Interface) error {
+	logger := extensionscontroller.LoggerFromContext(ctx, a.logger)
+
+	callerARN, err := awsClient.GetCallerARN(ctx)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && unauthorizedErrorCodes.Has(aerr.Code()) {
+			return gardencorev1alpha1helper.NewErrorWithCode(gardencorev1alpha1.ErrorInfraUnauthorized, fmt.Sprintf("the AWS credentials are invalid: %v", err))
+		}
+		return fmt.Errorf("could not validate the AWS credentials: %+v", err)
+	}
+
+	values, err := computeInfrastructureValues(ctx, infrastructure, infrastructureConfig, awsClient, a.defaultTags)
+	if err != nil {
+		return err
+	}
+
+	// Errors without a code mean that a check is not possible, e.g. because the identity may not simulate its policies.
+	var errs []error
+	for _, check := range []struct {
+		name string
+		fn   func() error
+	}{
+		{"AWS permissions", func() error { return checkPermissions(ctx, awsClient, callerARN, values) }},
+		{"VPC quota", func() error { return checkVPCQuota(ctx, awsClient, values, a.vpcQuota) }},
+		{"Elastic IP quota", func() error { return checkElasticIPQuota(ctx, awsClient, values) }},
+		{"security group rule quota", func() error { return checkSecurityGroupRuleQuota(ctx, awsClient, values) }},
+	} {
+		if err := check.fn(); err != nil {
+			if _, ok := err.(gardencorev1alpha1helper.Coder); !ok {
+				logger.Info(fmt.Sprintf("Skipping the pre-flight check of the %s", check.name), "reason", err.Error())
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	switch len(errs) {
+	case 0:
+	case 1:
+		return errs[0]
+	default:
+		return &multierror.Error{Errors: errs}
+	}
+
+	logger.Info("The AWS credentials are valid and sufficient for the infrastructure", "principal", callerARN)
+	return nil
+}
+
+// checkPermissions simulates the policies of the identity with the given <callerARN> for the actions needed to
+// create the infrastructure. It returns an error with code ErrorInfraInsufficientPrivileges if an action is not
+// allowed, and other errors if the simulation is not possible, e.g. because the identity may not simulate its policies.
+func checkPermissions(ctx context.Context, awsClient client.Interface, callerARN string, values *infrastructureValues) error {
+	principalARN, err := iamPrincipalARN(callerARN)
+	if err != nil {
+		return err
+	}
+
+	denied, err := awsClient.SimulatePrincipalPolicy(ctx, principalARN, requiredActions(values))
+	if err != nil {
+		return err
+	}
+	if len(denied) > 0 {
+		return gardencorev1alpha1helper.NewErrorWithCode(gardencorev1alpha1.ErrorInfraInsufficientPrivileges,
+			fmt.Sprintf("the AWS identity %s is not allowed to perform the actions %s", callerARN, strings.Join(denied, ", ")))
+	}
+	return nil
+}
+
+// iamPrincipalARN returns the ARN of the IAM user or role whose policies apply to the caller with the given ARN.
+// The policies of the root user and of federated users cannot be simulated.
+func iamPrincipalARN(callerARN string) (string, error) {
+	// arn:<partition>:<service>::<account>:<resource>
+	parts := strings.SplitN(callerARN, ":", 6)
+	if len(parts) != 6 {
+		return "", fmt.Errorf("invalid caller ARN %q", callerARN)
+	}
+	partition, service, account, resource := parts[1], parts[2], parts[4], parts[5]
+
+	switch {
+	case service == "iam" && strings.HasPrefix(resource, "user/"):
+		return callerARN, nil
+	case service == "sts" && strings.HasPrefix(resource, "assumed-role/"):
+		// assumed-role/<role name>/<session name>
+		roleName := strings.Split(strings.TrimPrefix(resource, "assumed-role/"), "/")[0]
+		return fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, account, roleName), nil
+	default:
+		return "", fmt.Errorf("the policies of the caller %q cannot be simulated", callerARN)
+	}
+}
+
+// requiredActions returns the AWS actions needed to create the infrastructure described by the given values.
+func requiredActions(values *infrastructureValues) []string {
+	actions := []string{
+		"ec2:CreateSecurityGroup",
+		"ec2:AuthorizeSecurityGroupIngress",
+		"ec2:AuthorizeSecurityGroupEgress",
+		"ec2:CreateTags",
+		"ec2:ImportKeyPair",
+		"iam:CreateRole",
+		"iam:PutRolePolicy",
+		"iam:CreateInstanceProfile",
+		"iam:AddRoleToInstanceProfile",
+		"iam:PassRole",
+		"iam:CreateUser",
+		"iam:PutUserPolicy",
+		"iam:CreateAccessKey",
+	}
+	if values.createVPC {
+		actions = append(actions,
+			"ec2:CreateVpc",
+			"ec2:CreateDhcpOptions",
+			"ec2:AssociateDhcpOptions",
+			"ec2:CreateInternetGateway",
+			"ec2:AttachInternetGateway",
+		)
+	}
+	if values.createSubnets {
+		actions = append(actions,
+			"ec2:CreateSubnet",
+			"ec2:CreateRouteTable",
+			"ec2:CreateRoute",
+			"ec2:AssociateRouteTable",
+			"ec2:CreateNatGateway",
+		)
+		if newElasticIPs(values) > 0 {
+			actions = append(actions, "ec2:AllocateAddress")
+		}
+	}
+	if len(values.vpcEndpoints) > 0 {
+		actions = append(actions, "ec2:CreateVpcEndpoint")
+	}
+	if len(values.vpcPeerings) > 0 {
+		actions = append(actions, "ec2:CreateVpcPeeringConnection", "ec2:AcceptVpcPeeringConnection")
+	}
+	return actions
+}
+
+// newElasticIPs returns the number of Elastic IPs allocated for the NAT gateways of the infrastructure, i.e. of the
+// NAT gateways whose zone does not specify an existing Elastic IP.
+func newElasticIPs(values *infrastructureValues) int {
+	if !values.createSubnets {
+		return 0
+	}
+
+	count := 0
+	for _, zoneIndex := range values.natGatewayZoneIndices {
+		if values.zones[zoneIndex].elasticIPAllocationID == "" {
+			count++
+		}
+	}
+	return count
+}
+
+// checkElasticIPQuota compares the Elastic IPs the infrastructure still has to allocate with the maximum number of
+// Elastic IPs of the account. It returns an error with code ErrorInfraQuotaExceeded if the quota is not sufficient,
+// and other errors if the quota cannot be determined.
+func checkElasticIPQuota(ctx context.Context, awsClient client.Interface, values *infrastructureValues) error {
+	needed := newElasticIPs(values)
+	if needed == 0 {
+		return nil
+	}
+
+	owned, err := awsClient.FindElasticIPs(ctx, map[string]string{"kubernetes.io/cluster/" + values.clusterName: "1"})
+	if err != nil {
+		return err
+	}
+	missing := needed - len(owned)
+	if missing <= 0 {
+		return nil
+	}
+
+	maxElasticIPs, err := getIntAccountAttribute(ctx, awsClient, maxElasticIPsAttribute)
+	if err != nil {
+		return err
+	}
+
+	all, err := awsClient.FindElasticIPs(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if len(all)+missing > maxElasticIPs {
+		return gardencorev1alpha1helper.NewErrorWithCode(gardencorev1alpha1.ErrorInfraQuotaExceeded,
+			fmt.Sprintf("the infrastructure needs %d more Elastic IPs, but %d of the maximum %d Elastic IPs of the region %s are already allocated", missing, len(all), maxElasticIPs, values.region))
+	}
+	return nil
+}
+
+// checkVPCQuota compares the VPCs of the region with the given VPC <quota> if the infrastructure still has to create
+// its VPC. EC2 does not expose the VPC quota, hence it is configured for the extension. A quota of 0 disables the
+// check. It returns an error with code ErrorInfraQuotaExceeded if the quota is not sufficient.
+func checkVPCQuota(ctx context.Context, awsClient client.Interface, values *infrastructureValues, quota int) error {
+	if !values.createVPC || quota <= 0 {
+		return nil
+	}
+
+	owned, err := awsClient.FindVPCs(ctx, map[string]string{"kubernetes.io/cluster/" + values.clusterName: "1"})
+	if err != nil {
+		return err
+	}
+	if len(owned) > 0 {
+		return nil
+	}
+
+	all, err := awsClient.FindVPCs(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if len(all) >= quota {
+		return gardencorev1alpha1helper.NewErrorWithCode(gardencorev1alpha1.ErrorInfraQuotaExceeded,
+			fmt.Sprintf("the infrastructure needs a new VPC, but %d of the maximum %d VPCs of the region %s already exist", len(all), quota, values.region))
+	}
+	return nil
+}
+
+// ingressRulesOfNodes returns the number of ingress rules of the nodes security group. Every source of a rule counts
+// as a separate rule.
+func ingressRulesOfNodes(values *infrastructureValues) int {
+	count := nodesIngressRules + nodesIngressRulesPerZone*len(values.zones)
+	for _, rule := range values.ingressRules {
+		count += len(rule.CIDRs) + len(rule.SecurityGroupIDs)
+	}
+	return count
+}
+
+// checkSecurityGroupRuleQuota compares the ingress rules of the nodes security group with the maximum number of rules
+// per security group AWS allows for the security groups per network interface of the account. It returns an error
+// with code ErrorInfraQuotaExceeded if the nodes security group has more rules, and other errors if the quota cannot
+// be determined.
+func checkSecurityGroupRuleQuota(ctx context.Context, awsClient client.Interface, values *infrastructureValues) error {
+	maxSecurityGroups, err := getIntAccountAttribute(ctx, awsClient, maxSecurityGroupsPerInterfaceAttribute)
+	if err != nil {
+		return err
+	}
+	if maxSecurityGroups <= 0 {
+		return fmt.Errorf("invalid value of the account attribute %s: %d", maxSecurityGroupsPerInterfaceAttribute, maxSecurityGroups)
+	}
+
+	maxRules := maxSecurityGroupRulesPerInterface / maxSecurityGroups
+	if rules := ingressRulesOfNodes(values); rules > maxRules {
+		return gardencorev1alpha1helper.NewErrorWithCode(gardencorev1alpha1.ErrorInfraQuotaExceeded,
+			fmt.Sprintf("the nodes security group needs %d ingress rules, but at most %d rules per security group are possible with %d security groups per network interface", rules, maxRules, maxSecurityGroups))
+	}
+	return nil
+}
+
+// getIntAccountAttribute returns the integer value of the EC2 account attribute with the given <name>.
+func getIntAccountAttribute(ctx context.Context, awsClient client.Interface, name string) (int, error) {
+	attributes, err := awsClient.GetAccountAttributes(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	if len(attributes[name]) != 1 {
+		return 0, fmt.Errorf("the account attribute %s is not set", name)
+	}
+	value, err := strconv.Atoi(attributes[name][0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid value of the account attribute %s: %+v", name, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"context"
+	"errors"
+
+	awsapi "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client/fake"
+
+	gardencore "github.com/gardener/gardener/pkg/apis/core"
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	gardencorev1alpha1helper "github.com/gardener/gardener/pkg/apis/core/v1alpha1/helper"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var _ = Describe("Pre-flight check", func() {
+	var (
+		ctx     context.Context
+		backend *fake.Backend
+		a       *actuator
+
+		infrastructure       *extensionsv1alpha1.Infrastructure
+		infrastructureConfig *awsapi.InfrastructureConfig
+
+		clusterName = "shoot--foo--bar"
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		backend = fake.NewBackend()
+		a = &actuator{logger: log.Log, vpcQuota: DefaultVPCQuota}
+
+		cidr := gardencore.CIDR("10.250.0.0/16")
+		infrastructure = &extensionsv1alpha1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Namespace: clusterName},
+			Spec:       extensionsv1alpha1.InfrastructureSpec{Region: "eu-west-1"},
+		}
+		infrastructureConfig = &awsapi.InfrastructureConfig{
+			Networks: awsapi.Networks{
+				VPC: awsapi.VPC{CIDR: &cidr},
+				Zones: []awsapi.Zone{
+					{Name: "eu-west-1a", Workers: "10.250.0.0/19", Public: "10.250.96.0/22", Internal: "10.250.112.0/22"},
+					{Name: "eu-west-1b", Workers: "10.250.32.0/19", Public: "10.250.100.0/22", Internal: "10.250.116.0/22"},
+				},
+			},
+		}
+	})
+
+	Describe("#preflightCheck", func() {
+		It("should succeed for valid credentials with sufficient permissions and quota", func() {
+			Expect(a.preflightCheck(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend))).To(Succeed())
+			Expect(backend.Calls("SimulatePrincipalPolicy")).To(Equal(1))
+			Expect(backend.Calls("DescribeAccountAttributes")).To(Equal(2))
+		})
+
+		It("should classify rejected credentials", func() {
+			backend.InjectError("GetCallerIdentity", awserr.NewRequestFailure(awserr.New("InvalidClientTokenId", "The security token included in the request is invalid.", nil), 403, "1"))
+
+			err := a.preflightCheck(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend))
+			Expect(gardencorev1alpha1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1alpha1.ErrorInfraUnauthorized))
+		})
+
+		DescribeTable("should not classify other errors of the credential check",
+			func(err error) {
+				backend.InjectError("GetCallerIdentity", err)
+
+				err = a.preflightCheck(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend))
+				Expect(err).To(HaveOccurred())
+				Expect(gardencorev1alpha1helper.ExtractErrorCodes(err)).To(BeEmpty())
+			},
+			Entry("connection error", errors.New("connection refused")),
+			Entry("throttling", awserr.NewRequestFailure(awserr.New("Throttling", "Rate exceeded", nil), 400, "1")),
+			Entry("internal error", awserr.NewRequestFailure(awserr.New("InternalFailure", "internal error", nil), 500, "1")),
+		)
+
+		It("should report the actions that are not allowed", func() {
+			backend.DenyActions("ec2:CreateVpc", "iam:CreateRole")
+
+			err := a.preflightCheck(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend))
+			Expect(gardencorev1alpha1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1alpha1.ErrorInfraInsufficientPrivileges))
+			Expect(err.Error()).To(ContainSubstring("ec2:CreateVpc"))
+			Expect(err.Error()).To(ContainSubstring("iam:CreateRole"))
+		})
+
+		It("should skip the permission check if the policies cannot be simulated", func() {
+			backend.DenyActions("ec2:CreateVpc")
+			backend.InjectError("SimulatePrincipalPolicy", awserr.New("AccessDenied", "not authorized to perform iam:SimulatePrincipalPolicy", nil))
+
+			Expect(a.preflightCheck(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend))).To(Succeed())
+		})
+
+		It("should fail if the Elastic IPs of the NAT gateways exceed the quota", func() {
+			backend.SetAccountAttribute(maxElasticIPsAttribute, "2")
+			backend.CreateElasticIP(nil)
+
+			err := a.preflightCheck(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend))
+			Expect(gardencorev1alpha1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1alpha1.ErrorInfraQuotaExceeded))
+			Expect(err.Error()).To(ContainSubstring("needs 2 more Elastic IPs, but 1 of the maximum 2"))
+		})
+
+		It("should count the Elastic IPs already allocated for the shoot", func() {
+			backend.SetAccountAttribute(maxElasticIPsAttribute, "2")
+			backend.CreateElasticIP(map[string]string{"kubernetes.io/cluster/" + clusterName: "1"})
+			backend.CreateElasticIP(map[string]string{"kubernetes.io/cluster/" + clusterName: "1"})
+
+			backend.SetAccountAttribute(maxElasticIPsAttribute, "0")
+
+			Expect(a.preflightCheck(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend))).To(Succeed())
+		})
+
+		It("should not count the Elastic IPs specified in the zones", func() {
+			allocationID := backend.CreateElasticIP(nil)
+			backend.SetAccountAttribute(maxElasticIPsAttribute, "2")
+			infrastructureConfig.Networks.Zones[0].ElasticIPAllocationID = &allocationID
+
+			Expect(a.preflightCheck(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend))).To(Succeed())
+		})
+
+		It("should fail if the VPCs of the region exceed the quota", func() {
+			for i := 0; i < DefaultVPCQuota; i++ {
+				backend.CreateVPC("10.0.0.0/16", nil)
+			}
+
+			err := a.preflightCheck(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend))
+			Expect(gardencorev1alpha1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1alpha1.ErrorInfraQuotaExceeded))
+			Expect(err.Error()).To(ContainSubstring("needs a new VPC, but 5 of the maximum 5 VPCs"))
+		})
+
+		It("should not check the VPC quota if the VPC of the shoot already exists", func() {
+			for i := 0; i < DefaultVPCQuota-1; i++ {
+				backend.CreateVPC("10.0.0.0/16", nil)
+			}
+			backend.CreateVPC("10.250.0.0/16", map[string]string{"kubernetes.io/cluster/" + clusterName: "1"})
+
+			Expect(a.preflightCheck(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend))).To(Succeed())
+		})
+
+		It("should not check the VPC quota if it is disabled", func() {
+			a.vpcQuota = 0
+			for i := 0; i < DefaultVPCQuota; i++ {
+				backend.CreateVPC("10.0.0.0/16", nil)
+			}
+
+			Expect(a.preflightCheck(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend))).To(Succeed())
+			Expect(backend.Calls("DescribeVpcs")).To(BeZero())
+		})
+
+		It("should fail if the nodes security group exceeds the rules per security group", func() {
+			backend.SetAccountAttribute(maxSecurityGroupsPerInterfaceAttribute, "100")
+			infrastructureConfig.Networks.IngressRules = []awsapi.IngressRule{
+				{Protocol: "tcp", FromPort: 443, ToPort: 443, CIDRs: []gardencore.CIDR{"10.1.0.0/16", "10.2.0.0/16", "10.3.0.0/16"}},
+			}
+
+			err := a.preflightCheck(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend))
+			Expect(gardencorev1alpha1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1alpha1.ErrorInfraQuotaExceeded))
+			Expect(err.Error()).To(ContainSubstring("needs 15 ingress rules, but at most 10 rules per security group are possible with 100 security groups per network interface"))
+		})
+
+		It("should report all failed checks", func() {
+			backend.DenyActions("ec2:AllocateAddress")
+			backend.SetAccountAttribute(maxElasticIPsAttribute, "1")
+
+			err := a.preflightCheck(ctx, infrastructure, infrastructureConfig, fake.NewClient(backend))
+			Expect(gardencorev1alpha1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1alpha1.ErrorInfraInsufficientPrivileges, gardencorev1alpha1.ErrorInfraQuotaExceeded))
+		})
+	})
+
+	DescribeTable("#iamPrincipalARN",
+		func(callerARN, expected string, expectErr bool) {
+			principalARN, err := iamPrincipalARN(callerARN)
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(principalARN).To(Equal(expected))
+		},
+		Entry("IAM user", "arn:aws:iam::123456789012:user/gardener", "arn:aws:iam::123456789012:user/gardener", false),
+		Entry("assumed role", "arn:aws-cn:sts::123456789012:assumed-role/gardener/gardener-extension-provider-aws", "arn:aws-cn:iam::123456789012:role/gardener", false),
+		Entry("root user", "arn:aws:iam::123456789012:root", "", true),
+		Entry("federated user", "arn:aws:sts::123456789012:federated-user/gardener", "", true),
+		Entry("invalid ARN", "gardener", "", true),
+	)
+})
@@ -0,0 +1,96 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxTags is the maximum number of custom tags. AWS allows 50 tags per resource, of which up to three are
+	// managed by this controller.
+	maxTags = 47
+	// maxTagKeyLength is the maximum length of a tag key in unicode characters.
+	maxTagKeyLength = 128
+	// maxTagValueLength is the maximum length of a tag value in unicode characters.
+	maxTagValueLength = 256
+)
+
+var (
+	tagRegex = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+	// reservedTagKeyPrefixes are the prefixes of tag keys that are reserved by AWS or Kubernetes.
+	reservedTagKeyPrefixes = []string{"aws:", "kubernetes.io"}
+	// reservedTagKeys are the tag keys that are set by this controller.
+	reservedTagKeys = []string{"Name"}
+)
+
+// MergeTags returns the union of the given tags, later tags take precedence over earlier ones.
+func MergeTags(tags ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, t := range tags {
+		for key, value := range t {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// validateTags checks that the given tags adhere to the AWS tag restrictions and do not use a key reserved by
+// AWS, Kubernetes or this controller.
+func validateTags(tags map[string]string) error {
+	if len(tags) > maxTags {
+		return fmt.Errorf("at most %d tags are allowed, got %d", maxTags, len(tags))
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := tags[key]
+
+		if length := utf8.RuneCountInString(key); length == 0 || length > maxTagKeyLength {
+			return fmt.Errorf("tag key %q must be between 1 and %d characters long", key, maxTagKeyLength)
+		}
+		if utf8.RuneCountInString(value) > maxTagValueLength {
+			return fmt.Errorf("value of tag %q must be at most %d characters long", key, maxTagValueLength)
+		}
+		if !tagRegex.MatchString(key) {
+			return fmt.Errorf("tag key %q may only contain letters, numbers, spaces and the characters _.:/=+-@", key)
+		}
+		if !tagRegex.MatchString(value) {
+			return fmt.Errorf("value of tag %q may only contain letters, numbers, spaces and the characters _.:/=+-@", key)
+		}
+		for _, prefix := range reservedTagKeyPrefixes {
+			if strings.HasPrefix(strings.ToLower(key), prefix) {
+				return fmt.Errorf("tag key %q must not start with the reserved prefix %q", key, prefix)
+			}
+		}
+		for _, reserved := range reservedTagKeys {
+			if key == reserved {
+				return fmt.Errorf("tag key %q is reserved", key)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrastructure
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tags", func() {
+	Describe("#MergeTags", func() {
+		It("should let later tags take precedence", func() {
+			Expect(MergeTags(
+				map[string]string{"cost-center": "1234", "owner": "seed"},
+				nil,
+				map[string]string{"owner": "shoot"},
+			)).To(Equal(map[string]string{"cost-center": "1234", "owner": "shoot"}))
+		})
+	})
+
+	Describe("#validateTags", func() {
+		It("should allow valid tags", func() {
+			Expect(validateTags(map[string]string{
+				"cost-center":          "1234",
+				"team:owner@corp/name": "Jane Doe + Ops=1",
+				"empty":                "",
+				"Größe":                "groß",
+			})).To(Succeed())
+		})
+
+		It("should reject too many tags", func() {
+			tags := make(map[string]string)
+			for i := 0; i <= maxTags; i++ {
+				tags[strings.Repeat("a", i+1)] = ""
+			}
+			Expect(validateTags(tags)).To(HaveOccurred())
+		})
+
+		DescribeTable("should reject invalid tags",
+			func(key, value string) {
+				Expect(validateTags(map[string]string{key: value})).To(HaveOccurred())
+			},
+			Entry("empty key", "", "foo"),
+			Entry("too long key", strings.Repeat("a", maxTagKeyLength+1), "foo"),
+			Entry("too long value", "foo", strings.Repeat("a", maxTagValueLength+1)),
+			Entry("invalid key character", "foo\"", "foo"),
+			Entry("invalid value character", "foo", "${var.foo}"),
+			Entry("AWS prefix", "aws:foo", "foo"),
+			Entry("AWS prefix in upper case", "AWS:foo", "foo"),
+			Entry("Kubernetes prefix", "kubernetes.io/cluster/foo", "owned"),
+			Entry("Name", "Name", "foo"),
+		)
+	})
+})
@@ -0,0 +1,394 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apisaws "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
+	awsclient "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws/client"
+	awsinfrastructure "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/controller/infrastructure"
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	controllererrors "github.com/gardener/gardener-extensions/pkg/controller/error"
+	"github.com/gardener/gardener-extensions/pkg/controller/worker"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// ActuatorName is the name of the AWS worker actuator.
+const ActuatorName = "aws-worker-actuator"
+
+// Keys of the secrets of the machine classes that are read by the machine-controller-manager.
+const (
+	machineClassSecretUserData        = "userData"
+	machineClassSecretAccessKeyID     = "providerAccessKeyId"
+	machineClassSecretSecretAccessKey = "providerSecretAccessKey"
+)
+
+const (
+	// machineClassKind is the kind of the machine classes referenced by the machine deployments.
+	machineClassKind = "AWSMachineClass"
+	// machineDeploymentMinReadySeconds is the time a new machine has to be ready before it counts as available.
+	machineDeploymentMinReadySeconds = 500
+	// deletionRequeueInterval is the interval in which the deletion checks whether all machines are gone.
+	deletionRequeueInterval = 30 * time.Second
+)
+
+type actuator struct {
+	logger      logr.Logger
+	defaultTags map[string]string
+
+	client  client.Client
+	scheme  *runtime.Scheme
+	decoder runtime.Decoder
+}
+
+// NewActuator creates a new Actuator that generates the machine classes and machine deployments of the handled Worker
+// resources and updates their status. The machines are tagged with the given default tags and the tags of the
+// InfrastructureConfig, like the infrastructure resources.
+func NewActuator(defaultTags map[string]string) worker.Actuator {
+	return &actuator{
+		logger:      log.Log.WithName("worker-actuator"),
+		defaultTags: defaultTags,
+	}
+}
+
+func (a *actuator) InjectScheme(scheme *runtime.Scheme) error {
+	a.scheme = scheme
+	a.decoder = serializer.NewCodecFactory(a.scheme).UniversalDecoder()
+	return nil
+}
+
+func (a *actuator) InjectClient(client client.Client) error {
+	a.client = client
+	return nil
+}
+
+// Reconcile deploys a machine class with its secret and a machine deployment per zone of every worker pool, deletes
+// the ones of removed pools and zones and reports the machine deployments in the status of the worker.
+func (a *actuator) Reconcile(ctx context.Context, worker *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster) error {
+	infraStatus, err := a.decodeInfrastructureStatus(worker)
+	if err != nil {
+		return err
+	}
+
+	credentials, err := a.machineCredentials(ctx, worker)
+	if err != nil {
+		return err
+	}
+
+	tags, err := a.machineTags(ctx, worker)
+	if err != nil {
+		return err
+	}
+
+	deployments, err := generateMachineDeployments(worker, cluster.CloudProfile, infraStatus, tags)
+	if err != nil {
+		return fmt.Errorf("could not generate the machine deployments: %+v", err)
+	}
+
+	var (
+		wantedDeployments = sets.NewString()
+		wantedClasses     = sets.NewString()
+	)
+	for _, deployment := range deployments {
+		if err := a.deployMachineClass(ctx, worker.Namespace, deployment, credentials); err != nil {
+			return fmt.Errorf("could not deploy machine class %q: %+v", deployment.ClassName, err)
+		}
+		if err := a.deployMachineDeployment(ctx, worker.Namespace, deployment); err != nil {
+			return fmt.Errorf("could not deploy machine deployment %q: %+v", deployment.Name, err)
+		}
+		wantedDeployments.Insert(deployment.Name)
+		wantedClasses.Insert(deployment.ClassName)
+	}
+
+	if err := a.cleanupMachineDeployments(ctx, worker.Namespace, wantedDeployments); err != nil {
+		return fmt.Errorf("could not clean up the machine deployments: %+v", err)
+	}
+	if err := a.cleanupMachineClasses(ctx, worker.Namespace, wantedClasses); err != nil {
+		return fmt.Errorf("could not clean up the machine classes: %+v", err)
+	}
+
+	return a.updateMachineDeploymentsStatus(ctx, worker, deployments)
+}
+
+// Delete deletes all machine deployments of the worker. The machine classes and their secrets are deleted once the
+// machine-controller-manager has deleted all machines, as it needs the credentials to terminate the instances.
+func (a *actuator) Delete(ctx context.Context, worker *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster) error {
+	if err := a.cleanupMachineDeployments(ctx, worker.Namespace, sets.NewString()); err != nil {
+		return fmt.Errorf("could not delete the machine deployments: %+v", err)
+	}
+
+	usedClasses, err := a.usedMachineClasses(ctx, worker.Namespace)
+	if err != nil {
+		return err
+	}
+	if usedClasses.Len() > 0 {
+		return &controllererrors.RequeueAfterError{
+			Cause:        fmt.Errorf("waiting until the machines of the machine classes %v are deleted", usedClasses.List()),
+			RequeueAfter: deletionRequeueInterval,
+		}
+	}
+
+	if err := a.cleanupMachineClasses(ctx, worker.Namespace, sets.NewString()); err != nil {
+		return fmt.Errorf("could not delete the machine classes: %+v", err)
+	}
+	return nil
+}
+
+func (a *actuator) decodeInfrastructureStatus(worker *extensionsv1alpha1.Worker) (*apisaws.InfrastructureStatus, error) {
+	if worker.Spec.InfrastructureProviderStatus == nil {
+		return nil, fmt.Errorf("infrastructureProviderStatus of worker %q is not set", worker.Name)
+	}
+
+	infraStatus := &apisaws.InfrastructureStatus{}
+	if _, _, err := a.decoder.Decode(worker.Spec.InfrastructureProviderStatus.Raw, nil, infraStatus); err != nil {
+		return nil, fmt.Errorf("could not decode infrastructureProviderStatus of worker %q: %+v", worker.Name, err)
+	}
+	return infraStatus, nil
+}
+
+// machineTags returns the tags of the machines of the given worker, i.e. the default tags merged with the tags of
+// the InfrastructureConfig of the AWS infrastructure in the namespace of the worker.
+func (a *actuator) machineTags(ctx context.Context, worker *extensionsv1alpha1.Worker) (map[string]string, error) {
+	infrastructures := &extensionsv1alpha1.InfrastructureList{}
+	if err := a.client.List(ctx, client.InNamespace(worker.Namespace), infrastructures); err != nil {
+		return nil, fmt.Errorf("could not list the infrastructures: %+v", err)
+	}
+
+	var infrastructure *extensionsv1alpha1.Infrastructure
+	for i := range infrastructures.Items {
+		if infrastructures.Items[i].Spec.Type != aws.Type {
+			continue
+		}
+		if infrastructure != nil {
+			return nil, fmt.Errorf("found more than one %s infrastructure in namespace %s", aws.Type, worker.Namespace)
+		}
+		infrastructure = &infrastructures.Items[i]
+	}
+	if infrastructure == nil {
+		return nil, fmt.Errorf("found no %s infrastructure in namespace %s", aws.Type, worker.Namespace)
+	}
+	if infrastructure.Spec.ProviderConfig == nil {
+		return awsinfrastructure.MergeTags(a.defaultTags), nil
+	}
+
+	infrastructureConfig := &apisaws.InfrastructureConfig{}
+	if _, _, err := a.decoder.Decode(infrastructure.Spec.ProviderConfig.Raw, nil, infrastructureConfig); err != nil {
+		return nil, fmt.Errorf("could not decode the provider config of infrastructure %s: %+v", infrastructure.Name, err)
+	}
+	return awsinfrastructure.MergeTags(a.defaultTags, infrastructureConfig.Tags), nil
+}
+
+// machineCredentials returns the access key of the cloud provider secret of the worker in the format of the machine
+// class secrets. The machine-controller-manager neither assumes roles nor supports web identities.
+func (a *actuator) machineCredentials(ctx context.Context, worker *extensionsv1alpha1.Worker) (map[string][]byte, error) {
+	secret := &corev1.Secret{}
+	if err := a.client.Get(ctx, kutil.Key(worker.Spec.SecretRef.Namespace, worker.Spec.SecretRef.Name), secret); err != nil {
+		return nil, err
+	}
+
+	credentials, err := awsclient.CredentialsFromSecretData(secret.Data)
+	if err != nil {
+		return nil, err
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, fmt.Errorf("the cloud provider secret has no access key, which the machine-controller-manager requires")
+	}
+	if credentials.RoleARN != "" {
+		return nil, fmt.Errorf("the machine-controller-manager cannot assume the role %q of the cloud provider secret", credentials.RoleARN)
+	}
+
+	return map[string][]byte{
+		machineClassSecretAccessKeyID:     []byte(credentials.AccessKeyID),
+		machineClassSecretSecretAccessKey: []byte(credentials.SecretAccessKey),
+	}, nil
+}
+
+// deployMachineClass deploys the machine class of the given deployment and its secret with the credentials and user
+// data. Both have the name of the machine class.
+func (a *actuator) deployMachineClass(ctx context.Context, namespace string, deployment machineDeployment, credentials map[string][]byte) error {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: deployment.ClassName}}
+	if err := extensionscontroller.CreateOrUpdate(ctx, a.client, secret, func() error {
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{
+			machineClassSecretUserData: deployment.UserData,
+		}
+		for key, value := range credentials {
+			secret.Data[key] = value
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	machineClass := &machinev1alpha1.AWSMachineClass{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: deployment.ClassName}}
+	return extensionscontroller.CreateOrUpdate(ctx, a.client, machineClass, func() error {
+		machineClass.Spec = deployment.ClassSpec
+		machineClass.Spec.SecretRef = &corev1.SecretReference{Namespace: namespace, Name: deployment.ClassName}
+		return nil
+	})
+}
+
+// deployMachineDeployment deploys the given machine deployment. The replicas of an existing machine deployment, e.g.
+// set by the cluster-autoscaler, are kept within the minimum and maximum of the deployment.
+func (a *actuator) deployMachineDeployment(ctx context.Context, namespace string, deployment machineDeployment) error {
+	machineDeployment := &machinev1alpha1.MachineDeployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: deployment.Name}}
+	return extensionscontroller.CreateOrUpdate(ctx, a.client, machineDeployment, func() error {
+		replicas := deployment.Minimum
+		if machineDeployment.ResourceVersion != "" {
+			replicas = clamp(int(machineDeployment.Spec.Replicas), deployment.Minimum, deployment.Maximum)
+		}
+
+		var (
+			labels         = map[string]string{"name": deployment.Name}
+			maxSurge       = deployment.MaxSurge
+			maxUnavailable = deployment.MaxUnavailable
+		)
+		machineDeployment.Spec = machinev1alpha1.MachineDeploymentSpec{
+			Replicas:        int32(replicas),
+			MinReadySeconds: machineDeploymentMinReadySeconds,
+			Strategy: machinev1alpha1.MachineDeploymentStrategy{
+				Type: machinev1alpha1.RollingUpdateMachineDeploymentStrategyType,
+				RollingUpdate: &machinev1alpha1.RollingUpdateMachineDeployment{
+					MaxSurge:       &maxSurge,
+					MaxUnavailable: &maxUnavailable,
+				},
+			},
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: machinev1alpha1.MachineTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: machinev1alpha1.MachineSpec{
+					Class: machinev1alpha1.ClassSpec{
+						Kind: machineClassKind,
+						Name: deployment.ClassName,
+					},
+				},
+			},
+		}
+		return nil
+	})
+}
+
+// cleanupMachineDeployments deletes the machine deployments in the given namespace that are not wanted.
+func (a *actuator) cleanupMachineDeployments(ctx context.Context, namespace string, wanted sets.String) error {
+	machineDeployments := &machinev1alpha1.MachineDeploymentList{}
+	if err := a.client.List(ctx, client.InNamespace(namespace), machineDeployments); err != nil {
+		return err
+	}
+
+	for _, machineDeployment := range machineDeployments.Items {
+		if wanted.Has(machineDeployment.Name) {
+			continue
+		}
+		if err := a.client.Delete(ctx, machineDeployment.DeepCopy()); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanupMachineClasses deletes the machine classes in the given namespace and their secrets that are neither wanted
+// nor still used by machine sets or machines, e.g. during a rolling update.
+func (a *actuator) cleanupMachineClasses(ctx context.Context, namespace string, wanted sets.String) error {
+	used, err := a.usedMachineClasses(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	machineClasses := &machinev1alpha1.AWSMachineClassList{}
+	if err := a.client.List(ctx, client.InNamespace(namespace), machineClasses); err != nil {
+		return err
+	}
+
+	for _, machineClass := range machineClasses.Items {
+		if wanted.Has(machineClass.Name) || used.Has(machineClass.Name) {
+			continue
+		}
+		if err := a.client.Delete(ctx, machineClass.DeepCopy()); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: machineClass.Name}}
+		if err := a.client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// usedMachineClasses returns the names of the machine classes that are referenced by machine sets or machines in the
+// given namespace.
+func (a *actuator) usedMachineClasses(ctx context.Context, namespace string) (sets.String, error) {
+	used := sets.NewString()
+
+	machineSets := &machinev1alpha1.MachineSetList{}
+	if err := a.client.List(ctx, client.InNamespace(namespace), machineSets); err != nil {
+		return nil, err
+	}
+	for _, machineSet := range machineSets.Items {
+		used.Insert(machineSet.Spec.Template.Spec.Class.Name)
+	}
+
+	machines := &machinev1alpha1.MachineList{}
+	if err := a.client.List(ctx, client.InNamespace(namespace), machines); err != nil {
+		return nil, err
+	}
+	for _, machine := range machines.Items {
+		used.Insert(machine.Spec.Class.Name)
+	}
+
+	used.Delete("")
+	return used, nil
+}
+
+func (a *actuator) updateMachineDeploymentsStatus(ctx context.Context, worker *extensionsv1alpha1.Worker, deployments []machineDeployment) error {
+	var statusDeployments []extensionsv1alpha1.MachineDeployment
+	for _, deployment := range deployments {
+		statusDeployments = append(statusDeployments, extensionsv1alpha1.MachineDeployment{
+			Name:    deployment.Name,
+			Minimum: deployment.Minimum,
+			Maximum: deployment.Maximum,
+		})
+	}
+
+	worker.Status.MachineDeployments = statusDeployments
+	return a.client.Status().Update(ctx, worker)
+}
+
+func clamp(value, minimum, maximum int) int {
+	if value < minimum {
+		return minimum
+	}
+	if value > maximum {
+		return maximum
+	}
+	return value
+}
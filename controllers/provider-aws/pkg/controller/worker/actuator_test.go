@@ -0,0 +1,366 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"testing"
+
+	apisaws "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws/install"
+	"github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/aws"
+	controllererrors "github.com/gardener/gardener-extensions/pkg/controller/error"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	gardenv1beta1 "github.com/gardener/gardener/pkg/apis/garden/v1beta1"
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestWorker(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "AWS Worker Suite")
+}
+
+const namespace = "shoot--foo--bar"
+
+var _ = Describe("Actuator", func() {
+	var (
+		cloudProfile *gardenv1beta1.CloudProfile
+		infraStatus  *apisaws.InfrastructureStatus
+		pool         extensionsv1alpha1.WorkerPool
+		worker       *extensionsv1alpha1.Worker
+	)
+
+	BeforeEach(func() {
+		cloudProfile = &gardenv1beta1.CloudProfile{
+			Spec: gardenv1beta1.CloudProfileSpec{
+				AWS: &gardenv1beta1.AWSProfile{
+					Constraints: gardenv1beta1.AWSConstraints{
+						MachineImages: []gardenv1beta1.AWSMachineImageMapping{
+							{
+								Name: "coreos",
+								Regions: []gardenv1beta1.AWSRegionalMachineImage{
+									{Name: "us-east-1", AMI: "ami-us"},
+									{Name: "eu-west-1", AMI: "ami-eu"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		infraStatus = &apisaws.InfrastructureStatus{
+			EC2: apisaws.EC2{KeyName: "ssh-key"},
+			IAM: apisaws.IAM{
+				InstanceProfiles: []apisaws.InstanceProfile{
+					{Purpose: apisaws.PurposeBastions, Name: "bastions"},
+					{Purpose: apisaws.PurposeNodes, Name: "nodes"},
+				},
+			},
+			VPC: apisaws.VPCStatus{
+				SecurityGroups: []apisaws.SecurityGroup{
+					{Purpose: apisaws.PurposeNodes, ID: "sg-nodes"},
+				},
+				Subnets: []apisaws.Subnet{
+					{Purpose: apisaws.PurposePublic, ID: "subnet-public-a", Zone: "eu-west-1a"},
+					{Purpose: apisaws.PurposeNodes, ID: "subnet-nodes-a", Zone: "eu-west-1a"},
+					{Purpose: apisaws.PurposeNodes, ID: "subnet-nodes-b", Zone: "eu-west-1b"},
+				},
+			},
+		}
+		pool = extensionsv1alpha1.WorkerPool{
+			Name:           "cpu",
+			MachineType:    "m5.large",
+			MachineImage:   extensionsv1alpha1.MachineImage{Name: "coreos", Version: "2023.5.0"},
+			Minimum:        3,
+			Maximum:        5,
+			MaxSurge:       intstr.FromInt(1),
+			MaxUnavailable: intstr.FromString("50%"),
+			UserData:       []byte("user-data"),
+			Volume:         &extensionsv1alpha1.Volume{Type: "io1", Size: "20Gi"},
+			Zones:          []string{"eu-west-1a", "eu-west-1b"},
+		}
+		worker = &extensionsv1alpha1.Worker{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "worker"},
+			Spec: extensionsv1alpha1.WorkerSpec{
+				Region:    "eu-west-1",
+				SecretRef: corev1.SecretReference{Namespace: namespace, Name: "cloudprovider"},
+			},
+		}
+	})
+
+	Describe("#generateMachineDeployments", func() {
+		It("should generate a machine deployment per zone", func() {
+			worker.Spec.Pools = []extensionsv1alpha1.WorkerPool{pool}
+
+			deployments, err := generateMachineDeployments(worker, cloudProfile, infraStatus, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployments).To(HaveLen(2))
+
+			first, second := deployments[0], deployments[1]
+			Expect(first.Name).To(Equal("shoot--foo--bar-cpu-z1"))
+			Expect(first.ClassName).To(HavePrefix("shoot--foo--bar-cpu-z1-"))
+			Expect(first.ClassName).To(HaveLen(len("shoot--foo--bar-cpu-z1-") + machineClassHashLength))
+			Expect(first.Minimum).To(Equal(2))
+			Expect(first.Maximum).To(Equal(3))
+			Expect(first.MaxSurge).To(Equal(intstr.FromInt(1)))
+			Expect(first.MaxUnavailable).To(Equal(intstr.FromString("60%")))
+			Expect(first.UserData).To(Equal([]byte("user-data")))
+			Expect(first.ClassSpec).To(Equal(machinev1alpha1.AWSMachineClassSpec{
+				AMI:         "ami-eu",
+				Region:      "eu-west-1",
+				MachineType: "m5.large",
+				KeyName:     "ssh-key",
+				IAM:         machinev1alpha1.AWSIAMProfileSpec{Name: "nodes"},
+				BlockDevices: []machinev1alpha1.AWSBlockDeviceMappingSpec{
+					{
+						DeviceName: "/root",
+						Ebs: machinev1alpha1.AWSEbsBlockDeviceSpec{
+							DeleteOnTermination: true,
+							VolumeSize:          20,
+							VolumeType:          "io1",
+						},
+					},
+				},
+				NetworkInterfaces: []machinev1alpha1.AWSNetworkInterfaceSpec{
+					{SubnetID: "subnet-nodes-a", SecurityGroupIDs: []string{"sg-nodes"}},
+				},
+				Tags: map[string]string{
+					"kubernetes.io/cluster/shoot--foo--bar": "1",
+					"kubernetes.io/role/node":               "1",
+				},
+			}))
+
+			Expect(second.Name).To(Equal("shoot--foo--bar-cpu-z2"))
+			Expect(second.Minimum).To(Equal(1))
+			Expect(second.Maximum).To(Equal(2))
+			Expect(second.MaxSurge).To(Equal(intstr.FromInt(0)))
+			Expect(second.MaxUnavailable).To(Equal(intstr.FromString("40%")))
+			Expect(second.ClassSpec.NetworkInterfaces[0].SubnetID).To(Equal("subnet-nodes-b"))
+		})
+
+		It("should change the machine class name if the user data changes", func() {
+			worker.Spec.Pools = []extensionsv1alpha1.WorkerPool{pool}
+			before, err := generateMachineDeployments(worker, cloudProfile, infraStatus, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			worker.Spec.Pools[0].UserData = []byte("other-user-data")
+			after, err := generateMachineDeployments(worker, cloudProfile, infraStatus, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(after[0].Name).To(Equal(before[0].Name))
+			Expect(after[0].ClassName).NotTo(Equal(before[0].ClassName))
+		})
+
+		It("should add the given tags without changing the machine class name", func() {
+			worker.Spec.Pools = []extensionsv1alpha1.WorkerPool{pool}
+			before, err := generateMachineDeployments(worker, cloudProfile, infraStatus, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			after, err := generateMachineDeployments(worker, cloudProfile, infraStatus, map[string]string{"team": "foo", "kubernetes.io/role/node": "0"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(after[0].ClassSpec.Tags).To(Equal(map[string]string{
+				"team":                                  "foo",
+				"kubernetes.io/cluster/shoot--foo--bar": "1",
+				"kubernetes.io/role/node":               "1",
+			}))
+			Expect(after[0].ClassName).To(Equal(before[0].ClassName))
+		})
+
+		It("should keep the root disk of the AMI and default the volume type", func() {
+			Expect(blockDevicesOf(nil)).To(BeEmpty())
+
+			blockDevices, err := blockDevicesOf(&extensionsv1alpha1.Volume{Size: "1500M"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(blockDevices[0].Ebs.VolumeSize).To(Equal(int64(2)))
+			Expect(blockDevices[0].Ebs.VolumeType).To(Equal(defaultVolumeType))
+		})
+
+		itShouldFailFor := func(description string, mutate func(), message string) {
+			It("should fail for "+description, func() {
+				worker.Spec.Pools = []extensionsv1alpha1.WorkerPool{pool}
+				mutate()
+
+				_, err := generateMachineDeployments(worker, cloudProfile, infraStatus, nil)
+
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			})
+		}
+
+		itShouldFailFor("an unknown machine image", func() { worker.Spec.Pools[0].MachineImage.Name = "ubuntu" }, `no AMI found for machine image "ubuntu"`)
+		itShouldFailFor("a region without AMI", func() { worker.Spec.Region = "ap-south-1" }, `in region "ap-south-1"`)
+		itShouldFailFor("a zone without subnet", func() { worker.Spec.Pools[0].Zones = []string{"eu-west-1c"} }, `found in zone "eu-west-1c"`)
+		itShouldFailFor("a pool without zones", func() { worker.Spec.Pools[0].Zones = nil }, "has no zones")
+		itShouldFailFor("an invalid volume size", func() { worker.Spec.Pools[0].Volume.Size = "large" }, "invalid volume size")
+		itShouldFailFor("an invalid percentage", func() { worker.Spec.Pools[0].MaxSurge = intstr.FromString("10") }, "invalid percentage")
+		itShouldFailFor("a missing security group", func() { infraStatus.VPC.SecurityGroups = nil }, "no security group")
+		itShouldFailFor("a missing instance profile", func() { infraStatus.IAM.InstanceProfiles = nil }, "no instance profile")
+	})
+
+	Describe("#clamp", func() {
+		It("should keep values between the minimum and maximum", func() {
+			Expect(clamp(0, 1, 3)).To(Equal(1))
+			Expect(clamp(2, 1, 3)).To(Equal(2))
+			Expect(clamp(5, 1, 3)).To(Equal(3))
+		})
+	})
+
+	Context("with client", func() {
+		var (
+			ctx  context.Context
+			ctrl *gomock.Controller
+			c    *mockclient.MockClient
+			a    *actuator
+		)
+
+		BeforeEach(func() {
+			ctx = context.TODO()
+			ctrl = gomock.NewController(GinkgoT())
+			c = mockclient.NewMockClient(ctrl)
+			a = &actuator{client: c}
+			scheme := runtime.NewScheme()
+			Expect(install.AddToScheme(scheme)).To(Succeed())
+			Expect(a.InjectScheme(scheme)).To(Succeed())
+		})
+
+		expectList := func(list interface{}, result interface{}) {
+			c.EXPECT().List(ctx, gomock.AssignableToTypeOf(client.InNamespace(namespace)), gomock.AssignableToTypeOf(list)).SetArg(2, result)
+		}
+
+		Describe("#machineTags", func() {
+			infrastructureOfType := func(name, providerType string, providerConfig []byte) extensionsv1alpha1.Infrastructure {
+				infrastructure := extensionsv1alpha1.Infrastructure{
+					ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+					Spec: extensionsv1alpha1.InfrastructureSpec{
+						DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: providerType},
+					},
+				}
+				if providerConfig != nil {
+					infrastructure.Spec.ProviderConfig = &runtime.RawExtension{Raw: providerConfig}
+				}
+				return infrastructure
+			}
+
+			It("should merge the default tags with the tags of the InfrastructureConfig", func() {
+				a.defaultTags = map[string]string{"team": "default", "cost-center": "42"}
+				expectList(&extensionsv1alpha1.InfrastructureList{}, extensionsv1alpha1.InfrastructureList{Items: []extensionsv1alpha1.Infrastructure{
+					infrastructureOfType("other", "gcp", nil),
+					infrastructureOfType("infrastructure", aws.Type, []byte(`{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureConfig","tags":{"team":"foo"}}`)),
+				}})
+
+				Expect(a.machineTags(ctx, worker)).To(Equal(map[string]string{"team": "foo", "cost-center": "42"}))
+			})
+
+			It("should return the default tags for an infrastructure without provider config", func() {
+				a.defaultTags = map[string]string{"team": "default"}
+				expectList(&extensionsv1alpha1.InfrastructureList{}, extensionsv1alpha1.InfrastructureList{Items: []extensionsv1alpha1.Infrastructure{
+					infrastructureOfType("infrastructure", aws.Type, nil),
+				}})
+
+				Expect(a.machineTags(ctx, worker)).To(Equal(map[string]string{"team": "default"}))
+			})
+
+			It("should fail without an AWS infrastructure", func() {
+				expectList(&extensionsv1alpha1.InfrastructureList{}, extensionsv1alpha1.InfrastructureList{})
+
+				_, err := a.machineTags(ctx, worker)
+
+				Expect(err).To(MatchError(ContainSubstring("found no aws infrastructure")))
+			})
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		Describe("#machineCredentials", func() {
+			expectSecret := func(data map[string][]byte) {
+				c.EXPECT().Get(ctx, kutil.Key(namespace, "cloudprovider"), gomock.AssignableToTypeOf(&corev1.Secret{})).SetArg(2, corev1.Secret{Data: data})
+			}
+
+			It("should return the access key of the cloud provider secret", func() {
+				expectSecret(map[string][]byte{aws.AccessKeyID: []byte("id"), aws.SecretAccessKey: []byte("secret")})
+
+				Expect(a.machineCredentials(ctx, worker)).To(Equal(map[string][]byte{
+					machineClassSecretAccessKeyID:     []byte("id"),
+					machineClassSecretSecretAccessKey: []byte("secret"),
+				}))
+			})
+
+			It("should fail without an access key", func() {
+				expectSecret(map[string][]byte{aws.RoleARN: []byte("arn:aws:iam::123456789012:role/gardener")})
+
+				_, err := a.machineCredentials(ctx, worker)
+
+				Expect(err).To(MatchError(ContainSubstring("has no access key")))
+			})
+
+			It("should fail for a role", func() {
+				expectSecret(map[string][]byte{aws.AccessKeyID: []byte("id"), aws.SecretAccessKey: []byte("secret"), aws.RoleARN: []byte("arn:aws:iam::123456789012:role/gardener")})
+
+				_, err := a.machineCredentials(ctx, worker)
+
+				Expect(err).To(MatchError(ContainSubstring("cannot assume the role")))
+			})
+		})
+
+		Describe("#Delete", func() {
+			var (
+				machineDeployment = machinev1alpha1.MachineDeployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "shoot--foo--bar-cpu-z1"}}
+				machineClass      = machinev1alpha1.AWSMachineClass{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "shoot--foo--bar-cpu-z1-abcde"}}
+			)
+
+			BeforeEach(func() {
+				expectList(&machinev1alpha1.MachineDeploymentList{}, machinev1alpha1.MachineDeploymentList{Items: []machinev1alpha1.MachineDeployment{machineDeployment}})
+				c.EXPECT().Delete(ctx, &machineDeployment)
+			})
+
+			It("should wait until the machines are deleted", func() {
+				expectList(&machinev1alpha1.MachineSetList{}, machinev1alpha1.MachineSetList{})
+				expectList(&machinev1alpha1.MachineList{}, machinev1alpha1.MachineList{Items: []machinev1alpha1.Machine{
+					{Spec: machinev1alpha1.MachineSpec{Class: machinev1alpha1.ClassSpec{Name: machineClass.Name}}},
+				}})
+
+				err := a.Delete(ctx, worker, nil)
+
+				Expect(err).To(BeAssignableToTypeOf(&controllererrors.RequeueAfterError{}))
+			})
+
+			It("should delete the machine classes and their secrets once the machines are deleted", func() {
+				expectList(&machinev1alpha1.MachineSetList{}, machinev1alpha1.MachineSetList{})
+				expectList(&machinev1alpha1.MachineList{}, machinev1alpha1.MachineList{})
+				expectList(&machinev1alpha1.MachineSetList{}, machinev1alpha1.MachineSetList{})
+				expectList(&machinev1alpha1.MachineList{}, machinev1alpha1.MachineList{})
+				expectList(&machinev1alpha1.AWSMachineClassList{}, machinev1alpha1.AWSMachineClassList{Items: []machinev1alpha1.AWSMachineClass{machineClass}})
+				c.EXPECT().Delete(ctx, &machineClass)
+				c.EXPECT().Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: machineClass.Name}})
+
+				Expect(a.Delete(ctx, worker, nil)).To(Succeed())
+			})
+		})
+	})
+})
@@ -0,0 +1,241 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	apisaws "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/apis/aws"
+	awsinfrastructure "github.com/gardener/gardener-extensions/controllers/provider-aws/pkg/controller/infrastructure"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	gardenv1beta1 "github.com/gardener/gardener/pkg/apis/garden/v1beta1"
+	"github.com/gardener/gardener/pkg/operation/common"
+	"github.com/gardener/gardener/pkg/utils"
+	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// machineClassHashLength is the length of the hash suffix of the machine class names.
+	machineClassHashLength = 5
+	// defaultVolumeType is the EBS volume type of the root disks if the pool does not specify one.
+	defaultVolumeType = "gp2"
+	// gibibyte is the number of bytes of a GiB, the unit of EBS volume sizes.
+	gibibyte = 1 << 30
+)
+
+// percentRegexp matches the percentages of the surge and unavailability of a worker pool.
+var percentRegexp = regexp.MustCompile(`^[0-9]+%$`)
+
+// machineDeployment is a machine deployment of a zone of a worker pool together with the machine class it uses.
+type machineDeployment struct {
+	Name           string
+	ClassName      string
+	Minimum        int
+	Maximum        int
+	MaxSurge       intstr.IntOrString
+	MaxUnavailable intstr.IntOrString
+	ClassSpec      machinev1alpha1.AWSMachineClassSpec
+	UserData       []byte
+}
+
+// generateMachineDeployments returns a machine deployment per zone of every pool of the given worker. The minimum,
+// maximum, surge and unavailability of a pool are distributed over its zones. The name of the machine class of a
+// deployment ends with a hash of its spec and the user data, so that changing either of them rolls the machines.
+func generateMachineDeployments(worker *extensionsv1alpha1.Worker, cloudProfile *gardenv1beta1.CloudProfile, infraStatus *apisaws.InfrastructureStatus, tags map[string]string) ([]machineDeployment, error) {
+	nodesSecurityGroup, err := findSecurityGroupByPurpose(infraStatus.VPC.SecurityGroups, apisaws.PurposeNodes)
+	if err != nil {
+		return nil, err
+	}
+	nodesInstanceProfile, err := findInstanceProfileByPurpose(infraStatus.IAM.InstanceProfiles, apisaws.PurposeNodes)
+	if err != nil {
+		return nil, err
+	}
+
+	var deployments []machineDeployment
+	for _, pool := range worker.Spec.Pools {
+		if len(pool.Zones) == 0 {
+			return nil, fmt.Errorf("worker pool %q has no zones", pool.Name)
+		}
+
+		ami, err := findAMI(cloudProfile, pool.MachineImage.Name, worker.Spec.Region)
+		if err != nil {
+			return nil, fmt.Errorf("worker pool %q: %v", pool.Name, err)
+		}
+
+		blockDevices, err := blockDevicesOf(pool.Volume)
+		if err != nil {
+			return nil, fmt.Errorf("worker pool %q: %v", pool.Name, err)
+		}
+
+		for _, value := range []intstr.IntOrString{pool.MaxSurge, pool.MaxUnavailable} {
+			if value.Type == intstr.String && !percentRegexp.MatchString(value.StrVal) {
+				return nil, fmt.Errorf("worker pool %q: invalid percentage %q", pool.Name, value.StrVal)
+			}
+		}
+
+		zoneCount := len(pool.Zones)
+		for zoneIndex, zone := range pool.Zones {
+			subnet, err := findSubnetByPurposeAndZone(infraStatus.VPC.Subnets, apisaws.PurposeNodes, zone)
+			if err != nil {
+				return nil, fmt.Errorf("worker pool %q: %v", pool.Name, err)
+			}
+
+			classSpec := machinev1alpha1.AWSMachineClassSpec{
+				AMI:          ami,
+				Region:       worker.Spec.Region,
+				MachineType:  pool.MachineType,
+				KeyName:      infraStatus.EC2.KeyName,
+				IAM:          machinev1alpha1.AWSIAMProfileSpec{Name: nodesInstanceProfile},
+				BlockDevices: blockDevices,
+				NetworkInterfaces: []machinev1alpha1.AWSNetworkInterfaceSpec{
+					{
+						SubnetID:         subnet,
+						SecurityGroupIDs: []string{nodesSecurityGroup},
+					},
+				},
+				Tags: awsinfrastructure.MergeTags(tags, map[string]string{
+					fmt.Sprintf("kubernetes.io/cluster/%s", worker.Namespace): "1",
+					"kubernetes.io/role/node":                                 "1",
+				}),
+			}
+
+			hash, err := machineClassHash(classSpec, pool.UserData)
+			if err != nil {
+				return nil, err
+			}
+
+			name := fmt.Sprintf("%s-%s-z%d", worker.Namespace, pool.Name, zoneIndex+1)
+			deployments = append(deployments, machineDeployment{
+				Name:           name,
+				ClassName:      fmt.Sprintf("%s-%s", name, hash),
+				Minimum:        common.DistributeOverZones(zoneIndex, pool.Minimum, zoneCount),
+				Maximum:        common.DistributeOverZones(zoneIndex, pool.Maximum, zoneCount),
+				MaxSurge:       distributeIntOrPercentOverZones(zoneIndex, pool.MaxSurge, zoneCount, pool.Maximum),
+				MaxUnavailable: distributeIntOrPercentOverZones(zoneIndex, pool.MaxUnavailable, zoneCount, pool.Maximum),
+				ClassSpec:      classSpec,
+				UserData:       pool.UserData,
+			})
+		}
+	}
+
+	return deployments, nil
+}
+
+// machineClassHash returns a short hash of the given machine class spec and user data. The credentials and the tags
+// are not part of the hash, rotating the credentials or changing the tags does not roll the machines.
+func machineClassHash(classSpec machinev1alpha1.AWSMachineClassSpec, userData []byte) (string, error) {
+	classSpec.Tags = nil
+	data, err := json.Marshal(struct {
+		Spec     machinev1alpha1.AWSMachineClassSpec
+		UserData []byte
+	}{classSpec, userData})
+	if err != nil {
+		return "", err
+	}
+	return utils.ComputeSHA256Hex(data)[:machineClassHashLength], nil
+}
+
+// distributeIntOrPercentOverZones distributes the given absolute value over the zones, percentages are weighted by
+// the share of the <total> of the zone with the given index. Percentages must match percentRegexp.
+func distributeIntOrPercentOverZones(zoneIndex int, value intstr.IntOrString, zoneCount, total int) intstr.IntOrString {
+	if value.Type == intstr.String {
+		return intstr.FromString(common.DistributePercentOverZones(zoneIndex, value.StrVal, zoneCount, total))
+	}
+	return intstr.FromInt(common.DistributeOverZones(zoneIndex, value.IntValue(), zoneCount))
+}
+
+// blockDevicesOf returns the root disk of the machines for the given volume. Without a volume, the machines keep the
+// root disk of the AMI.
+func blockDevicesOf(volume *extensionsv1alpha1.Volume) ([]machinev1alpha1.AWSBlockDeviceMappingSpec, error) {
+	if volume == nil {
+		return nil, nil
+	}
+
+	size, err := resource.ParseQuantity(volume.Size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid volume size %q: %v", volume.Size, err)
+	}
+	sizeGiB := (size.Value() + gibibyte - 1) / gibibyte
+	if sizeGiB < 1 {
+		return nil, fmt.Errorf("invalid volume size %q, it must be at least 1Gi", volume.Size)
+	}
+
+	volumeType := volume.Type
+	if volumeType == "" {
+		volumeType = defaultVolumeType
+	}
+
+	return []machinev1alpha1.AWSBlockDeviceMappingSpec{
+		{
+			DeviceName: "/root",
+			Ebs: machinev1alpha1.AWSEbsBlockDeviceSpec{
+				DeleteOnTermination: true,
+				VolumeSize:          sizeGiB,
+				VolumeType:          volumeType,
+			},
+		},
+	}, nil
+}
+
+// findAMI returns the AMI of the machine image with the given name in the given region from the cloud profile.
+func findAMI(cloudProfile *gardenv1beta1.CloudProfile, imageName, region string) (string, error) {
+	if cloudProfile == nil || cloudProfile.Spec.AWS == nil {
+		return "", fmt.Errorf("cloud profile has no AWS constraints")
+	}
+
+	for _, image := range cloudProfile.Spec.AWS.Constraints.MachineImages {
+		if string(image.Name) != imageName {
+			continue
+		}
+		for _, regionalImage := range image.Regions {
+			if regionalImage.Name == region {
+				return regionalImage.AMI, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no AMI found for machine image %q in region %q", imageName, region)
+}
+
+func findSubnetByPurposeAndZone(subnets []apisaws.Subnet, purpose, zone string) (string, error) {
+	for _, subnet := range subnets {
+		if subnet.Purpose == purpose && subnet.Zone == zone {
+			return subnet.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no subnet with purpose %q found in zone %q", purpose, zone)
+}
+
+func findSecurityGroupByPurpose(securityGroups []apisaws.SecurityGroup, purpose string) (string, error) {
+	for _, securityGroup := range securityGroups {
+		if securityGroup.Purpose == purpose {
+			return securityGroup.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no security group with purpose %q found", purpose)
+}
+
+func findInstanceProfileByPurpose(instanceProfiles []apisaws.InstanceProfile, purpose string) (string, error) {
+	for _, instanceProfile := range instanceProfiles {
+		if instanceProfile.Purpose == purpose {
+			return instanceProfile.Name, nil
+		}
+	}
+	return "", fmt.Errorf("no instance profile with purpose %q found", purpose)
+}
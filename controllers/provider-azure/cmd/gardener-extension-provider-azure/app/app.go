@@ -22,8 +22,12 @@ import (
 	"github.com/gardener/gardener-extensions/pkg/controller"
 	controllercmd "github.com/gardener/gardener-extensions/pkg/controller/cmd"
 	"github.com/gardener/gardener-extensions/pkg/controller/infrastructure"
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -31,23 +35,67 @@ import (
 // Name is the name of the Azure provider controller.
 const Name = "provider-azure"
 
+// Options are the command line options of the Azure provider controllers.
+type Options struct {
+	ctrlOpts                     *controllercmd.ControllerOptions
+	infrastructureReconcilerOpts *infrastructure.ReconcilerOptions
+
+	aggOption controllercmd.OptionAggregator
+}
+
+// NewOptions creates new Options for the Azure provider controllers.
+func NewOptions() *Options {
+	o := &Options{
+		ctrlOpts: &controllercmd.ControllerOptions{
+			MaxConcurrentReconciles: 5,
+		},
+		infrastructureReconcilerOpts: &infrastructure.ReconcilerOptions{
+			IgnoreOperationAnnotation: true,
+		},
+	}
+
+	o.aggOption = controllercmd.NewOptionAggregator(o.ctrlOpts, o.infrastructureReconcilerOpts)
+	return o
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	o.aggOption.AddFlags(fs)
+}
+
+// Complete implements Completer.Complete.
+func (o *Options) Complete() error {
+	return o.aggOption.Complete()
+}
+
+// AddToManager implements controllercmd.ExtensionOption.AddToManager.
+func (o *Options) AddToManager(mgr manager.Manager) error {
+	// The Azure provider does not have any controllers yet.
+	return nil
+}
+
+// ControllerResources implements controllercmd.ExtensionOption.ControllerResources.
+func (o *Options) ControllerResources() []gardencorev1alpha1.ControllerResource {
+	// The Azure provider does not have any controllers yet.
+	return nil
+}
+
 // NewControllerManagerCommand creates a new command for running a Azure provider controller.
 func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 	var (
+		logOpts  = &controllercmd.LogOptions{}
 		restOpts = &controllercmd.RESTOptions{}
 		mgrOpts  = &controllercmd.ManagerOptions{
 			LeaderElection:          true,
 			LeaderElectionID:        controllercmd.LeaderElectionNameID(Name),
 			LeaderElectionNamespace: os.Getenv("LEADER_ELECTION_NAMESPACE"),
 		}
-		ctrlOpts = &controllercmd.ControllerOptions{
-			MaxConcurrentReconciles: 5,
-		}
-		infrastructureReconcilerOpts = &infrastructure.ReconcilerOptions{
-			IgnoreOperationAnnotation: true,
-		}
 
-		aggOption = controllercmd.NewOptionAggregator(restOpts, mgrOpts, ctrlOpts, infrastructureReconcilerOpts)
+		providerOpts = NewOptions()
+
+		tracingOpts = &tracing.Options{ServiceName: Name}
+
+		aggOption = controllercmd.NewOptionAggregator(logOpts, restOpts, mgrOpts, providerOpts, tracingOpts)
 	)
 
 	cmd := &cobra.Command{
@@ -57,6 +105,7 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 			if err := aggOption.Complete(); err != nil {
 				controllercmd.LogErrAndExit(err, "Error completing options")
 			}
+			tracingOpts.Completed().Apply()
 
 			mgr, err := manager.New(restOpts.Completed().Config, mgrOpts.Completed().Options())
 			if err != nil {
@@ -67,13 +116,22 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 				controllercmd.LogErrAndExit(err, "Could not update manager scheme")
 			}
 
+			if err := providerOpts.AddToManager(mgr); err != nil {
+				controllercmd.LogErrAndExit(err, "Could not add controllers to manager")
+			}
+
 			if err := mgr.Start(ctx.Done()); err != nil {
 				controllercmd.LogErrAndExit(err, "Error running manager")
 			}
+
+			if err := tracing.GetTracer().Shutdown(context.Background()); err != nil {
+				controllercmd.LogErrAndExit(err, "Error shutting down tracer")
+			}
 		},
 	}
 
 	aggOption.AddFlags(cmd.Flags())
+	cmd.AddCommand(controllercmd.NewRegistrationCommand(Name, providerOpts.ControllerResources()))
 
 	return cmd
 }
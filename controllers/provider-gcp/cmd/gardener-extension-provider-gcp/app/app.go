@@ -17,16 +17,20 @@ package app
 import (
 	"context"
 	"fmt"
+	"os"
+
 	"github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/apis/gcp/install"
 	gcpcontroller "github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/controller"
 	gcpinfrastructure "github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/controller/infrastructure"
-	"os"
-
 	"github.com/gardener/gardener-extensions/pkg/controller"
 	controllercmd "github.com/gardener/gardener-extensions/pkg/controller/cmd"
 	"github.com/gardener/gardener-extensions/pkg/controller/infrastructure"
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -34,9 +38,61 @@ import (
 // Name is the name of the GCP provider controller.
 const Name = "provider-gcp"
 
+// Options are the command line options of the GCP provider controllers.
+type Options struct {
+	infraCtrlOpts      *controllercmd.ControllerOptions
+	infraReconcileOpts *infrastructure.ReconcilerOptions
+
+	aggOption controllercmd.OptionAggregator
+}
+
+// NewOptions creates new Options for the GCP provider controllers.
+func NewOptions() *Options {
+	o := &Options{
+		infraCtrlOpts: &controllercmd.ControllerOptions{
+			MaxConcurrentReconciles: 5,
+		},
+		infraReconcileOpts: &infrastructure.ReconcilerOptions{
+			IgnoreOperationAnnotation: true,
+		},
+	}
+
+	unprefixedInfraOpts := controllercmd.NewOptionAggregator(o.infraCtrlOpts, o.infraReconcileOpts)
+	o.aggOption = controllercmd.NewOptionAggregator(controllercmd.PrefixOption("infrastructure-", &unprefixedInfraOpts))
+	return o
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	o.aggOption.AddFlags(fs)
+}
+
+// Complete implements Completer.Complete.
+func (o *Options) Complete() error {
+	return o.aggOption.Complete()
+}
+
+// AddToManager implements controllercmd.ExtensionOption.AddToManager.
+func (o *Options) AddToManager(mgr manager.Manager) error {
+	if err := install.AddToScheme(mgr.GetScheme()); err != nil {
+		return fmt.Errorf("could not update manager scheme: %v", err)
+	}
+
+	o.infraCtrlOpts.Completed().Apply(&gcpinfrastructure.DefaultAddOptions.Controller)
+	o.infraReconcileOpts.Completed().Apply(&gcpinfrastructure.DefaultAddOptions.IgnoreOperationAnnotation)
+
+	return gcpcontroller.AddToManager(mgr)
+}
+
+// ControllerResources implements controllercmd.ExtensionOption.ControllerResources.
+func (o *Options) ControllerResources() []gardencorev1alpha1.ControllerResource {
+	return gcpcontroller.ControllerResources()
+}
+
 // NewControllerManagerCommand creates a new command for running a GCP provider controller.
 func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 	var (
+		logOpts  = &controllercmd.LogOptions{}
 		restOpts = &controllercmd.RESTOptions{}
 		mgrOpts  = &controllercmd.ManagerOptions{
 			LeaderElection:          true,
@@ -44,16 +100,11 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 			LeaderElectionNamespace: os.Getenv("LEADER_ELECTION_NAMESPACE"),
 		}
 
-		infraCtrlOpts = &controllercmd.ControllerOptions{
-			MaxConcurrentReconciles: 5,
-		}
-		infraReconcileOpts = &infrastructure.ReconcilerOptions{
-			IgnoreOperationAnnotation: true,
-		}
-		unprefixedInfraOpts = controllercmd.NewOptionAggregator(infraCtrlOpts, infraReconcileOpts)
-		infraOpts           = controllercmd.PrefixOption("infrastructure-", &unprefixedInfraOpts)
+		gcpOpts = NewOptions()
+
+		tracingOpts = &tracing.Options{ServiceName: Name}
 
-		aggOption = controllercmd.NewOptionAggregator(restOpts, mgrOpts, infraOpts)
+		aggOption = controllercmd.NewOptionAggregator(logOpts, restOpts, mgrOpts, gcpOpts, tracingOpts)
 	)
 
 	cmd := &cobra.Command{
@@ -63,6 +114,7 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 			if err := aggOption.Complete(); err != nil {
 				controllercmd.LogErrAndExit(err, "Error completing options")
 			}
+			tracingOpts.Completed().Apply()
 
 			mgr, err := manager.New(restOpts.Completed().Config, mgrOpts.Completed().Options())
 			if err != nil {
@@ -73,24 +125,22 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 				controllercmd.LogErrAndExit(err, "Could not update manager scheme")
 			}
 
-			if err := install.AddToScheme(mgr.GetScheme()); err != nil {
-				controllercmd.LogErrAndExit(err, "Could not update manager scheme")
-			}
-
-			infraCtrlOpts.Completed().Apply(&gcpinfrastructure.DefaultAddOptions.Controller)
-			infraReconcileOpts.Completed().Apply(&gcpinfrastructure.DefaultAddOptions.IgnoreOperationAnnotation)
-
-			if err := gcpcontroller.AddToManager(mgr); err != nil {
+			if err := gcpOpts.AddToManager(mgr); err != nil {
 				controllercmd.LogErrAndExit(err, "Could not add controllers to manager")
 			}
 
 			if err := mgr.Start(ctx.Done()); err != nil {
 				controllercmd.LogErrAndExit(err, "Error running manager")
 			}
+
+			if err := tracing.GetTracer().Shutdown(context.Background()); err != nil {
+				controllercmd.LogErrAndExit(err, "Error shutting down tracer")
+			}
 		},
 	}
 
 	aggOption.AddFlags(cmd.Flags())
+	cmd.AddCommand(controllercmd.NewRegistrationCommand(Name, gcpOpts.ControllerResources()))
 
 	return cmd
 }
@@ -16,14 +16,20 @@ package controller
 
 import (
 	"github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/controller/infrastructure"
+	"github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/gcp"
 	"github.com/gardener/gardener-extensions/pkg/controller"
+	genericinfrastructure "github.com/gardener/gardener-extensions/pkg/controller/infrastructure"
 )
 
 var (
-	managerBuilder = controller.NewAddToManagerBuilder(
-		infrastructure.AddToManager,
-	)
+	registry controller.Registry
 
 	// AddToManager adds all provider controllers to the given manager.
-	AddToManager = managerBuilder.AddToManager
+	AddToManager = registry.AddToManager
+	// ControllerResources returns the kinds and types of the resources handled by the provider controllers.
+	ControllerResources = registry.ControllerResources
 )
+
+func init() {
+	registry.Register(genericinfrastructure.InfrastructureResource, gcp.Type, infrastructure.AddToManager)
+}
@@ -19,23 +19,30 @@ import (
 	gcpv1alpha1 "github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/apis/gcp/v1alpha1"
 	infrainternal "github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/internal/infrastructure"
 	"github.com/gardener/gardener-extensions/pkg/controller/infrastructure"
+	"github.com/gardener/gardener-extensions/pkg/terraformer"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/gardener/gardener/pkg/chartrenderer"
-	"github.com/gardener/gardener/pkg/operation/terraformer"
+	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
 type actuator struct {
-	client        client.Client
-	restConfig    *rest.Config
-	chartRenderer chartrenderer.Interface
+	logger             logr.Logger
+	client             client.Client
+	restConfig         *rest.Config
+	chartRenderer      chartrenderer.Interface
+	terraformerFactory terraformer.Factory
 }
 
 // NewActuator creates a new infrastructure.Actuator.
 func NewActuator() infrastructure.Actuator {
-	return &actuator{}
+	return &actuator{
+		logger:             log.Log.WithName("infrastructure-actuator"),
+		terraformerFactory: terraformer.DefaultFactory(),
+	}
 }
 
 // InjectClient implements inject.Client.
@@ -59,7 +66,7 @@ func (a *actuator) InjectConfig(config *rest.Config) error {
 
 func (a *actuator) updateProviderStatus(
 	ctx context.Context,
-	tf *terraformer.Terraformer,
+	tf terraformer.Interface,
 	infra *extensionsv1alpha1.Infrastructure,
 	config *gcpv1alpha1.InfrastructureConfig,
 ) error {
@@ -21,8 +21,9 @@ import (
 	gcpclient "github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/internal/client"
 	"github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/internal/infrastructure"
 	"github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
+	"github.com/gardener/gardener-extensions/pkg/terraformer"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
-	"github.com/gardener/gardener/pkg/operation/terraformer"
 	"github.com/gardener/gardener/pkg/utils/flow"
 	"time"
 )
@@ -31,7 +32,7 @@ func (a *actuator) cleanupKubernetesFirewallRules(
 	ctx context.Context,
 	config *gcpv1alpha1.InfrastructureConfig,
 	client gcpclient.Interface,
-	tf *terraformer.Terraformer,
+	tf terraformer.Interface,
 	account *internal.ServiceAccount,
 ) error {
 	state, err := infrastructure.ExtractTerraformState(tf, config)
@@ -62,7 +63,9 @@ func (a *actuator) Delete(ctx context.Context, infra *extensionsv1alpha1.Infrast
 		return err
 	}
 
-	tf, err := internal.NewTerraformer(a.restConfig, serviceAccount, infrastructure.TerraformerPurpose, infra.Namespace, infra.Name)
+	logger := controller.NewLogrusLogger(controller.LoggerFromContext(ctx, a.logger))
+
+	tf, err := internal.NewTerraformer(a.terraformerFactory, logger, a.restConfig, serviceAccount, infrastructure.TerraformerPurpose, infra.Namespace, infra.Name)
 	if err != nil {
 		return err
 	}
@@ -76,7 +79,7 @@ func (a *actuator) Delete(ctx context.Context, infra *extensionsv1alpha1.Infrast
 		g                              = flow.NewGraph("GCP infrastructure destruction")
 		destroyKubernetesFirewallRules = g.Add(flow.Task{
 			Name: "Destroying Kubernetes firewall rules",
-			Fn: flow.TaskFn(func(ctx context.Context) error {
+			Fn: tracing.TaskFn("Destroying Kubernetes firewall rules", func(ctx context.Context) error {
 				return a.cleanupKubernetesFirewallRules(ctx, config, gcpClient, tf, serviceAccount)
 			}).
 				RetryUntilTimeout(10*time.Second, 5*time.Minute).
@@ -85,14 +88,14 @@ func (a *actuator) Delete(ctx context.Context, infra *extensionsv1alpha1.Infrast
 
 		_ = g.Add(flow.Task{
 			Name:         "Destroying Shoot infrastructure",
-			Fn:           flow.SimpleTaskFn(tf.Destroy),
+			Fn:           tracing.TaskFn("Terraformer destroy", flow.SimpleTaskFn(tf.Destroy)),
 			Dependencies: flow.NewTaskIDs(destroyKubernetesFirewallRules),
 		})
 
 		f = g.Compile()
 	)
 
-	if err := f.Run(flow.Opts{Context: ctx}); err != nil {
+	if err := f.Run(flow.Opts{Context: ctx, Logger: logger}); err != nil {
 		return flow.Causes(err)
 	}
 	return nil
@@ -20,8 +20,8 @@ import (
 	"github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/internal"
 	"github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/internal/infrastructure"
 	"github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
-	"github.com/gardener/gardener/pkg/operation/terraformer"
 )
 
 // Reconcile implements infrastructure.Actuator.
@@ -41,14 +41,16 @@ func (a *actuator) Reconcile(ctx context.Context, infra *extensionsv1alpha1.Infr
 		return err
 	}
 
-	tf, err := internal.NewTerraformer(a.restConfig, serviceAccount, infrastructure.TerraformerPurpose, infra.Namespace, infra.Name)
+	tf, err := internal.NewTerraformer(a.terraformerFactory, controller.NewLogrusLogger(controller.LoggerFromContext(ctx, a.logger)), a.restConfig, serviceAccount, infrastructure.TerraformerPurpose, infra.Namespace, infra.Name)
 	if err != nil {
 		return err
 	}
 
-	err = tf.
-		InitializeWith(terraformer.DefaultInitializer(a.client, terraformFiles.Main, terraformFiles.Variables, terraformFiles.TFVars)).
-		Apply()
+	err = tracing.Trace(ctx, "Terraformer apply", func(context.Context) error {
+		return tf.
+			InitializeWith(terraformFiles.Initializer(a.client)).
+			Apply()
+	}, "terraformer.purpose", infrastructure.TerraformerPurpose)
 	if err != nil {
 		return fmt.Errorf("failed to update the provider: %v", err)
 	}
@@ -16,6 +16,9 @@ package gcp
 
 import (
 	"context"
+
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
+
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/compute/v1"
@@ -46,6 +49,7 @@ func NewFromServiceAccount(ctx context.Context, serviceAccount []byte) (Interfac
 	}
 
 	httpClient := oauth2.NewClient(ctx, jwt.TokenSource(ctx))
+	httpClient.Transport = tracing.NewTransport(httpClient.Transport)
 	service, err := compute.New(httpClient)
 	if err != nil {
 		return nil, err
@@ -18,10 +18,10 @@ import (
 	gcpv1alpha1 "github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/apis/gcp/v1alpha1"
 	"github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/internal"
 	"github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/gardener/gardener-extensions/pkg/terraformer"
 	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/gardener/gardener/pkg/chartrenderer"
-	"github.com/gardener/gardener/pkg/operation/terraformer"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"path/filepath"
 )
@@ -114,66 +114,43 @@ func RenderTerraformerChart(
 	account *internal.ServiceAccount,
 	config *gcpv1alpha1.InfrastructureConfig,
 	cluster *controller.Cluster,
-) (*TerraformFiles, error) {
+) (*terraformer.Files, error) {
 	values := ComputeTerraformerChartValues(infra, account, config, cluster)
-
-	release, err := renderer.Render(filepath.Join(InternalChartsPath, "gcp-infra"), "gcp-infra", infra.Namespace, values)
-	if err != nil {
-		return nil, err
-	}
-
-	return &TerraformFiles{
-		Main:      release.FileContent("main.tf"),
-		Variables: release.FileContent("variables.tf"),
-		TFVars:    []byte(release.FileContent("terraform.tfvars")),
-	}, nil
-}
-
-// TerraformFiles are the files that have been rendered from the infrastructure chart.
-type TerraformFiles struct {
-	Main      string
-	Variables string
-	TFVars    []byte
+	return terraformer.RenderChart(renderer, filepath.Join(InternalChartsPath, "gcp-infra"), "gcp-infra", infra.Namespace, values)
 }
 
 // TerraformState is the Terraform state for an infrastructure.
 type TerraformState struct {
 	// VPCName is the name of the VPC created for an infrastructure.
-	VPCName string
+	VPCName string `terraform:"vpc_name"`
 	// ServiceAccountEmail is the service account email for a network.
-	ServiceAccountEmail string
+	ServiceAccountEmail string `terraform:"service_account_email"`
 	// SubnetNodes is the CIDR of the nodes subnet of an infrastructure.
-	SubnetNodes string
+	SubnetNodes string `terraform:"subnet_nodes"`
 	// SubnetInternal is the CIDR of the internal subnet of an infrastructure.
-	SubnetInternal *string
+	SubnetInternal *string `terraform:"subnet_internal"`
 }
 
 // ExtractTerraformState extracts the TerraformState from the given Terraformer.
-func ExtractTerraformState(tf *terraformer.Terraformer, config *gcpv1alpha1.InfrastructureConfig) (*TerraformState, error) {
+func ExtractTerraformState(tf terraformer.Interface, config *gcpv1alpha1.InfrastructureConfig) (*TerraformState, error) {
 	outputKeys := []string{
 		TerraformerOutputKeyVPCName,
 		TerraformerOutputKeySubnetNodes,
 		TerraformerOutputKeyServiceAccountEmail,
 	}
 
-	hasInternal := config.Networks.Internal != nil
-	if hasInternal {
+	if config.Networks.Internal != nil {
 		outputKeys = append(outputKeys, TerraformerOutputKeySubnetInternal)
 	}
 
-	vars, err := tf.GetStateOutputVariables(outputKeys...)
+	outputs, err := terraformer.GetOutputs(tf, outputKeys...)
 	if err != nil {
 		return nil, err
 	}
 
-	state := &TerraformState{
-		VPCName:             vars[TerraformerOutputKeyVPCName],
-		SubnetNodes:         vars[TerraformerOutputKeySubnetNodes],
-		ServiceAccountEmail: vars[TerraformerOutputKeyServiceAccountEmail],
-	}
-	if hasInternal {
-		subnetInternal := vars[TerraformerOutputKeySubnetInternal]
-		state.SubnetInternal = &subnetInternal
+	state := &TerraformState{}
+	if err := outputs.Decode(state); err != nil {
+		return nil, err
 	}
 	return state, nil
 }
@@ -209,7 +186,7 @@ func StatusFromTerraformState(state *TerraformState) *gcpv1alpha1.Infrastructure
 }
 
 // ComputeStatus computes the status based on the Terraformer and the given InfrastructureConfig.
-func ComputeStatus(tf *terraformer.Terraformer, config *gcpv1alpha1.InfrastructureConfig) (*gcpv1alpha1.InfrastructureStatus, error) {
+func ComputeStatus(tf terraformer.Interface, config *gcpv1alpha1.InfrastructureConfig) (*gcpv1alpha1.InfrastructureStatus, error) {
 	state, err := ExtractTerraformState(tf, config)
 	if err != nil {
 		return nil, err
@@ -19,6 +19,7 @@ import (
 	gcpv1alpha1 "github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/apis/gcp/v1alpha1"
 	"github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/internal"
 	"github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/gardener/gardener-extensions/pkg/terraformer"
 	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	gardenv1beta1 "github.com/gardener/gardener/pkg/apis/garden/v1beta1"
@@ -157,6 +158,46 @@ var _ = Describe("Terraform", func() {
 		})
 	})
 
+	Describe("#ExtractTerraformState", func() {
+		var tf *terraformer.Fake
+
+		BeforeEach(func() {
+			tf = terraformer.NewFake(map[string]string{
+				TerraformerOutputKeyVPCName:             "vpc-name",
+				TerraformerOutputKeyServiceAccountEmail: "gardener@cloud",
+				TerraformerOutputKeySubnetNodes:         "nodes-subnet",
+				TerraformerOutputKeySubnetInternal:      "internal",
+			})
+			Expect(tf.Apply()).To(Succeed())
+		})
+
+		It("should extract the state including the internal subnet", func() {
+			subnetInternal := "internal"
+
+			state, err := ExtractTerraformState(tf, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(Equal(&TerraformState{
+				VPCName:             "vpc-name",
+				ServiceAccountEmail: "gardener@cloud",
+				SubnetNodes:         "nodes-subnet",
+				SubnetInternal:      &subnetInternal,
+			}))
+		})
+
+		It("should extract the state without the internal subnet if none is configured", func() {
+			config.Networks.Internal = nil
+
+			state, err := ExtractTerraformState(tf, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.SubnetInternal).To(BeNil())
+		})
+
+		It("should fail with a variables not found error if the infrastructure has not been applied", func() {
+			_, err := ExtractTerraformState(terraformer.NewFake(nil), config)
+			Expect(terraformer.IsVariablesNotFoundError(err)).To(BeTrue())
+		})
+	})
+
 	Describe("#StatusFromTerraformState", func() {
 		var (
 			serviceAccountEmail string
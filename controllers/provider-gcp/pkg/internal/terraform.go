@@ -18,8 +18,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"github.com/gardener/gardener-extensions/controllers/provider-gcp/pkg/internal/imagevector"
-	"github.com/gardener/gardener/pkg/logger"
-	"github.com/gardener/gardener/pkg/operation/terraformer"
+	"github.com/gardener/gardener-extensions/pkg/terraformer"
+	"github.com/sirupsen/logrus"
 	"k8s.io/client-go/rest"
 )
 
@@ -41,15 +41,18 @@ func TerraformerVariablesEnvironmentFromServiceAccount(account *ServiceAccount)
 	}, nil
 }
 
-// NewTerraformer initializes a new Terraformer that has the ServiceAccount credentials.
+// NewTerraformer initializes a new Terraformer with the given factory that has the ServiceAccount credentials and
+// logs to the given logger.
 func NewTerraformer(
+	factory terraformer.Factory,
+	logger logrus.FieldLogger,
 	restConfig *rest.Config,
 	serviceAccount *ServiceAccount,
 	purpose,
 	namespace,
 	name string,
-) (*terraformer.Terraformer, error) {
-	tf, err := terraformer.NewForConfig(logger.NewLogger("info"), restConfig, purpose, namespace, name, imagevector.TerraformerImage())
+) (terraformer.Interface, error) {
+	tf, err := factory.NewForConfig(logger, restConfig, purpose, namespace, name, imagevector.TerraformerImage())
 	if err != nil {
 		return nil, err
 	}
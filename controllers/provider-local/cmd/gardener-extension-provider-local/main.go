@@ -20,12 +20,9 @@ import (
 
 	"github.com/gardener/gardener-extensions/controllers/provider-local/cmd/gardener-extension-provider-local/app"
 	"github.com/gardener/gardener-extensions/pkg/controller"
-
-	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
 func main() {
-	log.SetLogger(log.ZapLogger(false))
 	cmd := app.NewControllerManagerCommand(controller.SetupSignalHandlerContext())
 
 	if err := cmd.Execute(); err != nil {
@@ -19,17 +19,22 @@ import (
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	"github.com/gardener/gardener-extensions/pkg/controller/controlplane"
 
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 var (
-	addToManagerBuilder = extensionscontroller.NewAddToManagerBuilder()
+	registry extensionscontroller.Registry
+
 	// AddToManager adds all ControlPlane controllers to the given manager.
-	AddToManager = addToManagerBuilder.AddToManager
+	AddToManager = registry.AddToManager
+	// ControllerResources returns the kinds and types of the resources handled by the ControlPlane controllers.
+	ControllerResources = registry.ControllerResources
 )
 
 func init() {
-	addToManagerBuilder.Register(Add)
+	registry.Register(extensionsv1alpha1.ControlPlaneResource, local.Type, Add)
 }
 
 // Add adds a controller with the default Options.
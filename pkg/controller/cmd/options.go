@@ -16,12 +16,22 @@ package cmd
 
 import (
 	"fmt"
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"io"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"strings"
 )
 
 const (
@@ -42,6 +52,19 @@ const (
 	// MasterURLFlag is the name of the command line flag to specify the master URL override for
 	// a rest.Config of a manager.Manager.
 	MasterURLFlag = "master"
+
+	// EnableFlag is the name of the command line flag to specify the extensions to enable.
+	EnableFlag = "enable"
+
+	// LogLevelFlag is the name of the command line flag to specify the log level.
+	LogLevelFlag = "log-level"
+	// LogFormatFlag is the name of the command line flag to specify the log format.
+	LogFormatFlag = "log-format"
+
+	// LogFormatJSON is the log format that writes one JSON object per line.
+	LogFormatJSON = "json"
+	// LogFormatConsole is the human-readable log format.
+	LogFormatConsole = "console"
 )
 
 // LeaderElectionNameID returns a leader election ID for the given name.
@@ -97,6 +120,16 @@ type Option interface {
 	Completer
 }
 
+// ExtensionOption is an Option of an extension that can add the controllers of the extension to a manager.
+type ExtensionOption interface {
+	Option
+	// AddToManager adds the controllers of the extension to the given manager. Only call this if `Complete`
+	// was successful.
+	AddToManager(manager.Manager) error
+	// ControllerResources returns the kinds and types of the resources the controllers of the extension handle.
+	ControllerResources() []gardencorev1alpha1.ControllerResource
+}
+
 // OptionAggregator is a builder that aggregates multiple options.
 type OptionAggregator []Option
 
@@ -129,6 +162,106 @@ func (b *OptionAggregator) Complete() error {
 	return nil
 }
 
+// Switch is an ExtensionOption that can be enabled by its name.
+type Switch struct {
+	// Name is the name of the extension.
+	Name string
+	// Option is the option of the extension. Its flags are prefixed with the name of the extension.
+	Option ExtensionOption
+}
+
+// SwitchOptions are command line options to enable a selection of extensions out of a set of Switches.
+type SwitchOptions struct {
+	// Enabled are the names of the enabled extensions.
+	Enabled []string
+
+	switches []Switch
+	config   *SwitchConfig
+}
+
+// NewSwitchOptions creates new SwitchOptions with the given Switches.
+func NewSwitchOptions(switches ...Switch) *SwitchOptions {
+	return &SwitchOptions{switches: switches}
+}
+
+// Names returns the names of all Switches of this SwitchOptions.
+func (s *SwitchOptions) Names() []string {
+	names := make([]string, 0, len(s.switches))
+	for _, sw := range s.switches {
+		names = append(names, sw.Name)
+	}
+	return names
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (s *SwitchOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&s.Enabled, EnableFlag, s.Enabled, fmt.Sprintf("The extensions to enable, any of %s.", strings.Join(s.Names(), ", ")))
+	for _, sw := range s.switches {
+		PrefixOption(fmt.Sprintf("%s-", sw.Name), sw.Option).AddFlags(fs)
+	}
+}
+
+// Complete implements Completer.Complete.
+// It only completes the options of the enabled extensions.
+func (s *SwitchOptions) Complete() error {
+	if len(s.Enabled) == 0 {
+		return fmt.Errorf("no extension enabled, specify any of %s with --%s", strings.Join(s.Names(), ", "), EnableFlag)
+	}
+
+	var (
+		options []ExtensionOption
+		enabled = make(map[string]bool, len(s.Enabled))
+	)
+	for _, name := range s.Enabled {
+		if enabled[name] {
+			continue
+		}
+		enabled[name] = true
+
+		sw, ok := s.lookup(name)
+		if !ok {
+			return fmt.Errorf("unknown extension %q, must be any of %s", name, strings.Join(s.Names(), ", "))
+		}
+		if err := sw.Option.Complete(); err != nil {
+			return fmt.Errorf("could not complete options of extension %q: %v", name, err)
+		}
+		options = append(options, sw.Option)
+	}
+
+	s.config = &SwitchConfig{options}
+	return nil
+}
+
+func (s *SwitchOptions) lookup(name string) (Switch, bool) {
+	for _, sw := range s.switches {
+		if sw.Name == name {
+			return sw, true
+		}
+	}
+	return Switch{}, false
+}
+
+// Completed returns the completed SwitchConfig. Only call this if `Complete` was successful.
+func (s *SwitchOptions) Completed() *SwitchConfig {
+	return s.config
+}
+
+// SwitchConfig is a completed configuration of enabled extensions.
+type SwitchConfig struct {
+	// Options are the completed options of the enabled extensions.
+	Options []ExtensionOption
+}
+
+// AddToManager adds the controllers of all enabled extensions to the given manager.
+func (c *SwitchConfig) AddToManager(mgr manager.Manager) error {
+	for _, option := range c.Options {
+		if err := option.AddToManager(mgr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ManagerOptions are command line options that can be set for manager.Options.
 type ManagerOptions struct {
 	// LeaderElection is whether leader election is turned on or not.
@@ -293,3 +426,115 @@ func (r *RESTOptions) AddFlags(fs *pflag.FlagSet) {
 		"The address of the Kubernetes API server. Overrides any value in kubeconfig. "+
 			"Only required if out-of-cluster.")
 }
+
+type logLevelValue struct {
+	level *zapcore.Level
+}
+
+// String implements pflag.Value.
+func (v *logLevelValue) String() string {
+	return v.level.String()
+}
+
+// Set implements pflag.Value.
+func (v *logLevelValue) Set(s string) error {
+	return v.level.Set(s)
+}
+
+// Type implements pflag.Value.
+func (v *logLevelValue) Type() string {
+	return "string"
+}
+
+type logFormatValue struct {
+	format *string
+}
+
+// String implements pflag.Value.
+func (v *logFormatValue) String() string {
+	return *v.format
+}
+
+// Set implements pflag.Value.
+func (v *logFormatValue) Set(s string) error {
+	switch s {
+	case LogFormatJSON, LogFormatConsole:
+		*v.format = s
+		return nil
+	default:
+		return fmt.Errorf("unknown log format %q, must be one of %s", s, strings.Join([]string{LogFormatJSON, LogFormatConsole}, ", "))
+	}
+}
+
+// Type implements pflag.Value.
+func (v *logFormatValue) Type() string {
+	return "string"
+}
+
+// LogOptions are command line options for the logger used by the controllers.
+//
+// Register LogOptions first in an OptionAggregator: Complete sets the logger of controller-runtime as well as the
+// standard logrus logger, so that errors of all subsequently completed options are already logged with it.
+type LogOptions struct {
+	// Level is the minimum level of log entries to write.
+	Level zapcore.Level
+	// Format is the format log entries are written in, either LogFormatJSON or LogFormatConsole.
+	Format string
+	// Output is the destination of log entries. Defaults to os.Stderr.
+	Output io.Writer
+
+	config *LogConfig
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (l *LogOptions) AddFlags(fs *pflag.FlagSet) {
+	if l.Format == "" {
+		l.Format = LogFormatJSON
+	}
+	fs.Var(&logLevelValue{&l.Level}, LogLevelFlag, "The minimum log level, one of debug, info, warn, error.")
+	fs.Var(&logFormatValue{&l.Format}, LogFormatFlag, fmt.Sprintf("The log format, one of %s, %s.", LogFormatJSON, LogFormatConsole))
+}
+
+// Complete implements Completer.Complete.
+func (l *LogOptions) Complete() error {
+	output := l.Output
+	if output == nil {
+		output = os.Stderr
+	}
+
+	logger := NewZapLogger(output, l.Level, l.Format)
+	log.SetLogger(logger)
+	extensionscontroller.ConfigureLogrusLogger(logrus.StandardLogger(), logger)
+
+	l.config = &LogConfig{logger}
+	return nil
+}
+
+// Completed returns the completed LogConfig. Only call this if `Complete` was successful.
+func (l *LogOptions) Completed() *LogConfig {
+	return l.config
+}
+
+// LogConfig is a completed log configuration.
+type LogConfig struct {
+	// Logger is the configured logger.
+	Logger logr.Logger
+}
+
+// NewZapLogger creates a new zap based logr.Logger that writes entries of at least the given level in the given
+// format to the given output.
+func NewZapLogger(output io.Writer, level zapcore.Level, format string) logr.Logger {
+	var (
+		sink    = zapcore.AddSync(output)
+		encoder zapcore.Encoder
+	)
+
+	if format == LogFormatConsole {
+		encoder = zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	} else {
+		encoder = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	}
+
+	core := zapcore.NewCore(&log.KubeAwareEncoder{Encoder: encoder}, sink, zap.NewAtomicLevelAt(level))
+	return zapr.NewLogger(zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1), zap.ErrorOutput(sink), zap.AddStacktrace(zap.ErrorLevel)))
+}
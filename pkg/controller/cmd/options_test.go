@@ -15,8 +15,11 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	mockmanager "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/manager"
 	mockcmd "github.com/gardener/gardener-extensions/pkg/mock/gardener-extensions/controller/cmd"
 	"github.com/gardener/gardener-extensions/pkg/util/test"
 	"github.com/golang/mock/gomock"
@@ -24,6 +27,8 @@ import (
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/spf13/pflag"
+	"go.uber.org/zap/zapcore"
+	"io/ioutil"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -630,4 +635,179 @@ var _ = Describe("Options", func() {
 			})
 		})
 	})
+
+	Context("SwitchOptions", func() {
+		const (
+			name = "foo"
+		)
+
+		var (
+			o1, o2 *mockcmd.MockExtensionOption
+			bar    string
+		)
+		BeforeEach(func() {
+			o1 = mockcmd.NewMockExtensionOption(ctrl)
+			o2 = mockcmd.NewMockExtensionOption(ctrl)
+			o1.EXPECT().AddFlags(gomock.Any()).Do(func(fs *pflag.FlagSet) {
+				fs.StringVar(&bar, "bar", "", "bar")
+			})
+			o2.EXPECT().AddFlags(gomock.Any())
+		})
+
+		Describe("#AddFlags", func() {
+			It("should add the enable flag and the prefixed flags of all extensions", func() {
+				fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+				opts := NewSwitchOptions(Switch{"ext1", o1}, Switch{"ext2", o2})
+
+				opts.AddFlags(fs)
+
+				Expect(fs.Parse(NewCommandBuilder(name).
+					Flag(EnableFlag, "ext1,ext2").
+					Flag("ext1-bar", "baz").
+					Command().
+					Slice())).NotTo(HaveOccurred())
+				Expect(opts.Enabled).To(Equal([]string{"ext1", "ext2"}))
+				Expect(bar).To(Equal("baz"))
+			})
+		})
+
+		Describe("#Complete", func() {
+			It("should only complete the enabled extensions", func() {
+				fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+				opts := NewSwitchOptions(Switch{"ext1", o1}, Switch{"ext2", o2})
+				opts.AddFlags(fs)
+				o2.EXPECT().Complete()
+
+				Expect(fs.Parse(NewCommandBuilder(name).Flag(EnableFlag, "ext2,ext2").Command().Slice())).NotTo(HaveOccurred())
+				Expect(opts.Complete()).NotTo(HaveOccurred())
+				Expect(opts.Completed().Options).To(Equal([]ExtensionOption{o2}))
+			})
+
+			It("should fail if no extension is enabled", func() {
+				opts := NewSwitchOptions(Switch{"ext1", o1}, Switch{"ext2", o2})
+				opts.AddFlags(pflag.NewFlagSet(name, pflag.ContinueOnError))
+
+				Expect(opts.Complete()).To(HaveOccurred())
+			})
+
+			It("should fail for an unknown extension", func() {
+				opts := NewSwitchOptions(Switch{"ext1", o1}, Switch{"ext2", o2})
+				opts.AddFlags(pflag.NewFlagSet(name, pflag.ContinueOnError))
+				opts.Enabled = []string{"ext3"}
+
+				Expect(opts.Complete()).To(HaveOccurred())
+			})
+
+			It("should return errors of the extensions", func() {
+				opts := NewSwitchOptions(Switch{"ext1", o1}, Switch{"ext2", o2})
+				opts.AddFlags(pflag.NewFlagSet(name, pflag.ContinueOnError))
+				opts.Enabled = []string{"ext1"}
+				o1.EXPECT().Complete().Return(errors.New("error"))
+
+				Expect(opts.Complete()).To(HaveOccurred())
+			})
+		})
+
+		Describe("#AddToManager", func() {
+			It("should add the enabled extensions to the manager", func() {
+				mgr := mockmanager.NewMockManager(ctrl)
+				opts := NewSwitchOptions(Switch{"ext1", o1}, Switch{"ext2", o2})
+				opts.AddFlags(pflag.NewFlagSet(name, pflag.ContinueOnError))
+				opts.Enabled = []string{"ext2", "ext1"}
+				gomock.InOrder(
+					o2.EXPECT().Complete(),
+					o1.EXPECT().Complete(),
+					o2.EXPECT().AddToManager(mgr),
+					o1.EXPECT().AddToManager(mgr),
+				)
+
+				Expect(opts.Complete()).NotTo(HaveOccurred())
+				Expect(opts.Completed().AddToManager(mgr)).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Context("LogOptions", func() {
+		const (
+			name = "foo"
+		)
+
+		Describe("#AddFlags", func() {
+			It("should add all flags", func() {
+				fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+				opts := LogOptions{}
+
+				opts.AddFlags(fs)
+
+				Expect(fs.Parse(NewCommandBuilder(name).
+					Flag(LogLevelFlag, "debug").
+					Flag(LogFormatFlag, LogFormatConsole).
+					Command().
+					Slice())).NotTo(HaveOccurred())
+				Expect(opts).To(Equal(LogOptions{
+					Level:  zapcore.DebugLevel,
+					Format: LogFormatConsole,
+				}))
+			})
+
+			It("should default to the info level and the json format", func() {
+				fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+				opts := LogOptions{}
+
+				opts.AddFlags(fs)
+
+				Expect(fs.Parse(nil)).NotTo(HaveOccurred())
+				Expect(opts).To(Equal(LogOptions{
+					Level:  zapcore.InfoLevel,
+					Format: LogFormatJSON,
+				}))
+			})
+
+			It("should reject an unknown log level", func() {
+				fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+				fs.SetOutput(ioutil.Discard)
+				opts := LogOptions{}
+
+				opts.AddFlags(fs)
+
+				Expect(fs.Parse(NewCommandBuilder(name).Flag(LogLevelFlag, "loud").Command().Slice())).To(HaveOccurred())
+			})
+
+			It("should reject an unknown log format", func() {
+				fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+				fs.SetOutput(ioutil.Discard)
+				opts := LogOptions{}
+
+				opts.AddFlags(fs)
+
+				Expect(fs.Parse(NewCommandBuilder(name).Flag(LogFormatFlag, "xml").Command().Slice())).To(HaveOccurred())
+			})
+		})
+	})
+
+	Context("#NewZapLogger", func() {
+		It("should write entries of at least the given level in json format", func() {
+			var buf bytes.Buffer
+			logger := NewZapLogger(&buf, zapcore.InfoLevel, LogFormatJSON)
+
+			logger.V(1).Info("hidden")
+			logger.Info("shown", "foo", "bar")
+
+			Expect(strings.Count(buf.String(), "\n")).To(Equal(1))
+			entry := map[string]interface{}{}
+			Expect(json.Unmarshal(buf.Bytes(), &entry)).To(Succeed())
+			Expect(entry).To(HaveKeyWithValue("msg", "shown"))
+			Expect(entry).To(HaveKeyWithValue("foo", "bar"))
+		})
+
+		It("should write entries in console format", func() {
+			var buf bytes.Buffer
+			logger := NewZapLogger(&buf, zapcore.DebugLevel, LogFormatConsole)
+
+			logger.V(1).Info("shown")
+
+			Expect(buf.String()).To(ContainSubstring("shown"))
+			Expect(json.Valid(buf.Bytes())).To(BeFalse())
+		})
+	})
 })
@@ -0,0 +1,158 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/gardener/gardener-extensions/pkg/controller/registration"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// ChartDirFlag is the name of the command line flag to specify the directory of the chart of an extension.
+	ChartDirFlag = "chart-dir"
+	// VersionFlag is the name of the command line flag to specify the version of an extension.
+	VersionFlag = "version"
+	// VersionFileFlag is the name of the command line flag to specify a file containing the version of an extension.
+	VersionFileFlag = "version-file"
+	// OutputFlag is the name of the command line flag to specify the file to write to.
+	OutputFlag = "output"
+)
+
+// RegistrationOptions are command line options for generating the ControllerRegistration of an extension.
+type RegistrationOptions struct {
+	// ChartDir is the directory of the chart of the extension.
+	ChartDir string
+	// Version is the version of the extension. It defaults to the `VERSION` or `EFFECTIVE_VERSION` environment
+	// variable.
+	Version string
+	// VersionFile is a file containing the version of the extension. Only used if no version is given.
+	VersionFile string
+	// Output is the file to write the ControllerRegistration to. If empty, it is written to stdout.
+	Output string
+
+	config *RegistrationConfig
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (r *RegistrationOptions) AddFlags(fs *pflag.FlagSet) {
+	if r.Version == "" {
+		r.Version = Getenv("VERSION")
+	}
+	if r.Version == "" {
+		r.Version = Getenv("EFFECTIVE_VERSION")
+	}
+
+	fs.StringVar(&r.ChartDir, ChartDirFlag, r.ChartDir, "The directory of the chart of the extension.")
+	fs.StringVar(&r.Version, VersionFlag, r.Version, "The version of the extension. Defaults to the VERSION or EFFECTIVE_VERSION environment variable.")
+	fs.StringVar(&r.VersionFile, VersionFileFlag, r.VersionFile, fmt.Sprintf("A file containing the version of the extension. Only used if no --%s is given.", VersionFlag))
+	fs.StringVar(&r.Output, OutputFlag, r.Output, "The file to write the ControllerRegistration to. Defaults to stdout.")
+}
+
+// Complete implements Completer.Complete.
+func (r *RegistrationOptions) Complete() error {
+	if r.ChartDir == "" {
+		return fmt.Errorf("--%s has to be specified", ChartDirFlag)
+	}
+
+	version := r.Version
+	if version == "" && r.VersionFile != "" {
+		data, err := ioutil.ReadFile(r.VersionFile)
+		if err != nil {
+			return fmt.Errorf("could not read version file: %v", err)
+		}
+		version = strings.TrimSpace(string(data))
+	}
+	if version == "" {
+		return fmt.Errorf("either --%s or --%s has to be specified", VersionFlag, VersionFileFlag)
+	}
+
+	r.config = &RegistrationConfig{r.ChartDir, version, r.Output}
+	return nil
+}
+
+// Completed returns the completed RegistrationConfig. Only call this if `Complete` was successful.
+func (r *RegistrationOptions) Completed() *RegistrationConfig {
+	return r.config
+}
+
+// RegistrationConfig is a completed configuration for generating a ControllerRegistration.
+type RegistrationConfig struct {
+	// ChartDir is the directory of the chart of the extension.
+	ChartDir string
+	// Version is the version of the extension.
+	Version string
+	// Output is the file to write the ControllerRegistration to. If empty, it is written to stdout.
+	Output string
+}
+
+// Generate generates the ControllerRegistration with the given name for the given resources.
+func (c *RegistrationConfig) Generate(name string, resources []gardencorev1alpha1.ControllerResource) ([]byte, error) {
+	chart, err := registration.PackageChart(c.ChartDir, c.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	reg, err := registration.New(name, resources, chart, c.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return registration.Marshal(reg)
+}
+
+// Write generates the ControllerRegistration with the given name for the given resources and writes it
+// to the configured output.
+func (c *RegistrationConfig) Write(name string, resources []gardencorev1alpha1.ControllerResource) error {
+	data, err := c.Generate(name, resources)
+	if err != nil {
+		return err
+	}
+
+	if c.Output == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(c.Output, data, 0644)
+}
+
+// NewRegistrationCommand creates a new command that generates the ControllerRegistration of the extension
+// with the given name that handles the given resources.
+func NewRegistrationCommand(name string, resources []gardencorev1alpha1.ControllerResource) *cobra.Command {
+	opts := &RegistrationOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "registration",
+		Short: fmt.Sprintf("Generates the ControllerRegistration of %s", name),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(); err != nil {
+				return err
+			}
+			return opts.Completed().Write(name, resources)
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
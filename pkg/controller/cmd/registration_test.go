@@ -0,0 +1,133 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/gardener/gardener-extensions/pkg/util/test"
+
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Registration", func() {
+	Context("RegistrationOptions", func() {
+		var (
+			dir         string
+			versionFile string
+			resetEnv    func()
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "registration")
+			Expect(err).NotTo(HaveOccurred())
+
+			versionFile = filepath.Join(dir, "VERSION")
+			Expect(ioutil.WriteFile(versionFile, []byte("1.2.3\n"), 0644)).To(Succeed())
+
+			resetEnv = test.WithVar(&Getenv, func(string) string { return "" })
+		})
+
+		AfterEach(func() {
+			resetEnv()
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		Describe("#AddFlags", func() {
+			It("should add all flags", func() {
+				fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+				opts := RegistrationOptions{}
+
+				opts.AddFlags(fs)
+
+				Expect(fs.Parse([]string{
+					"--chart-dir", "charts/foo",
+					"--version", "1.0.0",
+					"--version-file", versionFile,
+					"--output", "out.yaml",
+				})).To(Succeed())
+				Expect(opts).To(Equal(RegistrationOptions{
+					ChartDir:    "charts/foo",
+					Version:     "1.0.0",
+					VersionFile: versionFile,
+					Output:      "out.yaml",
+				}))
+			})
+
+			It("should default the version from the environment", func() {
+				defer test.WithVar(&Getenv, func(key string) string {
+					if key == "EFFECTIVE_VERSION" {
+						return "2.0.0"
+					}
+					return ""
+				})()
+				fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+				opts := RegistrationOptions{}
+
+				opts.AddFlags(fs)
+
+				Expect(fs.Parse(nil)).To(Succeed())
+				Expect(opts.Version).To(Equal("2.0.0"))
+			})
+		})
+
+		Describe("#Complete", func() {
+			It("should fail if no chart directory is given", func() {
+				opts := RegistrationOptions{Version: "1.0.0"}
+
+				Expect(opts.Complete()).To(HaveOccurred())
+			})
+
+			It("should fail if no version is given", func() {
+				opts := RegistrationOptions{ChartDir: "charts/foo"}
+
+				Expect(opts.Complete()).To(HaveOccurred())
+			})
+
+			It("should fail if the version file does not exist", func() {
+				opts := RegistrationOptions{ChartDir: "charts/foo", VersionFile: filepath.Join(dir, "missing")}
+
+				Expect(opts.Complete()).To(HaveOccurred())
+			})
+
+			It("should prefer the version over the version file", func() {
+				opts := RegistrationOptions{ChartDir: "charts/foo", Version: "1.0.0", VersionFile: versionFile, Output: "out.yaml"}
+
+				Expect(opts.Complete()).To(Succeed())
+				Expect(opts.Completed()).To(Equal(&RegistrationConfig{
+					ChartDir: "charts/foo",
+					Version:  "1.0.0",
+					Output:   "out.yaml",
+				}))
+			})
+
+			It("should read the version from the version file", func() {
+				opts := RegistrationOptions{ChartDir: "charts/foo", VersionFile: versionFile}
+
+				Expect(opts.Complete()).To(Succeed())
+				Expect(opts.Completed()).To(Equal(&RegistrationConfig{
+					ChartDir: "charts/foo",
+					Version:  "1.2.3",
+				}))
+			})
+		})
+	})
+})
@@ -21,6 +21,7 @@ import (
 	gardenv1beta1 "github.com/gardener/gardener/pkg/apis/garden/v1beta1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 func TestController(t *testing.T) {
@@ -82,3 +83,27 @@ var _ = Describe("Shoot", func() {
 		})
 	})
 })
+
+var _ = Describe("Registry", func() {
+	It("should add the registered controllers and return their resources", func() {
+		var (
+			registry Registry
+			added    []string
+		)
+		registry.Register("Infrastructure", "foo", func(manager.Manager) error {
+			added = append(added, "infrastructure")
+			return nil
+		})
+		registry.Register("ControlPlane", "foo", func(manager.Manager) error {
+			added = append(added, "controlplane")
+			return nil
+		})
+
+		Expect(registry.AddToManager(nil)).To(Succeed())
+		Expect(added).To(Equal([]string{"infrastructure", "controlplane"}))
+		Expect(registry.ControllerResources()).To(Equal([]gardencorev1alpha1.ControllerResource{
+			{Kind: "Infrastructure", Type: "foo"},
+			{Kind: "ControlPlane", Type: "foo"},
+		}))
+	})
+})
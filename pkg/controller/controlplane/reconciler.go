@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
 	"github.com/gardener/gardener-extensions/pkg/util"
 
 	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
@@ -89,13 +90,17 @@ func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 		return reconcile.Result{}, err
 	}
 
+	ctx := extensionscontroller.ContextWithLogger(r.ctx, extensionscontroller.ReconcileLogger(r.logger, cp, cluster))
+
 	if cp.DeletionTimestamp != nil {
-		return r.delete(r.ctx, cp, cluster)
+		return r.delete(ctx, cp, cluster)
 	}
-	return r.reconcile(r.ctx, cp, cluster)
+	return r.reconcile(ctx, cp, cluster)
 }
 
 func (r *reconciler) reconcile(ctx context.Context, cp *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster) (reconcile.Result, error) {
+	logger := extensionscontroller.LoggerFromContext(ctx, r.logger)
+
 	if err := extensionscontroller.EnsureFinalizer(ctx, r.client, FinalizerName, cp); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -105,17 +110,19 @@ func (r *reconciler) reconcile(ctx context.Context, cp *extensionsv1alpha1.Contr
 		return reconcile.Result{}, err
 	}
 
-	r.logger.Info("Starting the reconciliation of controlplane", "controlplane", cp.Name)
+	logger.Info("Starting the reconciliation of controlplane", "controlplane", cp.Name)
 	r.recorder.Event(cp, corev1.EventTypeNormal, EventControlPlaneReconciliation, "Reconciling the controlplane")
-	if err := r.actuator.Reconcile(ctx, cp, cluster); err != nil {
+	if err := tracing.TraceReconcile(ctx, "ControlPlane", cp, cp.Spec.Type, operationType, func(ctx context.Context) error {
+		return r.actuator.Reconcile(ctx, cp, cluster)
+	}); err != nil {
 		msg := "Error reconciling controlplane"
 		r.updateStatusError(ctx, extensionscontroller.ReconcileErrCauseOrErr(err), cp, operationType, msg)
-		r.logger.Error(err, msg, "controlplane", cp.Name)
+		logger.Error(err, msg, "controlplane", cp.Name)
 		return extensionscontroller.ReconcileErr(err)
 	}
 
 	msg := "Successfully reconciled controlplane"
-	r.logger.Info(msg, "controlplane", cp.Name)
+	logger.Info(msg, "controlplane", cp.Name)
 	r.recorder.Event(cp, corev1.EventTypeNormal, EventControlPlaneReconciliation, msg)
 	if err := r.updateStatusSuccess(ctx, cp, operationType, msg); err != nil {
 		return reconcile.Result{}, err
@@ -125,13 +132,15 @@ func (r *reconciler) reconcile(ctx context.Context, cp *extensionsv1alpha1.Contr
 }
 
 func (r *reconciler) delete(ctx context.Context, cp *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster) (reconcile.Result, error) {
+	logger := extensionscontroller.LoggerFromContext(ctx, r.logger)
+
 	hasFinalizer, err := extensionscontroller.HasFinalizer(cp, FinalizerName)
 	if err != nil {
-		r.logger.Error(err, "Could not instantiate finalizer deletion")
+		logger.Error(err, "Could not instantiate finalizer deletion")
 		return reconcile.Result{}, err
 	}
 	if !hasFinalizer {
-		r.logger.Info("Deleting controlplane causes a no-op as there is no finalizer.", "controlplane", cp.Name)
+		logger.Info("Deleting controlplane causes a no-op as there is no finalizer.", "controlplane", cp.Name)
 		return reconcile.Result{}, nil
 	}
 
@@ -140,26 +149,28 @@ func (r *reconciler) delete(ctx context.Context, cp *extensionsv1alpha1.ControlP
 		return reconcile.Result{}, err
 	}
 
-	r.logger.Info("Starting the deletion of controlplane", "controlplane", cp.Name)
+	logger.Info("Starting the deletion of controlplane", "controlplane", cp.Name)
 	r.recorder.Event(cp, corev1.EventTypeNormal, EventControlPlaneDeletion, "Deleting the cp")
-	if err := r.actuator.Delete(r.ctx, cp, cluster); err != nil {
+	if err := tracing.TraceReconcile(ctx, "ControlPlane", cp, cp.Spec.Type, operationType, func(ctx context.Context) error {
+		return r.actuator.Delete(ctx, cp, cluster)
+	}); err != nil {
 		msg := "Error deleting controlplane"
 		r.recorder.Eventf(cp, corev1.EventTypeWarning, EventControlPlaneDeletion, "%s: %+v", msg, err)
 		r.updateStatusError(ctx, extensionscontroller.ReconcileErrCauseOrErr(err), cp, operationType, msg)
-		r.logger.Error(err, msg, "controlplane", cp.Name)
+		logger.Error(err, msg, "controlplane", cp.Name)
 		return extensionscontroller.ReconcileErr(err)
 	}
 
 	msg := "Successfully deleted controlplane"
-	r.logger.Info(msg, "controlplane", cp.Name)
+	logger.Info(msg, "controlplane", cp.Name)
 	r.recorder.Event(cp, corev1.EventTypeNormal, EventControlPlaneDeletion, msg)
 	if err := r.updateStatusSuccess(ctx, cp, operationType, msg); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	r.logger.Info("Removing finalizer.", "controlplane", cp.Name)
+	logger.Info("Removing finalizer.", "controlplane", cp.Name)
 	if err := extensionscontroller.DeleteFinalizer(ctx, r.client, FinalizerName, cp); err != nil {
-		r.logger.Error(err, "Error removing finalizer from ControlPlane", "controlplane", cp.Name)
+		logger.Error(err, "Error removing finalizer from ControlPlane", "controlplane", cp.Name)
 		return reconcile.Result{}, err
 	}
 
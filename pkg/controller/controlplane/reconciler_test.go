@@ -0,0 +1,149 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gardener/gardener-extensions/pkg/util/test/framework"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	"github.com/google/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	typeName = "test"
+	interval = 100 * time.Millisecond
+	timeout  = 30 * time.Second
+)
+
+var (
+	env    = &framework.Environment{CRDDirectoryPaths: []string{"../../../controllers/provider-local/example"}}
+	config *rest.Config
+)
+
+var _ = AfterSuite(func() {
+	if config != nil {
+		Expect(env.Stop()).To(Succeed())
+	}
+})
+
+var _ = Describe("Reconciler", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+
+		c        client.Client
+		actuator *framework.FakeControlPlaneActuator
+
+		namespace string
+		cp        *extensionsv1alpha1.ControlPlane
+	)
+
+	BeforeEach(func() {
+		if !env.IsAvailable() {
+			Skip("etcd and kube-apiserver are not available")
+		}
+		if config == nil {
+			var err error
+			config, err = env.Start()
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+
+		mgr, err := framework.NewManager(config)
+		Expect(err).NotTo(HaveOccurred())
+
+		actuator = &framework.FakeControlPlaneActuator{}
+		Expect(Add(mgr, AddArgs{
+			Actuator: actuator,
+			Type:     typeName,
+		})).To(Succeed())
+
+		_, err = framework.StartManager(ctx, mgr)
+		Expect(err).NotTo(HaveOccurred())
+
+		c, err = client.New(config, client.Options{Scheme: mgr.GetScheme()})
+		Expect(err).NotTo(HaveOccurred())
+
+		namespace = fmt.Sprintf("shoot--test--%s", uuid.New().String()[:8])
+		Expect(c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})).To(Succeed())
+
+		cluster, err := framework.NewClusterBuilder(namespace).
+			WithShoot(framework.NewShootBuilder("garden-test", "shoot").WithCloud(typeName, "region").Build()).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Create(ctx, cluster)).To(Succeed())
+
+		cp = &extensionsv1alpha1.ControlPlane{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "control-plane"},
+			Spec: extensionsv1alpha1.ControlPlaneSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: typeName},
+				Region:      "region",
+				SecretRef:   corev1.SecretReference{Namespace: namespace, Name: "cloudprovider"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		if cancel != nil {
+			cancel()
+		}
+	})
+
+	It("should reconcile the control plane with the cluster", func() {
+		Expect(c.Create(ctx, cp)).To(Succeed())
+
+		Expect(framework.WaitForLastOperation(ctx, c, cp, gardencorev1alpha1.LastOperationTypeCreate, gardencorev1alpha1.LastOperationStateSucceeded, interval)).To(Succeed())
+		Expect(cp.Finalizers).To(ConsistOf(FinalizerName))
+
+		calls := actuator.CallsOf(framework.OperationReconcile)
+		Expect(calls).NotTo(BeEmpty())
+		Expect(calls[0].Cluster.Shoot.Name).To(Equal("shoot"))
+	})
+
+	It("should report errors of the actuator", func() {
+		actuator.SetReconcileError(errors.New("error"))
+
+		Expect(c.Create(ctx, cp)).To(Succeed())
+
+		Expect(framework.WaitForLastOperation(ctx, c, cp, gardencorev1alpha1.LastOperationTypeCreate, gardencorev1alpha1.LastOperationStateError, interval)).To(Succeed())
+		Expect(cp.Status.LastError).NotTo(BeNil())
+	})
+
+	It("should delete the control plane and remove the finalizer", func() {
+		Expect(c.Create(ctx, cp)).To(Succeed())
+		Expect(framework.WaitForLastOperation(ctx, c, cp, gardencorev1alpha1.LastOperationTypeCreate, gardencorev1alpha1.LastOperationStateSucceeded, interval)).To(Succeed())
+
+		Expect(c.Delete(ctx, cp)).To(Succeed())
+
+		Expect(framework.WaitForDeletion(ctx, c, cp, interval)).To(Succeed())
+		Expect(actuator.CallsOf(framework.OperationDelete)).NotTo(BeEmpty())
+	})
+})
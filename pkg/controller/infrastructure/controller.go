@@ -33,6 +33,8 @@ const (
 	FinalizerName = "extensions.gardener.cloud/infrastructure"
 	// ControllerName is the name of the controller.
 	ControllerName = "infrastructure-controller"
+	// InfrastructureResource is the kind of the resources handled by infrastructure controllers.
+	InfrastructureResource = "Infrastructure"
 )
 
 // AddArgs are arguments for adding an infrastructure controller to a manager.
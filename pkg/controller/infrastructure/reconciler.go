@@ -20,6 +20,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
 	"github.com/gardener/gardener-extensions/pkg/util"
 
 	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
@@ -91,13 +92,17 @@ func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 		return reconcile.Result{}, err
 	}
 
+	ctx := extensionscontroller.ContextWithLogger(r.ctx, extensionscontroller.ReconcileLogger(r.logger, infrastructure, cluster))
+
 	if infrastructure.DeletionTimestamp != nil {
-		return r.delete(r.ctx, infrastructure, cluster)
+		return r.delete(ctx, infrastructure, cluster)
 	}
-	return r.reconcile(r.ctx, infrastructure, cluster)
+	return r.reconcile(ctx, infrastructure, cluster)
 }
 
 func (r *reconciler) reconcile(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) (reconcile.Result, error) {
+	logger := extensionscontroller.LoggerFromContext(ctx, r.logger)
+
 	if err := extensionscontroller.EnsureFinalizer(ctx, r.client, FinalizerName, infrastructure); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -107,17 +112,19 @@ func (r *reconciler) reconcile(ctx context.Context, infrastructure *extensionsv1
 		return reconcile.Result{}, err
 	}
 
-	r.logger.Info("Starting the reconciliation of infrastructure", "infrastructure", infrastructure.Name)
+	logger.Info("Starting the reconciliation of infrastructure", "infrastructure", infrastructure.Name)
 	r.recorder.Event(infrastructure, corev1.EventTypeNormal, EventInfrastructureReconciliation, "Reconciling the infrastructure")
-	if err := r.actuator.Reconcile(ctx, infrastructure, cluster); err != nil {
+	if err := tracing.TraceReconcile(ctx, "Infrastructure", infrastructure, infrastructure.Spec.Type, operationType, func(ctx context.Context) error {
+		return r.actuator.Reconcile(ctx, infrastructure, cluster)
+	}); err != nil {
 		msg := "Error reconciling infrastructure"
 		utilruntime.HandleError(r.updateStatusError(ctx, extensionscontroller.ReconcileErrCauseOrErr(err), infrastructure, operationType, msg))
-		r.logger.Error(err, msg, "infrastructure", infrastructure.Name)
+		logger.Error(err, msg, "infrastructure", infrastructure.Name)
 		return extensionscontroller.ReconcileErr(err)
 	}
 
 	msg := "Successfully reconciled infrastructure"
-	r.logger.Info(msg, "infrastructure", infrastructure.Name)
+	logger.Info(msg, "infrastructure", infrastructure.Name)
 	r.recorder.Event(infrastructure, corev1.EventTypeNormal, EventInfrastructureReconciliation, msg)
 	if err := r.updateStatusSuccess(ctx, infrastructure, operationType, msg); err != nil {
 		return reconcile.Result{}, err
@@ -127,13 +134,15 @@ func (r *reconciler) reconcile(ctx context.Context, infrastructure *extensionsv1
 }
 
 func (r *reconciler) delete(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) (reconcile.Result, error) {
+	logger := extensionscontroller.LoggerFromContext(ctx, r.logger)
+
 	hasFinalizer, err := extensionscontroller.HasFinalizer(infrastructure, FinalizerName)
 	if err != nil {
-		r.logger.Error(err, "Could not instantiate finalizer deletion")
+		logger.Error(err, "Could not instantiate finalizer deletion")
 		return reconcile.Result{}, err
 	}
 	if !hasFinalizer {
-		r.logger.Info("Deleting infrastructure causes a no-op as there is no finalizer.", "infrastructure", infrastructure.Name)
+		logger.Info("Deleting infrastructure causes a no-op as there is no finalizer.", "infrastructure", infrastructure.Name)
 		return reconcile.Result{}, nil
 	}
 
@@ -142,26 +151,28 @@ func (r *reconciler) delete(ctx context.Context, infrastructure *extensionsv1alp
 		return reconcile.Result{}, err
 	}
 
-	r.logger.Info("Starting the deletion of infrastructure", "infrastructure", infrastructure.Name)
+	logger.Info("Starting the deletion of infrastructure", "infrastructure", infrastructure.Name)
 	r.recorder.Event(infrastructure, corev1.EventTypeNormal, EventInfrastructureDeleton, "Deleting the infrastructure")
-	if err := r.actuator.Delete(r.ctx, infrastructure, cluster); err != nil {
+	if err := tracing.TraceReconcile(ctx, "Infrastructure", infrastructure, infrastructure.Spec.Type, operationType, func(ctx context.Context) error {
+		return r.actuator.Delete(ctx, infrastructure, cluster)
+	}); err != nil {
 		msg := "Error deleting infrastructure"
 		r.recorder.Eventf(infrastructure, corev1.EventTypeWarning, EventInfrastructureDeleton, "%s: %+v", msg, err)
 		utilruntime.HandleError(r.updateStatusError(ctx, extensionscontroller.ReconcileErrCauseOrErr(err), infrastructure, operationType, msg))
-		r.logger.Error(err, msg, "infrastructure", infrastructure.Name)
+		logger.Error(err, msg, "infrastructure", infrastructure.Name)
 		return extensionscontroller.ReconcileErr(err)
 	}
 
 	msg := "Successfully deleted infrastructure"
-	r.logger.Info(msg, "infrastructure", infrastructure.Name)
+	logger.Info(msg, "infrastructure", infrastructure.Name)
 	r.recorder.Event(infrastructure, corev1.EventTypeNormal, EventInfrastructureDeleton, msg)
 	if err := r.updateStatusSuccess(ctx, infrastructure, operationType, msg); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	r.logger.Info("Removing finalizer.", "infrastructure", infrastructure.Name)
+	logger.Info("Removing finalizer.", "infrastructure", infrastructure.Name)
 	if err := extensionscontroller.DeleteFinalizer(ctx, r.client, FinalizerName, infrastructure); err != nil {
-		r.logger.Error(err, "Error removing finalizer from Infrastructure", "infrastructure", infrastructure.Name)
+		logger.Error(err, "Error removing finalizer from Infrastructure", "infrastructure", infrastructure.Name)
 		return reconcile.Result{}, err
 	}
 
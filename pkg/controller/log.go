@@ -15,13 +15,29 @@
 package controller
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"sort"
+
 	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
+const (
+	// ReconcileIDLogKey is the log key of the unique ID of a single reconciliation.
+	ReconcileIDLogKey = "reconcileID"
+	// ShootNamespaceLogKey is the log key of the namespace of the shoot a reconciliation belongs to.
+	ShootNamespaceLogKey = "shoot.namespace"
+	// ShootNameLogKey is the log key of the name of the shoot a reconciliation belongs to.
+	ShootNameLogKey = "shoot.name"
+)
+
 // CreateEventLogger creates a Logger with keys and values from the given CreateEvent.
 func CreateEventLogger(log logr.Logger, event event.CreateEvent) logr.Logger {
 	return log.WithValues(CreateEventLogValues(event)...)
@@ -111,3 +127,105 @@ func RuntimeObjectLogValues(obj runtime.Object) []interface{} {
 		"object.kind", kind,
 	}
 }
+
+// ReconcileLogValues returns the log values for a single reconciliation of the given object. They contain a newly
+// generated reconcile ID and, if the given cluster contains a shoot, the namespace and name of that shoot.
+func ReconcileLogValues(obj metav1.Object, cluster *Cluster) []interface{} {
+	values := []interface{}{ReconcileIDLogKey, uuid.New().String()}
+	values = append(values, MetaObjectLogValues(obj)...)
+	if cluster != nil && cluster.Shoot != nil {
+		values = append(values, ShootNamespaceLogKey, cluster.Shoot.Namespace, ShootNameLogKey, cluster.Shoot.Name)
+	}
+	return values
+}
+
+// ReconcileLogger creates a Logger with the keys and values of a single reconciliation of the given object.
+func ReconcileLogger(log logr.Logger, obj metav1.Object, cluster *Cluster) logr.Logger {
+	return log.WithValues(ReconcileLogValues(obj, cluster)...)
+}
+
+type loggerContextKey struct{}
+
+// ContextWithLogger returns a copy of the given context that carries the given Logger.
+func ContextWithLogger(ctx context.Context, log logr.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, log)
+}
+
+// LoggerFromContext returns the Logger carried by the given context. If the context does not carry
+// a Logger, the given default Logger is returned.
+func LoggerFromContext(ctx context.Context, defaultLog logr.Logger) logr.Logger {
+	if log, ok := ctx.Value(loggerContextKey{}).(logr.Logger); ok {
+		return log
+	}
+	return defaultLog
+}
+
+// NewLogrusLogger creates a logrus.Logger that forwards all its entries to the given Logger.
+// Fields of an entry are passed as keys and values, entries with level error and above are logged as errors,
+// debug entries with verbosity 1 and trace entries with verbosity 2.
+func NewLogrusLogger(log logr.Logger) *logrus.Logger {
+	logger := logrus.New()
+	ConfigureLogrusLogger(logger, log)
+	return logger
+}
+
+// ConfigureLogrusLogger configures the given logrus.Logger to forward all its entries to the given Logger
+// instead of writing them to its own output. See NewLogrusLogger for details.
+func ConfigureLogrusLogger(logger *logrus.Logger, log logr.Logger) {
+	logger.SetOutput(ioutil.Discard)
+	logger.SetLevel(logrus.TraceLevel)
+	hooks := make(logrus.LevelHooks)
+	hooks.Add(&logrHook{log})
+	logger.ReplaceHooks(hooks)
+}
+
+type logrHook struct {
+	log logr.Logger
+}
+
+// Levels implements logrus.Hook.
+func (h *logrHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.
+func (h *logrHook) Fire(entry *logrus.Entry) error {
+	var (
+		err           error
+		keysAndValues = make([]interface{}, 0, 2*len(entry.Data))
+		keys          = make([]string, 0, len(entry.Data))
+	)
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := entry.Data[key]
+		if key == logrus.ErrorKey {
+			if e, ok := value.(error); ok {
+				err = e
+				continue
+			}
+		}
+		keysAndValues = append(keysAndValues, key, value)
+	}
+
+	switch entry.Level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		if err == nil {
+			err = errors.New(entry.Message)
+		}
+		h.log.Error(err, entry.Message, keysAndValues...)
+	case logrus.DebugLevel:
+		h.log.V(1).Info(entry.Message, keysAndValues...)
+	case logrus.TraceLevel:
+		h.log.V(2).Info(entry.Message, keysAndValues...)
+	default:
+		if err != nil {
+			keysAndValues = append(keysAndValues, logrus.ErrorKey, err.Error())
+		}
+		h.log.Info(entry.Message, keysAndValues...)
+	}
+	return nil
+}
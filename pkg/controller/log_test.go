@@ -0,0 +1,117 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	mocklogr "github.com/gardener/gardener-extensions/pkg/mock/go-logr/logr"
+	gardenv1beta1 "github.com/gardener/gardener/pkg/apis/garden/v1beta1"
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Log", func() {
+	var (
+		ctrl *gomock.Controller
+	)
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+	})
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("#ReconcileLogValues", func() {
+		obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar", Name: "baz"}}
+
+		It("should contain a reconcile ID and the object metadata", func() {
+			values := ReconcileLogValues(obj, nil)
+
+			Expect(values).To(HaveLen(6))
+			Expect(values[0]).To(Equal(ReconcileIDLogKey))
+			Expect(values[1]).NotTo(BeEmpty())
+			Expect(values[2:]).To(Equal([]interface{}{"meta.name", "baz", "meta.namespace", "shoot--foo--bar"}))
+		})
+
+		It("should generate a new reconcile ID for every reconciliation", func() {
+			Expect(ReconcileLogValues(obj, nil)[1]).NotTo(Equal(ReconcileLogValues(obj, nil)[1]))
+		})
+
+		It("should contain the namespace and name of the shoot", func() {
+			cluster := &Cluster{Shoot: &gardenv1beta1.Shoot{ObjectMeta: metav1.ObjectMeta{Namespace: "garden-foo", Name: "bar"}}}
+
+			values := ReconcileLogValues(obj, cluster)
+
+			Expect(values[6:]).To(Equal([]interface{}{ShootNamespaceLogKey, "garden-foo", ShootNameLogKey, "bar"}))
+		})
+	})
+
+	Describe("#LoggerFromContext", func() {
+		It("should return the logger of the context", func() {
+			logger := mocklogr.NewMockLogger(ctrl)
+
+			Expect(LoggerFromContext(ContextWithLogger(context.TODO(), logger), nil)).To(BeIdenticalTo(logger))
+		})
+
+		It("should return the default logger if the context does not carry one", func() {
+			logger := mocklogr.NewMockLogger(ctrl)
+
+			Expect(LoggerFromContext(context.TODO(), logger)).To(BeIdenticalTo(logger))
+		})
+	})
+
+	Describe("#NewLogrusLogger", func() {
+		var (
+			logger *mocklogr.MockLogger
+		)
+		BeforeEach(func() {
+			logger = mocklogr.NewMockLogger(ctrl)
+		})
+
+		It("should forward info entries with their fields", func() {
+			logger.EXPECT().Info("foo", "a", 1, "b", "2")
+
+			NewLogrusLogger(logger).WithField("b", "2").WithField("a", 1).Info("foo")
+		})
+
+		It("should forward debug entries with verbosity 1", func() {
+			gomock.InOrder(
+				logger.EXPECT().V(1).Return(logr.InfoLogger(logger)),
+				logger.EXPECT().Info("foo"),
+			)
+
+			NewLogrusLogger(logger).Debug("foo")
+		})
+
+		It("should forward error entries as errors", func() {
+			err := errors.New("bar")
+			logger.EXPECT().Error(err, "foo", "a", 1)
+
+			NewLogrusLogger(logger).WithError(err).WithField("a", 1).Error("foo")
+		})
+
+		It("should forward error entries without an error as errors", func() {
+			logger.EXPECT().Error(errors.New("foo"), "foo")
+
+			NewLogrusLogger(logger).Error("foo")
+		})
+	})
+})
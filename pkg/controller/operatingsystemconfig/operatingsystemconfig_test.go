@@ -0,0 +1,159 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operatingsystemconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gardener/gardener-extensions/pkg/util/test/framework"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+
+	"github.com/google/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestOperatingSystemConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OperatingSystemConfig Suite")
+}
+
+const (
+	typeName = "test"
+	interval = 100 * time.Millisecond
+	timeout  = 30 * time.Second
+)
+
+var (
+	env    = &framework.Environment{CRDDirectoryPaths: []string{"../../../controllers/os-coreos/example"}}
+	config *rest.Config
+)
+
+var _ = AfterSuite(func() {
+	if config != nil {
+		Expect(env.Stop()).To(Succeed())
+	}
+})
+
+var _ = Describe("Reconciler", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+
+		c        client.Client
+		actuator *framework.FakeOperatingSystemConfigActuator
+
+		namespace string
+		osc       *extensionsv1alpha1.OperatingSystemConfig
+	)
+
+	BeforeEach(func() {
+		if !env.IsAvailable() {
+			Skip("etcd and kube-apiserver are not available")
+		}
+		if config == nil {
+			var err error
+			config, err = env.Start()
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+
+		mgr, err := framework.NewManager(config)
+		Expect(err).NotTo(HaveOccurred())
+
+		command := "/usr/bin/cloud-init"
+		actuator = &framework.FakeOperatingSystemConfigActuator{
+			CloudConfig: []byte("cloud-config"),
+			Command:     &command,
+			Units:       []string{"kubelet.service"},
+		}
+		Expect(Add(mgr, AddArgs{
+			Actuator:   actuator,
+			Predicates: DefaultPredicates(typeName),
+		})).To(Succeed())
+
+		_, err = framework.StartManager(ctx, mgr)
+		Expect(err).NotTo(HaveOccurred())
+
+		c, err = client.New(config, client.Options{Scheme: mgr.GetScheme()})
+		Expect(err).NotTo(HaveOccurred())
+
+		namespace = fmt.Sprintf("shoot--test--%s", uuid.New().String()[:8])
+		Expect(c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})).To(Succeed())
+
+		osc = &extensionsv1alpha1.OperatingSystemConfig{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "osc"},
+			Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: typeName},
+				Purpose:     extensionsv1alpha1.OperatingSystemConfigPurposeProvision,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		if cancel != nil {
+			cancel()
+		}
+	})
+
+	It("should store the cloud config of the actuator in a secret", func() {
+		Expect(c.Create(ctx, osc)).To(Succeed())
+
+		Expect(framework.WaitForLastOperation(ctx, c, osc, gardencorev1alpha1.LastOperationTypeCreate, gardencorev1alpha1.LastOperationStateSucceeded, interval)).To(Succeed())
+		Expect(osc.Finalizers).To(ConsistOf(FinalizerName))
+		Expect(osc.Status.Command).To(Equal(actuator.Command))
+		Expect(osc.Status.Units).To(ConsistOf("kubelet.service"))
+		Expect(osc.Status.CloudConfig).NotTo(BeNil())
+
+		secret := &corev1.Secret{}
+		Expect(c.Get(ctx, kutil.Key(osc.Status.CloudConfig.SecretRef.Namespace, osc.Status.CloudConfig.SecretRef.Name), secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue(extensionsv1alpha1.OperatingSystemConfigSecretDataKey, []byte("cloud-config")))
+		Expect(actuator.CallsOf(framework.OperationReconcile)).NotTo(BeEmpty())
+	})
+
+	It("should report errors of the actuator", func() {
+		actuator.SetReconcileError(errors.New("error"))
+
+		Expect(c.Create(ctx, osc)).To(Succeed())
+
+		Expect(framework.WaitForLastOperation(ctx, c, osc, gardencorev1alpha1.LastOperationTypeCreate, gardencorev1alpha1.LastOperationStateError, interval)).To(Succeed())
+		Expect(osc.Status.LastError).NotTo(BeNil())
+		Expect(osc.Status.CloudConfig).To(BeNil())
+	})
+
+	It("should delete the operating system config and remove the finalizer", func() {
+		Expect(c.Create(ctx, osc)).To(Succeed())
+		Expect(framework.WaitForLastOperation(ctx, c, osc, gardencorev1alpha1.LastOperationTypeCreate, gardencorev1alpha1.LastOperationStateSucceeded, interval)).To(Succeed())
+
+		Expect(c.Delete(ctx, osc)).To(Succeed())
+
+		Expect(framework.WaitForDeletion(ctx, c, osc, interval)).To(Succeed())
+		Expect(actuator.CallsOf(framework.OperationDelete)).NotTo(BeEmpty())
+	})
+})
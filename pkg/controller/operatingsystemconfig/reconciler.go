@@ -21,6 +21,7 @@ import (
 
 	"github.com/gardener/gardener-extensions/pkg/controller"
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
 	"github.com/gardener/gardener-extensions/pkg/util"
 	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
 	gardencorev1alpha1helper "github.com/gardener/gardener/pkg/apis/core/v1alpha1/helper"
@@ -93,13 +94,19 @@ func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 		return reconcile.Result{}, err
 	}
 
+	// The cluster is only used to enrich the log values of this reconciliation, hence failing to read it is not fatal.
+	cluster, _ := extensionscontroller.GetCluster(r.ctx, r.client, osc.Namespace)
+	ctx := extensionscontroller.ContextWithLogger(r.ctx, extensionscontroller.ReconcileLogger(r.logger, osc, cluster))
+
 	if osc.DeletionTimestamp != nil {
-		return r.delete(r.ctx, osc)
+		return r.delete(ctx, osc)
 	}
-	return r.reconcile(r.ctx, osc)
+	return r.reconcile(ctx, osc)
 }
 
 func (r *reconciler) reconcile(ctx context.Context, osc *extensionsv1alpha1.OperatingSystemConfig) (reconcile.Result, error) {
+	logger := extensionscontroller.LoggerFromContext(ctx, r.logger)
+
 	if err := extensionscontroller.EnsureFinalizer(ctx, r.client, FinalizerName, osc); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -109,12 +116,20 @@ func (r *reconciler) reconcile(ctx context.Context, osc *extensionsv1alpha1.Oper
 		return reconcile.Result{}, err
 	}
 
-	r.logger.Info("Starting the reconciliation of operating system config", "osc", osc.Name)
-	userData, command, units, err := r.actuator.Reconcile(ctx, osc)
-	if err != nil {
+	logger.Info("Starting the reconciliation of operating system config", "osc", osc.Name)
+	var (
+		userData []byte
+		command  *string
+		units    []string
+	)
+	if err := tracing.TraceReconcile(ctx, "OperatingSystemConfig", osc, osc.Spec.Type, operationType, func(ctx context.Context) error {
+		var err error
+		userData, command, units, err = r.actuator.Reconcile(ctx, osc)
+		return err
+	}); err != nil {
 		msg := "Error reconciling operating system config"
 		utilruntime.HandleError(r.updateStatusError(ctx, extensionscontroller.ReconcileErrCauseOrErr(err), osc, operationType, msg))
-		r.logger.Error(err, msg, "osc", osc.Name)
+		logger.Error(err, msg, "osc", osc.Name)
 		return extensionscontroller.ReconcileErr(err)
 	}
 
@@ -129,7 +144,7 @@ func (r *reconciler) reconcile(ctx context.Context, osc *extensionsv1alpha1.Oper
 	}); err != nil {
 		msg := "Could not apply secret for generated cloud config"
 		utilruntime.HandleError(r.updateStatusError(ctx, extensionscontroller.ReconcileErrCauseOrErr(err), osc, operationType, msg))
-		r.logger.Error(err, msg, "osc", osc.Name)
+		logger.Error(err, msg, "osc", osc.Name)
 		return extensionscontroller.ReconcileErr(err)
 	}
 
@@ -145,7 +160,7 @@ func (r *reconciler) reconcile(ctx context.Context, osc *extensionsv1alpha1.Oper
 	}
 
 	msg := "Successfully reconciled operating system config"
-	r.logger.Info(msg, "osc", osc.Name)
+	logger.Info(msg, "osc", osc.Name)
 	if err := r.updateStatusSuccess(ctx, osc, operationType, msg); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -154,13 +169,15 @@ func (r *reconciler) reconcile(ctx context.Context, osc *extensionsv1alpha1.Oper
 }
 
 func (r *reconciler) delete(ctx context.Context, osc *extensionsv1alpha1.OperatingSystemConfig) (reconcile.Result, error) {
+	logger := extensionscontroller.LoggerFromContext(ctx, r.logger)
+
 	hasFinalizer, err := extensionscontroller.HasFinalizer(osc, FinalizerName)
 	if err != nil {
-		r.logger.Error(err, "Could not instantiate finalizer deletion")
+		logger.Error(err, "Could not instantiate finalizer deletion")
 		return reconcile.Result{}, err
 	}
 	if !hasFinalizer {
-		r.logger.Info("Deleting operating system config causes a no-op as there is no finalizer.", "osc", osc.Name)
+		logger.Info("Deleting operating system config causes a no-op as there is no finalizer.", "osc", osc.Name)
 		return reconcile.Result{}, nil
 	}
 
@@ -169,23 +186,25 @@ func (r *reconciler) delete(ctx context.Context, osc *extensionsv1alpha1.Operati
 		return reconcile.Result{}, err
 	}
 
-	r.logger.Info("Starting the deletion of operating system config", "osc", osc.Name)
-	if err := r.actuator.Delete(ctx, osc); err != nil {
+	logger.Info("Starting the deletion of operating system config", "osc", osc.Name)
+	if err := tracing.TraceReconcile(ctx, "OperatingSystemConfig", osc, osc.Spec.Type, operationType, func(ctx context.Context) error {
+		return r.actuator.Delete(ctx, osc)
+	}); err != nil {
 		msg := "Error deleting operating system config"
 		utilruntime.HandleError(r.updateStatusError(ctx, extensionscontroller.ReconcileErrCauseOrErr(err), osc, operationType, msg))
-		r.logger.Error(err, msg, "osc", osc.Name)
+		logger.Error(err, msg, "osc", osc.Name)
 		return extensionscontroller.ReconcileErr(err)
 	}
 
 	msg := "Successfully deleted operating system config"
-	r.logger.Info(msg, "osc", osc.Name)
+	logger.Info(msg, "osc", osc.Name)
 	if err := r.updateStatusSuccess(ctx, osc, operationType, msg); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	r.logger.Info("Removing finalizer.", "osc", osc.Name)
+	logger.Info("Removing finalizer.", "osc", osc.Name)
 	if err := extensionscontroller.DeleteFinalizer(ctx, r.client, FinalizerName, osc); err != nil {
-		r.logger.Error(err, "Error removing finalizer from operating system config", "osc", osc.Name)
+		logger.Error(err, "Error removing finalizer from operating system config", "osc", osc.Name)
 		return reconcile.Result{}, err
 	}
 
@@ -0,0 +1,193 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registration
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// DeploymentTypeHelm is the type of a ControllerDeployment that deploys a Helm chart.
+const DeploymentTypeHelm = "helm"
+
+// ChartModTime is the modification time of all files of a packaged chart. Using a constant time makes
+// packaging idempotent.
+var ChartModTime = time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// PackageChart packages the chart in the given directory with the given version as gzipped tarball.
+// As opposed to `helm package`, the result only depends on the content of the chart, hence packaging the
+// same chart twice yields the same bytes.
+func PackageChart(dir, version string) ([]byte, error) {
+	c, err := chartutil.LoadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not load chart from %s: %v", dir, err)
+	}
+	c.Metadata.Version = version
+	c.Metadata.AppVersion = version
+
+	var buf bytes.Buffer
+	zipper := gzip.NewWriter(&buf)
+	twriter := tar.NewWriter(zipper)
+	if err := writeChart(twriter, c, ""); err != nil {
+		return nil, err
+	}
+	if err := twriter.Close(); err != nil {
+		return nil, err
+	}
+	if err := zipper.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeChart(out *tar.Writer, c *chart.Chart, prefix string) error {
+	base := path.Join(prefix, c.Metadata.Name)
+
+	chartFile, err := yaml.Marshal(c.Metadata)
+	if err != nil {
+		return err
+	}
+	files := map[string][]byte{path.Join(base, chartutil.ChartfileName): chartFile}
+	if c.Values != nil && len(c.Values.Raw) > 0 {
+		files[path.Join(base, chartutil.ValuesfileName)] = []byte(c.Values.Raw)
+	}
+	for _, template := range c.Templates {
+		files[path.Join(base, template.Name)] = template.Data
+	}
+	for _, file := range c.Files {
+		files[path.Join(base, file.TypeUrl)] = file.Value
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := writeFile(out, name, files[name]); err != nil {
+			return err
+		}
+	}
+
+	dependencies := append([]*chart.Chart(nil), c.Dependencies...)
+	sort.Slice(dependencies, func(i, j int) bool { return dependencies[i].Metadata.Name < dependencies[j].Metadata.Name })
+	for _, dependency := range dependencies {
+		if err := writeChart(out, dependency, path.Join(base, "charts")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(out *tar.Writer, name string, data []byte) error {
+	if err := out.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0755,
+		Size:     int64(len(data)),
+		ModTime:  ChartModTime,
+		Typeflag: tar.TypeReg,
+		Uname:    "root",
+		Gname:    "root",
+	}); err != nil {
+		return err
+	}
+	_, err := out.Write(data)
+	return err
+}
+
+// HelmProviderConfig is the provider configuration of a ControllerDeployment of type DeploymentTypeHelm.
+type HelmProviderConfig struct {
+	// Chart is the packaged chart.
+	Chart []byte `json:"chart"`
+	// Values are the values the chart is deployed with.
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+// New creates a new ControllerRegistration with the given name for the given resources that deploys the given
+// packaged chart. The image tag of the chart is set to the given version. An extension without controllers gets an
+// empty list of resources.
+func New(name string, resources []gardencorev1alpha1.ControllerResource, chart []byte, version string) (*gardencorev1alpha1.ControllerRegistration, error) {
+	if resources == nil {
+		resources = []gardencorev1alpha1.ControllerResource{}
+	}
+
+	providerConfig, err := json.Marshal(&HelmProviderConfig{
+		Chart: chart,
+		Values: map[string]interface{}{
+			"image": map[string]interface{}{
+				"tag": version,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &gardencorev1alpha1.ControllerRegistration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gardencorev1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ControllerRegistration",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: gardencorev1alpha1.ControllerRegistrationSpec{
+			Resources: resources,
+			Deployment: &gardencorev1alpha1.ControllerDeployment{
+				Type: DeploymentTypeHelm,
+				ProviderConfig: &gardencorev1alpha1.ProviderConfig{
+					RawExtension: runtime.RawExtension{Raw: providerConfig},
+				},
+			},
+		},
+	}, nil
+}
+
+// Marshal marshals the given ControllerRegistration as YAML document. The unset creation timestamp is omitted
+// instead of being rendered as `null`.
+func Marshal(registration *gardencorev1alpha1.ControllerRegistration) ([]byte, error) {
+	raw, err := json.Marshal(registration)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok && metadata["creationTimestamp"] == nil {
+		delete(metadata, "creationTimestamp")
+	}
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte("---\n"), data...), nil
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registration
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	"github.com/ghodss/yaml"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRegistration(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Registration Suite")
+}
+
+func writeFiles(dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		ExpectWithOffset(1, os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		ExpectWithOffset(1, ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+}
+
+func readPackagedChart(data []byte) map[string]*tar.Header {
+	zipReader, err := gzip.NewReader(bytes.NewReader(data))
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+	headers := make(map[string]*tar.Header)
+	tarReader := tar.NewReader(zipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		headers[header.Name] = header
+	}
+	return headers
+}
+
+var _ = Describe("Registration", func() {
+	var (
+		dir string
+
+		resources = []gardencorev1alpha1.ControllerResource{
+			{Kind: "Infrastructure", Type: "foo"},
+		}
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "registration")
+		Expect(err).NotTo(HaveOccurred())
+
+		writeFiles(dir, map[string]string{
+			"Chart.yaml":                          "apiVersion: v1\nname: foo\nversion: 0.1.0\n",
+			"values.yaml":                         "image:\n  tag: latest\n",
+			"templates/deployment.yaml":           "kind: Deployment\n",
+			"charts/bar/Chart.yaml":               "apiVersion: v1\nname: bar\nversion: 0.1.0\n",
+			"charts/bar/templates/service.yaml":   "kind: Service\n",
+			"charts/bar/templates/configmap.yaml": "kind: ConfigMap\n",
+		})
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	Describe("#PackageChart", func() {
+		It("should package all files of the chart and its dependencies", func() {
+			data, err := PackageChart(dir, "1.2.3")
+			Expect(err).NotTo(HaveOccurred())
+
+			headers := readPackagedChart(data)
+			Expect(headers).To(HaveLen(6))
+			Expect(headers).To(HaveKey("foo/Chart.yaml"))
+			Expect(headers).To(HaveKey("foo/values.yaml"))
+			Expect(headers).To(HaveKey("foo/templates/deployment.yaml"))
+			Expect(headers).To(HaveKey("foo/charts/bar/Chart.yaml"))
+			Expect(headers).To(HaveKey("foo/charts/bar/templates/service.yaml"))
+			Expect(headers).To(HaveKey("foo/charts/bar/templates/configmap.yaml"))
+			for _, header := range headers {
+				Expect(header.ModTime.Equal(ChartModTime)).To(BeTrue())
+			}
+		})
+
+		It("should yield the same bytes when packaging the same chart twice", func() {
+			first, err := PackageChart(dir, "1.2.3")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.Chtimes(filepath.Join(dir, "values.yaml"), ChartModTime, ChartModTime)).To(Succeed())
+
+			second, err := PackageChart(dir, "1.2.3")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).To(Equal(first))
+		})
+
+		It("should yield different bytes for a different version", func() {
+			first, err := PackageChart(dir, "1.2.3")
+			Expect(err).NotTo(HaveOccurred())
+
+			second, err := PackageChart(dir, "1.2.4")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).NotTo(Equal(first))
+		})
+
+		It("should fail if the directory does not contain a chart", func() {
+			_, err := PackageChart(filepath.Join(dir, "templates"), "1.2.3")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#New", func() {
+		It("should create a ControllerRegistration deploying the chart with the version as image tag", func() {
+			chart := []byte("chart")
+
+			reg, err := New("foo", resources, chart, "1.2.3")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reg.Name).To(Equal("foo"))
+			Expect(reg.Kind).To(Equal("ControllerRegistration"))
+			Expect(reg.APIVersion).To(Equal(gardencorev1alpha1.SchemeGroupVersion.String()))
+			Expect(reg.Spec.Resources).To(Equal(resources))
+			Expect(reg.Spec.Deployment.Type).To(Equal(DeploymentTypeHelm))
+
+			providerConfig := &HelmProviderConfig{}
+			Expect(json.Unmarshal(reg.Spec.Deployment.ProviderConfig.Raw, providerConfig)).To(Succeed())
+			Expect(providerConfig).To(Equal(&HelmProviderConfig{
+				Chart: chart,
+				Values: map[string]interface{}{
+					"image": map[string]interface{}{
+						"tag": "1.2.3",
+					},
+				},
+			}))
+		})
+	})
+
+	Describe("#Marshal", func() {
+		It("should marshal the ControllerRegistration as YAML document", func() {
+			reg, err := New("foo", resources, []byte("chart"), "1.2.3")
+			Expect(err).NotTo(HaveOccurred())
+
+			data, err := Marshal(reg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(HavePrefix("---\n"))
+
+			actual := &gardencorev1alpha1.ControllerRegistration{}
+			Expect(yaml.Unmarshal(data, actual)).To(Succeed())
+			Expect(actual.Name).To(Equal("foo"))
+			Expect(actual.Spec.Resources).To(Equal(resources))
+		})
+
+		It("should omit the unset creation timestamp", func() {
+			reg, err := New("foo", resources, []byte("chart"), "1.2.3")
+			Expect(err).NotTo(HaveOccurred())
+
+			data, err := Marshal(reg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).NotTo(ContainSubstring("creationTimestamp"))
+		})
+
+		It("should marshal an empty list of resources for an extension without controllers", func() {
+			reg, err := New("foo", nil, []byte("chart"), "1.2.3")
+			Expect(err).NotTo(HaveOccurred())
+
+			data, err := Marshal(reg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring("resources: []\n"))
+		})
+	})
+})
@@ -0,0 +1,245 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Exporter exports finished spans to a tracing backend.
+type Exporter interface {
+	// ExportSpans exports the given batch of finished spans.
+	ExportSpans(ctx context.Context, spans []*Span) error
+	// Shutdown releases all resources held by the exporter.
+	Shutdown(ctx context.Context) error
+}
+
+type nopExporter struct{}
+
+// ExportSpans implements Exporter.
+func (nopExporter) ExportSpans(context.Context, []*Span) error { return nil }
+
+// Shutdown implements Exporter.
+func (nopExporter) Shutdown(context.Context) error { return nil }
+
+// NopExporter is an Exporter that discards all spans.
+var NopExporter Exporter = nopExporter{}
+
+type fileExporter struct {
+	lock    sync.Mutex
+	writer  io.WriteCloser
+	encoder *json.Encoder
+}
+
+// NewFileExporter creates an Exporter that appends every span as a single JSON object per line
+// to the file at the given path. The file is created if it does not exist.
+func NewFileExporter(path string) (Exporter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriterExporter(file), nil
+}
+
+// NewWriterExporter creates an Exporter that writes every span as a single JSON object per line
+// to the given writer. The writer is closed on Shutdown.
+func NewWriterExporter(writer io.WriteCloser) Exporter {
+	return &fileExporter{writer: writer, encoder: json.NewEncoder(writer)}
+}
+
+// ExportSpans implements Exporter.
+func (e *fileExporter) ExportSpans(_ context.Context, spans []*Span) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for _, span := range spans {
+		if err := e.encoder.Encode(span); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown implements Exporter.
+func (e *fileExporter) Shutdown(context.Context) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.writer.Close()
+}
+
+// OTLPTracesPath is the path of the OTLP/HTTP traces endpoint.
+const OTLPTracesPath = "/v1/traces"
+
+type otlpExporter struct {
+	url         string
+	serviceName string
+	client      *http.Client
+}
+
+// NewOTLPExporter creates an Exporter that sends spans in the OTLP/HTTP JSON encoding to the collector
+// at the given endpoint (e.g. `http://otel-collector:4318`). The given service name is reported as
+// `service.name` resource attribute.
+func NewOTLPExporter(endpoint, serviceName string, client *http.Client) Exporter {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &otlpExporter{
+		url:         strings.TrimSuffix(endpoint, "/") + OTLPTracesPath,
+		serviceName: serviceName,
+		client:      client,
+	}
+}
+
+// ExportSpans implements Exporter.
+func (e *otlpExporter) ExportSpans(ctx context.Context, spans []*Span) error {
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("could not export %d spans to %s: %s: %s", len(spans), e.url, resp.Status, msg)
+	}
+	return nil
+}
+
+// Shutdown implements Exporter.
+func (e *otlpExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusCodeOK     = 1
+	otlpStatusCodeError  = 2
+
+	otlpScopeName = "github.com/gardener/gardener-extensions/pkg/controller/tracing"
+)
+
+func (e *otlpExporter) request(spans []*Span) *otlpRequest {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		s := otlpSpan{
+			TraceID:           span.TraceID,
+			SpanID:            span.SpanID,
+			ParentSpanID:      span.ParentSpanID,
+			Name:              span.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+			Status:            otlpStatus{Code: otlpStatusCodeOK},
+		}
+		if span.Error != "" {
+			s.Status = otlpStatus{Code: otlpStatusCodeError, Message: span.Error}
+		}
+		out = append(out, s)
+	}
+
+	return &otlpRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: otlpAttributes(map[string]string{"service.name": e.serviceName}),
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{Name: otlpScopeName},
+						Spans: out,
+					},
+				},
+			},
+		},
+	}
+}
+
+func otlpAttributes(attributes map[string]string) []otlpKeyValue {
+	out := make([]otlpKeyValue, 0, len(attributes))
+	for key, value := range attributes {
+		out = append(out, otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: value}})
+	}
+	return out
+}
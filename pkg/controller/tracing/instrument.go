@@ -0,0 +1,82 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils/flow"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TaskFn wraps the given flow.TaskFn so that every execution is recorded as a child span with the given name
+// of the span carried by the context passed to the flow.
+func TaskFn(name string, fn flow.TaskFn) flow.TaskFn {
+	return func(ctx context.Context) error {
+		return Trace(ctx, name, fn, "flow.task", name)
+	}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// NewTransport wraps the given http.RoundTripper so that every request is recorded as a child span
+// of the span carried by the request's context. If base is nil, http.DefaultTransport is used.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := Start(req.Context(), fmt.Sprintf("HTTP %s %s", req.Method, req.URL.Host),
+		"http.method", req.Method,
+		"http.host", req.URL.Host,
+		"http.path", req.URL.Path,
+	)
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+
+	span.SetAttributes("http.status_code", resp.StatusCode)
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.End(fmt.Errorf("%s", resp.Status))
+	} else {
+		span.End(nil)
+	}
+	return resp, nil
+}
+
+// TraceReconcile runs the given function within a new span describing the given operation on the given
+// extension object of the given kind and extension type.
+func TraceReconcile(ctx context.Context, kind string, obj metav1.Object, extensionType string, operationType gardencorev1alpha1.LastOperationType, fn func(context.Context) error) error {
+	return Trace(ctx, fmt.Sprintf("%s %s", operationType, kind), fn,
+		"object.kind", kind,
+		"object.namespace", obj.GetNamespace(),
+		"object.name", obj.GetName(),
+		"extension.type", extensionType,
+		"operation", operationType,
+	)
+}
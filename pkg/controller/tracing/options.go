@@ -0,0 +1,103 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// ExporterFlag is the name of the command line flag to specify the trace exporter.
+	ExporterFlag = "tracing-exporter"
+	// OTLPEndpointFlag is the name of the command line flag to specify the OTLP/HTTP collector endpoint.
+	OTLPEndpointFlag = "tracing-otlp-endpoint"
+	// FileFlag is the name of the command line flag to specify the file spans are written to.
+	FileFlag = "tracing-file"
+
+	// ExporterNone disables the export of spans.
+	ExporterNone = "none"
+	// ExporterOTLP exports spans to an OTLP/HTTP collector.
+	ExporterOTLP = "otlp"
+	// ExporterFile writes spans as JSON lines to a local file.
+	ExporterFile = "file"
+)
+
+// Options are command line options for tracing.
+type Options struct {
+	// ServiceName is the name of the service reported with every span.
+	ServiceName string
+	// Exporter is the exporter to use, one of `none`, `otlp` and `file`.
+	Exporter string
+	// OTLPEndpoint is the endpoint of the OTLP/HTTP collector.
+	OTLPEndpoint string
+	// File is the path of the file spans are written to.
+	File string
+
+	config *Config
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Exporter, ExporterFlag, o.Exporter, fmt.Sprintf("The trace exporter to use, one of %q, %q and %q.", ExporterNone, ExporterOTLP, ExporterFile))
+	fs.StringVar(&o.OTLPEndpoint, OTLPEndpointFlag, o.OTLPEndpoint, "The endpoint of the OTLP/HTTP collector, e.g. http://otel-collector:4318.")
+	fs.StringVar(&o.File, FileFlag, o.File, "The file spans are written to as JSON lines.")
+}
+
+// Complete implements Completer.Complete.
+func (o *Options) Complete() error {
+	var exporter Exporter
+
+	switch o.Exporter {
+	case "", ExporterNone:
+		exporter = NopExporter
+	case ExporterOTLP:
+		if o.OTLPEndpoint == "" {
+			return fmt.Errorf("--%s is required for exporter %q", OTLPEndpointFlag, ExporterOTLP)
+		}
+		exporter = NewOTLPExporter(o.OTLPEndpoint, o.ServiceName, nil)
+	case ExporterFile:
+		if o.File == "" {
+			return fmt.Errorf("--%s is required for exporter %q", FileFlag, ExporterFile)
+		}
+		fileExporter, err := NewFileExporter(o.File)
+		if err != nil {
+			return err
+		}
+		exporter = fileExporter
+	default:
+		return fmt.Errorf("unknown trace exporter %q", o.Exporter)
+	}
+
+	o.config = &Config{NewTracer(o.ServiceName, exporter)}
+	return nil
+}
+
+// Completed returns the completed Config. Only call this if `Complete` was successful.
+func (o *Options) Completed() *Config {
+	return o.config
+}
+
+// Config is a completed tracing configuration.
+type Config struct {
+	// Tracer is the Tracer created from the options.
+	Tracer *Tracer
+}
+
+// Apply sets the Tracer of this Config as the global Tracer.
+func (c *Config) Apply() {
+	SetTracer(c.Tracer)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+)
+
+const (
+	defaultBatchSize    = 128
+	defaultBatchTimeout = 5 * time.Second
+)
+
+// batchProcessor collects finished spans and exports them in batches, either when the batch is full
+// or when the batch timeout elapsed.
+type batchProcessor struct {
+	exporter Exporter
+	size     int
+
+	lock    sync.Mutex
+	batch   []*Span
+	stopped bool
+
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+func newBatchProcessor(exporter Exporter, size int, timeout time.Duration) *batchProcessor {
+	p := &batchProcessor{
+		exporter: exporter,
+		size:     size,
+		flushCh:  make(chan struct{}, 1),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+	go p.run(timeout)
+	return p
+}
+
+func (p *batchProcessor) onEnd(span *Span) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.stopped {
+		return
+	}
+
+	p.batch = append(p.batch, span)
+	if len(p.batch) >= p.size {
+		select {
+		case p.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (p *batchProcessor) run(timeout time.Duration) {
+	defer close(p.doneCh)
+
+	ticker := time.NewTicker(timeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-p.flushCh:
+		case <-ticker.C:
+		}
+		utilruntime.HandleError(p.flush(context.Background()))
+	}
+}
+
+func (p *batchProcessor) flush(ctx context.Context) error {
+	p.lock.Lock()
+	batch := p.batch
+	p.batch = nil
+	p.lock.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	return p.exporter.ExportSpans(ctx, batch)
+}
+
+func (p *batchProcessor) shutdown(ctx context.Context) error {
+	p.lock.Lock()
+	if p.stopped {
+		p.lock.Unlock()
+		return nil
+	}
+	p.stopped = true
+	p.lock.Unlock()
+
+	close(p.stopCh)
+	<-p.doneCh
+
+	if err := p.flush(ctx); err != nil {
+		return err
+	}
+	return p.exporter.Shutdown(ctx)
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Span is a single timed operation of a trace. Spans started from a context that already carries a span
+// become children of that span and share its trace ID.
+type Span struct {
+	// TraceID is the hex encoded 16 byte ID of the trace this span belongs to.
+	TraceID string `json:"traceId"`
+	// SpanID is the hex encoded 8 byte ID of this span.
+	SpanID string `json:"spanId"`
+	// ParentSpanID is the ID of the parent span. It is empty for root spans.
+	ParentSpanID string `json:"parentSpanId,omitempty"`
+	// Name is the name of the operation this span describes.
+	Name string `json:"name"`
+	// StartTime is the time the span has been started.
+	StartTime time.Time `json:"startTime"`
+	// EndTime is the time the span has been ended.
+	EndTime time.Time `json:"endTime"`
+	// Attributes are additional key-value pairs describing the operation.
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Error is the error message in case the operation failed.
+	Error string `json:"error,omitempty"`
+
+	tracer *Tracer
+	lock   sync.Mutex
+	ended  bool
+}
+
+// SetAttributes adds the given key-value pairs to the attributes of the span.
+func (s *Span) SetAttributes(keysAndValues ...interface{}) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	setAttributes(s.Attributes, keysAndValues)
+}
+
+// End ends the span, recording the given error if it is non-nil, and hands it to the exporter of its tracer.
+// Subsequent calls to End are no-ops.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	if err != nil {
+		s.Error = err.Error()
+	}
+	s.lock.Unlock()
+
+	s.tracer.processor.onEnd(s)
+}
+
+// Duration returns the duration of the span. It is zero if the span has not been ended yet.
+func (s *Span) Duration() time.Duration {
+	if s.EndTime.IsZero() {
+		return 0
+	}
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// Tracer starts spans and hands finished spans to an Exporter.
+type Tracer struct {
+	serviceName string
+	processor   *batchProcessor
+}
+
+// NewTracer creates a new Tracer for the given service name that exports finished spans to the given exporter.
+func NewTracer(serviceName string, exporter Exporter) *Tracer {
+	return &Tracer{
+		serviceName: serviceName,
+		processor:   newBatchProcessor(exporter, defaultBatchSize, defaultBatchTimeout),
+	}
+}
+
+// ServiceName returns the name of the service this Tracer records spans for.
+func (t *Tracer) ServiceName() string {
+	return t.serviceName
+}
+
+// Start starts a new span with the given name and attributes. If the given context carries a span,
+// the new span is a child of it. The returned context carries the new span.
+func (t *Tracer) Start(ctx context.Context, name string, keysAndValues ...interface{}) (context.Context, *Span) {
+	span := &Span{
+		SpanID:     newID(8),
+		Name:       name,
+		StartTime:  time.Now(),
+		Attributes: make(map[string]string, len(keysAndValues)/2),
+		tracer:     t,
+	}
+
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.TraceID = parent.TraceID
+		span.ParentSpanID = parent.SpanID
+	} else {
+		span.TraceID = newID(16)
+	}
+	setAttributes(span.Attributes, keysAndValues)
+
+	return ContextWithSpan(ctx, span), span
+}
+
+// Shutdown flushes all pending spans and shuts down the exporter of this Tracer.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	return t.processor.shutdown(ctx)
+}
+
+var (
+	globalTracerLock sync.RWMutex
+	globalTracer     = NewTracer("", NopExporter)
+)
+
+// SetTracer sets the global Tracer used by Start and Trace.
+func SetTracer(tracer *Tracer) {
+	globalTracerLock.Lock()
+	defer globalTracerLock.Unlock()
+	globalTracer = tracer
+}
+
+// GetTracer returns the global Tracer.
+func GetTracer() *Tracer {
+	globalTracerLock.RLock()
+	defer globalTracerLock.RUnlock()
+	return globalTracer
+}
+
+// Start starts a new span with the global Tracer. See Tracer.Start.
+func Start(ctx context.Context, name string, keysAndValues ...interface{}) (context.Context, *Span) {
+	return GetTracer().Start(ctx, name, keysAndValues...)
+}
+
+// Trace runs the given function within a new span with the given name and attributes. The span is ended
+// with the error returned by the function.
+func Trace(ctx context.Context, name string, fn func(context.Context) error, keysAndValues ...interface{}) error {
+	ctx, span := Start(ctx, name, keysAndValues...)
+	err := fn(ctx)
+	span.End(err)
+	return err
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpan returns a new context carrying the given span.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromContext returns the span carried by the given context or nil if there is none.
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+func setAttributes(attributes map[string]string, keysAndValues []interface{}) {
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		attributes[fmt.Sprint(keysAndValues[i])] = fmt.Sprint(keysAndValues[i+1])
+	}
+}
+
+func newID(length int) string {
+	id := make([]byte, length)
+	if _, err := rand.Read(id); err != nil {
+		panic(fmt.Sprintf("could not generate random ID: %v", err))
+	}
+	return hex.EncodeToString(id)
+}
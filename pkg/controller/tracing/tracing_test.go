@@ -0,0 +1,192 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTracing(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tracing Suite")
+}
+
+type recordingExporter struct {
+	lock  sync.Mutex
+	spans []*Span
+}
+
+func (r *recordingExporter) ExportSpans(_ context.Context, spans []*Span) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+func (r *recordingExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+type nopCloser struct {
+	*bytes.Buffer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+var _ = Describe("Tracing", func() {
+	var ctx = context.TODO()
+
+	Describe("Tracer", func() {
+		It("should start child spans within the trace of the parent span", func() {
+			exporter := &recordingExporter{}
+			tracer := NewTracer("test", exporter)
+
+			parentCtx, parent := tracer.Start(ctx, "parent", "foo", "bar")
+			childCtx, child := tracer.Start(parentCtx, "child")
+			child.End(errors.New("boom"))
+			parent.End(nil)
+
+			Expect(SpanFromContext(parentCtx)).To(BeIdenticalTo(parent))
+			Expect(SpanFromContext(childCtx)).To(BeIdenticalTo(child))
+			Expect(child.TraceID).To(Equal(parent.TraceID))
+			Expect(child.ParentSpanID).To(Equal(parent.SpanID))
+			Expect(parent.ParentSpanID).To(BeEmpty())
+			Expect(parent.Attributes).To(Equal(map[string]string{"foo": "bar"}))
+
+			Expect(tracer.Shutdown(ctx)).To(Succeed())
+			Expect(exporter.spans).To(HaveLen(2))
+			Expect(exporter.spans[0].Name).To(Equal("child"))
+			Expect(exporter.spans[0].Error).To(Equal("boom"))
+			Expect(exporter.spans[1].Name).To(Equal("parent"))
+		})
+
+		It("should export a span only once", func() {
+			exporter := &recordingExporter{}
+			tracer := NewTracer("test", exporter)
+
+			_, span := tracer.Start(ctx, "span")
+			span.End(nil)
+			span.End(nil)
+
+			Expect(tracer.Shutdown(ctx)).To(Succeed())
+			Expect(exporter.spans).To(HaveLen(1))
+		})
+	})
+
+	Describe("#Trace", func() {
+		It("should run the function within a span and record its error", func() {
+			exporter := &recordingExporter{}
+			defer SetTracer(GetTracer())
+			SetTracer(NewTracer("test", exporter))
+
+			err := Trace(ctx, "op", func(ctx context.Context) error {
+				Expect(SpanFromContext(ctx)).NotTo(BeNil())
+				return errors.New("failed")
+			}, "key", 1)
+
+			Expect(err).To(MatchError("failed"))
+			Expect(GetTracer().Shutdown(ctx)).To(Succeed())
+			Expect(exporter.spans).To(HaveLen(1))
+			Expect(exporter.spans[0].Attributes).To(HaveKeyWithValue("key", "1"))
+			Expect(exporter.spans[0].Error).To(Equal("failed"))
+		})
+	})
+
+	Describe("#NewWriterExporter", func() {
+		It("should write one JSON object per span", func() {
+			buf := &bytes.Buffer{}
+			tracer := NewTracer("test", NewWriterExporter(nopCloser{buf}))
+
+			_, span := tracer.Start(ctx, "span")
+			span.End(nil)
+			Expect(tracer.Shutdown(ctx)).To(Succeed())
+
+			out := &Span{}
+			Expect(json.Unmarshal(buf.Bytes(), out)).To(Succeed())
+			Expect(out.Name).To(Equal("span"))
+			Expect(out.SpanID).To(Equal(span.SpanID))
+		})
+	})
+
+	Describe("#NewOTLPExporter", func() {
+		It("should post the spans in the OTLP/HTTP JSON encoding", func() {
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				Expect(r.URL.Path).To(Equal(OTLPTracesPath))
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				var err error
+				body, err = ioutil.ReadAll(r.Body)
+				Expect(err).NotTo(HaveOccurred())
+			}))
+			defer server.Close()
+
+			tracer := NewTracer("test-service", NewOTLPExporter(server.URL, "test-service", nil))
+			_, span := tracer.Start(ctx, "span")
+			span.End(errors.New("boom"))
+			Expect(tracer.Shutdown(ctx)).To(Succeed())
+
+			request := &otlpRequest{}
+			Expect(json.Unmarshal(body, request)).To(Succeed())
+			Expect(request.ResourceSpans).To(HaveLen(1))
+			Expect(request.ResourceSpans[0].Resource.Attributes).To(ConsistOf(otlpKeyValue{Key: "service.name", Value: otlpAnyValue{StringValue: "test-service"}}))
+			spans := request.ResourceSpans[0].ScopeSpans[0].Spans
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].TraceID).To(Equal(span.TraceID))
+			Expect(spans[0].Status).To(Equal(otlpStatus{Code: otlpStatusCodeError, Message: "boom"}))
+		})
+
+		It("should fail if the collector rejects the spans", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			}))
+			defer server.Close()
+
+			Expect(NewOTLPExporter(server.URL, "test", nil).ExportSpans(ctx, []*Span{{Name: "span"}})).NotTo(Succeed())
+		})
+	})
+
+	Describe("Options", func() {
+		It("should require an endpoint for the OTLP exporter", func() {
+			opts := &Options{Exporter: ExporterOTLP}
+			Expect(opts.Complete()).NotTo(Succeed())
+		})
+
+		It("should reject unknown exporters", func() {
+			opts := &Options{Exporter: "foo"}
+			Expect(opts.Complete()).NotTo(Succeed())
+		})
+
+		It("should create a tracer with the service name", func() {
+			opts := &Options{ServiceName: "test"}
+			Expect(opts.Complete()).To(Succeed())
+			Expect(opts.Completed().Tracer.ServiceName()).To(Equal("test"))
+		})
+	})
+})
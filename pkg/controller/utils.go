@@ -22,6 +22,7 @@ import (
 	controllererror "github.com/gardener/gardener-extensions/pkg/controller/error"
 	"github.com/gardener/gardener-extensions/pkg/util"
 
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -114,6 +115,30 @@ func (a *AddToManagerBuilder) AddToManager(m manager.Manager) error {
 	return nil
 }
 
+// Registry aggregates the AddToManager functions of controllers together with the kinds and types of the
+// extension resources they handle, so that the resources of the ControllerRegistration of an extension are
+// derived from its controllers.
+type Registry struct {
+	builder   AddToManagerBuilder
+	resources []gardencorev1alpha1.ControllerResource
+}
+
+// Register registers the given AddToManager function of a controller for the resources of the given kind and type.
+func (r *Registry) Register(kind, typeName string, addToManager func(manager.Manager) error) {
+	r.builder.Register(addToManager)
+	r.resources = append(r.resources, gardencorev1alpha1.ControllerResource{Kind: kind, Type: typeName})
+}
+
+// AddToManager adds all registered controllers to the given manager. It exits on the first error and returns it.
+func (r *Registry) AddToManager(m manager.Manager) error {
+	return r.builder.AddToManager(m)
+}
+
+// ControllerResources returns the kinds and types of the resources of all registered controllers.
+func (r *Registry) ControllerResources() []gardencorev1alpha1.ControllerResource {
+	return append([]gardencorev1alpha1.ControllerResource(nil), r.resources...)
+}
+
 func finalizersAndAccessorOf(obj runtime.Object) (sets.String, metav1.Object, error) {
 	accessor, err := meta.Accessor(obj)
 	if err != nil {
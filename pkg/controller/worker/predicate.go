@@ -0,0 +1,59 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"strings"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// TypePredicate filters the incoming Worker resources for ones that have the same type
+// as the given type.
+func TypePredicate(typeName string) predicate.Predicate {
+	typeMatches := func(obj runtime.Object) bool {
+		if worker, ok := obj.(*extensionsv1alpha1.Worker); ok {
+			return strings.ToLower(worker.Spec.Type) == typeName
+		}
+		return false
+	}
+
+	return predicate.Funcs{
+		CreateFunc: func(event event.CreateEvent) bool {
+			return typeMatches(event.Object)
+		},
+		UpdateFunc: func(event event.UpdateEvent) bool {
+			return typeMatches(event.ObjectNew)
+		},
+		DeleteFunc: func(event event.DeleteEvent) bool {
+			return typeMatches(event.Object)
+		},
+		GenericFunc: func(event event.GenericEvent) bool {
+			return typeMatches(event.Object)
+		},
+	}
+}
+
+// GenerationChangedPredicate is a predicate for generation changes.
+func GenerationChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(event event.UpdateEvent) bool {
+			return event.MetaOld.GetGeneration() != event.MetaNew.GetGeneration()
+		},
+	}
+}
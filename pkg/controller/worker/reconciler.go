@@ -0,0 +1,195 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/gardener/gardener-extensions/pkg/controller/tracing"
+	"github.com/gardener/gardener-extensions/pkg/util"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	gardencorev1alpha1helper "github.com/gardener/gardener/pkg/apis/core/v1alpha1/helper"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+const (
+	// EventWorkerReconciliation an event reason to describe worker reconciliation.
+	EventWorkerReconciliation string = "WorkerReconciliation"
+	// EventWorkerDeletion an event reason to describe worker deletion.
+	EventWorkerDeletion string = "WorkerDeletion"
+)
+
+type reconciler struct {
+	logger   logr.Logger
+	actuator Actuator
+
+	ctx      context.Context
+	client   client.Client
+	recorder record.EventRecorder
+}
+
+// NewReconciler creates a new reconcile.Reconciler that reconciles
+// worker resources of Gardener's `extensions.gardener.cloud` API group.
+func NewReconciler(mgr manager.Manager, actuator Actuator) reconcile.Reconciler {
+	return &reconciler{
+		logger:   log.Log.WithName(ControllerName),
+		actuator: actuator,
+		recorder: mgr.GetRecorder(ControllerName),
+	}
+}
+
+func (r *reconciler) InjectFunc(f inject.Func) error {
+	return f(r.actuator)
+}
+
+func (r *reconciler) InjectClient(client client.Client) error {
+	r.client = client
+	return nil
+}
+
+func (r *reconciler) InjectStopChannel(stopCh <-chan struct{}) error {
+	r.ctx = util.ContextFromStopChannel(stopCh)
+	return nil
+}
+
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	worker := &extensionsv1alpha1.Worker{}
+	if err := r.client.Get(r.ctx, request.NamespacedName, worker); err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	cluster, err := extensionscontroller.GetCluster(r.ctx, r.client, worker.Namespace)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	ctx := extensionscontroller.ContextWithLogger(r.ctx, extensionscontroller.ReconcileLogger(r.logger, worker, cluster))
+
+	if worker.DeletionTimestamp != nil {
+		return r.delete(ctx, worker, cluster)
+	}
+	return r.reconcile(ctx, worker, cluster)
+}
+
+func (r *reconciler) reconcile(ctx context.Context, worker *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster) (reconcile.Result, error) {
+	logger := extensionscontroller.LoggerFromContext(ctx, r.logger)
+
+	if err := extensionscontroller.EnsureFinalizer(ctx, r.client, FinalizerName, worker); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	operationType := gardencorev1alpha1helper.ComputeOperationType(worker.ObjectMeta, worker.Status.LastOperation)
+	if err := r.updateStatusProcessing(ctx, worker, operationType, "Reconciling the worker"); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	logger.Info("Starting the reconciliation of worker", "worker", worker.Name)
+	r.recorder.Event(worker, corev1.EventTypeNormal, EventWorkerReconciliation, "Reconciling the worker")
+	if err := tracing.TraceReconcile(ctx, "Worker", worker, worker.Spec.Type, operationType, func(ctx context.Context) error {
+		return r.actuator.Reconcile(ctx, worker, cluster)
+	}); err != nil {
+		msg := "Error reconciling worker"
+		r.updateStatusError(ctx, extensionscontroller.ReconcileErrCauseOrErr(err), worker, operationType, msg)
+		logger.Error(err, msg, "worker", worker.Name)
+		return extensionscontroller.ReconcileErr(err)
+	}
+
+	msg := "Successfully reconciled worker"
+	logger.Info(msg, "worker", worker.Name)
+	r.recorder.Event(worker, corev1.EventTypeNormal, EventWorkerReconciliation, msg)
+	if err := r.updateStatusSuccess(ctx, worker, operationType, msg); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func (r *reconciler) delete(ctx context.Context, worker *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster) (reconcile.Result, error) {
+	logger := extensionscontroller.LoggerFromContext(ctx, r.logger)
+
+	hasFinalizer, err := extensionscontroller.HasFinalizer(worker, FinalizerName)
+	if err != nil {
+		logger.Error(err, "Could not instantiate finalizer deletion")
+		return reconcile.Result{}, err
+	}
+	if !hasFinalizer {
+		logger.Info("Deleting worker causes a no-op as there is no finalizer.", "worker", worker.Name)
+		return reconcile.Result{}, nil
+	}
+
+	operationType := gardencorev1alpha1helper.ComputeOperationType(worker.ObjectMeta, worker.Status.LastOperation)
+	if err := r.updateStatusProcessing(ctx, worker, operationType, "Deleting the worker"); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	logger.Info("Starting the deletion of worker", "worker", worker.Name)
+	r.recorder.Event(worker, corev1.EventTypeNormal, EventWorkerDeletion, "Deleting the worker")
+	if err := tracing.TraceReconcile(ctx, "Worker", worker, worker.Spec.Type, operationType, func(ctx context.Context) error {
+		return r.actuator.Delete(ctx, worker, cluster)
+	}); err != nil {
+		msg := "Error deleting worker"
+		r.recorder.Eventf(worker, corev1.EventTypeWarning, EventWorkerDeletion, "%s: %+v", msg, err)
+		r.updateStatusError(ctx, extensionscontroller.ReconcileErrCauseOrErr(err), worker, operationType, msg)
+		logger.Error(err, msg, "worker", worker.Name)
+		return extensionscontroller.ReconcileErr(err)
+	}
+
+	msg := "Successfully deleted worker"
+	logger.Info(msg, "worker", worker.Name)
+	r.recorder.Event(worker, corev1.EventTypeNormal, EventWorkerDeletion, msg)
+	if err := r.updateStatusSuccess(ctx, worker, operationType, msg); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	logger.Info("Removing finalizer.", "worker", worker.Name)
+	if err := extensionscontroller.DeleteFinalizer(ctx, r.client, FinalizerName, worker); err != nil {
+		logger.Error(err, "Error removing finalizer from Worker", "worker", worker.Name)
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func (r *reconciler) updateStatusProcessing(ctx context.Context, worker *extensionsv1alpha1.Worker, lastOperationType gardencorev1alpha1.LastOperationType, description string) error {
+	worker.Status.LastOperation = extensionscontroller.LastOperation(lastOperationType, gardencorev1alpha1.LastOperationStateProcessing, 1, description)
+	return r.client.Status().Update(ctx, worker)
+}
+
+func (r *reconciler) updateStatusError(ctx context.Context, err error, worker *extensionsv1alpha1.Worker, lastOperationType gardencorev1alpha1.LastOperationType, description string) error {
+	worker.Status.ObservedGeneration = worker.Generation
+	worker.Status.LastOperation, worker.Status.LastError = extensionscontroller.ReconcileError(lastOperationType, gardencorev1alpha1helper.FormatLastErrDescription(fmt.Errorf("%s: %v", description, err)), 50, gardencorev1alpha1helper.ExtractErrorCodes(err)...)
+	return r.client.Status().Update(ctx, worker)
+}
+
+func (r *reconciler) updateStatusSuccess(ctx context.Context, worker *extensionsv1alpha1.Worker, lastOperationType gardencorev1alpha1.LastOperationType, description string) error {
+	worker.Status.ObservedGeneration = worker.Generation
+	worker.Status.LastOperation, worker.Status.LastError = extensionscontroller.ReconcileSucceeded(lastOperationType, description)
+	return r.client.Status().Update(ctx, worker)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"testing"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestController(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Worker Suite")
+}
+
+var _ = Describe("Predicate", func() {
+	Describe("#TypePredicate", func() {
+		newWorker := func(typeName string) *extensionsv1alpha1.Worker {
+			return &extensionsv1alpha1.Worker{
+				Spec: extensionsv1alpha1.WorkerSpec{
+					DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: typeName},
+				},
+			}
+		}
+
+		It("should match workers of the given type", func() {
+			worker := newWorker("AWS")
+			predicate := TypePredicate("aws")
+
+			Expect(predicate.Create(event.CreateEvent{Object: worker})).To(BeTrue())
+			Expect(predicate.Update(event.UpdateEvent{ObjectNew: worker})).To(BeTrue())
+			Expect(predicate.Delete(event.DeleteEvent{Object: worker})).To(BeTrue())
+			Expect(predicate.Generic(event.GenericEvent{Object: worker})).To(BeTrue())
+		})
+
+		It("should not match workers of other types", func() {
+			worker := newWorker("gcp")
+			predicate := TypePredicate("aws")
+
+			Expect(predicate.Create(event.CreateEvent{Object: worker})).To(BeFalse())
+			Expect(predicate.Update(event.UpdateEvent{ObjectNew: worker})).To(BeFalse())
+		})
+
+		It("should not match other resources", func() {
+			predicate := TypePredicate("aws")
+
+			Expect(predicate.Create(event.CreateEvent{Object: &extensionsv1alpha1.ControlPlane{}})).To(BeFalse())
+		})
+	})
+})
@@ -12,6 +12,6 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:generate mockgen -package=cmd -destination=mocks.go github.com/gardener/gardener-extensions/pkg/controller/cmd Completer,Option,Flagger
+//go:generate mockgen -package=cmd -destination=mocks.go github.com/gardener/gardener-extensions/pkg/controller/cmd Completer,Option,Flagger,ExtensionOption
 
 package cmd
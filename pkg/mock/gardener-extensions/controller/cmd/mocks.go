@@ -1,13 +1,15 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/gardener/gardener-extensions/pkg/controller/cmd (interfaces: Completer,Option,Flagger)
+// Source: github.com/gardener/gardener-extensions/pkg/controller/cmd (interfaces: Completer,Option,Flagger,ExtensionOption)
 
 // Package cmd is a generated GoMock package.
 package cmd
 
 import (
+	v1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
 	gomock "github.com/golang/mock/gomock"
 	pflag "github.com/spf13/pflag"
 	reflect "reflect"
+	manager "sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 // MockCompleter is a mock of Completer interface
@@ -130,3 +132,80 @@ func (mr *MockFlaggerMockRecorder) AddFlags(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFlags", reflect.TypeOf((*MockFlagger)(nil).AddFlags), arg0)
 }
+
+// MockExtensionOption is a mock of ExtensionOption interface
+type MockExtensionOption struct {
+	ctrl     *gomock.Controller
+	recorder *MockExtensionOptionMockRecorder
+}
+
+// MockExtensionOptionMockRecorder is the mock recorder for MockExtensionOption
+type MockExtensionOptionMockRecorder struct {
+	mock *MockExtensionOption
+}
+
+// NewMockExtensionOption creates a new mock instance
+func NewMockExtensionOption(ctrl *gomock.Controller) *MockExtensionOption {
+	mock := &MockExtensionOption{ctrl: ctrl}
+	mock.recorder = &MockExtensionOptionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockExtensionOption) EXPECT() *MockExtensionOptionMockRecorder {
+	return m.recorder
+}
+
+// AddFlags mocks base method
+func (m *MockExtensionOption) AddFlags(arg0 *pflag.FlagSet) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddFlags", arg0)
+}
+
+// AddFlags indicates an expected call of AddFlags
+func (mr *MockExtensionOptionMockRecorder) AddFlags(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFlags", reflect.TypeOf((*MockExtensionOption)(nil).AddFlags), arg0)
+}
+
+// AddToManager mocks base method
+func (m *MockExtensionOption) AddToManager(arg0 manager.Manager) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToManager", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToManager indicates an expected call of AddToManager
+func (mr *MockExtensionOptionMockRecorder) AddToManager(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToManager", reflect.TypeOf((*MockExtensionOption)(nil).AddToManager), arg0)
+}
+
+// Complete mocks base method
+func (m *MockExtensionOption) Complete() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete
+func (mr *MockExtensionOptionMockRecorder) Complete() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockExtensionOption)(nil).Complete))
+}
+
+// ControllerResources mocks base method
+func (m *MockExtensionOption) ControllerResources() []v1alpha1.ControllerResource {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControllerResources")
+	ret0, _ := ret[0].([]v1alpha1.ControllerResource)
+	return ret0
+}
+
+// ControllerResources indicates an expected call of ControllerResources
+func (mr *MockExtensionOptionMockRecorder) ControllerResources() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControllerResources", reflect.TypeOf((*MockExtensionOption)(nil).ControllerResources))
+}
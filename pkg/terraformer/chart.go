@@ -0,0 +1,58 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformer
+
+import (
+	"github.com/gardener/gardener/pkg/chartrenderer"
+	"github.com/gardener/gardener/pkg/operation/terraformer"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// MainFile is the name of the Terraform main file of a rendered chart.
+	MainFile = "main.tf"
+	// VariablesFile is the name of the Terraform variables file of a rendered chart.
+	VariablesFile = "variables.tf"
+	// TFVarsFile is the name of the Terraform tfvars file of a rendered chart.
+	TFVarsFile = "terraform.tfvars"
+)
+
+// Files are the Terraform files that have been rendered from an infrastructure chart.
+type Files struct {
+	Main      string
+	Variables string
+	TFVars    []byte
+}
+
+// RenderChart renders the infrastructure chart at the given path with the given values and returns the
+// Terraform files contained in it.
+func RenderChart(renderer chartrenderer.Interface, chartPath, releaseName, namespace string, values map[string]interface{}) (*Files, error) {
+	release, err := renderer.Render(chartPath, releaseName, namespace, values)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Files{
+		Main:      release.FileContent(MainFile),
+		Variables: release.FileContent(VariablesFile),
+		TFVars:    []byte(release.FileContent(TFVarsFile)),
+	}, nil
+}
+
+// Initializer returns an Initializer that stores the files with the given client.
+func (f *Files) Initializer(c client.Client) terraformer.Initializer {
+	return terraformer.DefaultInitializer(c, f.Main, f.Variables, f.TFVars)
+}
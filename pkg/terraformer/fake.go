@@ -0,0 +1,230 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformer
+
+import (
+	"sync"
+
+	"github.com/gardener/gardener/pkg/operation/terraformer"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+)
+
+// Invocation is a recorded Apply or Destroy of a Fake.
+type Invocation struct {
+	// Purpose is the purpose of the Terraformer.
+	Purpose string
+	// Namespace is the namespace of the Terraformer.
+	Namespace string
+	// Name is the name of the Terraformer.
+	Name string
+	// VariablesEnvironment is the variables environment set at the time of the invocation.
+	VariablesEnvironment map[string]string
+	// Initialized indicates whether an Initializer was set at the time of the invocation.
+	Initialized bool
+}
+
+// Fake is an in-memory Terraformer for unit tests. It records applies and destroys and returns the
+// configured outputs. It is safe for concurrent use.
+type Fake struct {
+	// Purpose is the purpose of the Terraformer.
+	Purpose string
+	// Namespace is the namespace of the Terraformer.
+	Namespace string
+	// Name is the name of the Terraformer.
+	Name string
+
+	lock                 sync.Mutex
+	outputs              map[string]string
+	applyErr             error
+	destroyErr           error
+	configExists         bool
+	variablesEnvironment map[string]string
+	initializer          terraformer.Initializer
+	applies              []Invocation
+	destroys             []Invocation
+}
+
+// NewFake creates a new Fake whose state contains the given outputs once it has been applied.
+func NewFake(outputs map[string]string) *Fake {
+	return &Fake{outputs: outputs}
+}
+
+// SetOutputs sets the outputs that are returned once the Fake has been applied.
+func (f *Fake) SetOutputs(outputs map[string]string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.outputs = outputs
+}
+
+// SetApplyError sets the error returned by Apply.
+func (f *Fake) SetApplyError(err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.applyErr = err
+}
+
+// SetDestroyError sets the error returned by Destroy.
+func (f *Fake) SetDestroyError(err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.destroyErr = err
+}
+
+// SetConfigExists sets whether the configuration exists without an Apply.
+func (f *Fake) SetConfigExists(exists bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.configExists = exists
+}
+
+// Applies returns all recorded successful applies.
+func (f *Fake) Applies() []Invocation {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]Invocation(nil), f.applies...)
+}
+
+// Destroys returns all recorded successful destroys.
+func (f *Fake) Destroys() []Invocation {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]Invocation(nil), f.destroys...)
+}
+
+// Initializer returns the Initializer the Fake was initialized with, if any.
+func (f *Fake) Initializer() terraformer.Initializer {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.initializer
+}
+
+// SetVariablesEnvironment implements Interface.
+func (f *Fake) SetVariablesEnvironment(tfvarsEnvironment map[string]string) Interface {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.variablesEnvironment = tfvarsEnvironment
+	return f
+}
+
+// InitializeWith implements Interface. The Initializer is recorded but not run.
+func (f *Fake) InitializeWith(initializer terraformer.Initializer) Interface {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.initializer = initializer
+	return f
+}
+
+// Apply implements Interface.
+func (f *Fake) Apply() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.applyErr != nil {
+		return f.applyErr
+	}
+
+	f.applies = append(f.applies, f.invocation())
+	f.configExists = true
+	return nil
+}
+
+// Destroy implements Interface. After a successful destroy, the configuration does not exist anymore.
+func (f *Fake) Destroy() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.destroyErr != nil {
+		return f.destroyErr
+	}
+
+	f.destroys = append(f.destroys, f.invocation())
+	f.configExists = false
+	return nil
+}
+
+// GetStateOutputVariables implements Interface. Outputs only exist while the configuration exists.
+func (f *Fake) GetStateOutputVariables(variables ...string) (map[string]string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var (
+		values  = make(map[string]string)
+		missing []string
+	)
+	for _, variable := range variables {
+		value, ok := f.outputs[variable]
+		if !ok || !f.configExists {
+			missing = append(missing, variable)
+			continue
+		}
+		values[variable] = value
+	}
+
+	if len(missing) > 0 {
+		return nil, &VariablesNotFoundError{missing}
+	}
+	return values, nil
+}
+
+// ConfigExists implements Interface.
+func (f *Fake) ConfigExists() (bool, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.configExists, nil
+}
+
+func (f *Fake) invocation() Invocation {
+	return Invocation{
+		Purpose:              f.Purpose,
+		Namespace:            f.Namespace,
+		Name:                 f.Name,
+		VariablesEnvironment: f.variablesEnvironment,
+		Initialized:          f.initializer != nil,
+	}
+}
+
+// FakeFactory is a Factory that creates Fakes. Every purpose, namespace and name combination yields the
+// same Fake, so that a Fake outlives a single reconciliation.
+type FakeFactory struct {
+	// Outputs are the outputs of newly created Fakes.
+	Outputs map[string]string
+
+	lock  sync.Mutex
+	fakes map[string]*Fake
+}
+
+// NewForConfig implements Factory.
+func (f *FakeFactory) NewForConfig(_ logrus.FieldLogger, _ *rest.Config, purpose, namespace, name, _ string) (Interface, error) {
+	return f.Get(purpose, namespace, name), nil
+}
+
+// Get returns the Fake for the given purpose, namespace and name, creating it if necessary.
+func (f *FakeFactory) Get(purpose, namespace, name string) *Fake {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.fakes == nil {
+		f.fakes = make(map[string]*Fake)
+	}
+
+	key := purpose + "/" + namespace + "/" + name
+	fake, ok := f.fakes[key]
+	if !ok {
+		fake = NewFake(f.Outputs)
+		fake.Purpose, fake.Namespace, fake.Name = purpose, namespace, name
+		f.fakes[key] = fake
+	}
+	return fake
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformer
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gardener/gardener/pkg/operation/terraformer"
+)
+
+// OutputTag is the struct tag naming the output variable of a field for Outputs.Decode.
+const OutputTag = "terraform"
+
+// VariablesNotFoundError is returned if not all requested output variables are found in a Terraform state.
+type VariablesNotFoundError struct {
+	// Variables are the variables that have not been found.
+	Variables []string
+}
+
+// Error implements error.
+func (e *VariablesNotFoundError) Error() string {
+	return fmt.Sprintf("could not find all requested variables: %+v", e.Variables)
+}
+
+// IsVariablesNotFoundError checks whether the given error indicates that not all output variables have
+// been found, either reported by the Terraformer or by Outputs.
+func IsVariablesNotFoundError(err error) bool {
+	if _, ok := err.(*VariablesNotFoundError); ok {
+		return true
+	}
+	return terraformer.IsVariablesNotFoundError(err)
+}
+
+// Outputs are output variables of a Terraform state.
+type Outputs map[string]string
+
+// GetOutputs returns the given output variables of the state of the given Terraformer.
+func GetOutputs(tf Interface, variables ...string) (Outputs, error) {
+	values, err := tf.GetStateOutputVariables(variables...)
+	if err != nil {
+		return nil, err
+	}
+	return Outputs(values), nil
+}
+
+// String returns the value of the given variable or an error if it does not exist.
+func (o Outputs) String(variable string) (string, error) {
+	value, ok := o[variable]
+	if !ok {
+		return "", &VariablesNotFoundError{[]string{variable}}
+	}
+	return value, nil
+}
+
+// Int returns the value of the given variable as integer.
+func (o Outputs) Int(variable string) (int, error) {
+	value, err := o.String(variable)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(value)
+}
+
+// Bool returns the value of the given variable as boolean.
+func (o Outputs) Bool(variable string) (bool, error) {
+	value, err := o.String(variable)
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(value)
+}
+
+// List returns the value of the given variable split by commas. An empty value yields an empty list.
+func (o Outputs) List(variable string) ([]string, error) {
+	value, err := o.String(variable)
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return nil, nil
+	}
+	return strings.Split(value, ","), nil
+}
+
+// Indexed returns the values of all variables consisting of the given prefix followed by an index, by index.
+func (o Outputs) Indexed(prefix string) (map[int]string, error) {
+	values := make(map[int]string)
+	for variable, value := range o {
+		if !strings.HasPrefix(variable, prefix) {
+			continue
+		}
+
+		index, err := strconv.Atoi(strings.TrimPrefix(variable, prefix))
+		if err != nil {
+			return nil, fmt.Errorf("variable %s does not end with an index: %v", variable, err)
+		}
+		values[index] = value
+	}
+	return values, nil
+}
+
+// Decode sets the fields of the given struct pointer that are tagged with `terraform:"<variable>"` to the values
+// of the respective variables. Fields of type string are required, fields of type *string are left nil if the
+// variable does not exist.
+func (o Outputs) Decode(out interface{}) error {
+	value := reflect.ValueOf(out)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("expected a struct pointer but got %T", out)
+	}
+	value = value.Elem()
+
+	var missing []string
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		variable, ok := field.Tag.Lookup(OutputTag)
+		if !ok {
+			continue
+		}
+
+		output, exists := o[variable]
+		switch field.Type {
+		case reflect.TypeOf(""):
+			if !exists {
+				missing = append(missing, variable)
+				continue
+			}
+			value.Field(i).SetString(output)
+		case reflect.TypeOf((*string)(nil)):
+			if exists {
+				value.Field(i).Set(reflect.ValueOf(&output))
+			}
+		default:
+			return fmt.Errorf("field %s has unsupported type %s", field.Name, field.Type)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &VariablesNotFoundError{missing}
+	}
+	return nil
+}
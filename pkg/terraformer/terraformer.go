@@ -0,0 +1,110 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformer
+
+import (
+	"github.com/gardener/gardener/pkg/operation/terraformer"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+)
+
+// Interface is the interface of a Terraformer that runs Terraform configurations for a purpose
+// in a namespace.
+type Interface interface {
+	// SetVariablesEnvironment sets the environment containing the Terraform variables.
+	SetVariablesEnvironment(tfvarsEnvironment map[string]string) Interface
+	// InitializeWith initializes the Terraformer configuration with the given Initializer.
+	InitializeWith(initializer terraformer.Initializer) Interface
+	// Apply applies the Terraform configuration.
+	Apply() error
+	// Destroy destroys the resources of the Terraform configuration and removes the configuration.
+	Destroy() error
+	// GetStateOutputVariables returns the given output variables of the Terraform state. If not all
+	// variables are found, an error is returned for which IsVariablesNotFoundError is true.
+	GetStateOutputVariables(variables ...string) (map[string]string, error)
+	// ConfigExists checks whether the Terraform configuration exists.
+	ConfigExists() (bool, error)
+}
+
+// Factory creates Terraformers.
+type Factory interface {
+	// NewForConfig creates a new Terraformer for the given purpose, namespace and name that uses the given
+	// image and logs to the given logger.
+	NewForConfig(logger logrus.FieldLogger, config *rest.Config, purpose, namespace, name, image string) (Interface, error)
+}
+
+// FactoryFunc is a function that implements Factory.
+type FactoryFunc func(logger logrus.FieldLogger, config *rest.Config, purpose, namespace, name, image string) (Interface, error)
+
+// NewForConfig implements Factory.
+func (f FactoryFunc) NewForConfig(logger logrus.FieldLogger, config *rest.Config, purpose, namespace, name, image string) (Interface, error) {
+	return f(logger, config, purpose, namespace, name, image)
+}
+
+// DefaultFactory returns a Factory that creates Terraformers running Terraform in pods of the given cluster.
+func DefaultFactory() Factory {
+	return FactoryFunc(NewForConfig)
+}
+
+// NewForConfig creates a new Terraformer running Terraform in pods of the cluster of the given config.
+func NewForConfig(logger logrus.FieldLogger, config *rest.Config, purpose, namespace, name, image string) (Interface, error) {
+	tf, err := terraformer.NewForConfig(logger, config, purpose, namespace, name, image)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(tf), nil
+}
+
+type wrapper struct {
+	tf *terraformer.Terraformer
+}
+
+// Wrap wraps the given Terraformer so that it implements Interface.
+func Wrap(tf *terraformer.Terraformer) Interface {
+	return &wrapper{tf}
+}
+
+// SetVariablesEnvironment implements Interface.
+func (w *wrapper) SetVariablesEnvironment(tfvarsEnvironment map[string]string) Interface {
+	w.tf.SetVariablesEnvironment(tfvarsEnvironment)
+	return w
+}
+
+// InitializeWith implements Interface.
+func (w *wrapper) InitializeWith(initializer terraformer.Initializer) Interface {
+	w.tf.InitializeWith(initializer)
+	return w
+}
+
+// Apply implements Interface.
+func (w *wrapper) Apply() error {
+	return w.tf.Apply()
+}
+
+// Destroy implements Interface.
+func (w *wrapper) Destroy() error {
+	return w.tf.Destroy()
+}
+
+// GetStateOutputVariables implements Interface.
+func (w *wrapper) GetStateOutputVariables(variables ...string) (map[string]string, error) {
+	return w.tf.GetStateOutputVariables(variables...)
+}
+
+// ConfigExists implements Interface.
+func (w *wrapper) ConfigExists() (bool, error) {
+	return w.tf.ConfigExists()
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformer
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gardener/gardener/pkg/chartrenderer"
+	"github.com/gardener/gardener/pkg/operation/terraformer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/version"
+)
+
+func TestTerraformer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Terraformer Suite")
+}
+
+var _ = Describe("Terraformer", func() {
+	Describe("#Outputs", func() {
+		outputs := Outputs{
+			"vpc_id":    "vpc-1",
+			"count":     "3",
+			"enabled":   "true",
+			"zones":     "a,b",
+			"empty":     "",
+			"subnet_z0": "subnet-0",
+			"subnet_z1": "subnet-1",
+			"subnet_zx": "subnet-x",
+			"other_z0":  "other",
+		}
+
+		It("should return typed values", func() {
+			Expect(outputs.String("vpc_id")).To(Equal("vpc-1"))
+			Expect(outputs.Int("count")).To(Equal(3))
+			Expect(outputs.Bool("enabled")).To(BeTrue())
+			Expect(outputs.List("zones")).To(Equal([]string{"a", "b"}))
+			Expect(outputs.List("empty")).To(BeEmpty())
+		})
+
+		It("should fail with a variables not found error for missing variables", func() {
+			_, err := outputs.String("missing")
+			Expect(IsVariablesNotFoundError(err)).To(BeTrue())
+		})
+
+		It("should fail for values of the wrong type", func() {
+			_, err := outputs.Int("vpc_id")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return indexed values", func() {
+			Expect(Outputs{"subnet_z0": "subnet-0", "subnet_z1": "subnet-1", "other_z0": "other"}.Indexed("subnet_z")).To(Equal(map[int]string{
+				0: "subnet-0",
+				1: "subnet-1",
+			}))
+		})
+
+		It("should fail for indexed values without index", func() {
+			_, err := outputs.Indexed("subnet_z")
+			Expect(err).To(HaveOccurred())
+		})
+
+		Describe("#Decode", func() {
+			type state struct {
+				VPCID    string  `terraform:"vpc_id"`
+				Zones    string  `terraform:"zones"`
+				Optional *string `terraform:"optional"`
+				Empty    *string `terraform:"empty"`
+				Ignored  string
+			}
+
+			It("should decode the tagged fields", func() {
+				actual := &state{}
+				Expect(outputs.Decode(actual)).To(Succeed())
+
+				empty := ""
+				Expect(actual).To(Equal(&state{VPCID: "vpc-1", Zones: "a,b", Empty: &empty}))
+			})
+
+			It("should fail with a variables not found error for missing required variables", func() {
+				err := Outputs{"vpc_id": "vpc-1"}.Decode(&state{})
+				Expect(IsVariablesNotFoundError(err)).To(BeTrue())
+				Expect(err.(*VariablesNotFoundError).Variables).To(Equal([]string{"zones"}))
+			})
+
+			It("should fail for values that are no struct pointers", func() {
+				Expect(outputs.Decode(state{})).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("#RenderChart", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "terraformer")
+			Expect(err).NotTo(HaveOccurred())
+
+			for name, content := range map[string]string{
+				"Chart.yaml":                 "apiVersion: v1\nname: infra\nversion: 0.1.0\n",
+				"templates/main.tf":          "vpc = \"{{ .Values.vpc }}\"\n",
+				"templates/variables.tf":     "variable \"ACCESS_KEY\" {}\n",
+				"templates/terraform.tfvars": "region = \"{{ .Values.region }}\"\n",
+			} {
+				path := filepath.Join(dir, name)
+				Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should render the Terraform files of the chart", func() {
+			renderer := chartrenderer.New(engine.New(), &chartutil.Capabilities{TillerVersion: version.GetVersionProto()})
+
+			files, err := RenderChart(renderer, dir, "infra", "shoot--foo--bar", map[string]interface{}{
+				"vpc":    "vpc-1",
+				"region": "eu-west-1",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal(&Files{
+				Main:      "vpc = \"vpc-1\"\n",
+				Variables: "variable \"ACCESS_KEY\" {}\n",
+				TFVars:    []byte("region = \"eu-west-1\"\n"),
+			}))
+		})
+	})
+
+	Describe("#Fake", func() {
+		var (
+			fake        *Fake
+			environment = map[string]string{"TF_VAR_ACCESS_KEY": "key"}
+			initializer = func(*terraformer.InitializerConfig) error { return nil }
+		)
+
+		BeforeEach(func() {
+			fake = NewFake(map[string]string{"vpc_id": "vpc-1"})
+		})
+
+		It("should only return outputs after an apply", func() {
+			exists, err := fake.ConfigExists()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse())
+			_, err = fake.GetStateOutputVariables("vpc_id")
+			Expect(IsVariablesNotFoundError(err)).To(BeTrue())
+
+			Expect(fake.SetVariablesEnvironment(environment).InitializeWith(initializer).Apply()).To(Succeed())
+
+			exists, err = fake.ConfigExists()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
+			Expect(fake.GetStateOutputVariables("vpc_id")).To(Equal(map[string]string{"vpc_id": "vpc-1"}))
+			Expect(fake.Applies()).To(Equal([]Invocation{{VariablesEnvironment: environment, Initialized: true}}))
+			Expect(fake.Initializer()).NotTo(BeNil())
+		})
+
+		It("should remove the configuration on destroy", func() {
+			Expect(fake.Apply()).To(Succeed())
+			Expect(fake.Destroy()).To(Succeed())
+
+			exists, err := fake.ConfigExists()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse())
+			Expect(fake.Destroys()).To(HaveLen(1))
+		})
+
+		It("should return the configured errors", func() {
+			err := errors.New("error")
+			fake.SetApplyError(err)
+			fake.SetDestroyError(err)
+
+			Expect(fake.Apply()).To(Equal(err))
+			Expect(fake.Destroy()).To(Equal(err))
+			Expect(fake.Applies()).To(BeEmpty())
+			Expect(fake.Destroys()).To(BeEmpty())
+		})
+	})
+
+	Describe("#FakeFactory", func() {
+		It("should return the same Fake for the same purpose, namespace and name", func() {
+			factory := &FakeFactory{Outputs: map[string]string{"vpc_id": "vpc-1"}}
+
+			tf, err := factory.NewForConfig(nil, nil, "infra", "shoot--foo--bar", "bar", "image")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tf.Apply()).To(Succeed())
+
+			fake := factory.Get("infra", "shoot--foo--bar", "bar")
+			Expect(fake).To(BeIdenticalTo(tf))
+			Expect(fake.Applies()).To(Equal([]Invocation{{Purpose: "infra", Namespace: "shoot--foo--bar", Name: "bar"}}))
+			Expect(factory.Get("infra", "shoot--foo--baz", "baz")).NotTo(BeIdenticalTo(tf))
+		})
+	})
+})
@@ -0,0 +1,169 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framework
+
+import (
+	"context"
+	"sync"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// OperationReconcile is the operation of an ActuatorCall of Reconcile.
+	OperationReconcile = "Reconcile"
+	// OperationDelete is the operation of an ActuatorCall of Delete.
+	OperationDelete = "Delete"
+)
+
+// ActuatorCall is a call of a fake actuator.
+type ActuatorCall struct {
+	// Operation is either OperationReconcile or OperationDelete.
+	Operation string
+	// Namespace is the namespace of the object the actuator was called with.
+	Namespace string
+	// Name is the name of the object the actuator was called with.
+	Name string
+	// Generation is the generation of the object the actuator was called with.
+	Generation int64
+	// Cluster is the cluster the actuator was called with, if any.
+	Cluster *extensionscontroller.Cluster
+}
+
+// FakeActuator records the calls of an actuator and returns the configured errors. It is safe for concurrent use.
+type FakeActuator struct {
+	lock         sync.Mutex
+	calls        []ActuatorCall
+	reconcileErr error
+	deleteErr    error
+}
+
+// SetReconcileError sets the error returned by Reconcile.
+func (f *FakeActuator) SetReconcileError(err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.reconcileErr = err
+}
+
+// SetDeleteError sets the error returned by Delete.
+func (f *FakeActuator) SetDeleteError(err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.deleteErr = err
+}
+
+// Calls returns all recorded calls.
+func (f *FakeActuator) Calls() []ActuatorCall {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]ActuatorCall(nil), f.calls...)
+}
+
+// CallsOf returns all recorded calls of the given operation.
+func (f *FakeActuator) CallsOf(operation string) []ActuatorCall {
+	var calls []ActuatorCall
+	for _, call := range f.Calls() {
+		if call.Operation == operation {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset removes all recorded calls and errors.
+func (f *FakeActuator) Reset() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.calls = nil
+	f.reconcileErr = nil
+	f.deleteErr = nil
+}
+
+func (f *FakeActuator) record(operation string, obj metav1.Object, cluster *extensionscontroller.Cluster) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.calls = append(f.calls, ActuatorCall{
+		Operation:  operation,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		Generation: obj.GetGeneration(),
+		Cluster:    cluster,
+	})
+
+	if operation == OperationDelete {
+		return f.deleteErr
+	}
+	return f.reconcileErr
+}
+
+// FakeInfrastructureActuator is a fake infrastructure actuator.
+type FakeInfrastructureActuator struct {
+	FakeActuator
+}
+
+// Reconcile implements infrastructure.Actuator.
+func (f *FakeInfrastructureActuator) Reconcile(_ context.Context, infrastructure *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) error {
+	return f.record(OperationReconcile, infrastructure, cluster)
+}
+
+// Delete implements infrastructure.Actuator.
+func (f *FakeInfrastructureActuator) Delete(_ context.Context, infrastructure *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) error {
+	return f.record(OperationDelete, infrastructure, cluster)
+}
+
+// FakeControlPlaneActuator is a fake control plane actuator.
+type FakeControlPlaneActuator struct {
+	FakeActuator
+}
+
+// Reconcile implements controlplane.Actuator.
+func (f *FakeControlPlaneActuator) Reconcile(_ context.Context, controlPlane *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster) error {
+	return f.record(OperationReconcile, controlPlane, cluster)
+}
+
+// Delete implements controlplane.Actuator.
+func (f *FakeControlPlaneActuator) Delete(_ context.Context, controlPlane *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster) error {
+	return f.record(OperationDelete, controlPlane, cluster)
+}
+
+// FakeOperatingSystemConfigActuator is a fake operating system config actuator.
+type FakeOperatingSystemConfigActuator struct {
+	FakeActuator
+
+	// CloudConfig is the cloud config returned by Reconcile.
+	CloudConfig []byte
+	// Command is the command returned by Reconcile.
+	Command *string
+	// Units are the units returned by Reconcile.
+	Units []string
+}
+
+// Reconcile implements operatingsystemconfig.Actuator.
+func (f *FakeOperatingSystemConfigActuator) Reconcile(_ context.Context, config *extensionsv1alpha1.OperatingSystemConfig) ([]byte, *string, []string, error) {
+	if err := f.record(OperationReconcile, config, nil); err != nil {
+		return nil, nil, nil, err
+	}
+	return f.CloudConfig, f.Command, f.Units, nil
+}
+
+// Delete implements operatingsystemconfig.Actuator.
+func (f *FakeOperatingSystemConfigActuator) Delete(_ context.Context, config *extensionsv1alpha1.OperatingSystemConfig) error {
+	return f.record(OperationDelete, config, nil)
+}
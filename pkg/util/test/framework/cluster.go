@@ -0,0 +1,229 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framework
+
+import (
+	"encoding/json"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	gardenv1beta1 "github.com/gardener/gardener/pkg/apis/garden/v1beta1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ShootBuilder builds Shoot resources.
+type ShootBuilder struct {
+	shoot *gardenv1beta1.Shoot
+}
+
+// NewShootBuilder creates a new ShootBuilder for a Shoot with the given name in the given namespace.
+func NewShootBuilder(namespace, name string) *ShootBuilder {
+	return &ShootBuilder{&gardenv1beta1.Shoot{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gardenv1beta1.SchemeGroupVersion.String(),
+			Kind:       "Shoot",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}}
+}
+
+// WithCloud sets the cloud profile and region of the Shoot.
+func (b *ShootBuilder) WithCloud(profile, region string) *ShootBuilder {
+	b.shoot.Spec.Cloud.Profile = profile
+	b.shoot.Spec.Cloud.Region = region
+	return b
+}
+
+// WithSeed sets the name of the Seed of the Shoot.
+func (b *ShootBuilder) WithSeed(seed string) *ShootBuilder {
+	b.shoot.Spec.Cloud.Seed = &seed
+	return b
+}
+
+// WithKubernetesVersion sets the Kubernetes version of the Shoot.
+func (b *ShootBuilder) WithKubernetesVersion(version string) *ShootBuilder {
+	b.shoot.Spec.Kubernetes.Version = version
+	return b
+}
+
+// WithLastOperation sets the last operation of the Shoot. The observed generation is set to the generation of the
+// Shoot, hence a Shoot with a failed last operation is considered failed.
+func (b *ShootBuilder) WithLastOperation(t gardencorev1alpha1.LastOperationType, state gardencorev1alpha1.LastOperationState) *ShootBuilder {
+	b.shoot.Status.LastOperation = &gardencorev1alpha1.LastOperation{Type: t, State: state}
+	b.shoot.Status.ObservedGeneration = b.shoot.Generation
+	return b
+}
+
+// With applies the given function to the Shoot.
+func (b *ShootBuilder) With(f func(*gardenv1beta1.Shoot)) *ShootBuilder {
+	f(b.shoot)
+	return b
+}
+
+// Build returns the Shoot.
+func (b *ShootBuilder) Build() *gardenv1beta1.Shoot {
+	return b.shoot.DeepCopy()
+}
+
+// SeedBuilder builds Seed resources.
+type SeedBuilder struct {
+	seed *gardenv1beta1.Seed
+}
+
+// NewSeedBuilder creates a new SeedBuilder for a Seed with the given name.
+func NewSeedBuilder(name string) *SeedBuilder {
+	return &SeedBuilder{&gardenv1beta1.Seed{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gardenv1beta1.SchemeGroupVersion.String(),
+			Kind:       "Seed",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}}
+}
+
+// WithCloud sets the cloud profile and region of the Seed.
+func (b *SeedBuilder) WithCloud(profile, region string) *SeedBuilder {
+	b.seed.Spec.Cloud.Profile = profile
+	b.seed.Spec.Cloud.Region = region
+	return b
+}
+
+// With applies the given function to the Seed.
+func (b *SeedBuilder) With(f func(*gardenv1beta1.Seed)) *SeedBuilder {
+	f(b.seed)
+	return b
+}
+
+// Build returns the Seed.
+func (b *SeedBuilder) Build() *gardenv1beta1.Seed {
+	return b.seed.DeepCopy()
+}
+
+// CloudProfileBuilder builds CloudProfile resources.
+type CloudProfileBuilder struct {
+	cloudProfile *gardenv1beta1.CloudProfile
+}
+
+// NewCloudProfileBuilder creates a new CloudProfileBuilder for a CloudProfile with the given name.
+func NewCloudProfileBuilder(name string) *CloudProfileBuilder {
+	return &CloudProfileBuilder{&gardenv1beta1.CloudProfile{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gardenv1beta1.SchemeGroupVersion.String(),
+			Kind:       "CloudProfile",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}}
+}
+
+// With applies the given function to the CloudProfile.
+func (b *CloudProfileBuilder) With(f func(*gardenv1beta1.CloudProfile)) *CloudProfileBuilder {
+	f(b.cloudProfile)
+	return b
+}
+
+// Build returns the CloudProfile.
+func (b *CloudProfileBuilder) Build() *gardenv1beta1.CloudProfile {
+	return b.cloudProfile.DeepCopy()
+}
+
+// ClusterBuilder builds Cluster resources with embedded CloudProfile, Seed and Shoot.
+type ClusterBuilder struct {
+	name         string
+	cloudProfile *gardenv1beta1.CloudProfile
+	seed         *gardenv1beta1.Seed
+	shoot        *gardenv1beta1.Shoot
+}
+
+// NewClusterBuilder creates a new ClusterBuilder for a Cluster with the given name. The name is also the name
+// of the namespace of the Shoot in the Seed. The CloudProfile, Seed and Shoot default to empty resources.
+func NewClusterBuilder(name string) *ClusterBuilder {
+	return &ClusterBuilder{
+		name:         name,
+		cloudProfile: NewCloudProfileBuilder("").Build(),
+		seed:         NewSeedBuilder("").Build(),
+		shoot:        NewShootBuilder("", "").Build(),
+	}
+}
+
+// WithCloudProfile sets the CloudProfile of the Cluster.
+func (b *ClusterBuilder) WithCloudProfile(cloudProfile *gardenv1beta1.CloudProfile) *ClusterBuilder {
+	b.cloudProfile = cloudProfile
+	return b
+}
+
+// WithSeed sets the Seed of the Cluster.
+func (b *ClusterBuilder) WithSeed(seed *gardenv1beta1.Seed) *ClusterBuilder {
+	b.seed = seed
+	return b
+}
+
+// WithShoot sets the Shoot of the Cluster.
+func (b *ClusterBuilder) WithShoot(shoot *gardenv1beta1.Shoot) *ClusterBuilder {
+	b.shoot = shoot
+	return b
+}
+
+// Build returns the Cluster.
+func (b *ClusterBuilder) Build() (*extensionsv1alpha1.Cluster, error) {
+	cloudProfile, err := rawExtension(b.cloudProfile, "CloudProfile")
+	if err != nil {
+		return nil, err
+	}
+	seed, err := rawExtension(b.seed, "Seed")
+	if err != nil {
+		return nil, err
+	}
+	shoot, err := rawExtension(b.shoot, "Shoot")
+	if err != nil {
+		return nil, err
+	}
+
+	return &extensionsv1alpha1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: extensionsv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: b.name,
+		},
+		Spec: extensionsv1alpha1.ClusterSpec{
+			CloudProfile: cloudProfile,
+			Seed:         seed,
+			Shoot:        shoot,
+		},
+	}, nil
+}
+
+// rawExtension encodes the given garden resource with the given kind. The type meta is always set as it is
+// required to decode the resource again.
+func rawExtension(obj runtime.Object, kind string) (runtime.RawExtension, error) {
+	obj = obj.DeepCopyObject()
+	obj.GetObjectKind().SetGroupVersionKind(gardenv1beta1.SchemeGroupVersion.WithKind(kind))
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return runtime.RawExtension{}, err
+	}
+	return runtime.RawExtension{Raw: data}, nil
+}
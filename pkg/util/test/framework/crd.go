@@ -0,0 +1,169 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framework
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"time"
+
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CRDFilePattern is the pattern of the files CRDs are read from.
+const CRDFilePattern = "crd-*.yaml"
+
+// ReadCRDs reads all CRDs of the `crd-*.yaml` files in the given directories.
+func ReadCRDs(dirs ...string) ([]*apiextensionsv1beta1.CustomResourceDefinition, error) {
+	var crds []*apiextensionsv1beta1.CustomResourceDefinition
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, CRDFilePattern))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+
+		for _, file := range files {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+
+			fileCRDs, err := DecodeCRDs(data)
+			if err != nil {
+				return nil, fmt.Errorf("could not decode CRDs of %s: %v", file, err)
+			}
+			crds = append(crds, fileCRDs...)
+		}
+	}
+	return crds, nil
+}
+
+// DecodeCRDs decodes all CRDs of the given YAML documents. Empty documents are skipped.
+func DecodeCRDs(data []byte) ([]*apiextensionsv1beta1.CustomResourceDefinition, error) {
+	var (
+		crds    []*apiextensionsv1beta1.CustomResourceDefinition
+		decoder = yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	)
+	for {
+		crd := &apiextensionsv1beta1.CustomResourceDefinition{}
+		if err := decoder.Decode(crd); err != nil {
+			if err == io.EOF {
+				return crds, nil
+			}
+			return nil, err
+		}
+		if crd.Name == "" {
+			continue
+		}
+		if crd.Kind != "CustomResourceDefinition" {
+			return nil, fmt.Errorf("expected a CustomResourceDefinition but got %s %s", crd.Kind, crd.Name)
+		}
+		crds = append(crds, crd)
+	}
+}
+
+// InstallCRDs creates or updates the given CRDs and waits until they are served by the API server.
+func InstallCRDs(config *rest.Config, crds []*apiextensionsv1beta1.CustomResourceDefinition, timeout time.Duration) error {
+	scheme := runtime.NewScheme()
+	if err := apiextensionsv1beta1.AddToScheme(scheme); err != nil {
+		return err
+	}
+
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for _, crd := range crds {
+		crd = crd.DeepCopy()
+		if err := c.Create(ctx, crd); err != nil {
+			if !apierrors.IsAlreadyExists(err) {
+				return fmt.Errorf("could not create CRD %s: %v", crd.Name, err)
+			}
+
+			existing := &apiextensionsv1beta1.CustomResourceDefinition{}
+			if err := c.Get(ctx, client.ObjectKey{Name: crd.Name}, existing); err != nil {
+				return err
+			}
+			crd.ResourceVersion = existing.ResourceVersion
+			if err := c.Update(ctx, crd); err != nil {
+				return fmt.Errorf("could not update CRD %s: %v", crd.Name, err)
+			}
+		}
+	}
+
+	return WaitForCRDs(config, crds, timeout)
+}
+
+// WaitForCRDs waits until all versions of the given CRDs are served by the API server.
+func WaitForCRDs(config *rest.Config, crds []*apiextensionsv1beta1.CustomResourceDefinition, timeout time.Duration) error {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	return wait.PollImmediate(100*time.Millisecond, timeout, func() (bool, error) {
+		for _, crd := range crds {
+			for _, version := range crdVersions(crd) {
+				resources, err := discoveryClient.ServerResourcesForGroupVersion(fmt.Sprintf("%s/%s", crd.Spec.Group, version))
+				if err != nil {
+					return false, nil
+				}
+				if !containsResource(resources.APIResources, crd.Spec.Names.Plural) {
+					return false, nil
+				}
+			}
+		}
+		return true, nil
+	})
+}
+
+func crdVersions(crd *apiextensionsv1beta1.CustomResourceDefinition) []string {
+	if len(crd.Spec.Versions) == 0 {
+		return []string{crd.Spec.Version}
+	}
+
+	var versions []string
+	for _, version := range crd.Spec.Versions {
+		if version.Served {
+			versions = append(versions, version.Name)
+		}
+	}
+	return versions
+}
+
+func containsResource(resources []metav1.APIResource, name string) bool {
+	for _, resource := range resources {
+		if resource.Name == name {
+			return true
+		}
+	}
+	return false
+}
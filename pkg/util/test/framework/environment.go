@@ -0,0 +1,279 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package framework contains helpers for integration tests of extension controllers. It runs a local
+// control plane consisting of etcd and kube-apiserver, installs the extension CRDs and runs controllers
+// with fake actuators against it.
+package framework
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// AssetsDirEnv is the environment variable pointing to a directory containing the etcd and kube-apiserver binaries.
+	AssetsDirEnv = "KUBEBUILDER_ASSETS"
+	// EtcdBinaryEnv is the environment variable pointing to the etcd binary.
+	EtcdBinaryEnv = "TEST_ASSET_ETCD"
+	// KubeAPIServerBinaryEnv is the environment variable pointing to the kube-apiserver binary.
+	KubeAPIServerBinaryEnv = "TEST_ASSET_KUBE_APISERVER"
+	// UseExistingClusterEnv is the environment variable that, if set to `true`, makes an Environment use the
+	// cluster of the current kubeconfig instead of starting a local control plane.
+	UseExistingClusterEnv = "USE_EXISTING_CLUSTER"
+
+	// DefaultAssetsDir is the directory the binaries are looked up in if no AssetsDirEnv is set.
+	DefaultAssetsDir = "/usr/local/kubebuilder/bin"
+	// DefaultStartTimeout is the default timeout for starting the control plane.
+	DefaultStartTimeout = 60 * time.Second
+	// DefaultStopTimeout is the default timeout for stopping the control plane.
+	DefaultStopTimeout = 20 * time.Second
+)
+
+// Environment is a local control plane for integration tests.
+type Environment struct {
+	// CRDDirectoryPaths are the directories the `crd-*.yaml` files are read and installed from.
+	CRDDirectoryPaths []string
+	// CRDs are additional CRDs to install.
+	CRDs []*apiextensionsv1beta1.CustomResourceDefinition
+	// UseExistingCluster makes the environment use the cluster of the current kubeconfig instead of starting a
+	// local control plane. Defaults to the UseExistingClusterEnv environment variable.
+	UseExistingCluster *bool
+	// StartTimeout is the timeout for starting the control plane. Defaults to DefaultStartTimeout.
+	StartTimeout time.Duration
+	// StopTimeout is the timeout for stopping the control plane. Defaults to DefaultStopTimeout.
+	StopTimeout time.Duration
+	// Output is where the output of etcd and kube-apiserver is written to. Defaults to ioutil.Discard.
+	Output io.Writer
+
+	// Config is the configuration to access the API server. Only set after Start.
+	Config *rest.Config
+
+	dir       string
+	processes []*exec.Cmd
+}
+
+// BinaryPath returns the path of the binary with the given name. The path is either taken from the given
+// environment variable, from the AssetsDirEnv directory or from the DefaultAssetsDir.
+func BinaryPath(name, env string) string {
+	if path := os.Getenv(env); path != "" {
+		return path
+	}
+	if dir := os.Getenv(AssetsDirEnv); dir != "" {
+		return filepath.Join(dir, name)
+	}
+	return filepath.Join(DefaultAssetsDir, name)
+}
+
+// IsAvailable checks whether an Environment can be started, i.e. either an existing cluster shall be used
+// or the etcd and kube-apiserver binaries exist.
+func (e *Environment) IsAvailable() bool {
+	if e.useExistingCluster() {
+		return true
+	}
+	for _, path := range []string{BinaryPath("etcd", EtcdBinaryEnv), BinaryPath("kube-apiserver", KubeAPIServerBinaryEnv)} {
+		if _, err := os.Stat(path); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Environment) useExistingCluster() bool {
+	if e.UseExistingCluster != nil {
+		return *e.UseExistingCluster
+	}
+	return os.Getenv(UseExistingClusterEnv) == "true"
+}
+
+// Start starts the control plane, installs the CRDs and returns the configuration to access it.
+func (e *Environment) Start() (*rest.Config, error) {
+	if e.useExistingCluster() {
+		config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("could not load kubeconfig: %v", err)
+		}
+		e.Config = config
+	} else if err := e.startControlPlane(); err != nil {
+		_ = e.Stop()
+		return nil, err
+	}
+
+	crds, err := ReadCRDs(e.CRDDirectoryPaths...)
+	if err != nil {
+		_ = e.Stop()
+		return nil, err
+	}
+	if err := InstallCRDs(e.Config, append(crds, e.CRDs...), e.startTimeout()); err != nil {
+		_ = e.Stop()
+		return nil, err
+	}
+
+	return e.Config, nil
+}
+
+func (e *Environment) startControlPlane() error {
+	dir, err := ioutil.TempDir("", "framework")
+	if err != nil {
+		return err
+	}
+	e.dir = dir
+
+	etcdPort, err := freePort()
+	if err != nil {
+		return err
+	}
+	etcdPeerPort, err := freePort()
+	if err != nil {
+		return err
+	}
+	etcdURL := fmt.Sprintf("http://127.0.0.1:%d", etcdPort)
+
+	if err := e.startProcess(fmt.Sprintf("%s/health", etcdURL), BinaryPath("etcd", EtcdBinaryEnv),
+		"--data-dir", filepath.Join(dir, "etcd"),
+		"--listen-client-urls", etcdURL,
+		"--advertise-client-urls", etcdURL,
+		"--listen-peer-urls", fmt.Sprintf("http://127.0.0.1:%d", etcdPeerPort),
+	); err != nil {
+		return fmt.Errorf("could not start etcd: %v", err)
+	}
+
+	insecurePort, err := freePort()
+	if err != nil {
+		return err
+	}
+	securePort, err := freePort()
+	if err != nil {
+		return err
+	}
+	host := fmt.Sprintf("http://127.0.0.1:%d", insecurePort)
+
+	if err := e.startProcess(fmt.Sprintf("%s/healthz", host), BinaryPath("kube-apiserver", KubeAPIServerBinaryEnv),
+		"--etcd-servers", etcdURL,
+		"--cert-dir", filepath.Join(dir, "certs"),
+		"--insecure-bind-address", "127.0.0.1",
+		"--insecure-port", strconv.Itoa(insecurePort),
+		"--secure-port", strconv.Itoa(securePort),
+		"--admission-control", "AlwaysAdmit",
+		"--service-cluster-ip-range", "10.0.0.0/24",
+		"--allow-privileged=true",
+	); err != nil {
+		return fmt.Errorf("could not start kube-apiserver: %v", err)
+	}
+
+	e.Config = &rest.Config{Host: host}
+	return nil
+}
+
+func (e *Environment) startProcess(healthURL, path string, args ...string) error {
+	output := e.Output
+	if output == nil {
+		output = ioutil.Discard
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	e.processes = append(e.processes, cmd)
+
+	return wait.PollImmediate(100*time.Millisecond, e.startTimeout(), func() (bool, error) {
+		resp, err := http.Get(healthURL)
+		if err != nil {
+			return false, nil
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusOK, nil
+	})
+}
+
+// Stop stops the control plane and removes its data.
+func (e *Environment) Stop() error {
+	timeout := e.StopTimeout
+	if timeout == 0 {
+		timeout = DefaultStopTimeout
+	}
+
+	var errs []error
+	for i := len(e.processes) - 1; i >= 0; i-- {
+		if err := stopProcess(e.processes[i], timeout); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	e.processes = nil
+
+	if e.dir != "" {
+		if err := os.RemoveAll(e.dir); err != nil {
+			errs = append(errs, err)
+		}
+		e.dir = ""
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("could not stop environment: %v", errs)
+	}
+	return nil
+}
+
+func (e *Environment) startTimeout() time.Duration {
+	if e.StartTimeout == 0 {
+		return DefaultStartTimeout
+	}
+	return e.StartTimeout
+}
+
+func stopProcess(cmd *exec.Cmd, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		return err
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		if err := cmd.Process.Kill(); err != nil {
+			return err
+		}
+		<-done
+		return nil
+	}
+}
+
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
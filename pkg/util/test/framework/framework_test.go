@@ -0,0 +1,302 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framework
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	mockclient "github.com/gardener/gardener-extensions/pkg/mock/controller-runtime/client"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	gardenv1beta1 "github.com/gardener/gardener/pkg/apis/garden/v1beta1"
+
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestFramework(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Framework Suite")
+}
+
+const crdInfrastructure = `---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: infrastructures.extensions.gardener.cloud
+spec:
+  group: extensions.gardener.cloud
+  version: v1alpha1
+  scope: Namespaced
+  names:
+    plural: infrastructures
+    singular: infrastructure
+    kind: Infrastructure
+`
+
+const crdCluster = `---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: clusters.extensions.gardener.cloud
+spec:
+  group: extensions.gardener.cloud
+  version: v1alpha1
+  scope: Cluster
+  names:
+    plural: clusters
+    singular: cluster
+    kind: Cluster
+---
+`
+
+var _ = Describe("Framework", func() {
+	Describe("#DecodeCRDs", func() {
+		It("should decode all CRDs and skip empty documents", func() {
+			crds, err := DecodeCRDs([]byte(crdInfrastructure + crdCluster))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(crds).To(HaveLen(2))
+			Expect(crds[0].Name).To(Equal("infrastructures.extensions.gardener.cloud"))
+			Expect(crds[0].Spec.Names.Kind).To(Equal("Infrastructure"))
+			Expect(crds[1].Name).To(Equal("clusters.extensions.gardener.cloud"))
+		})
+
+		It("should fail for documents that are no CRDs", func() {
+			_, err := DecodeCRDs([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: foo\n"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#ReadCRDs", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "framework")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should read the CRDs of all crd-*.yaml files", func() {
+			Expect(ioutil.WriteFile(filepath.Join(dir, "crd-infrastructure.yaml"), []byte(crdInfrastructure), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "crd-cluster.yaml"), []byte(crdCluster), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "infrastructure.yaml"), []byte("kind: Infrastructure\n"), 0644)).To(Succeed())
+
+			crds, err := ReadCRDs(dir)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(crds).To(HaveLen(2))
+			Expect(crds[0].Name).To(Equal("clusters.extensions.gardener.cloud"))
+			Expect(crds[1].Name).To(Equal("infrastructures.extensions.gardener.cloud"))
+		})
+
+		It("should read the CRDs of the examples", func() {
+			crds, err := ReadCRDs("../../../../controllers/provider-aws/example")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(crds).To(HaveLen(4))
+		})
+	})
+
+	Describe("#ClusterBuilder", func() {
+		It("should build a Cluster with the embedded resources", func() {
+			var (
+				cloudProfile = NewCloudProfileBuilder("aws").With(func(cloudProfile *gardenv1beta1.CloudProfile) {
+					cloudProfile.Spec.AWS = &gardenv1beta1.AWSProfile{}
+				}).Build()
+				seed  = NewSeedBuilder("seed").WithCloud("aws", "eu-west-1").Build()
+				shoot = NewShootBuilder("garden-dev", "shoot").
+					WithCloud("aws", "eu-west-1").
+					WithSeed("seed").
+					WithKubernetesVersion("1.13.4").
+					Build()
+			)
+			cloudProfile.TypeMeta = metav1.TypeMeta{}
+
+			cluster, err := NewClusterBuilder("shoot--dev--shoot").
+				WithCloudProfile(cloudProfile).
+				WithSeed(seed).
+				WithShoot(shoot).
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cluster.Name).To(Equal("shoot--dev--shoot"))
+
+			actualCloudProfile, err := extensionscontroller.CloudProfileFromCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualCloudProfile.Name).To(Equal("aws"))
+			Expect(actualCloudProfile.Spec.AWS).NotTo(BeNil())
+
+			actualSeed, err := extensionscontroller.SeedFromCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualSeed.Spec.Cloud).To(Equal(gardenv1beta1.SeedCloud{Profile: "aws", Region: "eu-west-1"}))
+
+			actualShoot, err := extensionscontroller.ShootFromCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualShoot.Namespace).To(Equal("garden-dev"))
+			Expect(actualShoot.Name).To(Equal("shoot"))
+			Expect(actualShoot.Spec.Cloud.Region).To(Equal("eu-west-1"))
+			Expect(*actualShoot.Spec.Cloud.Seed).To(Equal("seed"))
+			Expect(actualShoot.Spec.Kubernetes.Version).To(Equal("1.13.4"))
+		})
+
+		It("should build a Cluster of a failed Shoot", func() {
+			shoot := NewShootBuilder("garden-dev", "shoot").
+				WithLastOperation(gardencorev1alpha1.LastOperationTypeReconcile, gardencorev1alpha1.LastOperationStateFailed).
+				Build()
+
+			cluster, err := NewClusterBuilder("shoot--dev--shoot").WithShoot(shoot).Build()
+			Expect(err).NotTo(HaveOccurred())
+
+			actualShoot, err := extensionscontroller.ShootFromCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(extensionscontroller.ShootIsFailed(actualShoot)).To(BeTrue())
+		})
+	})
+
+	Describe("#FakeInfrastructureActuator", func() {
+		It("should record the calls and return the configured errors", func() {
+			var (
+				ctx      = context.TODO()
+				actuator = &FakeInfrastructureActuator{}
+				cluster  = &extensionscontroller.Cluster{}
+				infra    = &extensionsv1alpha1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "infra", Generation: 2}}
+				err      = errors.New("error")
+			)
+
+			Expect(actuator.Reconcile(ctx, infra, cluster)).To(Succeed())
+			actuator.SetDeleteError(err)
+			Expect(actuator.Delete(ctx, infra, cluster)).To(Equal(err))
+
+			Expect(actuator.Calls()).To(Equal([]ActuatorCall{
+				{Operation: OperationReconcile, Namespace: "ns", Name: "infra", Generation: 2, Cluster: cluster},
+				{Operation: OperationDelete, Namespace: "ns", Name: "infra", Generation: 2, Cluster: cluster},
+			}))
+			Expect(actuator.CallsOf(OperationDelete)).To(HaveLen(1))
+
+			actuator.Reset()
+			Expect(actuator.Calls()).To(BeEmpty())
+			Expect(actuator.Delete(ctx, infra, cluster)).To(Succeed())
+		})
+	})
+
+	Describe("#FakeOperatingSystemConfigActuator", func() {
+		It("should return the configured cloud config", func() {
+			var (
+				command  = "command"
+				actuator = &FakeOperatingSystemConfigActuator{CloudConfig: []byte("cloud-config"), Command: &command, Units: []string{"unit"}}
+				config   = &extensionsv1alpha1.OperatingSystemConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "osc"}}
+			)
+
+			cloudConfig, actualCommand, units, err := actuator.Reconcile(context.TODO(), config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cloudConfig).To(Equal([]byte("cloud-config")))
+			Expect(actualCommand).To(Equal(&command))
+			Expect(units).To(Equal([]string{"unit"}))
+		})
+	})
+
+	Context("Wait", func() {
+		var (
+			ctrl *gomock.Controller
+			c    *mockclient.MockClient
+
+			ctx    context.Context
+			cancel context.CancelFunc
+
+			key = client.ObjectKey{Namespace: "ns", Name: "infra"}
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			c = mockclient.NewMockClient(ctrl)
+			ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		})
+
+		AfterEach(func() {
+			cancel()
+			ctrl.Finish()
+		})
+
+		withLastOperation := func(generation, observedGeneration int64, state gardencorev1alpha1.LastOperationState) func(context.Context, client.ObjectKey, runtime.Object) error {
+			return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				infra := obj.(*extensionsv1alpha1.Infrastructure)
+				infra.Generation = generation
+				infra.Status.ObservedGeneration = observedGeneration
+				infra.Status.LastOperation = &gardencorev1alpha1.LastOperation{Type: gardencorev1alpha1.LastOperationTypeReconcile, State: state}
+				return nil
+			}
+		}
+
+		Describe("#WaitForLastOperation", func() {
+			It("should wait until the last operation is reached for the current generation", func() {
+				gomock.InOrder(
+					c.EXPECT().Get(ctx, key, gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "infra")),
+					c.EXPECT().Get(ctx, key, gomock.Any()).DoAndReturn(withLastOperation(2, 1, gardencorev1alpha1.LastOperationStateSucceeded)),
+					c.EXPECT().Get(ctx, key, gomock.Any()).DoAndReturn(withLastOperation(2, 2, gardencorev1alpha1.LastOperationStateSucceeded)),
+				)
+
+				infra := &extensionsv1alpha1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "infra"}}
+				Expect(WaitForLastOperation(ctx, c, infra, gardencorev1alpha1.LastOperationTypeReconcile, gardencorev1alpha1.LastOperationStateSucceeded, time.Millisecond)).To(Succeed())
+				Expect(infra.Status.ObservedGeneration).To(Equal(int64(2)))
+			})
+
+			It("should fail if the last operation is not reached in time", func() {
+				c.EXPECT().Get(ctx, key, gomock.Any()).DoAndReturn(withLastOperation(1, 1, gardencorev1alpha1.LastOperationStateError)).AnyTimes()
+
+				infra := &extensionsv1alpha1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "infra"}}
+				Expect(WaitForLastOperation(ctx, c, infra, gardencorev1alpha1.LastOperationTypeReconcile, gardencorev1alpha1.LastOperationStateSucceeded, 10*time.Millisecond)).To(HaveOccurred())
+			})
+
+			It("should fail for resources that are no extension resources", func() {
+				c.EXPECT().Get(ctx, key, gomock.Any())
+
+				cluster := &extensionsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "infra"}}
+				Expect(WaitForLastOperation(ctx, c, cluster, gardencorev1alpha1.LastOperationTypeReconcile, gardencorev1alpha1.LastOperationStateSucceeded, time.Millisecond)).To(HaveOccurred())
+			})
+		})
+
+		Describe("#WaitForDeletion", func() {
+			It("should wait until the resource is gone", func() {
+				gomock.InOrder(
+					c.EXPECT().Get(ctx, key, gomock.Any()),
+					c.EXPECT().Get(ctx, key, gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "infra")),
+				)
+
+				infra := &extensionsv1alpha1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "infra"}}
+				Expect(WaitForDeletion(ctx, c, infra, time.Millisecond)).To(Succeed())
+			})
+		})
+	})
+})
@@ -0,0 +1,53 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framework
+
+import (
+	"context"
+	"fmt"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// NewManager creates a new manager for the given configuration whose scheme contains the extension resources.
+// Leader election and metrics are disabled.
+func NewManager(config *rest.Config) (manager.Manager, error) {
+	mgr, err := manager.New(config, manager.Options{MetricsBindAddress: "0"})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := extensionscontroller.AddToScheme(mgr.GetScheme()); err != nil {
+		return nil, err
+	}
+	return mgr, nil
+}
+
+// StartManager starts the given manager in the background until the context is done and waits for its caches
+// to be synced. Errors of the running manager are sent to the returned channel.
+func StartManager(ctx context.Context, mgr manager.Manager) (<-chan error, error) {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- mgr.Start(ctx.Done())
+	}()
+
+	if !mgr.GetCache().WaitForCacheSync(ctx.Done()) {
+		return nil, fmt.Errorf("could not sync caches of manager")
+	}
+	return errs, nil
+}
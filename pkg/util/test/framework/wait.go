@@ -0,0 +1,129 @@
+// Copyright (c) 2019 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultStatus returns the DefaultStatus of the given extension resource.
+func DefaultStatus(obj runtime.Object) (*extensionsv1alpha1.DefaultStatus, error) {
+	switch o := obj.(type) {
+	case *extensionsv1alpha1.Infrastructure:
+		return &o.Status.DefaultStatus, nil
+	case *extensionsv1alpha1.ControlPlane:
+		return &o.Status.DefaultStatus, nil
+	case *extensionsv1alpha1.OperatingSystemConfig:
+		return &o.Status.DefaultStatus, nil
+	case *extensionsv1alpha1.Worker:
+		return &o.Status.DefaultStatus, nil
+	case *extensionsv1alpha1.Extension:
+		return &o.Status.DefaultStatus, nil
+	default:
+		return nil, fmt.Errorf("%T is not an extension resource", obj)
+	}
+}
+
+// WaitUntil fetches the given extension resource every interval until the given condition on its status
+// is met or the context is done.
+func WaitUntil(ctx context.Context, c client.Client, obj runtime.Object, interval time.Duration, condition func(*extensionsv1alpha1.DefaultStatus) bool) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	key := client.ObjectKey{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}
+
+	return wait.PollImmediateUntil(interval, func() (bool, error) {
+		if err := c.Get(ctx, key, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		status, err := DefaultStatus(obj)
+		if err != nil {
+			return false, err
+		}
+		return condition(status), nil
+	}, ctx.Done())
+}
+
+// WaitForLastOperation waits until the last operation of the given extension resource has the given type and state
+// and, unless the state is processing, the resource's generation has been observed.
+func WaitForLastOperation(ctx context.Context, c client.Client, obj runtime.Object, t gardencorev1alpha1.LastOperationType, state gardencorev1alpha1.LastOperationState, interval time.Duration) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	if err := WaitUntil(ctx, c, obj, interval, func(status *extensionsv1alpha1.DefaultStatus) bool {
+		lastOperation := status.LastOperation
+		if lastOperation == nil || lastOperation.Type != t || lastOperation.State != state {
+			return false
+		}
+		return state == gardencorev1alpha1.LastOperationStateProcessing || status.ObservedGeneration >= accessor.GetGeneration()
+	}); err != nil {
+		if err == wait.ErrWaitTimeout {
+			status, _ := DefaultStatus(obj)
+			return fmt.Errorf("last operation of %s/%s did not reach %s %s, last seen: %+v", accessor.GetNamespace(), accessor.GetName(), t, state, lastOperationOf(status))
+		}
+		return err
+	}
+	return nil
+}
+
+// WaitForDeletion waits until the given object does not exist anymore.
+func WaitForDeletion(ctx context.Context, c client.Client, obj runtime.Object, interval time.Duration) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	key := client.ObjectKey{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}
+
+	if err := wait.PollImmediateUntil(interval, func() (bool, error) {
+		if err := c.Get(ctx, key, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		return false, nil
+	}, ctx.Done()); err != nil {
+		if err == wait.ErrWaitTimeout {
+			return fmt.Errorf("%s/%s has not been deleted, finalizers: %v", key.Namespace, key.Name, accessor.GetFinalizers())
+		}
+		return err
+	}
+	return nil
+}
+
+func lastOperationOf(status *extensionsv1alpha1.DefaultStatus) *gardencorev1alpha1.LastOperation {
+	if status == nil {
+		return nil
+	}
+	return status.LastOperation
+}